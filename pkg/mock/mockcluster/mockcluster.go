@@ -24,7 +24,9 @@ import (
 	"github.com/pingcap/pd/pkg/mock/mockid"
 	"github.com/pingcap/pd/pkg/mock/mockoption"
 	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/kv"
 	"github.com/pingcap/pd/server/namespace"
+	"github.com/pingcap/pd/server/placement/rule"
 	"github.com/pingcap/pd/server/statistics"
 	"go.uber.org/zap"
 )
@@ -36,7 +38,8 @@ type Cluster struct {
 	*mockoption.ScheduleOptions
 	*statistics.HotSpotCache
 	*statistics.StoresStats
-	ID uint64
+	RuleManager *rule.Manager
+	ID          uint64
 }
 
 // NewCluster creates a new Cluster
@@ -47,6 +50,7 @@ func NewCluster(opt *mockoption.ScheduleOptions) *Cluster {
 		ScheduleOptions: opt,
 		HotSpotCache:    statistics.NewHotSpotCache(),
 		StoresStats:     statistics.NewStoresStats(),
+		RuleManager:     rule.NewManager(core.NewStorage(kv.NewMemoryKV())),
 	}
 }
 
@@ -139,6 +143,16 @@ func (mc *Cluster) SetStoreDown(storeID uint64) {
 	mc.PutStore(newStore)
 }
 
+// SetStoreMaintenance puts the store into maintenance for ttl.
+func (mc *Cluster) SetStoreMaintenance(storeID uint64, ttl time.Duration, evictLeader bool) {
+	store := mc.GetStore(storeID)
+	newStore := store.Clone(core.SetStoreMaintenance(&core.StoreMaintenance{
+		Expire:      time.Now().Add(ttl),
+		EvictLeader: evictLeader,
+	}))
+	mc.PutStore(newStore)
+}
+
 // SetStoreOffline sets store state to be offline.
 func (mc *Cluster) SetStoreOffline(storeID uint64) {
 	store := mc.GetStore(storeID)
@@ -229,8 +243,13 @@ func (mc *Cluster) AddLeaderRegionWithRange(regionID uint64, startKey string, en
 
 // AddLeaderRegionWithReadInfo adds region with specified leader, followers and read info.
 func (mc *Cluster) AddLeaderRegionWithReadInfo(regionID uint64, leaderID uint64, readBytes uint64, followerIds ...uint64) {
+	mc.AddLeaderRegionWithReadFlow(regionID, leaderID, readBytes, 0, followerIds...)
+}
+
+// AddLeaderRegionWithReadFlow adds region with specified leader, followers, read bytes and read keys.
+func (mc *Cluster) AddLeaderRegionWithReadFlow(regionID uint64, leaderID uint64, readBytes, readKeys uint64, followerIds ...uint64) {
 	r := mc.newMockRegionInfo(regionID, leaderID, followerIds...)
-	r = r.Clone(core.SetReadBytes(readBytes))
+	r = r.Clone(core.SetReadBytes(readBytes), core.SetReadKeys(readKeys))
 	items := mc.HotSpotCache.CheckRead(r, mc.StoresStats)
 	for _, item := range items {
 		mc.HotSpotCache.Update(item)
@@ -240,8 +259,13 @@ func (mc *Cluster) AddLeaderRegionWithReadInfo(regionID uint64, leaderID uint64,
 
 // AddLeaderRegionWithWriteInfo adds region with specified leader, followers and write info.
 func (mc *Cluster) AddLeaderRegionWithWriteInfo(regionID uint64, leaderID uint64, writtenBytes uint64, followerIds ...uint64) {
+	mc.AddLeaderRegionWithWriteFlow(regionID, leaderID, writtenBytes, 0, followerIds...)
+}
+
+// AddLeaderRegionWithWriteFlow adds region with specified leader, followers, written bytes and written keys.
+func (mc *Cluster) AddLeaderRegionWithWriteFlow(regionID uint64, leaderID uint64, writtenBytes, writtenKeys uint64, followerIds ...uint64) {
 	r := mc.newMockRegionInfo(regionID, leaderID, followerIds...)
-	r = r.Clone(core.SetWrittenBytes(writtenBytes))
+	r = r.Clone(core.SetWrittenBytes(writtenBytes), core.SetWrittenKeys(writtenKeys))
 	items := mc.HotSpotCache.CheckWrite(r, mc.StoresStats)
 	for _, item := range items {
 		mc.HotSpotCache.Update(item)
@@ -427,6 +451,14 @@ func (mc *Cluster) GetHotRegionScheduleLimit() uint64 {
 	return mc.ScheduleOptions.GetHotRegionScheduleLimit(namespace.DefaultNamespace)
 }
 
+// GetRegionRule returns the placement rule which the region should follow.
+func (mc *Cluster) GetRegionRule(region *core.RegionInfo) *rule.Rule {
+	if r := mc.RuleManager.GetRuleForRegion(region); r != nil {
+		return r
+	}
+	return rule.NewDefaultRule(mc.GetMaxReplicas(), mc.GetLocationLabels())
+}
+
 // GetMaxReplicas mocks method.
 func (mc *Cluster) GetMaxReplicas() int {
 	return mc.ScheduleOptions.GetMaxReplicas(namespace.DefaultNamespace)
@@ -38,6 +38,8 @@ const (
 	defaultHighSpaceRatio              = 0.6
 	defaultSchedulerMaxWaitingOperator = 3
 	defaultHotRegionCacheHitsThreshold = 3
+	defaultHotRegionBalanceDimension   = "bytes"
+	defaultSplitScheduleLimit          = 4
 	defaultStrictlyMatchLabel          = true
 )
 
@@ -62,6 +64,11 @@ type ScheduleOptions struct {
 	LocationLabels               []string
 	StrictlyMatchLabel           bool
 	HotRegionCacheHitsThreshold  int
+	HotRegionBalanceDimension    string
+	SplitScheduleLimit           uint64
+	HotRegionSplitDuration       time.Duration
+	HotRegionSplitBytesThreshold uint64
+	HotRegionSplitKeysThreshold  uint64
 	TolerantSizeRatio            float64
 	LowSpaceRatio                float64
 	HighSpaceRatio               float64
@@ -93,6 +100,8 @@ func NewScheduleOptions() *ScheduleOptions {
 	mso.MaxReplicas = defaultMaxReplicas
 	mso.StrictlyMatchLabel = defaultStrictlyMatchLabel
 	mso.HotRegionCacheHitsThreshold = defaultHotRegionCacheHitsThreshold
+	mso.HotRegionBalanceDimension = defaultHotRegionBalanceDimension
+	mso.SplitScheduleLimit = defaultSplitScheduleLimit
 	mso.MaxPendingPeerCount = defaultMaxPendingPeerCount
 	mso.TolerantSizeRatio = defaultTolerantSizeRatio
 	mso.LowSpaceRatio = defaultLowSpaceRatio
@@ -185,6 +194,31 @@ func (mso *ScheduleOptions) GetHotRegionCacheHitsThreshold() int {
 	return mso.HotRegionCacheHitsThreshold
 }
 
+// GetHotRegionBalanceDimension mocks method
+func (mso *ScheduleOptions) GetHotRegionBalanceDimension() string {
+	return mso.HotRegionBalanceDimension
+}
+
+// GetSplitScheduleLimit mocks method
+func (mso *ScheduleOptions) GetSplitScheduleLimit() uint64 {
+	return mso.SplitScheduleLimit
+}
+
+// GetHotRegionSplitDuration mocks method
+func (mso *ScheduleOptions) GetHotRegionSplitDuration() time.Duration {
+	return mso.HotRegionSplitDuration
+}
+
+// GetHotRegionSplitBytesThreshold mocks method
+func (mso *ScheduleOptions) GetHotRegionSplitBytesThreshold() uint64 {
+	return mso.HotRegionSplitBytesThreshold
+}
+
+// GetHotRegionSplitKeysThreshold mocks method
+func (mso *ScheduleOptions) GetHotRegionSplitKeysThreshold() uint64 {
+	return mso.HotRegionSplitKeysThreshold
+}
+
 // GetTolerantSizeRatio mocks method
 func (mso *ScheduleOptions) GetTolerantSizeRatio() float64 {
 	return mso.TolerantSizeRatio
@@ -22,6 +22,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/pingcap/errcode"
 	"github.com/pingcap/pd/server"
+	"github.com/pingcap/pd/server/placement"
 	"github.com/pkg/errors"
 	"github.com/unrolled/render"
 )
@@ -208,3 +209,42 @@ func (h *confHandler) SetClusterVersion(w http.ResponseWriter, r *http.Request)
 	}
 	h.rd.JSON(w, http.StatusOK, nil)
 }
+
+func (h *confHandler) GetPlacement(w http.ResponseWriter, r *http.Request) {
+	cfg, err := h.svr.GetPlacementConfig()
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, cfg)
+}
+
+func (h *confHandler) SetPlacement(w http.ResponseWriter, r *http.Request) {
+	input := make(map[string]string)
+	if err := readJSONRespondError(h.rd, w, r.Body, &input); err != nil {
+		return
+	}
+	expr, ok := input["constraints"]
+	if !ok {
+		errorResp(h.rd, w, errcode.NewInvalidInputErr(errors.New("not set constraints")))
+		return
+	}
+	cfg, err := placement.ParseConfig(expr)
+	if err != nil {
+		errorResp(h.rd, w, errcode.NewInvalidInputErr(err))
+		return
+	}
+	if err := h.svr.SetPlacementConfig(cfg); err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, nil)
+}
+
+func (h *confHandler) DeletePlacement(w http.ResponseWriter, r *http.Request) {
+	if err := h.svr.SetPlacementConfig(&placement.Config{}); err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, nil)
+}
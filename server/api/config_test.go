@@ -21,6 +21,7 @@ import (
 	. "github.com/pingcap/check"
 	"github.com/pingcap/pd/server"
 	"github.com/pingcap/pd/server/config"
+	"github.com/pingcap/pd/server/placement"
 )
 
 var _ = Suite(&testConfigSuite{})
@@ -173,3 +174,32 @@ func (s *testConfigSuite) TestConfigLabelProperty(c *C) {
 	c.Assert(cfg, HasLen, 1)
 	c.Assert(cfg["foo"], DeepEquals, []config.StoreLabel{{Key: "zone", Value: "cn2"}})
 }
+
+func (s *testConfigSuite) TestConfigPlacement(c *C) {
+	addr := s.servers[0].GetAddr() + apiPrefix + "/api/v1/config/placement"
+
+	loadPlacement := func() *placement.Config {
+		res, err := doGet(addr)
+		c.Assert(err, IsNil)
+		cfg := &placement.Config{}
+		err = readJSON(res.Body, cfg)
+		c.Assert(err, IsNil)
+		return cfg
+	}
+
+	c.Assert(loadPlacement().Constraints, HasLen, 0)
+
+	err := postJSON(addr, []byte(`{"constraints": "count(zone:z1)>=2;count_leader(zone:z1)=1"}`))
+	c.Assert(err, IsNil)
+	cfg := loadPlacement()
+	c.Assert(cfg.Constraints, HasLen, 2)
+	c.Assert(cfg.String(), Equals, "count(zone:z1)>=2;count_leader(zone:z1)=1")
+
+	// Invalid expressions are rejected and the config is kept.
+	err = postJSON(addr, []byte(`{"constraints": "count(zone:z1)"}`))
+	c.Assert(err, NotNil)
+	c.Assert(loadPlacement().Constraints, HasLen, 2)
+
+	c.Assert(doDelete(addr), IsNil)
+	c.Assert(loadPlacement().Constraints, HasLen, 0)
+}
@@ -58,8 +58,14 @@ const (
 	tikvCap90
 	tikvLostPeers
 	tikvLostPeersLongTime
+	tikvLowSpaceSoon
+	tikvClusterLowSpaceSoon
 )
 
+// lowSpaceWarningDays is the projected days to the low space ratio within
+// which an early warning is reported.
+const lowSpaceWarningDays = 30
+
 var (
 	diagnoseMap = map[diagnoseType]Recommendation{
 		memberOneInstance:           {modMember, levelWarning, "only one PD instance is running.", "please add PD instance."},
@@ -72,6 +78,8 @@ var (
 		tikvCap90:                   {modTiKV, levelMajor, "some TiKV storage used more than 90%.", "please add TiKV node."},
 		tikvLostPeers:               {modTiKV, levelWarning, "some TiKV lost connect.", "please check network."},
 		tikvLostPeersLongTime:       {modTiKV, levelMajor, "some TiKV lost connect more than 1h.", "please check network."},
+		tikvLowSpaceSoon:            {modTiKV, levelWarning, "some TiKV storage is projected to reach the low space ratio in 30 days.", "please add TiKV node."},
+		tikvClusterLowSpaceSoon:     {modTiKV, levelMinor, "the TiKV cluster is projected to reach the low space ratio in 30 days.", "please add TiKV node."},
 	}
 )
 
@@ -157,6 +165,23 @@ func (d *diagnoseHandler) membersDiagnose(rdd *[]*Recommendation) error {
 }
 
 func (d *diagnoseHandler) tikvDiagnose(rdd *[]*Recommendation) error {
+	cluster := d.svr.GetRaftCluster()
+	if cluster == nil {
+		return nil
+	}
+	forecast := cluster.GetCapacityForecast()
+	stringStores := ""
+	for _, f := range forecast.Stores {
+		if f.DaysToLowSpace != nil && *f.DaysToLowSpace < lowSpaceWarningDays {
+			stringStores = fmt.Sprintf("%s store %d in %.1f days,", stringStores, f.StoreID, *f.DaysToLowSpace)
+		}
+	}
+	if stringStores != "" {
+		*rdd = append(*rdd, diagnosePD(tikvLowSpaceSoon, stringStores, ""))
+	}
+	if f := forecast.Cluster; f.DaysToLowSpace != nil && *f.DaysToLowSpace < lowSpaceWarningDays {
+		*rdd = append(*rdd, diagnosePD(tikvClusterLowSpaceSoon, fmt.Sprintf("in %.1f days", *f.DaysToLowSpace), ""))
+	}
 	return nil
 }
 
@@ -166,5 +191,9 @@ func (d *diagnoseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		d.rd.JSON(w, http.StatusInternalServerError, err.Error())
 		return
 	}
+	if err := d.tikvDiagnose(&rdd); err != nil {
+		d.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
 	d.rd.JSON(w, http.StatusOK, rdd)
 }
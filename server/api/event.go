@@ -0,0 +1,116 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/pd/server"
+	"github.com/pingcap/pd/server/schedule"
+	"github.com/pkg/errors"
+	"github.com/unrolled/render"
+	"go.uber.org/zap"
+)
+
+// eventStreamContentType is the content type of the server-sent events.
+const eventStreamContentType = "text/event-stream"
+
+type eventHandler struct {
+	*server.Handler
+	rd *render.Render
+}
+
+func newEventHandler(handler *server.Handler, rd *render.Render) *eventHandler {
+	return &eventHandler{
+		Handler: handler,
+		rd:      rd,
+	}
+}
+
+// Stream streams the scheduling events as server-sent events until the client
+// disconnects or the leader steps down. The events can be filtered by
+// region_id, store_id and kind, which can be a comma separated list.
+func (h *eventHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseEventFilter(r.URL.Query())
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		h.rd.JSON(w, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+	sub, err := h.SubscribeEvents(filter)
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", eventStreamContentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	for {
+		select {
+		case e, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(e)
+			if err != nil {
+				log.Error("failed to marshal event", zap.Error(err))
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Kind, data); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func parseEventFilter(query url.Values) (schedule.EventFilter, error) {
+	var filter schedule.EventFilter
+	var err error
+	if v := query.Get("region_id"); v != "" {
+		if filter.RegionID, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return filter, errors.WithStack(err)
+		}
+	}
+	if v := query.Get("store_id"); v != "" {
+		if filter.StoreID, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return filter, errors.WithStack(err)
+		}
+	}
+	for _, v := range query["kind"] {
+		for _, name := range strings.Split(v, ",") {
+			kind, err := schedule.ParseEventKind(name)
+			if err != nil {
+				return filter, err
+			}
+			filter.Kinds = append(filter.Kinds, kind)
+		}
+	}
+	return filter, nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/server"
+	"github.com/pingcap/pd/server/schedule"
+)
+
+var _ = Suite(&testEventSuite{})
+
+type testEventSuite struct {
+	svr       *server.Server
+	cleanup   cleanUpFunc
+	urlPrefix string
+}
+
+func (s *testEventSuite) SetUpSuite(c *C) {
+	s.svr, s.cleanup = mustNewServer(c)
+	mustWaitLeader(c, []*server.Server{s.svr})
+
+	addr := s.svr.GetAddr()
+	s.urlPrefix = fmt.Sprintf("%s%s/api/v1", addr, apiPrefix)
+
+	mustBootstrapCluster(c, s.svr)
+}
+
+func (s *testEventSuite) TearDownSuite(c *C) {
+	s.cleanup()
+}
+
+func (s *testEventSuite) subscribeEvents(c *C, query string) (<-chan *schedule.Event, func()) {
+	return mustSubscribeEvents(c, s.urlPrefix, query)
+}
+
+// mustSubscribeEvents opens the event stream and sends the events to the
+// channel until the stream ends.
+func mustSubscribeEvents(c *C, urlPrefix string, query string) (<-chan *schedule.Event, func()) {
+	resp, err := dialClient.Get(urlPrefix + "/events?" + query)
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	c.Assert(resp.Header.Get("Content-Type"), Equals, eventStreamContentType)
+	ch := make(chan *schedule.Event, 10)
+	go func() {
+		defer close(ch)
+		reader := bufio.NewReader(resp.Body)
+		var kind string
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimSpace(line)
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				kind = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				e := &schedule.Event{}
+				if json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), e) != nil || string(e.Kind) != kind {
+					return
+				}
+				ch <- e
+			}
+		}
+	}()
+	return ch, func() { resp.Body.Close() }
+}
+
+func mustReceiveEvent(c *C, ch <-chan *schedule.Event) *schedule.Event {
+	select {
+	case e, ok := <-ch:
+		c.Assert(ok, IsTrue)
+		return e
+	case <-time.After(5 * time.Second):
+		c.Fatal("no event received")
+	}
+	return nil
+}
+
+func (s *testEventSuite) TestStream(c *C) {
+	schedulerEvents, closeSchedulerEvents := s.subscribeEvents(c, "kind=scheduler-add,scheduler-remove")
+	defer closeSchedulerEvents()
+	storeEvents, closeStoreEvents := s.subscribeEvents(c, "store_id=2")
+	defer closeStoreEvents()
+
+	c.Assert(postJSON(s.urlPrefix+"/schedulers", []byte(`{"name": "shuffle-leader-scheduler"}`)), IsNil)
+	c.Assert(doDelete(s.urlPrefix+"/schedulers/shuffle-leader-scheduler"), IsNil)
+	e := mustReceiveEvent(c, schedulerEvents)
+	c.Assert(e.Kind, Equals, schedule.EventSchedulerAdd)
+	c.Assert(e.Scheduler, Equals, "shuffle-leader-scheduler")
+	e = mustReceiveEvent(c, schedulerEvents)
+	c.Assert(e.Kind, Equals, schedule.EventSchedulerRemove)
+
+	mustPutStore(c, s.svr, 3, metapb.StoreState_Up, nil)
+	mustPutStore(c, s.svr, 2, metapb.StoreState_Up, nil)
+	c.Assert(s.svr.GetRaftCluster().RemoveStore(2), IsNil)
+	e = mustReceiveEvent(c, storeEvents)
+	c.Assert(e.Kind, Equals, schedule.EventStoreState)
+	c.Assert(e.State, Equals, metapb.StoreState_Up.String())
+	e = mustReceiveEvent(c, storeEvents)
+	c.Assert(e.State, Equals, metapb.StoreState_Offline.String())
+	c.Assert(e.StoreIDs, DeepEquals, []uint64{2})
+
+	_, err := doGet(s.urlPrefix + "/events?kind=foo")
+	c.Assert(err, NotNil)
+}
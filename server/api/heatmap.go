@@ -0,0 +1,63 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pingcap/pd/server"
+	"github.com/unrolled/render"
+)
+
+type heatmapHandler struct {
+	*server.Handler
+	rd *render.Render
+}
+
+func newHeatmapHandler(handler *server.Handler, rd *render.Render) *heatmapHandler {
+	return &heatmapHandler{
+		Handler: handler,
+		rd:      rd,
+	}
+}
+
+// Get returns the key-space traffic heatmap, the time buckets can be filtered
+// by the time window [start, end] in unix seconds.
+func (h *heatmapHandler) Get(w http.ResponseWriter, r *http.Request) {
+	var start, end time.Time
+	if v := r.URL.Query().Get("start"); v != "" {
+		t, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		start = time.Unix(t, 0)
+	}
+	if v := r.URL.Query().Get("end"); v != "" {
+		t, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		end = time.Unix(t, 0)
+	}
+	matrix, err := h.Handler.GetHeatmap(start, end)
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, matrix)
+}
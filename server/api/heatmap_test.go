@@ -0,0 +1,60 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/pd/server"
+	"github.com/pingcap/pd/server/statistics"
+)
+
+var _ = Suite(&testHeatmapSuite{})
+
+type testHeatmapSuite struct {
+	svr       *server.Server
+	cleanup   cleanUpFunc
+	urlPrefix string
+}
+
+func (s *testHeatmapSuite) SetUpSuite(c *C) {
+	s.svr, s.cleanup = mustNewServer(c)
+	mustWaitLeader(c, []*server.Server{s.svr})
+
+	addr := s.svr.GetAddr()
+	s.urlPrefix = fmt.Sprintf("%s%s/api/v1/heatmap", addr, apiPrefix)
+
+	mustBootstrapCluster(c, s.svr)
+}
+
+func (s *testHeatmapSuite) TearDownSuite(c *C) {
+	s.cleanup()
+}
+
+func (s *testHeatmapSuite) TestGetHeatmap(c *C) {
+	var matrix statistics.HeatmapMatrix
+	err := readJSONWithURL(s.urlPrefix+"?start=0", &matrix)
+	c.Assert(err, IsNil)
+	c.Assert(matrix.TimeAxis, HasLen, 0)
+	c.Assert(matrix.KeyAxis, HasLen, 0)
+
+	for _, query := range []string{"start=a", "end=a"} {
+		resp, err := http.Get(s.urlPrefix + "?" + query)
+		c.Assert(err, IsNil)
+		c.Assert(resp.StatusCode, Equals, http.StatusBadRequest)
+		resp.Body.Close()
+	}
+}
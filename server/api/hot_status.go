@@ -15,8 +15,13 @@ package api
 
 import (
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/pingcap/pd/server"
+	"github.com/pingcap/pd/server/statistics"
+	"github.com/pkg/errors"
 	"github.com/unrolled/render"
 )
 
@@ -48,6 +53,66 @@ func (h *hotStatusHandler) GetHotReadRegions(w http.ResponseWriter, r *http.Requ
 	h.rd.JSON(w, http.StatusOK, h.Handler.GetHotReadRegions())
 }
 
+const defaultHotRegionHistoryLimit = 1000
+
+// GetHotRegionHistory returns the hot peers saved in the history, the latest
+// first. The peers can be filtered by store_id, table_id, kind (read or write)
+// and the time window [start, end] in unix seconds.
+func (h *hotStatusHandler) GetHotRegionHistory(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseHotRegionHistoryFilter(r.URL.Query())
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	records, err := h.Handler.GetHotRegionHistory(filter)
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, records)
+}
+
+func parseHotRegionHistoryFilter(query url.Values) (*statistics.HotRegionHistoryFilter, error) {
+	filter := &statistics.HotRegionHistoryFilter{Limit: defaultHotRegionHistoryLimit}
+	var err error
+	if v := query.Get("store_id"); v != "" {
+		if filter.StoreID, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	if v := query.Get("table_id"); v != "" {
+		if filter.TableID, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	if v := query.Get("kind"); v != "" {
+		if v != statistics.ReadFlow.String() && v != statistics.WriteFlow.String() {
+			return nil, errors.Errorf("unknown kind %s", v)
+		}
+		filter.Kind = v
+	}
+	if v := query.Get("start"); v != "" {
+		start, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		filter.Start = time.Unix(start, 0)
+	}
+	if v := query.Get("end"); v != "" {
+		end, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		filter.End = time.Unix(end, 0)
+	}
+	if v := query.Get("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	return filter, nil
+}
+
 func (h *hotStatusHandler) GetHotStores(w http.ResponseWriter, r *http.Request) {
 	bytesWriteStats := h.GetHotBytesWriteStores()
 	bytesReadStats := h.GetHotBytesReadStores()
@@ -20,6 +20,7 @@ import (
 	. "github.com/pingcap/check"
 	"github.com/pingcap/pd/server"
 	_ "github.com/pingcap/pd/server/schedulers"
+	"github.com/pingcap/pd/server/statistics"
 )
 
 var _ = Suite(&testHotStatusSuite{})
@@ -51,3 +52,17 @@ func (s testHotStatusSuite) TestGetHotStore(c *C) {
 	err = readJSON(resp.Body, &stat)
 	c.Assert(err, IsNil)
 }
+
+func (s testHotStatusSuite) TestGetHotRegionHistory(c *C) {
+	var records []*statistics.HotRegionHistoryRecord
+	err := readJSONWithURL(s.urlPrefix+"/regions/history?kind=write&table_id=45&start=0", &records)
+	c.Assert(err, IsNil)
+	c.Assert(records, HasLen, 0)
+
+	for _, query := range []string{"kind=unknown", "store_id=a", "table_id=a", "start=a", "limit=a"} {
+		resp, err := http.Get(s.urlPrefix + "/regions/history?" + query)
+		c.Assert(err, IsNil)
+		c.Assert(resp.StatusCode, Equals, http.StatusBadRequest)
+		resp.Body.Close()
+	}
+}
@@ -15,11 +15,17 @@ package api
 
 import (
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/pingcap/pd/server"
+	"github.com/pingcap/pd/server/schedule"
 	"github.com/pingcap/pd/server/schedule/operator"
+	"github.com/pkg/errors"
 	"github.com/unrolled/render"
 )
 
@@ -267,6 +273,66 @@ func (h *operatorHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	h.r.JSON(w, http.StatusOK, nil)
 }
 
+// PostBatch adds a batch of operators, either all of them are added or none
+// of them. It returns the progress of the batch.
+func (h *operatorHandler) PostBatch(w http.ResponseWriter, r *http.Request) {
+	var specs []*server.OperatorSpec
+	if err := readJSONRespondError(h.r, w, r.Body, &specs); err != nil {
+		return
+	}
+	if len(specs) == 0 {
+		h.r.JSON(w, http.StatusBadRequest, "missing operators")
+		return
+	}
+	for _, spec := range specs {
+		if spec == nil || spec.Name == "" {
+			h.r.JSON(w, http.StatusBadRequest, "missing operator name")
+			return
+		}
+	}
+
+	id, err := h.AddOperatorBatch(specs)
+	if err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	status, err := h.Handler.GetOperatorBatch(id)
+	if err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.r.JSON(w, http.StatusOK, status)
+}
+
+// GetBatch returns the progress of the operator batch.
+func (h *operatorHandler) GetBatch(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["batch_id"], 10, 64)
+	if err != nil {
+		h.r.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	status, err := h.Handler.GetOperatorBatch(id)
+	if err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.r.JSON(w, http.StatusOK, status)
+}
+
+// DeleteBatch cancels the running operators of the batch.
+func (h *operatorHandler) DeleteBatch(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["batch_id"], 10, 64)
+	if err != nil {
+		h.r.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.CancelOperatorBatch(id); err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.r.JSON(w, http.StatusOK, nil)
+}
+
 func parseStoreIDs(v interface{}) (map[uint64]struct{}, bool) {
 	items, ok := v.([]interface{})
 	if !ok {
@@ -282,3 +348,68 @@ func parseStoreIDs(v interface{}) (map[uint64]struct{}, bool) {
 	}
 	return ids, true
 }
+
+const defaultOperatorHistoryLimit = 100
+
+// History returns the ended operators, the latest first. The operators can be
+// filtered by region_id, store_id, kind, status and the time window [start,
+// end] of the finish time in unix seconds.
+func (h *operatorHandler) History(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseOperatorHistoryFilter(r.URL.Query())
+	if err != nil {
+		h.r.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	records, err := h.GetOperatorHistory(filter)
+	if err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.r.JSON(w, http.StatusOK, records)
+}
+
+func parseOperatorHistoryFilter(query url.Values) (*schedule.OperatorHistoryFilter, error) {
+	filter := &schedule.OperatorHistoryFilter{Limit: defaultOperatorHistoryLimit}
+	var err error
+	if v := query.Get("region_id"); v != "" {
+		if filter.RegionID, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	if v := query.Get("store_id"); v != "" {
+		if filter.StoreID, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	if v := query.Get("kind"); v != "" {
+		if filter.Kind, err = operator.ParseOperatorKind(v); err != nil {
+			return nil, err
+		}
+	}
+	if v := query.Get("status"); v != "" {
+		filter.Status = strings.ToUpper(v)
+		if _, ok := pdpb.OperatorStatus_value[filter.Status]; !ok {
+			return nil, errors.Errorf("unknown status %s", v)
+		}
+	}
+	if v := query.Get("start"); v != "" {
+		start, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		filter.Start = time.Unix(start, 0)
+	}
+	if v := query.Get("end"); v != "" {
+		end, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		filter.End = time.Unix(end, 0)
+	}
+	if v := query.Get("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	return filter, nil
+}
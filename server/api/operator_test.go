@@ -15,6 +15,7 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -26,6 +27,7 @@ import (
 	"github.com/pingcap/pd/server"
 	"github.com/pingcap/pd/server/config"
 	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/schedule"
 )
 
 var _ = Suite(&testOperatorSuite{})
@@ -90,6 +92,19 @@ func (s *testOperatorSuite) TestAddRemovePeer(c *C) {
 	err = doDelete(regionURL)
 	c.Assert(err, IsNil)
 
+	// The removed operators are recorded in the history.
+	var records []*schedule.OperatorHistoryRecord
+	c.Assert(readJSONWithURL(fmt.Sprintf("%s/operators/history?region_id=1&status=cancel", s.urlPrefix), &records), IsNil)
+	c.Assert(records, HasLen, 2)
+	c.Assert(records[0].Steps, DeepEquals, []string{"remove peer on store 2"})
+	c.Assert(records[0].Stores, DeepEquals, []uint64{2})
+	c.Assert(records[1].Kind, Equals, "region,admin")
+	c.Assert(readJSONWithURL(fmt.Sprintf("%s/operators/history?store_id=2&limit=1", s.urlPrefix), &records), IsNil)
+	c.Assert(records, HasLen, 1)
+	c.Assert(records[0].Status, Equals, "CANCEL")
+	_, err = doGet(fmt.Sprintf("%s/operators/history?status=unknown", s.urlPrefix))
+	c.Assert(err, NotNil)
+
 	mustPutStore(c, s.svr, 4, metapb.StoreState_Up, nil)
 	err = postJSON(fmt.Sprintf("%s/operators", s.urlPrefix), []byte(`{"name":"add-learner", "region_id": 1, "store_id": 4}`))
 	c.Assert(err, IsNil)
@@ -133,6 +148,69 @@ func (s *testOperatorSuite) TestMergeRegionOperator(c *C) {
 	c.Assert(err, NotNil)
 }
 
+func (s *testOperatorSuite) TestOperatorBatch(c *C) {
+	mustPutStore(c, s.svr, 1, metapb.StoreState_Up, nil)
+	mustPutStore(c, s.svr, 2, metapb.StoreState_Up, nil)
+	for i := uint64(100); i <= 101; i++ {
+		peer := &metapb.Peer{Id: i*10 + 1, StoreId: 1}
+		region := &metapb.Region{
+			Id:          i,
+			StartKey:    []byte(fmt.Sprintf("x%d", i)),
+			EndKey:      []byte(fmt.Sprintf("x%d", i+1)),
+			Peers:       []*metapb.Peer{peer},
+			RegionEpoch: &metapb.RegionEpoch{ConfVer: 10, Version: 10},
+		}
+		mustRegionHeartbeat(c, s.svr, core.NewRegionInfo(region, peer))
+	}
+	batchURL := fmt.Sprintf("%s/operators/batch", s.urlPrefix)
+
+	// Region 102 does not exist, so none of the operators is added.
+	err := postJSON(batchURL, []byte(`[{"name":"add-peer", "region_id": 100, "store_id": 2}, {"name":"add-peer", "region_id": 102, "store_id": 2}]`))
+	c.Assert(err, NotNil)
+	c.Assert(strings.Contains(mustReadURL(c, fmt.Sprintf("%s/operators/100", s.urlPrefix)), "operator not found"), IsTrue)
+	err = postJSON(batchURL, []byte(`[]`))
+	c.Assert(err, NotNil)
+	err = postJSON(batchURL, []byte(`[{"region_id": 100, "store_id": 2}]`))
+	c.Assert(err, NotNil)
+	// The epoch of region 101 is changed.
+	err = postJSON(batchURL, []byte(`[{"name":"add-peer", "region_id": 100, "store_id": 2, "conf_ver": 10, "version": 10}, {"name":"add-peer", "region_id": 101, "store_id": 2, "conf_ver": 9}]`))
+	c.Assert(err, NotNil)
+	c.Assert(strings.Contains(err.Error(), "epoch is changed"), IsTrue)
+	c.Assert(strings.Contains(mustReadURL(c, fmt.Sprintf("%s/operators/100", s.urlPrefix)), "operator not found"), IsTrue)
+	// The batch with a duplicated region is rejected without allocating the
+	// peer IDs.
+	lastID, err := s.svr.GetAllocator().Alloc()
+	c.Assert(err, IsNil)
+	err = postJSON(batchURL, []byte(`[{"name":"add-peer", "region_id": 100, "store_id": 2}, {"name":"transfer-region", "region_id": 100, "to_store_ids": [2]}]`))
+	c.Assert(err, NotNil)
+	id, err := s.svr.GetAllocator().Alloc()
+	c.Assert(err, IsNil)
+	c.Assert(id, Equals, lastID+1)
+
+	var status schedule.OperatorBatchStatus
+	err = postJSON(batchURL, []byte(`[{"name":"add-peer", "region_id": 100, "store_id": 2}, {"name":"transfer-region", "region_id": 101, "to_store_ids": [2], "version": 10}]`), func(res []byte) bool {
+		return json.Unmarshal(res, &status) == nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(status.Total, Equals, 2)
+	c.Assert(status.Running, Equals, 2)
+	c.Assert(status.Operators[0].Desc, Equals, "admin-add-peer")
+	c.Assert(status.Operators[1].Desc, Equals, "admin-move-region")
+
+	id = status.ID
+	status = schedule.OperatorBatchStatus{}
+	c.Assert(readJSONWithURL(fmt.Sprintf("%s/%d", batchURL, id), &status), IsNil)
+	c.Assert(status.ID, Equals, id)
+	c.Assert(status.Running, Equals, 2)
+	err = doDelete(fmt.Sprintf("%s/%d", batchURL, id))
+	c.Assert(err, IsNil)
+	c.Assert(readJSONWithURL(fmt.Sprintf("%s/%d", batchURL, id), &status), IsNil)
+	c.Assert(status.Failed, Equals, 2)
+	c.Assert(status.Finished, IsTrue)
+	c.Assert(status.Operators[0].Status, Equals, "CANCEL")
+	c.Assert(readJSONWithURL(fmt.Sprintf("%s/%d", batchURL, 100), &status), NotNil)
+}
+
 func mustPutStore(c *C, svr *server.Server, id uint64, state metapb.StoreState, labels []*metapb.StoreLabel) {
 	_, err := svr.PutStore(context.Background(), &pdpb.PutStoreRequest{
 		Header: &pdpb.RequestHeader{ClusterId: svr.ClusterID()},
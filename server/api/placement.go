@@ -0,0 +1,93 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/hex"
+	"net/http"
+	"strconv"
+
+	"github.com/pingcap/pd/server"
+	"github.com/unrolled/render"
+)
+
+type placementHandler struct {
+	*server.Handler
+	r *render.Render
+}
+
+func newPlacementHandler(handler *server.Handler, r *render.Render) *placementHandler {
+	return &placementHandler{
+		Handler: handler,
+		r:       r,
+	}
+}
+
+// Explain explains the placement of a region if region_id is specified,
+// otherwise the placement of the regions in the hex encoded key range
+// [start_key, end_key).
+func (h *placementHandler) Explain(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if id := query.Get("region_id"); id != "" {
+		regionID, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			h.r.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		cluster := h.GetRaftCluster()
+		if cluster == nil {
+			h.r.JSON(w, http.StatusInternalServerError, server.ErrNotBootstrapped.Error())
+			return
+		}
+		if cluster.GetRegion(regionID) == nil {
+			h.r.JSON(w, http.StatusNotFound, server.ErrRegionNotFound(regionID).Error())
+			return
+		}
+		exp, err := h.ExplainRegionPlacement(regionID)
+		if err != nil {
+			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		h.r.JSON(w, http.StatusOK, exp)
+		return
+	}
+
+	startKey, err := hex.DecodeString(query.Get("start_key"))
+	if err != nil {
+		h.r.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	endKey, err := hex.DecodeString(query.Get("end_key"))
+	if err != nil {
+		h.r.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	limit := defaultRegionLimit
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			h.r.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if limit > maxRegionLimit {
+		limit = maxRegionLimit
+	}
+	exps, err := h.ExplainRangePlacement(startKey, endKey, limit)
+	if err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.r.JSON(w, http.StatusOK, exps)
+}
@@ -0,0 +1,80 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/pd/server"
+)
+
+var _ = Suite(&testPlacementSuite{})
+
+type testPlacementSuite struct {
+	svr       *server.Server
+	cleanup   cleanUpFunc
+	urlPrefix string
+}
+
+func (s *testPlacementSuite) SetUpSuite(c *C) {
+	s.svr, s.cleanup = mustNewServer(c)
+	mustWaitLeader(c, []*server.Server{s.svr})
+
+	addr := s.svr.GetAddr()
+	s.urlPrefix = fmt.Sprintf("%s%s/api/v1/placement", addr, apiPrefix)
+
+	mustBootstrapCluster(c, s.svr)
+}
+
+func (s *testPlacementSuite) TearDownSuite(c *C) {
+	s.cleanup()
+}
+
+// explanation only contains the fields can be decoded, the operators are
+// encoded as strings.
+type explanation struct {
+	RegionID       uint64                   `json:"region_id"`
+	Statuses       []server.PlacementStatus `json:"statuses"`
+	IsolationLevel int                      `json:"isolation_level"`
+	NextOperators  []string                 `json:"next_operators"`
+}
+
+func (s *testPlacementSuite) TestExplain(c *C) {
+	mustRegionHeartbeat(c, s.svr, newTestRegionInfo(2, 1, []byte("a"), []byte("b")))
+	mustRegionHeartbeat(c, s.svr, newTestRegionInfo(3, 1, []byte("b"), []byte("c")))
+
+	exp := &explanation{}
+	c.Assert(readJSONWithURL(s.urlPrefix+"/explain?region_id=2", exp), IsNil)
+	c.Assert(exp.RegionID, Equals, uint64(2))
+	c.Assert(exp.Statuses[0].Constraint, Equals, "voters=3")
+	c.Assert(exp.Statuses[0].Satisfied, IsFalse)
+	c.Assert(exp.Statuses[0].Detail, Equals, "1 voters")
+
+	var exps []*explanation
+	c.Assert(readJSONWithURL(s.urlPrefix+"/explain?start_key=61&end_key=63", &exps), IsNil)
+	c.Assert(exps, HasLen, 2)
+	c.Assert(exps[0].RegionID, Equals, uint64(2))
+	c.Assert(exps[1].RegionID, Equals, uint64(3))
+	c.Assert(readJSONWithURL(s.urlPrefix+"/explain?start_key=62&end_key=63", &exps), IsNil)
+	c.Assert(exps, HasLen, 1)
+
+	resp, err := dialClient.Get(s.urlPrefix + "/explain?region_id=100")
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusNotFound)
+	resp.Body.Close()
+	_, err = doGet(s.urlPrefix + "/explain?start_key=xx")
+	c.Assert(err, NotNil)
+}
@@ -14,6 +14,7 @@
 package api
 
 import (
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -99,6 +100,16 @@ func (p *customReverseProxies) ServeHTTP(w http.ResponseWriter, r *http.Request)
 			continue
 		}
 
+		// The event stream does not end until the client disconnects, so it
+		// is forwarded as soon as the events arrive.
+		if strings.HasPrefix(resp.Header.Get("Content-Type"), eventStreamContentType) {
+			copyHeader(w.Header(), resp.Header)
+			w.WriteHeader(resp.StatusCode)
+			streamBody(w, resp.Body)
+			resp.Body.Close()
+			return
+		}
+
 		b, err := ioutil.ReadAll(resp.Body)
 		resp.Body.Close()
 		if err != nil {
@@ -119,6 +130,31 @@ func (p *customReverseProxies) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	http.Error(w, errRedirectFailed, http.StatusInternalServerError)
 }
 
+// streamBody copies the body to w and flushes the data as soon as it arrives.
+// The header is flushed first, so the client does not wait for the first
+// event to get the response.
+func streamBody(w http.ResponseWriter, body io.Reader) {
+	flusher, _ := w.(http.Flusher)
+	if flusher != nil {
+		flusher.Flush()
+	}
+	buf := make([]byte, 4096)
+	for {
+		n, err := body.Read(buf)
+		if n > 0 {
+			if _, err := w.Write(buf[:n]); err != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
 func copyHeader(dst, src http.Header) {
 	for k, vv := range src {
 		values := dst[k]
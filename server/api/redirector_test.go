@@ -18,6 +18,7 @@ import (
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/pd/server"
+	"github.com/pingcap/pd/server/schedule"
 )
 
 var _ = Suite(&testRedirectorSuite{})
@@ -77,6 +78,33 @@ func (s *testRedirectorSuite) TestNotLeader(c *C) {
 	c.Assert(resp.StatusCode, Not(Equals), http.StatusOK)
 }
 
+func (s *testRedirectorSuite) TestStreamEvents(c *C) {
+	leader := mustWaitLeader(c, s.servers)
+	mustBootstrapCluster(c, leader)
+	var follower *server.Server
+	for _, svr := range s.servers {
+		if svr != leader {
+			follower = svr
+			break
+		}
+	}
+
+	// The events are streamed through the follower as soon as they happen.
+	leaderPrefix := leader.GetAddr() + apiPrefix + "/api/v1"
+	events, closeEvents := mustSubscribeEvents(c, follower.GetAddr()+apiPrefix+"/api/v1", "kind=scheduler-add")
+	defer closeEvents()
+	c.Assert(postJSON(leaderPrefix+"/schedulers", []byte(`{"name": "shuffle-leader-scheduler"}`)), IsNil)
+	defer doDelete(leaderPrefix + "/schedulers/shuffle-leader-scheduler")
+	// The default schedulers may be added after the subscription.
+	for {
+		e := mustReceiveEvent(c, events)
+		c.Assert(e.Kind, Equals, schedule.EventSchedulerAdd)
+		if e.Scheduler == "shuffle-leader-scheduler" {
+			break
+		}
+	}
+}
+
 func mustRequest(c *C, s *server.Server) *http.Response {
 	resp, err := http.Get(s.GetAddr() + apiPrefix + "/api/v1/version")
 	c.Assert(err, IsNil)
@@ -248,6 +248,18 @@ func (h *regionsHandler) GetIncorrectNamespaceRegions(w http.ResponseWriter, r *
 	h.rd.JSON(w, http.StatusOK, regionsInfo)
 }
 
+// GetKeySpaceIssues returns the holes between the adjacent regions and the
+// regions whose leader has not reported for a while.
+func (h *regionsHandler) GetKeySpaceIssues(w http.ResponseWriter, r *http.Request) {
+	handler := h.svr.GetHandler()
+	report, err := handler.AuditKeySpace()
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, report)
+}
+
 func (h *regionsHandler) GetRegionSiblings(w http.ResponseWriter, r *http.Request) {
 	cluster := h.svr.GetRaftCluster()
 	if cluster == nil {
@@ -24,6 +24,7 @@ import (
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/pingcap/pd/server"
 	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/statistics"
 )
 
 var _ = Suite(&testRegionSuite{})
@@ -109,6 +110,15 @@ func (s *testRegionSuite) TestRegionCheck(c *C) {
 	c.Assert(r3, DeepEquals, &RegionsInfo{Count: 1, Regions: []*RegionInfo{NewRegionInfo(r)}})
 }
 
+func (s *testRegionSuite) TestKeySpaceIssues(c *C) {
+	url := fmt.Sprintf("%s/regions/check/key-holes", s.urlPrefix)
+	report := &statistics.KeySpaceAuditReport{}
+	err := readJSONWithURL(url, report)
+	c.Assert(err, IsNil)
+	c.Assert(report.RegionCount, Greater, 0)
+	c.Assert(report.StaleLeaders, HasLen, 0)
+}
+
 func (s *testRegionSuite) TestRegions(c *C) {
 	rs := []*core.RegionInfo{
 		newTestRegionInfo(2, 1, []byte("a"), []byte("b")),
@@ -35,13 +35,23 @@ func createRouter(prefix string, svr *server.Server) *mux.Router {
 	operatorHandler := newOperatorHandler(handler, rd)
 	router.HandleFunc("/api/v1/operators", operatorHandler.List).Methods("GET")
 	router.HandleFunc("/api/v1/operators", operatorHandler.Post).Methods("POST")
+	router.HandleFunc("/api/v1/operators/history", operatorHandler.History).Methods("GET")
+	router.HandleFunc("/api/v1/operators/batch", operatorHandler.PostBatch).Methods("POST")
+	router.HandleFunc("/api/v1/operators/batch/{batch_id}", operatorHandler.GetBatch).Methods("GET")
+	router.HandleFunc("/api/v1/operators/batch/{batch_id}", operatorHandler.DeleteBatch).Methods("DELETE")
 	router.HandleFunc("/api/v1/operators/{region_id}", operatorHandler.Get).Methods("GET")
 	router.HandleFunc("/api/v1/operators/{region_id}", operatorHandler.Delete).Methods("DELETE")
 
+	eventHandler := newEventHandler(handler, rd)
+	router.HandleFunc("/api/v1/events", eventHandler.Stream).Methods("GET")
+
 	schedulerHandler := newSchedulerHandler(handler, rd)
 	router.HandleFunc("/api/v1/schedulers", schedulerHandler.List).Methods("GET")
 	router.HandleFunc("/api/v1/schedulers", schedulerHandler.Post).Methods("POST")
 	router.HandleFunc("/api/v1/schedulers/{name}", schedulerHandler.Delete).Methods("DELETE")
+	router.HandleFunc("/api/v1/schedulers/{name}", schedulerHandler.PauseOrResume).Methods("POST")
+	router.HandleFunc("/api/v1/schedulers/{name}/progress", schedulerHandler.GetProgress).Methods("GET")
+	router.HandleFunc("/api/v1/schedulers/{name}/diagnosis", schedulerHandler.GetDiagnosis).Methods("GET")
 
 	clusterHandler := newClusterHandler(svr, rd)
 	router.Handle("/api/v1/cluster", clusterHandler).Methods("GET")
@@ -61,6 +71,22 @@ func createRouter(prefix string, svr *server.Server) *mux.Router {
 	router.HandleFunc("/api/v1/config/label-property", confHandler.SetLabelProperty).Methods("POST")
 	router.HandleFunc("/api/v1/config/cluster-version", confHandler.GetClusterVersion).Methods("GET")
 	router.HandleFunc("/api/v1/config/cluster-version", confHandler.SetClusterVersion).Methods("POST")
+	router.HandleFunc("/api/v1/config/placement", confHandler.GetPlacement).Methods("GET")
+	router.HandleFunc("/api/v1/config/placement", confHandler.SetPlacement).Methods("POST")
+	router.HandleFunc("/api/v1/config/placement", confHandler.DeletePlacement).Methods("DELETE")
+
+	ruleHandler := newRuleHandler(svr, rd)
+	router.HandleFunc("/api/v1/config/rules", ruleHandler.GetAll).Methods("GET")
+	router.HandleFunc("/api/v1/config/rule", ruleHandler.Set).Methods("POST")
+	router.HandleFunc("/api/v1/config/rule/{id}", ruleHandler.Get).Methods("GET")
+	router.HandleFunc("/api/v1/config/rule/{id}", ruleHandler.Delete).Methods("DELETE")
+
+	placementHandler := newPlacementHandler(handler, rd)
+	router.HandleFunc("/api/v1/placement/explain", placementHandler.Explain).Methods("GET")
+
+	schedulePlanHandler := newSchedulePlanHandler(handler, rd)
+	router.HandleFunc("/api/v1/schedule/plan", schedulePlanHandler.Get).Methods("GET")
+	router.HandleFunc("/api/v1/schedule/plan", schedulePlanHandler.Post).Methods("POST")
 
 	storeHandler := newStoreHandler(handler, rd)
 	router.HandleFunc("/api/v1/store/{id}", storeHandler.Get).Methods("GET")
@@ -69,21 +95,31 @@ func createRouter(prefix string, svr *server.Server) *mux.Router {
 	router.HandleFunc("/api/v1/store/{id}/label", storeHandler.SetLabels).Methods("POST")
 	router.HandleFunc("/api/v1/store/{id}/weight", storeHandler.SetWeight).Methods("POST")
 	router.HandleFunc("/api/v1/store/{id}/limit", storeHandler.SetLimit).Methods("POST")
+	router.HandleFunc("/api/v1/store/{id}/maintenance", storeHandler.SetMaintenance).Methods("POST")
+	router.HandleFunc("/api/v1/store/{id}/maintenance", storeHandler.RemoveMaintenance).Methods("DELETE")
 	storesHandler := newStoresHandler(handler, rd)
 	router.Handle("/api/v1/stores", storesHandler).Methods("GET")
 	router.HandleFunc("/api/v1/stores/remove-tombstone", storesHandler.RemoveTombStone).Methods("DELETE")
 	router.HandleFunc("/api/v1/stores/limit", storesHandler.GetAllLimit).Methods("GET")
 	router.HandleFunc("/api/v1/stores/limit", storesHandler.SetAllLimit).Methods("POST")
+	router.HandleFunc("/api/v1/stores/forecast", storesHandler.GetForecast).Methods("GET")
 
 	labelsHandler := newLabelsHandler(svr, rd)
 	router.HandleFunc("/api/v1/labels", labelsHandler.Get).Methods("GET")
 	router.HandleFunc("/api/v1/labels/stores", labelsHandler.GetStores).Methods("GET")
 
+	topologyHandler := newTopologyHandler(svr, rd)
+	router.HandleFunc("/api/v1/topology", topologyHandler.Get).Methods("GET")
+
 	hotStatusHandler := newHotStatusHandler(handler, rd)
 	router.HandleFunc("/api/v1/hotspot/regions/write", hotStatusHandler.GetHotWriteRegions).Methods("GET")
 	router.HandleFunc("/api/v1/hotspot/regions/read", hotStatusHandler.GetHotReadRegions).Methods("GET")
+	router.HandleFunc("/api/v1/hotspot/regions/history", hotStatusHandler.GetHotRegionHistory).Methods("GET")
 	router.HandleFunc("/api/v1/hotspot/stores", hotStatusHandler.GetHotStores).Methods("GET")
 
+	heatmapHandler := newHeatmapHandler(handler, rd)
+	router.HandleFunc("/api/v1/heatmap", heatmapHandler.Get).Methods("GET")
+
 	regionHandler := newRegionHandler(svr, rd)
 	router.HandleFunc("/api/v1/region/id/{id}", regionHandler.GetRegionByID).Methods("GET")
 	router.HandleFunc("/api/v1/region/key/{key}", regionHandler.GetRegionByKey).Methods("GET")
@@ -103,6 +139,7 @@ func createRouter(prefix string, svr *server.Server) *mux.Router {
 	router.HandleFunc("/api/v1/regions/check/down-peer", regionsHandler.GetDownPeerRegions).Methods("GET")
 	router.HandleFunc("/api/v1/regions/sibling/{id}", regionsHandler.GetRegionSiblings).Methods("GET")
 	router.HandleFunc("/api/v1/regions/check/incorrect-ns", regionsHandler.GetIncorrectNamespaceRegions).Methods("GET")
+	router.HandleFunc("/api/v1/regions/check/key-holes", regionsHandler.GetKeySpaceIssues).Methods("GET")
 
 	router.Handle("/api/v1/version", newVersionHandler(rd)).Methods("GET")
 	router.Handle("/api/v1/status", newStatusHandler(rd)).Methods("GET")
@@ -124,6 +161,8 @@ func createRouter(prefix string, svr *server.Server) *mux.Router {
 
 	statsHandler := newStatsHandler(svr, rd)
 	router.HandleFunc("/api/v1/stats/region", statsHandler.Region).Methods("GET")
+	router.HandleFunc("/api/v1/stats/tables", statsHandler.Tables).Methods("GET")
+	router.HandleFunc("/api/v1/stats/table/{id}", statsHandler.Table).Methods("GET")
 
 	trendHandler := newTrendHandler(svr, rd)
 	router.HandleFunc("/api/v1/trend", trendHandler.Handle).Methods("GET")
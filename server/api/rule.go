@@ -0,0 +1,95 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/pingcap/errcode"
+	"github.com/pingcap/pd/server"
+	"github.com/pingcap/pd/server/placement/rule"
+	"github.com/unrolled/render"
+)
+
+type ruleHandler struct {
+	svr *server.Server
+	rd  *render.Render
+}
+
+func newRuleHandler(svr *server.Server, rd *render.Render) *ruleHandler {
+	return &ruleHandler{
+		svr: svr,
+		rd:  rd,
+	}
+}
+
+func (h *ruleHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	cluster := h.svr.GetRaftCluster()
+	if cluster == nil {
+		h.rd.JSON(w, http.StatusInternalServerError, server.ErrNotBootstrapped.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, cluster.GetRuleManager().GetAllRules())
+}
+
+func (h *ruleHandler) Get(w http.ResponseWriter, r *http.Request) {
+	cluster := h.svr.GetRaftCluster()
+	if cluster == nil {
+		h.rd.JSON(w, http.StatusInternalServerError, server.ErrNotBootstrapped.Error())
+		return
+	}
+	id := mux.Vars(r)["id"]
+	rule := cluster.GetRuleManager().GetRule(id)
+	if rule == nil {
+		h.rd.JSON(w, http.StatusNotFound, nil)
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, rule)
+}
+
+func (h *ruleHandler) Set(w http.ResponseWriter, r *http.Request) {
+	cluster := h.svr.GetRaftCluster()
+	if cluster == nil {
+		h.rd.JSON(w, http.StatusInternalServerError, server.ErrNotBootstrapped.Error())
+		return
+	}
+	newRule := &rule.Rule{}
+	if err := readJSONRespondError(h.rd, w, r.Body, newRule); err != nil {
+		return
+	}
+	if err := newRule.Adjust(); err != nil {
+		errorResp(h.rd, w, errcode.NewInvalidInputErr(err))
+		return
+	}
+	if err := cluster.GetRuleManager().SetRule(newRule); err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, nil)
+}
+
+func (h *ruleHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	cluster := h.svr.GetRaftCluster()
+	if cluster == nil {
+		h.rd.JSON(w, http.StatusInternalServerError, server.ErrNotBootstrapped.Error())
+		return
+	}
+	id := mux.Vars(r)["id"]
+	if err := cluster.GetRuleManager().DeleteRule(id); err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, nil)
+}
@@ -0,0 +1,69 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/pd/server"
+	"github.com/pingcap/pd/server/placement/rule"
+)
+
+var _ = Suite(&testRuleSuite{})
+
+type testRuleSuite struct {
+	svr       *server.Server
+	cleanup   cleanUpFunc
+	urlPrefix string
+}
+
+func (s *testRuleSuite) SetUpSuite(c *C) {
+	s.svr, s.cleanup = mustNewServer(c)
+	mustWaitLeader(c, []*server.Server{s.svr})
+
+	addr := s.svr.GetAddr()
+	s.urlPrefix = fmt.Sprintf("%s%s/api/v1/config", addr, apiPrefix)
+
+	mustBootstrapCluster(c, s.svr)
+}
+
+func (s *testRuleSuite) TearDownSuite(c *C) {
+	s.cleanup()
+}
+
+func (s *testRuleSuite) TestRule(c *C) {
+	var rules []*rule.Rule
+	c.Assert(readJSONWithURL(s.urlPrefix+"/rules", &rules), IsNil)
+	c.Assert(rules, HasLen, 0)
+
+	body := `{"id": "t1", "priority": 1, "start_key": "61", "end_key": "62", "voters": 5, "location_labels": ["zone", "host"], "isolation_level": "host"}`
+	c.Assert(postJSON(s.urlPrefix+"/rule", []byte(body)), IsNil)
+	// Invalid rules are rejected.
+	c.Assert(postJSON(s.urlPrefix+"/rule", []byte(`{"id": "t2", "voters": 0}`)), NotNil)
+	c.Assert(postJSON(s.urlPrefix+"/rule", []byte(`{"id": "t2", "start_key": "xx", "voters": 3}`)), NotNil)
+
+	c.Assert(readJSONWithURL(s.urlPrefix+"/rules", &rules), IsNil)
+	c.Assert(rules, HasLen, 1)
+	r := &rule.Rule{}
+	c.Assert(readJSONWithURL(s.urlPrefix+"/rule/t1", r), IsNil)
+	c.Assert(r.Voters, Equals, 5)
+	c.Assert(r.IsolationLevel, Equals, "host")
+	c.Assert(s.svr.GetRaftCluster().GetRuleManager().GetRule("t1").EndKey, DeepEquals, []byte("b"))
+
+	c.Assert(doDelete(s.urlPrefix+"/rule/t1"), IsNil)
+	// The rule is not found.
+	_, err := doGet(s.urlPrefix + "/rule/t1")
+	c.Assert(err, NotNil)
+}
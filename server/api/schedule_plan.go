@@ -0,0 +1,78 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/pingcap/pd/server"
+	"github.com/pingcap/pd/server/config"
+	"github.com/unrolled/render"
+)
+
+type schedulePlanHandler struct {
+	*server.Handler
+	r *render.Render
+}
+
+func newSchedulePlanHandler(handler *server.Handler, r *render.Render) *schedulePlanHandler {
+	return &schedulePlanHandler{
+		Handler: handler,
+		r:       r,
+	}
+}
+
+type schedulePlanInput struct {
+	Schedulers []config.SchedulerConfig `json:"schedulers"`
+	Checkers   []string                 `json:"checkers"`
+	Limit      int                      `json:"limit"`
+}
+
+// Get runs the enabled schedulers and all the checkers once and returns the
+// operators they create without dispatching them.
+func (h *schedulePlanHandler) Get(w http.ResponseWriter, r *http.Request) {
+	limit := defaultRegionLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			h.r.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	h.plan(w, schedulePlanInput{Limit: limit})
+}
+
+// Post runs the specified schedulers and checkers once and returns the
+// operators they create without dispatching them.
+func (h *schedulePlanHandler) Post(w http.ResponseWriter, r *http.Request) {
+	input := schedulePlanInput{Limit: defaultRegionLimit}
+	if err := readJSONRespondError(h.r, w, r.Body, &input); err != nil {
+		return
+	}
+	h.plan(w, input)
+}
+
+func (h *schedulePlanHandler) plan(w http.ResponseWriter, input schedulePlanInput) {
+	if input.Limit > maxRegionLimit {
+		input.Limit = maxRegionLimit
+	}
+	ops, err := h.PlanSchedule(input.Schedulers, input.Checkers, input.Limit)
+	if err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.r.JSON(w, http.StatusOK, ops)
+}
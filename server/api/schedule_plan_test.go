@@ -0,0 +1,93 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/pd/server"
+)
+
+var _ = Suite(&testSchedulePlanSuite{})
+
+type testSchedulePlanSuite struct {
+	svr       *server.Server
+	cleanup   cleanUpFunc
+	urlPrefix string
+}
+
+func (s *testSchedulePlanSuite) SetUpSuite(c *C) {
+	s.svr, s.cleanup = mustNewServer(c)
+	mustWaitLeader(c, []*server.Server{s.svr})
+	addr := s.svr.GetAddr()
+	s.urlPrefix = fmt.Sprintf("%s%s/api/v1/schedule/plan", addr, apiPrefix)
+	mustBootstrapCluster(c, s.svr)
+}
+
+func (s *testSchedulePlanSuite) TearDownSuite(c *C) {
+	s.cleanup()
+}
+
+// plannedOperator only contains the fields can be decoded, the operator is
+// encoded as a string.
+type plannedOperator struct {
+	Source    string                  `json:"source"`
+	Operator  string                  `json:"operator"`
+	Influence []server.StoreInfluence `json:"influence"`
+}
+
+func (s *testSchedulePlanSuite) TestPlan(c *C) {
+	for id := uint64(1); id <= 3; id++ {
+		mustPutStore(c, s.svr, id, metapb.StoreState_Up, nil)
+		_, err := s.svr.StoreHeartbeat(context.Background(), &pdpb.StoreHeartbeatRequest{
+			Header: &pdpb.RequestHeader{ClusterId: s.svr.ClusterID()},
+			Stats:  &pdpb.StoreStats{StoreId: id, Capacity: 1 << 30, Available: 1 << 30},
+		})
+		c.Assert(err, IsNil)
+	}
+	mustRegionHeartbeat(c, s.svr, newTestRegionInfo(2, 1, []byte("a"), []byte("b")))
+
+	var planned []*plannedOperator
+	c.Assert(readJSONWithURL(s.urlPrefix, &planned), IsNil)
+	c.Assert(len(planned), Greater, 0)
+
+	input := map[string]interface{}{"checkers": []string{"replica"}}
+	data, err := json.Marshal(input)
+	c.Assert(err, IsNil)
+	err = postJSON(s.urlPrefix, data, func(res []byte) bool {
+		c.Assert(json.Unmarshal(res, &planned), IsNil)
+		return true
+	})
+	c.Assert(err, IsNil)
+	c.Assert(len(planned), Greater, 0)
+	for _, p := range planned {
+		c.Assert(p.Source, Equals, "replica-checker")
+		c.Assert(p.Influence, HasLen, 1)
+		c.Assert(p.Influence[0].RegionCount, Equals, int64(1))
+	}
+	// The planned operators are not dispatched.
+	ops, err := s.svr.GetHandler().GetOperators()
+	c.Assert(err, IsNil)
+	c.Assert(ops, HasLen, 0)
+
+	input = map[string]interface{}{"checkers": []string{"unknown"}}
+	data, err = json.Marshal(input)
+	c.Assert(err, IsNil)
+	c.Assert(postJSON(s.urlPrefix, data), NotNil)
+}
@@ -34,7 +34,19 @@ func newSchedulerHandler(handler *server.Handler, r *render.Render) *schedulerHa
 }
 
 func (h *schedulerHandler) List(w http.ResponseWriter, r *http.Request) {
-	schedulers, err := h.GetSchedulers()
+	var (
+		schedulers []string
+		err        error
+	)
+	switch status := r.URL.Query().Get("status"); status {
+	case "":
+		schedulers, err = h.GetSchedulers()
+	case "paused":
+		schedulers, err = h.GetPausedSchedulers()
+	default:
+		h.r.JSON(w, http.StatusBadRequest, "unknown status")
+		return
+	}
 	if err != nil {
 		h.r.JSON(w, http.StatusInternalServerError, err.Error())
 		return
@@ -129,6 +141,25 @@ func (h *schedulerHandler) Post(w http.ResponseWriter, r *http.Request) {
 			h.r.JSON(w, http.StatusInternalServerError, err.Error())
 			return
 		}
+	case "evict-region-scheduler":
+		ids, ok := input["store_ids"].([]interface{})
+		if !ok || len(ids) == 0 {
+			h.r.JSON(w, http.StatusBadRequest, "missing store ids")
+			return
+		}
+		storeIDs := make([]uint64, 0, len(ids))
+		for _, id := range ids {
+			storeID, ok := id.(float64)
+			if !ok {
+				h.r.JSON(w, http.StatusBadRequest, "invalid store id")
+				return
+			}
+			storeIDs = append(storeIDs, uint64(storeID))
+		}
+		if err := h.AddEvictRegionScheduler(storeIDs...); err != nil {
+			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			return
+		}
 	case "shuffle-leader-scheduler":
 		if err := h.AddShuffleLeaderScheduler(); err != nil {
 			h.r.JSON(w, http.StatusInternalServerError, err.Error())
@@ -144,6 +175,11 @@ func (h *schedulerHandler) Post(w http.ResponseWriter, r *http.Request) {
 			h.r.JSON(w, http.StatusInternalServerError, err.Error())
 			return
 		}
+	case "split-hot-region-scheduler":
+		if err := h.AddSplitHotRegionScheduler(); err != nil {
+			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			return
+		}
 	case "shuffle-hot-region-scheduler":
 		limit := uint64(1)
 		l, ok := input["limit"].(float64)
@@ -172,3 +208,66 @@ func (h *schedulerHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	h.r.JSON(w, http.StatusOK, nil)
 }
+
+func (h *schedulerHandler) PauseOrResume(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+
+	var input map[string]interface{}
+	if err := readJSONRespondError(h.r, w, r.Body, &input); err != nil {
+		return
+	}
+
+	action, ok := input["action"].(string)
+	if !ok {
+		h.r.JSON(w, http.StatusBadRequest, "missing action")
+		return
+	}
+
+	var err error
+	switch action {
+	case "pause":
+		var seconds float64
+		if v, ok := input["seconds"]; ok {
+			if seconds, ok = v.(float64); !ok || seconds < 0 {
+				h.r.JSON(w, http.StatusBadRequest, "invalid seconds")
+				return
+			}
+		}
+		err = h.PauseScheduler(name, int64(seconds))
+	case "resume":
+		err = h.ResumeScheduler(name)
+	default:
+		h.r.JSON(w, http.StatusBadRequest, "unknown action")
+		return
+	}
+	if err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.r.JSON(w, http.StatusOK, nil)
+}
+
+func (h *schedulerHandler) GetProgress(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+
+	progress, err := h.GetSchedulerProgress(name)
+	if err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.r.JSON(w, http.StatusOK, progress)
+}
+
+func (h *schedulerHandler) GetDiagnosis(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+
+	diagnosis, err := h.GetSchedulerDiagnosis(name)
+	if err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.r.JSON(w, http.StatusOK, diagnosis)
+}
@@ -20,6 +20,7 @@ import (
 	. "github.com/pingcap/check"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/pd/server"
+	"github.com/pingcap/pd/server/schedule"
 	_ "github.com/pingcap/pd/server/schedulers"
 )
 
@@ -61,6 +62,7 @@ func (s *testScheduleSuite) TestAPI(c *C) {
 		{name: "balance-region-scheduler"},
 		{name: "shuffle-leader-scheduler"},
 		{name: "shuffle-region-scheduler"},
+		{name: "split-hot-region-scheduler"},
 		{
 			name:        "grant-leader-scheduler",
 			createdName: "grant-leader-scheduler-1",
@@ -71,6 +73,11 @@ func (s *testScheduleSuite) TestAPI(c *C) {
 			createdName: "evict-leader-scheduler-1",
 			args:        []arg{{"store_id", 1}},
 		},
+		{
+			name:        "evict-region-scheduler",
+			createdName: "evict-region-scheduler-1",
+			args:        []arg{{"store_ids", []uint64{1}}},
+		},
 	}
 	for _, ca := range cases {
 		input := make(map[string]interface{})
@@ -85,6 +92,76 @@ func (s *testScheduleSuite) TestAPI(c *C) {
 
 }
 
+func (s *testScheduleSuite) TestProgress(c *C) {
+	body := []byte(`{"name": "evict-region-scheduler", "store_ids": [1]}`)
+	c.Assert(postJSON(s.urlPrefix, body), IsNil)
+	// Store 2 does not exist.
+	c.Assert(postJSON(s.urlPrefix, []byte(`{"name": "evict-region-scheduler", "store_ids": [2]}`)), NotNil)
+	c.Assert(postJSON(s.urlPrefix, []byte(`{"name": "evict-region-scheduler"}`)), NotNil)
+
+	progress := &schedule.Progress{}
+	c.Assert(readJSONWithURL(s.urlPrefix+"/evict-region-scheduler-1/progress", progress), IsNil)
+	c.Assert(progress.RemainingRegions, Equals, 0)
+	c.Assert(doDelete(s.urlPrefix+"/evict-region-scheduler-1"), IsNil)
+
+	// The scheduler does not exist or does not report progress.
+	_, err := doGet(s.urlPrefix + "/evict-region-scheduler-1/progress")
+	c.Assert(err, NotNil)
+	c.Assert(postJSON(s.urlPrefix, []byte(`{"name": "shuffle-leader-scheduler"}`)), IsNil)
+	_, err = doGet(s.urlPrefix + "/shuffle-leader-scheduler/progress")
+	c.Assert(err, NotNil)
+	c.Assert(doDelete(s.urlPrefix+"/shuffle-leader-scheduler"), IsNil)
+}
+
+func (s *testScheduleSuite) TestDiagnosis(c *C) {
+	c.Assert(postJSON(s.urlPrefix, []byte(`{"name": "balance-region-scheduler"}`)), IsNil)
+	diagnosis := &server.SchedulerDiagnosis{}
+	c.Assert(readJSONWithURL(s.urlPrefix+"/balance-region-scheduler/diagnosis", diagnosis), IsNil)
+	c.Assert(diagnosis.Name, Equals, "balance-region-scheduler")
+	c.Assert(diagnosis.Paused, IsFalse)
+	c.Assert(diagnosis.Trace, NotNil)
+	c.Assert(doDelete(s.urlPrefix+"/balance-region-scheduler"), IsNil)
+
+	// The scheduler does not trace its decisions.
+	c.Assert(postJSON(s.urlPrefix, []byte(`{"name": "shuffle-leader-scheduler"}`)), IsNil)
+	diagnosis = &server.SchedulerDiagnosis{}
+	c.Assert(readJSONWithURL(s.urlPrefix+"/shuffle-leader-scheduler/diagnosis", diagnosis), IsNil)
+	c.Assert(diagnosis.Name, Equals, "shuffle-leader-scheduler")
+	c.Assert(diagnosis.Trace, IsNil)
+	c.Assert(doDelete(s.urlPrefix+"/shuffle-leader-scheduler"), IsNil)
+
+	// The scheduler does not exist.
+	_, err := doGet(s.urlPrefix + "/shuffle-leader-scheduler/diagnosis")
+	c.Assert(err, NotNil)
+}
+
+func (s *testScheduleSuite) TestPauseResume(c *C) {
+	c.Assert(postJSON(s.urlPrefix, []byte(`{"name": "shuffle-leader-scheduler"}`)), IsNil)
+	defer doDelete(s.urlPrefix + "/shuffle-leader-scheduler")
+
+	pauseURL := s.urlPrefix + "/shuffle-leader-scheduler"
+	c.Assert(postJSON(pauseURL, []byte(`{"action": "pause", "seconds": 60}`)), IsNil)
+	var paused []string
+	c.Assert(readJSONWithURL(s.urlPrefix+"?status=paused", &paused), IsNil)
+	c.Assert(paused, DeepEquals, []string{"shuffle-leader-scheduler"})
+	for _, cfg := range s.svr.GetScheduleConfig().Schedulers {
+		if cfg.Type == "shuffle-leader" {
+			c.Assert(cfg.Paused, IsTrue)
+			c.Assert(cfg.PauseExpireTime, Greater, int64(0))
+		}
+	}
+
+	c.Assert(postJSON(pauseURL, []byte(`{"action": "resume"}`)), IsNil)
+	c.Assert(readJSONWithURL(s.urlPrefix+"?status=paused", &paused), IsNil)
+	c.Assert(paused, HasLen, 0)
+
+	c.Assert(postJSON(pauseURL, []byte(`{"action": "stop"}`)), NotNil)
+	c.Assert(postJSON(pauseURL, []byte(`{"action": "pause", "seconds": -1}`)), NotNil)
+	c.Assert(postJSON(s.urlPrefix+"/not-found-scheduler", []byte(`{"action": "pause"}`)), NotNil)
+	_, err := doGet(s.urlPrefix + "?status=unknown")
+	c.Assert(err, NotNil)
+}
+
 func (s *testScheduleSuite) testAddAndRemoveScheduler(name, createdName string, body []byte, c *C) {
 	if createdName == "" {
 		createdName = name
@@ -15,8 +15,11 @@ package api
 
 import (
 	"net/http"
+	"strconv"
 
+	"github.com/gorilla/mux"
 	"github.com/pingcap/pd/server"
+	"github.com/pingcap/pd/server/statistics"
 	"github.com/unrolled/render"
 )
 
@@ -42,3 +45,51 @@ func (h *statsHandler) Region(w http.ResponseWriter, r *http.Request) {
 	stats := cluster.GetRegionStats([]byte(startKey), []byte(endKey))
 	h.rd.JSON(w, http.StatusOK, stats)
 }
+
+// Table returns the statistics of the regions that intersect the table.
+func (h *statsHandler) Table(w http.ResponseWriter, r *http.Request) {
+	cluster := h.svr.GetRaftCluster()
+	if cluster == nil {
+		h.rd.JSON(w, http.StatusInternalServerError, server.ErrNotBootstrapped.Error())
+		return
+	}
+	tableID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, cluster.GetTableStats(tableID))
+}
+
+const defaultTablesStatsSortKey = "size"
+
+// Tables returns the statistics of the tables sorted by sort_by in descending
+// order, at most limit tables are returned if limit is set.
+func (h *statsHandler) Tables(w http.ResponseWriter, r *http.Request) {
+	cluster := h.svr.GetRaftCluster()
+	if cluster == nil {
+		h.rd.JSON(w, http.StatusInternalServerError, server.ErrNotBootstrapped.Error())
+		return
+	}
+	sortKey := r.URL.Query().Get("sort_by")
+	if sortKey == "" {
+		sortKey = defaultTablesStatsSortKey
+	}
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		var err error
+		if limit, err = strconv.Atoi(v); err != nil {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	stats := cluster.GetTablesStats()
+	if err := statistics.SortTableStats(stats, sortKey); err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if limit > 0 && len(stats) > limit {
+		stats = stats[:limit]
+	}
+	h.rd.JSON(w, http.StatusOK, stats)
+}
@@ -176,3 +176,27 @@ func (s *testStatsSuite) TestRegionStats(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(stats, DeepEquals, stats23)
 }
+
+func (s *testStatsSuite) TestTableStats(c *C) {
+	// The regions in TestRegionStats are not split by the table keys, so no
+	// table is listed and table 1 is inside region 3 ["t", "x").
+	var tables []*statistics.TableStats
+	err := readJSONWithURL(s.urlPrefix+"/stats/tables?sort_by=written_bytes&limit=10", &tables)
+	c.Assert(err, IsNil)
+	c.Assert(tables, HasLen, 0)
+
+	stats := &statistics.TableStats{}
+	err = readJSONWithURL(s.urlPrefix+"/stats/table/1", stats)
+	c.Assert(err, IsNil)
+	c.Assert(stats.TableID, Equals, int64(1))
+	c.Assert(stats.Count, Equals, 1)
+	c.Assert(stats.StorageSize, Equals, int64(1))
+	c.Assert(stats.StoreLeaderCount, DeepEquals, map[uint64]int{5: 1})
+
+	for _, path := range []string{"/stats/tables?sort_by=unknown", "/stats/tables?limit=a", "/stats/table/a"} {
+		res, err := http.Get(s.urlPrefix + path)
+		c.Assert(err, IsNil)
+		c.Assert(res.StatusCode, Equals, http.StatusBadRequest)
+		res.Body.Close()
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/pingcap/pd/server/config"
 	"github.com/pingcap/pd/server/core"
 	"github.com/pingcap/pd/server/schedule"
+	"github.com/pingcap/pd/server/schedule/storelimit"
 	"github.com/pkg/errors"
 	"github.com/unrolled/render"
 )
@@ -57,6 +58,8 @@ type StoreStatus struct {
 	StartTS            *time.Time         `json:"start_ts,omitempty"`
 	LastHeartbeatTS    *time.Time         `json:"last_heartbeat_ts,omitempty"`
 	Uptime             *typeutil.Duration `json:"uptime,omitempty"`
+	// Maintenance is set when the store is under maintenance.
+	Maintenance *core.StoreMaintenance `json:"maintenance,omitempty"`
 }
 
 // StoreInfo contains information about a store.
@@ -105,6 +108,9 @@ func newStoreInfo(opt *config.ScheduleConfig, store *core.StoreInfo) *StoreInfo
 		duration := typeutil.NewDuration(upTime)
 		s.Status.Uptime = &duration
 	}
+	if store.IsInMaintenance() {
+		s.Status.Maintenance = store.GetMaintenance()
+	}
 
 	if store.GetState() == metapb.StoreState_Up {
 		if store.DownTime() > opt.MaxStoreDownTime.Duration {
@@ -306,6 +312,73 @@ func (h *storeHandler) SetWeight(w http.ResponseWriter, r *http.Request) {
 	h.rd.JSON(w, http.StatusOK, nil)
 }
 
+func (h *storeHandler) SetMaintenance(w http.ResponseWriter, r *http.Request) {
+	cluster := h.GetRaftCluster()
+	if cluster == nil {
+		h.rd.JSON(w, http.StatusInternalServerError, server.ErrNotBootstrapped.Error())
+		return
+	}
+
+	vars := mux.Vars(r)
+	storeID, errParse := apiutil.ParseUint64VarsField(vars, "id")
+	if errParse != nil {
+		errorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+
+	var input map[string]interface{}
+	if err := readJSONRespondError(h.rd, w, r.Body, &input); err != nil {
+		return
+	}
+
+	ttlVal, ok := input["ttl"]
+	if !ok {
+		h.rd.JSON(w, http.StatusBadRequest, "ttl unset")
+		return
+	}
+	ttl, ok := ttlVal.(float64)
+	if !ok || ttl <= 0 {
+		h.rd.JSON(w, http.StatusBadRequest, "badformat ttl")
+		return
+	}
+	evictLeader := false
+	if evictLeaderVal, ok := input["evict_leader"]; ok {
+		if evictLeader, ok = evictLeaderVal.(bool); !ok {
+			h.rd.JSON(w, http.StatusBadRequest, "badformat evict_leader")
+			return
+		}
+	}
+
+	if err := cluster.SetStoreMaintenance(storeID, time.Duration(ttl*float64(time.Second)), evictLeader); err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.rd.JSON(w, http.StatusOK, nil)
+}
+
+func (h *storeHandler) RemoveMaintenance(w http.ResponseWriter, r *http.Request) {
+	cluster := h.GetRaftCluster()
+	if cluster == nil {
+		h.rd.JSON(w, http.StatusInternalServerError, server.ErrNotBootstrapped.Error())
+		return
+	}
+
+	vars := mux.Vars(r)
+	storeID, errParse := apiutil.ParseUint64VarsField(vars, "id")
+	if errParse != nil {
+		errorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+
+	if err := cluster.RemoveStoreMaintenance(storeID); err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.rd.JSON(w, http.StatusOK, nil)
+}
+
 func (h *storeHandler) SetLimit(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	storeID, errParse := apiutil.ParseUint64VarsField(vars, "id")
@@ -330,14 +403,40 @@ func (h *storeHandler) SetLimit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.SetStoreLimit(storeID, rate/schedule.StoreBalanceBaseTime); err != nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+	limitTypes, err := parseStoreLimitTypes(input)
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
+	for _, limitType := range limitTypes {
+		if err := h.SetStoreLimit(storeID, rate/schedule.StoreBalanceBaseTime, limitType); err != nil {
+			h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
 	h.rd.JSON(w, http.StatusOK, nil)
 }
 
+// parseStoreLimitTypes returns the store limit type in the input, all the
+// types are returned if the type is unset.
+func parseStoreLimitTypes(input map[string]interface{}) ([]storelimit.Type, error) {
+	typeVal, ok := input["type"]
+	if !ok {
+		return storelimit.Types, nil
+	}
+	typeName, ok := typeVal.(string)
+	if !ok {
+		return nil, errors.New("badformat type")
+	}
+	limitType, err := storelimit.ParseType(typeName)
+	if err != nil {
+		return nil, err
+	}
+	return []storelimit.Type{limitType}, nil
+}
+
 type storesHandler struct {
 	*server.Handler
 	rd *render.Render
@@ -383,25 +482,58 @@ func (h *storesHandler) SetAllLimit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.SetAllStoresLimit(rate / schedule.StoreBalanceBaseTime); err != nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+	limitTypes, err := parseStoreLimitTypes(input)
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
+	for _, limitType := range limitTypes {
+		if err := h.SetAllStoresLimit(rate/schedule.StoreBalanceBaseTime, limitType); err != nil {
+			h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
 	h.rd.JSON(w, http.StatusOK, nil)
 }
 
-func (h *storesHandler) GetAllLimit(w http.ResponseWriter, r *http.Request) {
-	limit, err := h.GetAllStoresLimit()
+// GetForecast returns the projected days until the stores and the cluster
+// reach the low space ratio.
+func (h *storesHandler) GetForecast(w http.ResponseWriter, r *http.Request) {
+	forecast, err := h.GetCapacityForecast()
 	if err != nil {
 		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	ret := make(map[uint64]interface{})
-	for s, l := range limit {
-		ret[s] = struct {
-			Rate float64 `json:"rate"`
-		}{Rate: l * schedule.StoreBalanceBaseTime}
+	h.rd.JSON(w, http.StatusOK, forecast)
+}
+
+func (h *storesHandler) GetAllLimit(w http.ResponseWriter, r *http.Request) {
+	limitTypes := storelimit.Types
+	if typeName := r.URL.Query().Get("type"); typeName != "" {
+		limitType, err := storelimit.ParseType(typeName)
+		if err != nil {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		limitTypes = []storelimit.Type{limitType}
+	}
+
+	// The rates of each store are keyed by the limit types.
+	ret := make(map[uint64]map[string]float64)
+	for _, limitType := range limitTypes {
+		limit, err := h.GetAllStoresLimit(limitType)
+		if err != nil {
+			h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		for s, l := range limit {
+			if ret[s] == nil {
+				ret[s] = make(map[string]float64)
+			}
+			ret[s][limitType.String()] = l * schedule.StoreBalanceBaseTime
+		}
 	}
 
 	h.rd.JSON(w, http.StatusOK, ret)
@@ -14,6 +14,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -27,6 +28,8 @@ import (
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/pingcap/pd/server"
 	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/schedule"
+	"github.com/pingcap/pd/server/statistics"
 )
 
 var _ = Suite(&testStoreSuite{})
@@ -251,6 +254,81 @@ func (s *testStoreSuite) TestStoreSetState(c *C) {
 	c.Assert(info.Store.State, Equals, metapb.StoreState_Up)
 }
 
+func (s *testStoreSuite) TestStoreLimit(c *C) {
+	url := fmt.Sprintf("%s/store/1/limit", s.urlPrefix)
+	c.Assert(postJSON(url, []byte(`{"rate": 12, "type": "add-peer"}`)), IsNil)
+	c.Assert(postJSON(url, []byte(`{"rate": 6, "type": "remove-peer"}`)), IsNil)
+	c.Assert(postJSON(url, []byte(`{"rate": 6, "type": "foo"}`)), NotNil)
+
+	allURL := fmt.Sprintf("%s/stores/limit", s.urlPrefix)
+	limits := make(map[uint64]map[string]float64)
+	c.Assert(readJSONWithURL(allURL, &limits), IsNil)
+	c.Assert(limits[1], DeepEquals, map[string]float64{"add-peer": 12, "remove-peer": 6})
+
+	// Sets the limit of all stores except the tombstone ones.
+	c.Assert(postJSON(allURL, []byte(`{"rate": 30, "type": "remove-peer"}`)), IsNil)
+	limits = make(map[uint64]map[string]float64)
+	c.Assert(readJSONWithURL(allURL+"?type=remove-peer", &limits), IsNil)
+	c.Assert(limits, Not(HasLen), 0)
+	for _, limit := range limits {
+		c.Assert(limit, DeepEquals, map[string]float64{"remove-peer": 30})
+	}
+	c.Assert(limits, Not(HasKey), uint64(7))
+	_, err := doGet(allURL + "?type=foo")
+	c.Assert(err, NotNil)
+
+	// The limits are saved to storage.
+	saved := make(map[string]float64)
+	err = s.svr.GetStorage().LoadStoreLimits(func(storeID uint64, limitType string, rate float64) {
+		if storeID == 1 {
+			saved[limitType] = rate
+		}
+	})
+	c.Assert(err, IsNil)
+	c.Assert(saved, DeepEquals, map[string]float64{"add-peer": 12 / schedule.StoreBalanceBaseTime, "remove-peer": 30 / schedule.StoreBalanceBaseTime})
+}
+
+func (s *testStoreSuite) TestStoreMaintenance(c *C) {
+	url := fmt.Sprintf("%s/store/4/maintenance", s.urlPrefix)
+	c.Assert(postJSON(url, []byte(`{"evict_leader": true}`)), NotNil)
+	c.Assert(postJSON(url, []byte(`{"ttl": 0}`)), NotNil)
+	c.Assert(postJSON(url, []byte(`{"ttl": 600, "evict_leader": "true"}`)), NotNil)
+	c.Assert(postJSON(fmt.Sprintf("%s/store/7/maintenance", s.urlPrefix), []byte(`{"ttl": 600}`)), NotNil)
+	c.Assert(postJSON(url, []byte(`{"ttl": 600, "evict_leader": true}`)), IsNil)
+
+	info := new(StoreInfo)
+	c.Assert(readJSONWithURL(fmt.Sprintf("%s/store/4", s.urlPrefix), info), IsNil)
+	c.Assert(info.Status.Maintenance, NotNil)
+	c.Assert(info.Status.Maintenance.EvictLeader, IsTrue)
+	c.Assert(info.Status.Maintenance.Expire.After(time.Now().Add(9*time.Minute)), IsTrue)
+
+	c.Assert(doDelete(url), IsNil)
+	info = new(StoreInfo)
+	c.Assert(readJSONWithURL(fmt.Sprintf("%s/store/4", s.urlPrefix), info), IsNil)
+	c.Assert(info.Status.Maintenance, IsNil)
+}
+
+func (s *testStoreSuite) TestStoresForecast(c *C) {
+	req := &pdpb.StoreHeartbeatRequest{
+		Header: &pdpb.RequestHeader{ClusterId: s.svr.ClusterID()},
+		Stats:  &pdpb.StoreStats{StoreId: 1, Capacity: 100, Available: 50, UsedSize: 40},
+	}
+	_, err := s.svr.StoreHeartbeat(context.Background(), req)
+	c.Assert(err, IsNil)
+
+	forecast := &statistics.CapacityForecasts{}
+	err = readJSONWithURL(fmt.Sprintf("%s/stores/forecast", s.urlPrefix), forecast)
+	c.Assert(err, IsNil)
+	c.Assert(forecast.Stores, HasLen, 1)
+	c.Assert(forecast.Stores[0].StoreID, Equals, uint64(1))
+	c.Assert(forecast.Stores[0].Available, Equals, uint64(50))
+	c.Assert(forecast.Stores[0].Samples, Equals, 1)
+	// One sample is not enough to fit the trend.
+	c.Assert(forecast.Stores[0].DaysToLowSpace, IsNil)
+	c.Assert(forecast.Cluster.Capacity, Equals, uint64(100))
+	c.Assert(forecast.Cluster.DaysToLowSpace, IsNil)
+}
+
 func (s *testStoreSuite) TestUrlStoreFilter(c *C) {
 	table := []struct {
 		u    string
@@ -0,0 +1,44 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/pingcap/pd/server"
+	"github.com/unrolled/render"
+)
+
+type topologyHandler struct {
+	svr *server.Server
+	rd  *render.Render
+}
+
+func newTopologyHandler(svr *server.Server, rd *render.Render) *topologyHandler {
+	return &topologyHandler{
+		svr: svr,
+		rd:  rd,
+	}
+}
+
+// Get returns the tree of the stores grouped by the location labels, with the
+// aggregated stats and the isolation risk of each location.
+func (h *topologyHandler) Get(w http.ResponseWriter, r *http.Request) {
+	cluster := h.svr.GetRaftCluster()
+	if cluster == nil {
+		h.rd.JSON(w, http.StatusInternalServerError, server.ErrNotBootstrapped.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, cluster.GetTopology())
+}
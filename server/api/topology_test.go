@@ -0,0 +1,88 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/server"
+	"github.com/pingcap/pd/server/config"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/statistics"
+)
+
+var _ = Suite(&testTopologySuite{})
+
+type testTopologySuite struct {
+	svr       *server.Server
+	cleanup   cleanUpFunc
+	urlPrefix string
+}
+
+func (s *testTopologySuite) SetUpSuite(c *C) {
+	s.svr, s.cleanup = mustNewServer(c, func(cfg *config.Config) {
+		cfg.Replication.LocationLabels = []string{"zone", "host"}
+	})
+	mustWaitLeader(c, []*server.Server{s.svr})
+
+	addr := s.svr.GetAddr()
+	s.urlPrefix = fmt.Sprintf("%s%s/api/v1", addr, apiPrefix)
+
+	mustBootstrapCluster(c, s.svr)
+}
+
+func (s *testTopologySuite) TearDownSuite(c *C) {
+	s.cleanup()
+}
+
+func (s *testTopologySuite) TestTopology(c *C) {
+	labels := []struct {
+		zone, host string
+	}{{"z1", "h1"}, {"z1", "h2"}, {"z2", "h3"}, {"z3", "h4"}}
+	for i, l := range labels {
+		mustPutStore(c, s.svr, uint64(i+1), metapb.StoreState_Up, []*metapb.StoreLabel{
+			{Key: "zone", Value: l.zone},
+			{Key: "host", Value: l.host},
+		})
+	}
+	// The region has 2 replicas in zone z1.
+	peers := []*metapb.Peer{{Id: 11, StoreId: 1}, {Id: 12, StoreId: 2}, {Id: 13, StoreId: 3}}
+	region := core.NewRegionInfo(&metapb.Region{
+		Id:          10,
+		StartKey:    []byte("a"),
+		EndKey:      []byte("b"),
+		Peers:       peers,
+		RegionEpoch: &metapb.RegionEpoch{ConfVer: 1, Version: 1},
+	}, peers[0])
+	mustRegionHeartbeat(c, s.svr, region)
+
+	topo := &statistics.Topology{}
+	c.Assert(readJSONWithURL(fmt.Sprintf("%s/topology", s.urlPrefix), topo), IsNil)
+	c.Assert(topo.LocationLabels, DeepEquals, []string{"zone", "host"})
+	c.Assert(topo.MaxReplicas, Equals, 3)
+	c.Assert(topo.Root.StoreCount, Equals, 4)
+	c.Assert(topo.Root.Children, HasLen, 3)
+	z1 := topo.Root.Children[0]
+	c.Assert(z1.Value, Equals, "z1")
+	c.Assert(z1.StoreCount, Equals, 2)
+	c.Assert(z1.RegionCount, Equals, 2)
+	c.Assert(z1.LeaderCount, Equals, 1)
+	c.Assert(z1.IsolationAtRisk, IsTrue)
+	c.Assert(z1.IsolationRiskRegions, Equals, 1)
+	c.Assert(z1.Children, HasLen, 2)
+	c.Assert(z1.Children[0].Children[0].StoreID, Equals, uint64(1))
+	c.Assert(topo.Root.Children[1].IsolationAtRisk, IsFalse)
+}
@@ -15,17 +15,32 @@ package checker
 
 import (
 	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/schedule"
 	"github.com/pingcap/pd/server/schedule/operator"
 )
 
 // LearnerChecker ensures region has a learner will be promoted.
-type LearnerChecker struct{}
+type LearnerChecker struct {
+	cluster schedule.Cluster
+}
 
 // NewLearnerChecker creates a learner checker.
-func NewLearnerChecker() *LearnerChecker { return &LearnerChecker{} }
+func NewLearnerChecker(cluster schedule.Cluster) *LearnerChecker {
+	return &LearnerChecker{cluster: cluster}
+}
+
+// DryRun returns a copy of the checker running on the dry run cluster.
+func (l *LearnerChecker) DryRun(cluster *schedule.DryRunCluster) *LearnerChecker {
+	return &LearnerChecker{cluster: cluster}
+}
 
 // Check verifies a region's namespace, creating an Operator if need.
 func (l *LearnerChecker) Check(region *core.RegionInfo) *operator.Operator {
+	// Only promote the learners beyond the placement rule when the region lacks voters.
+	rule := l.cluster.GetRegionRule(region)
+	if len(region.GetLearners()) <= rule.Learners || len(region.GetVoters()) >= rule.Voters {
+		return nil
+	}
 	for _, p := range region.GetLearners() {
 		if region.GetPendingLearner(p.GetId()) != nil {
 			continue
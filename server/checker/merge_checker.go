@@ -20,6 +20,7 @@ import (
 	"github.com/pingcap/pd/pkg/cache"
 	"github.com/pingcap/pd/server/core"
 	"github.com/pingcap/pd/server/namespace"
+	"github.com/pingcap/pd/server/placement/rule"
 	"github.com/pingcap/pd/server/schedule"
 	"github.com/pingcap/pd/server/schedule/operator"
 	"go.uber.org/zap"
@@ -53,65 +54,76 @@ func (m *MergeChecker) RecordRegionSplit(regionID uint64) {
 	m.splitCache.PutWithTTL(regionID, nil, m.cluster.GetSplitMergeInterval())
 }
 
+// DryRun returns a copy of the checker running on the dry run cluster. The
+// copy shares the recently split regions with the checker.
+func (m *MergeChecker) DryRun(cluster *schedule.DryRunCluster) *MergeChecker {
+	return &MergeChecker{
+		cluster:    cluster,
+		classifier: m.classifier,
+		splitCache: m.splitCache,
+	}
+}
+
 // Check verifies a region's replicas, creating an Operator if need.
 func (m *MergeChecker) Check(region *core.RegionInfo) []*operator.Operator {
 	if m.splitCache.Exists(mergeBlockMarker) {
-		checkerCounter.WithLabelValues("merge_checker", "recently_start").Inc()
+		schedule.Counter(m.cluster, checkerCounter).WithLabelValues("merge_checker", "recently_start").Inc()
 		return nil
 	}
 
 	if m.splitCache.Exists(region.GetID()) {
-		checkerCounter.WithLabelValues("merge_checker", "recently_split").Inc()
+		schedule.Counter(m.cluster, checkerCounter).WithLabelValues("merge_checker", "recently_split").Inc()
 		return nil
 	}
 
-	checkerCounter.WithLabelValues("merge_checker", "check").Inc()
+	schedule.Counter(m.cluster, checkerCounter).WithLabelValues("merge_checker", "check").Inc()
 
 	// when pd just started, it will load region meta from etcd
 	// but the size for these loaded region info is 0
 	// pd don't know the real size of one region until the first heartbeat of the region
 	// thus here when size is 0, just skip.
 	if region.GetApproximateSize() == 0 {
-		checkerCounter.WithLabelValues("merge_checker", "skip").Inc()
+		schedule.Counter(m.cluster, checkerCounter).WithLabelValues("merge_checker", "skip").Inc()
 		return nil
 	}
 
 	// region is not small enough
 	if region.GetApproximateSize() > int64(m.cluster.GetMaxMergeRegionSize()) ||
 		region.GetApproximateKeys() > int64(m.cluster.GetMaxMergeRegionKeys()) {
-		checkerCounter.WithLabelValues("merge_checker", "no_need").Inc()
+		schedule.Counter(m.cluster, checkerCounter).WithLabelValues("merge_checker", "no_need").Inc()
 		return nil
 	}
 
 	// skip region has down peers or pending peers or learner peers
 	if len(region.GetDownPeers()) > 0 || len(region.GetPendingPeers()) > 0 || len(region.GetLearners()) > 0 {
-		checkerCounter.WithLabelValues("merge_checker", "special_peer").Inc()
+		schedule.Counter(m.cluster, checkerCounter).WithLabelValues("merge_checker", "special_peer").Inc()
 		return nil
 	}
 
-	if len(region.GetPeers()) != m.cluster.GetMaxReplicas() {
-		checkerCounter.WithLabelValues("merge_checker", "abnormal_replica").Inc()
+	rule := m.cluster.GetRegionRule(region)
+	if len(region.GetPeers()) != rule.GetReplicas() {
+		schedule.Counter(m.cluster, checkerCounter).WithLabelValues("merge_checker", "abnormal_replica").Inc()
 		return nil
 	}
 
 	// skip hot region
 	if m.cluster.IsRegionHot(region) {
-		checkerCounter.WithLabelValues("merge_checker", "hot_region").Inc()
+		schedule.Counter(m.cluster, checkerCounter).WithLabelValues("merge_checker", "hot_region").Inc()
 		return nil
 	}
 
 	prev, next := m.cluster.GetAdjacentRegions(region)
 
 	var target *core.RegionInfo
-	targetNext := m.checkTarget(region, next, target)
-	target = m.checkTarget(region, prev, target)
+	targetNext := m.checkTarget(region, next, target, rule)
+	target = m.checkTarget(region, prev, target, rule)
 	if target != targetNext && m.cluster.GetEnableOneWayMerge() {
-		checkerCounter.WithLabelValues("merge_checker", "skip_left").Inc()
+		schedule.Counter(m.cluster, checkerCounter).WithLabelValues("merge_checker", "skip_left").Inc()
 		target = targetNext
 	}
 
 	if target == nil {
-		checkerCounter.WithLabelValues("merge_checker", "no_target").Inc()
+		schedule.Counter(m.cluster, checkerCounter).WithLabelValues("merge_checker", "no_target").Inc()
 		return nil
 	}
 
@@ -120,23 +132,23 @@ func (m *MergeChecker) Check(region *core.RegionInfo) []*operator.Operator {
 	if err != nil {
 		return nil
 	}
-	checkerCounter.WithLabelValues("merge_checker", "new_operator").Inc()
+	schedule.Counter(m.cluster, checkerCounter).WithLabelValues("merge_checker", "new_operator").Inc()
 	if region.GetApproximateSize() > target.GetApproximateSize() ||
 		region.GetApproximateKeys() > target.GetApproximateKeys() {
-		checkerCounter.WithLabelValues("merge_checker", "larger_source").Inc()
+		schedule.Counter(m.cluster, checkerCounter).WithLabelValues("merge_checker", "larger_source").Inc()
 	}
 	return ops
 }
 
-func (m *MergeChecker) checkTarget(region, adjacent, target *core.RegionInfo) *core.RegionInfo {
+func (m *MergeChecker) checkTarget(region, adjacent, target *core.RegionInfo, rule *rule.Rule) *core.RegionInfo {
 	// if is not hot region and under same namespace
 	if adjacent != nil && !m.cluster.IsRegionHot(adjacent) &&
 		m.classifier.AllowMerge(region, adjacent) &&
 		len(adjacent.GetDownPeers()) == 0 && len(adjacent.GetPendingPeers()) == 0 && len(adjacent.GetLearners()) == 0 {
 		// if both region is not hot, prefer the one with smaller size
 		if target == nil || target.GetApproximateSize() > adjacent.GetApproximateSize() {
-			// peer count should equal
-			if len(adjacent.GetPeers()) == m.cluster.GetMaxReplicas() {
+			// the regions should follow the same rule and have the same peer count.
+			if adjacentRule := m.cluster.GetRegionRule(adjacent); adjacentRule.ID == rule.ID && len(adjacent.GetPeers()) == rule.GetReplicas() {
 				target = adjacent
 			}
 		}
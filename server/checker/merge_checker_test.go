@@ -24,6 +24,7 @@ import (
 	"github.com/pingcap/pd/pkg/mock/mockoption"
 	"github.com/pingcap/pd/server/core"
 	"github.com/pingcap/pd/server/namespace"
+	"github.com/pingcap/pd/server/placement/rule"
 	"github.com/pingcap/pd/server/schedule"
 	"github.com/pingcap/pd/server/schedule/operator"
 )
@@ -124,10 +125,10 @@ func (s *testMergeCheckerSuite) TestBasic(c *C) {
 	ops = s.mc.Check(s.regions[2])
 	c.Assert(ops, NotNil)
 	for _, op := range ops {
-		op.SetStartTime(time.Now())
-		op.SetStartTime(op.GetStartTime().Add(-operator.LeaderOperatorWaitTime - time.Second))
+		timeout := op.CurrentStep().Timeout(op.GetApproximateSize())
+		op.SetStartTime(time.Now().Add(-timeout + time.Second))
 		c.Assert(op.IsTimeout(), IsFalse)
-		op.SetStartTime(op.GetStartTime().Add(-operator.RegionOperatorWaitTime - time.Second))
+		op.SetStartTime(time.Now().Add(-timeout - time.Second))
 		c.Assert(op.IsTimeout(), IsTrue)
 	}
 
@@ -145,6 +146,29 @@ func (s *testMergeCheckerSuite) TestBasic(c *C) {
 	c.Assert(ops, IsNil)
 }
 
+func (s *testMergeCheckerSuite) TestRule(c *C) {
+	// The regions in [a, t) follow a rule with 3 voters, region 3 in [t, x)
+	// follows the default rule, so they are not merged.
+	c.Assert(s.cluster.RuleManager.SetRule(&rule.Rule{ID: "r1", StartKeyHex: "61", EndKeyHex: "74", Voters: 3}), IsNil)
+	c.Assert(s.mc.Check(s.regions[2]), IsNil)
+
+	// The regions with 2 peers are complete under a rule with 2 voters.
+	c.Assert(s.cluster.RuleManager.SetRule(&rule.Rule{ID: "r1", StartKeyHex: "", EndKeyHex: "74", Voters: 2}), IsNil)
+	region2 := s.regions[1].Clone(
+		core.WithRemoveStorePeer(4),
+		core.WithRemoveStorePeer(5),
+		core.WithAddPeer(&metapb.Peer{Id: 110, StoreId: 2}),
+		core.WithLeader(&metapb.Peer{Id: 103, StoreId: 1}),
+		core.SetApproximateSize(1),
+		core.SetApproximateKeys(1),
+	)
+	s.cluster.PutRegion(region2)
+	ops := s.mc.Check(region2)
+	c.Assert(ops, HasLen, 2)
+	c.Assert(ops[0].RegionID(), Equals, uint64(2))
+	c.Assert(ops[1].RegionID(), Equals, uint64(1))
+}
+
 func (s *testMergeCheckerSuite) checkSteps(c *C, op *operator.Operator, steps []operator.OpStep) {
 	c.Assert(op.Kind()&operator.OpMerge, Not(Equals), 0)
 	c.Assert(steps, NotNil)
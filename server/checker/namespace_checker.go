@@ -45,24 +45,33 @@ func NewNamespaceChecker(cluster schedule.Cluster, classifier namespace.Classifi
 	}
 }
 
+// DryRun returns a copy of the checker running on the dry run cluster.
+func (n *NamespaceChecker) DryRun(cluster *schedule.DryRunCluster) *NamespaceChecker {
+	return &NamespaceChecker{
+		cluster:    cluster,
+		filters:    n.filters,
+		classifier: n.classifier,
+	}
+}
+
 // Check verifies a region's namespace, creating an Operator if need.
 func (n *NamespaceChecker) Check(region *core.RegionInfo) *operator.Operator {
 	if !n.cluster.IsNamespaceRelocationEnabled() {
 		return nil
 	}
 
-	checkerCounter.WithLabelValues("namespace_checker", "check").Inc()
+	schedule.Counter(n.cluster, checkerCounter).WithLabelValues("namespace_checker", "check").Inc()
 
 	// fail-fast if there is only ONE namespace
 	if n.classifier == nil || len(n.classifier.GetAllNamespaces()) == 1 {
-		checkerCounter.WithLabelValues("namespace_checker", "no_namespace").Inc()
+		schedule.Counter(n.cluster, checkerCounter).WithLabelValues("namespace_checker", "no_namespace").Inc()
 		return nil
 	}
 
 	// get all the stores belong to the namespace
 	targetStores := n.getNamespaceStores(region)
 	if len(targetStores) == 0 {
-		checkerCounter.WithLabelValues("namespace_checker", "no_target_store").Inc()
+		schedule.Counter(n.cluster, checkerCounter).WithLabelValues("namespace_checker", "no_target_store").Inc()
 		return nil
 	}
 	for _, peer := range region.GetPeers() {
@@ -73,19 +82,19 @@ func (n *NamespaceChecker) Check(region *core.RegionInfo) *operator.Operator {
 		log.Debug("peer is not located in namespace target stores", zap.Uint64("region-id", region.GetID()), zap.Reflect("peer", peer))
 		newPeer := n.SelectBestPeerToRelocate(region, targetStores)
 		if newPeer == nil {
-			checkerCounter.WithLabelValues("namespace_checker", "no_target_peer").Inc()
+			schedule.Counter(n.cluster, checkerCounter).WithLabelValues("namespace_checker", "no_target_peer").Inc()
 			return nil
 		}
 		op, err := operator.CreateMovePeerOperator("make-namespace-relocation", n.cluster, region, operator.OpReplica, peer.GetStoreId(), newPeer.GetStoreId(), newPeer.GetId())
 		if err != nil {
-			checkerCounter.WithLabelValues("namespace_checker", "create_operator_fail").Inc()
+			schedule.Counter(n.cluster, checkerCounter).WithLabelValues("namespace_checker", "create_operator_fail").Inc()
 			return nil
 		}
-		checkerCounter.WithLabelValues("namespace_checker", "new_operator").Inc()
+		schedule.Counter(n.cluster, checkerCounter).WithLabelValues("namespace_checker", "new_operator").Inc()
 		return op
 	}
 
-	checkerCounter.WithLabelValues("namespace_checker", "all_right").Inc()
+	schedule.Counter(n.cluster, checkerCounter).WithLabelValues("namespace_checker", "all_right").Inc()
 	return nil
 }
 
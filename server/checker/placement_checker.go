@@ -0,0 +1,245 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"sync/atomic"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/log"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/namespace"
+	"github.com/pingcap/pd/server/placement"
+	"github.com/pingcap/pd/server/schedule"
+	"github.com/pingcap/pd/server/schedule/filter"
+	"github.com/pingcap/pd/server/schedule/operator"
+	"go.uber.org/zap"
+)
+
+// PlacementChecker ensures region's replicas satisfy the user-defined
+// placement constraints. It only handles healthy regions, the down, offline
+// and pending replicas are left to the ReplicaChecker.
+type PlacementChecker struct {
+	cluster    schedule.Cluster
+	classifier namespace.Classifier
+	filters    []filter.Filter
+	config     atomic.Value // Store as *placement.Config
+	// opController provides the stores which failed to add a peer in time,
+	// nil means no store is avoided.
+	opController *schedule.OperatorController
+}
+
+// NewPlacementChecker creates a placement checker.
+func NewPlacementChecker(cluster schedule.Cluster, classifier namespace.Classifier) *PlacementChecker {
+	filters := []filter.Filter{
+		filter.NewOverloadFilter(),
+		filter.NewHealthFilter(),
+		filter.NewSnapshotCountFilter(),
+		filter.NewPendingPeerCountFilter(),
+		filter.NewStorageThresholdFilter(),
+	}
+
+	p := &PlacementChecker{
+		cluster:    cluster,
+		classifier: classifier,
+		filters:    filters,
+	}
+	p.SetConfig(&placement.Config{})
+	return p
+}
+
+// GetConfig returns the placement constraints used by the checker.
+func (p *PlacementChecker) GetConfig() *placement.Config {
+	return p.config.Load().(*placement.Config)
+}
+
+// SetConfig updates the placement constraints used by the checker.
+func (p *PlacementChecker) SetConfig(cfg *placement.Config) {
+	p.config.Store(cfg)
+}
+
+// SetOperatorController sets the operator controller, the stores which failed
+// to add a peer of a region in time are not selected for the region.
+func (p *PlacementChecker) SetOperatorController(opController *schedule.OperatorController) {
+	p.opController = opController
+}
+
+// DryRun returns a copy of the checker running on the dry run cluster.
+func (p *PlacementChecker) DryRun(cluster *schedule.DryRunCluster) *PlacementChecker {
+	dryRun := &PlacementChecker{
+		cluster:      cluster,
+		classifier:   p.classifier,
+		filters:      p.filters,
+		opController: p.opController,
+	}
+	dryRun.SetConfig(p.GetConfig())
+	return dryRun
+}
+
+// Check verifies a region's placement, creating an operator.Operator if need.
+func (p *PlacementChecker) Check(region *core.RegionInfo) *operator.Operator {
+	cfg := p.GetConfig()
+	if len(cfg.Constraints) == 0 {
+		return nil
+	}
+
+	schedule.Counter(p.cluster, checkerCounter).WithLabelValues("placement_checker", "check").Inc()
+	if len(region.GetDownPeers()) > 0 || len(region.GetPendingPeers()) > 0 || len(region.GetLearners()) > 0 {
+		schedule.Counter(p.cluster, checkerCounter).WithLabelValues("placement_checker", "abnormal_replica").Inc()
+		return nil
+	}
+
+	score := cfg.Score(region, p.cluster)
+	if score >= 0 {
+		schedule.Counter(p.cluster, checkerCounter).WithLabelValues("placement_checker", "all_right").Inc()
+		return nil
+	}
+	log.Debug("region violates placement constraints",
+		zap.Uint64("region-id", region.GetID()),
+		zap.Int("score", score),
+		zap.Stringer("constraints", cfg))
+
+	op := p.checkLeader(region, cfg, score)
+	if op == nil {
+		// the regions with learners are skipped, so only the voters are compared.
+		voters := p.cluster.GetRegionRule(region).Voters
+		switch replicas := len(region.GetPeers()); {
+		case replicas < voters:
+			if op = p.checkAddPeer(region, cfg, score); op != nil {
+				op.AttachKind(operator.OpRepair)
+			}
+		case replicas > voters:
+			op = p.checkRemovePeer(region, cfg, score)
+		default:
+			op = p.checkMovePeer(region, cfg, score)
+		}
+	}
+	if op == nil {
+		schedule.Counter(p.cluster, checkerCounter).WithLabelValues("placement_checker", "no_better_placement").Inc()
+		return nil
+	}
+	schedule.Counter(p.cluster, checkerCounter).WithLabelValues("placement_checker", "new_operator").Inc()
+	return op
+}
+
+// checkLeader tries to fix the placement by transferring the leader.
+func (p *PlacementChecker) checkLeader(region *core.RegionInfo, cfg *placement.Config, score int) *operator.Operator {
+	leaderFilters := []filter.Filter{filter.StoreStateFilter{TransferLeader: true}}
+	var target uint64
+	for storeID, peer := range region.GetFollowers() {
+		store := p.cluster.GetStore(storeID)
+		if store == nil || filter.Target(p.cluster, store, leaderFilters) {
+			continue
+		}
+		if s := cfg.Score(region.Clone(core.WithLeader(peer)), p.cluster); s > score {
+			target, score = storeID, s
+		}
+	}
+	if target == 0 {
+		return nil
+	}
+	return operator.CreateTransferLeaderOperator("placement-transfer-leader", region, region.GetLeader().GetStoreId(), target, operator.OpLeader)
+}
+
+// checkAddPeer tries to fix the placement by adding a replica.
+func (p *PlacementChecker) checkAddPeer(region *core.RegionInfo, cfg *placement.Config, score int) *operator.Operator {
+	var target uint64
+	for _, store := range p.candidateStores(region) {
+		newRegion := region.Clone(core.WithAddPeer(&metapb.Peer{StoreId: store.GetID()}))
+		if s := cfg.Score(newRegion, p.cluster); s > score {
+			target, score = store.GetID(), s
+		}
+	}
+	if target == 0 {
+		return nil
+	}
+	newPeer, err := p.cluster.AllocPeer(target)
+	if err != nil {
+		return nil
+	}
+	return operator.CreateAddPeerOperator("placement-add-replica", p.cluster, region, newPeer.GetId(), newPeer.GetStoreId(), operator.OpReplica)
+}
+
+// checkRemovePeer tries to fix the placement by removing a replica.
+func (p *PlacementChecker) checkRemovePeer(region *core.RegionInfo, cfg *placement.Config, score int) *operator.Operator {
+	var source uint64
+	for _, peer := range region.GetPeers() {
+		newRegion := region.Clone(core.WithRemoveStorePeer(peer.GetStoreId()))
+		if s := cfg.Score(newRegion, p.cluster); s > score {
+			source, score = peer.GetStoreId(), s
+		}
+	}
+	if source == 0 {
+		return nil
+	}
+	op, err := operator.CreateRemovePeerOperator("placement-remove-replica", p.cluster, operator.OpReplica, region, source)
+	if err != nil {
+		schedule.Counter(p.cluster, checkerCounter).WithLabelValues("placement_checker", "create_operator_fail").Inc()
+		return nil
+	}
+	return op
+}
+
+// checkMovePeer tries to fix the placement by moving a replica to another store.
+func (p *PlacementChecker) checkMovePeer(region *core.RegionInfo, cfg *placement.Config, score int) *operator.Operator {
+	var source, target uint64
+	candidates := p.candidateStores(region)
+	for _, peer := range region.GetPeers() {
+		for _, store := range candidates {
+			newRegion := region.Clone(
+				core.WithRemoveStorePeer(peer.GetStoreId()),
+				core.WithAddPeer(&metapb.Peer{StoreId: store.GetID()}),
+			)
+			if s := cfg.Score(newRegion, p.cluster); s > score {
+				source, target, score = peer.GetStoreId(), store.GetID(), s
+			}
+		}
+	}
+	if target == 0 {
+		return nil
+	}
+	newPeer, err := p.cluster.AllocPeer(target)
+	if err != nil {
+		return nil
+	}
+	op, err := operator.CreateMovePeerOperator("placement-move-replica", p.cluster, region, operator.OpReplica, source, target, newPeer.GetId())
+	if err != nil {
+		schedule.Counter(p.cluster, checkerCounter).WithLabelValues("placement_checker", "create_operator_fail").Inc()
+		return nil
+	}
+	return op
+}
+
+// candidateStores returns the stores which can accept a new replica of the region.
+func (p *PlacementChecker) candidateStores(region *core.RegionInfo) []*core.StoreInfo {
+	filters := []filter.Filter{
+		filter.NewStateFilter(),
+		filter.StoreStateFilter{MoveRegion: true},
+		filter.NewExcludedFilter(nil, region.GetStoreIds()),
+	}
+	filters = append(filters, p.filters...)
+	if p.classifier != nil {
+		filters = append(filters, filter.NewNamespaceFilter(p.classifier, p.classifier.GetRegionNamespace(region)))
+	}
+	if p.opController != nil {
+		filters = append(filters, p.opController.NewSlowTargetFilter(region.GetID()))
+	}
+	var stores []*core.StoreInfo
+	for _, store := range p.cluster.GetStores() {
+		if !filter.Target(p.cluster, store, filters) {
+			stores = append(stores, store)
+		}
+	}
+	return stores
+}
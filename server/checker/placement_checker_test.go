@@ -0,0 +1,142 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/pkg/mock/mockcluster"
+	"github.com/pingcap/pd/pkg/mock/mockhbstream"
+	"github.com/pingcap/pd/pkg/mock/mockoption"
+	"github.com/pingcap/pd/pkg/testutil"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/namespace"
+	"github.com/pingcap/pd/server/placement"
+	"github.com/pingcap/pd/server/placement/rule"
+	"github.com/pingcap/pd/server/schedule"
+	"github.com/pingcap/pd/server/schedule/operator"
+	"github.com/pingcap/pd/server/schedule/storelimit"
+)
+
+var _ = Suite(&testPlacementCheckerSuite{})
+
+type testPlacementCheckerSuite struct {
+	cluster *mockcluster.Cluster
+	pc      *PlacementChecker
+}
+
+func (s *testPlacementCheckerSuite) SetUpTest(c *C) {
+	s.cluster = mockcluster.NewCluster(mockoption.NewScheduleOptions())
+	s.cluster.AddLabelsStore(1, 1, map[string]string{"zone": "z1", "host": "h1"})
+	s.cluster.AddLabelsStore(2, 1, map[string]string{"zone": "z1", "host": "h2"})
+	s.cluster.AddLabelsStore(3, 1, map[string]string{"zone": "z1", "host": "h3"})
+	s.cluster.AddLabelsStore(4, 1, map[string]string{"zone": "z2", "host": "h4"})
+	s.pc = NewPlacementChecker(s.cluster, namespace.DefaultClassifier)
+}
+
+func (s *testPlacementCheckerSuite) setConstraints(c *C, expr string) {
+	cfg, err := placement.ParseConfig(expr)
+	c.Assert(err, IsNil)
+	s.pc.SetConfig(cfg)
+}
+
+func (s *testPlacementCheckerSuite) TestNoConstraint(c *C) {
+	s.cluster.AddLeaderRegion(1, 1, 2, 3)
+	c.Assert(s.pc.Check(s.cluster.GetRegion(1)), IsNil)
+
+	// The constraint is satisfied.
+	s.setConstraints(c, "count(zone:z1)>=2")
+	c.Assert(s.pc.Check(s.cluster.GetRegion(1)), IsNil)
+}
+
+func (s *testPlacementCheckerSuite) TestTransferLeader(c *C) {
+	s.setConstraints(c, "count_leader(zone:z2)=1")
+	s.cluster.AddLeaderRegion(1, 1, 2, 4)
+	testutil.CheckTransferLeader(c, s.pc.Check(s.cluster.GetRegion(1)), operator.OpLeader, 1, 4)
+}
+
+func (s *testPlacementCheckerSuite) TestMovePeer(c *C) {
+	s.setConstraints(c, "count(zone:z2)>=1")
+	s.cluster.AddLeaderRegion(1, 1, 2, 3)
+	op := s.pc.Check(s.cluster.GetRegion(1))
+	c.Assert(op, NotNil)
+	c.Assert(op.Desc(), Equals, "placement-move-replica")
+	c.Assert(op.Step(0).(operator.AddLearner).ToStore, Equals, uint64(4))
+
+	// No store is available in zone z3.
+	s.setConstraints(c, "count(zone:z3)>=1")
+	c.Assert(s.pc.Check(s.cluster.GetRegion(1)), IsNil)
+}
+
+func (s *testPlacementCheckerSuite) TestAddPeer(c *C) {
+	s.setConstraints(c, "count(zone:z2)>=1")
+	s.cluster.AddLeaderRegion(1, 1, 2)
+	testutil.CheckAddPeer(c, s.pc.Check(s.cluster.GetRegion(1)), operator.OpReplica, 4)
+}
+
+func (s *testPlacementCheckerSuite) TestRemovePeer(c *C) {
+	s.setConstraints(c, "count(host:h2)=0")
+	s.cluster.AddLeaderRegion(1, 1, 2, 3, 4)
+	testutil.CheckRemovePeer(c, s.pc.Check(s.cluster.GetRegion(1)), 2)
+}
+
+func (s *testPlacementCheckerSuite) TestRuleReplicas(c *C) {
+	s.cluster.AddLabelsStore(5, 1, map[string]string{"zone": "z2", "host": "h5"})
+	s.cluster.AddLabelsStore(6, 1, map[string]string{"zone": "z2", "host": "h6"})
+	c.Assert(s.cluster.RuleManager.SetRule(&rule.Rule{ID: "r1", Voters: 5}), IsNil)
+	s.setConstraints(c, "count(host:h2)=0")
+
+	// The region has the 5 voters required by the rule, so the peer is moved
+	// instead of removed.
+	s.cluster.AddLeaderRegion(1, 1, 2, 3, 4, 5)
+	op := s.pc.Check(s.cluster.GetRegion(1))
+	c.Assert(op, NotNil)
+	c.Assert(op.Desc(), Equals, "placement-move-replica")
+	c.Assert(op.Step(0).(operator.AddLearner).ToStore, Equals, uint64(6))
+
+	s.cluster.AddLeaderRegion(2, 1, 3, 4, 5)
+	c.Assert(s.pc.Check(s.cluster.GetRegion(2)), IsNil)
+	s.cluster.AddLeaderRegion(3, 1, 2, 3, 4, 5, 6)
+	testutil.CheckRemovePeer(c, s.pc.Check(s.cluster.GetRegion(3)), 2)
+}
+
+func (s *testPlacementCheckerSuite) TestSlowTarget(c *C) {
+	oc := schedule.NewOperatorController(s.cluster, mockhbstream.NewHeartbeatStream())
+	s.pc.SetOperatorController(oc)
+	s.setConstraints(c, "count(zone:z2)>=1")
+	s.cluster.AddLeaderRegion(1, 1, 2, 3)
+	c.Assert(s.pc.Check(s.cluster.GetRegion(1)).Step(0).(operator.AddLearner).ToStore, Equals, uint64(4))
+
+	// Store 4 fails to add a learner in time, so it is not selected again.
+	oc.SetStoreLimit(4, 1000, storelimit.AddPeer)
+	op := operator.NewOperator("test", 1, &metapb.RegionEpoch{}, operator.OpRegion, operator.AddLearner{ToStore: 4, PeerID: 4})
+	c.Assert(oc.AddOperator(op), IsTrue)
+	op.SetStartTime(time.Now().Add(-time.Hour))
+	oc.Dispatch(s.cluster.GetRegion(1), "test")
+	c.Assert(oc.GetSlowTargets(1), HasLen, 1)
+	c.Assert(s.pc.Check(s.cluster.GetRegion(1)), IsNil)
+
+	s.cluster.AddLabelsStore(5, 1, map[string]string{"zone": "z2", "host": "h5"})
+	c.Assert(s.pc.Check(s.cluster.GetRegion(1)).Step(0).(operator.AddLearner).ToStore, Equals, uint64(5))
+}
+
+func (s *testPlacementCheckerSuite) TestAbnormalRegion(c *C) {
+	s.setConstraints(c, "count(zone:z2)>=1")
+	s.cluster.AddLeaderRegion(1, 1, 2, 3)
+	region := s.cluster.GetRegion(1)
+	region = region.Clone(core.WithPendingPeers([]*metapb.Peer{region.GetStorePeer(2)}))
+	c.Assert(s.pc.Check(region), IsNil)
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/pd/server/core"
 	"github.com/pingcap/pd/server/namespace"
+	"github.com/pingcap/pd/server/placement/rule"
 	"github.com/pingcap/pd/server/schedule"
 	"github.com/pingcap/pd/server/schedule/filter"
 	"github.com/pingcap/pd/server/schedule/operator"
@@ -36,6 +37,9 @@ type ReplicaChecker struct {
 	cluster    schedule.Cluster
 	classifier namespace.Classifier
 	filters    []filter.Filter
+	// opController provides the stores which failed to add a peer in time
+	// and the placement constraints, nil means no store is avoided.
+	opController *schedule.OperatorController
 }
 
 // NewReplicaChecker creates a replica checker.
@@ -54,59 +58,112 @@ func NewReplicaChecker(cluster schedule.Cluster, classifier namespace.Classifier
 	}
 }
 
+// SetOperatorController sets the operator controller, the stores which failed
+// to add a peer of a region in time or break the placement constraints are not
+// selected for the region.
+func (r *ReplicaChecker) SetOperatorController(opController *schedule.OperatorController) {
+	r.opController = opController
+}
+
+// DryRun returns a copy of the checker running on the dry run cluster.
+func (r *ReplicaChecker) DryRun(cluster *schedule.DryRunCluster) *ReplicaChecker {
+	return &ReplicaChecker{
+		cluster:      cluster,
+		classifier:   r.classifier,
+		filters:      r.filters,
+		opController: r.opController,
+	}
+}
+
 // Check verifies a region's replicas, creating an operator.Operator if need.
 func (r *ReplicaChecker) Check(region *core.RegionInfo) *operator.Operator {
-	checkerCounter.WithLabelValues("replica_checker", "check").Inc()
-	if op := r.checkDownPeer(region); op != nil {
-		checkerCounter.WithLabelValues("replica_checker", "new_operator").Inc()
+	schedule.Counter(r.cluster, checkerCounter).WithLabelValues("replica_checker", "check").Inc()
+	rule := r.cluster.GetRegionRule(region)
+	if op := r.checkDownPeer(region, rule); op != nil {
+		schedule.Counter(r.cluster, checkerCounter).WithLabelValues("replica_checker", "new_operator").Inc()
 		op.SetPriorityLevel(core.HighPriority)
+		op.AttachKind(operator.OpRepair)
 		return op
 	}
-	if op := r.checkOfflinePeer(region); op != nil {
-		checkerCounter.WithLabelValues("replica_checker", "new_operator").Inc()
+	if op := r.checkOfflinePeer(region, rule); op != nil {
+		schedule.Counter(r.cluster, checkerCounter).WithLabelValues("replica_checker", "new_operator").Inc()
 		op.SetPriorityLevel(core.HighPriority)
+		op.AttachKind(operator.OpRepair)
 		return op
 	}
 
-	if len(region.GetPeers()) < r.cluster.GetMaxReplicas() && r.cluster.IsMakeUpReplicaEnabled() {
-		log.Debug("region has fewer than max replicas", zap.Uint64("region-id", region.GetID()), zap.Int("peers", len(region.GetPeers())))
+	if len(region.GetPeers()) < rule.GetReplicas() && r.cluster.IsMakeUpReplicaEnabled() {
+		log.Debug("region has fewer than required replicas", zap.Uint64("region-id", region.GetID()), zap.Int("peers", len(region.GetPeers())), zap.String("rule", rule.ID))
 		newPeer, _ := r.selectBestPeerToAddReplica(region, filter.NewStorageThresholdFilter())
 		if newPeer == nil {
-			checkerCounter.WithLabelValues("replica_checker", "no_target_store").Inc()
+			schedule.Counter(r.cluster, checkerCounter).WithLabelValues("replica_checker", "no_target_store").Inc()
 			return nil
 		}
-		checkerCounter.WithLabelValues("replica_checker", "new_operator").Inc()
-		return operator.CreateAddPeerOperator("make-up-replica", r.cluster, region, newPeer.GetId(), newPeer.GetStoreId(), operator.OpReplica)
+		schedule.Counter(r.cluster, checkerCounter).WithLabelValues("replica_checker", "new_operator").Inc()
+		if len(region.GetVoters()) < rule.Voters {
+			return operator.CreateAddPeerOperator("make-up-replica", r.cluster, region, newPeer.GetId(), newPeer.GetStoreId(), operator.OpReplica|operator.OpRepair)
+		}
+		return operator.CreateAddLearnerOperator("make-up-learner", r.cluster, region, newPeer.GetId(), newPeer.GetStoreId(), operator.OpReplica|operator.OpRepair)
 	}
 
 	// when add learner peer, the number of peer will exceed max replicas for a while,
 	// just comparing the the number of voters to avoid too many cancel add operator log.
-	if len(region.GetVoters()) > r.cluster.GetMaxReplicas() && r.cluster.IsRemoveExtraReplicaEnabled() {
-		log.Debug("region has more than max replicas", zap.Uint64("region-id", region.GetID()), zap.Int("peers", len(region.GetPeers())))
-		oldPeer, _ := r.selectWorstPeer(region)
-		if oldPeer == nil {
-			checkerCounter.WithLabelValues("replica_checker", "no_worst_peer").Inc()
-			return nil
-		}
-		op, err := operator.CreateRemovePeerOperator("remove-extra-replica", r.cluster, operator.OpReplica, region, oldPeer.GetStoreId())
-		if err != nil {
-			checkerCounter.WithLabelValues("replica_checker", "create_operator_fail").Inc()
-			return nil
-		}
-		checkerCounter.WithLabelValues("replica_checker", "new_operator").Inc()
+	if len(region.GetVoters()) > rule.Voters && r.cluster.IsRemoveExtraReplicaEnabled() {
+		log.Debug("region has more than required voters", zap.Uint64("region-id", region.GetID()), zap.Int("peers", len(region.GetPeers())), zap.String("rule", rule.ID))
+		return r.removeWorstPeer("remove-extra-replica", region, region.GetVoters())
+	}
+	// the learners beyond the rule are promoted by the LearnerChecker if the
+	// region lacks voters, only remove them when the voters are enough.
+	if len(region.GetLearners()) > rule.Learners && len(region.GetVoters()) >= rule.Voters && r.cluster.IsRemoveExtraReplicaEnabled() {
+		log.Debug("region has more than required learners", zap.Uint64("region-id", region.GetID()), zap.Int("peers", len(region.GetPeers())), zap.String("rule", rule.ID))
+		return r.removeWorstPeer("remove-extra-learner", region, region.GetLearners())
+	}
+
+	if op := r.checkMisplacedPeer(region, rule); op != nil {
+		schedule.Counter(r.cluster, checkerCounter).WithLabelValues("replica_checker", "new_operator").Inc()
 		return op
 	}
 
 	return r.checkBestReplacement(region)
 }
 
+func (r *ReplicaChecker) removeWorstPeer(desc string, region *core.RegionInfo, peers []*metapb.Peer) *operator.Operator {
+	oldPeer, _ := r.selectWorstPeer(region, peers)
+	if oldPeer == nil {
+		schedule.Counter(r.cluster, checkerCounter).WithLabelValues("replica_checker", "no_worst_peer").Inc()
+		return nil
+	}
+	op, err := operator.CreateRemovePeerOperator(desc, r.cluster, operator.OpReplica, region, oldPeer.GetStoreId())
+	if err != nil {
+		schedule.Counter(r.cluster, checkerCounter).WithLabelValues("replica_checker", "create_operator_fail").Inc()
+		return nil
+	}
+	schedule.Counter(r.cluster, checkerCounter).WithLabelValues("replica_checker", "new_operator").Inc()
+	return op
+}
+
 // SelectBestReplacementStore returns a store id that to be used to replace the old peer and distinct score.
 func (r *ReplicaChecker) SelectBestReplacementStore(region *core.RegionInfo, oldPeer *metapb.Peer, filters ...filter.Filter) (uint64, float64) {
 	filters = append(filters, filter.NewExcludedFilter(nil, region.GetStoreIds()))
+	if source := r.cluster.GetStore(oldPeer.GetStoreId()); source != nil && r.opController != nil {
+		filters = append(filters, filter.NewPlacementFilter(r.opController.GetPlacementConfig(), r.cluster, region, source))
+	}
 	newRegion := region.Clone(core.WithRemoveStorePeer(oldPeer.GetStoreId()))
 	return r.selectBestStoreToAddReplica(newRegion, filters...)
 }
 
+// SelectBestReplacement returns the worst peer of the region with its distinct
+// score, and the best store to replace it with the distinct score after the
+// replacement. The store id is 0 if there is no store to replace the peer.
+func (r *ReplicaChecker) SelectBestReplacement(region *core.RegionInfo) (*metapb.Peer, float64, uint64, float64) {
+	oldPeer, oldScore := r.selectWorstPeer(region, region.GetPeers())
+	if oldPeer == nil {
+		return nil, 0, 0, 0
+	}
+	storeID, newScore := r.SelectBestReplacementStore(region, oldPeer, filter.NewStorageThresholdFilter())
+	return oldPeer, oldScore, storeID, newScore
+}
+
 // selectBestPeerToAddReplica returns a new peer that to be used to add a replica and distinct score.
 func (r *ReplicaChecker) selectBestPeerToAddReplica(region *core.RegionInfo, filters ...filter.Filter) (*metapb.Peer, float64) {
 	storeID, score := r.selectBestStoreToAddReplica(region, filters...)
@@ -123,38 +180,50 @@ func (r *ReplicaChecker) selectBestPeerToAddReplica(region *core.RegionInfo, fil
 
 // selectBestStoreToAddReplica returns the store to add a replica.
 func (r *ReplicaChecker) selectBestStoreToAddReplica(region *core.RegionInfo, filters ...filter.Filter) (uint64, float64) {
+	rule := r.cluster.GetRegionRule(region)
+	regionStores := r.cluster.GetRegionStores(region)
 	// Add some must have filters.
 	newFilters := []filter.Filter{
 		filter.NewStateFilter(),
 		filter.NewExcludedFilter(nil, region.GetStoreIds()),
+		filter.NewRuleFilter(rule, regionStores, r.cluster.GetStores()),
 	}
 	filters = append(filters, r.filters...)
 	filters = append(filters, newFilters...)
 	if r.classifier != nil {
 		filters = append(filters, filter.NewNamespaceFilter(r.classifier, r.classifier.GetRegionNamespace(region)))
 	}
-	regionStores := r.cluster.GetRegionStores(region)
-	s := selector.NewReplicaSelector(regionStores, r.cluster.GetLocationLabels(), r.filters...)
+	if r.opController != nil {
+		filters = append(filters, r.opController.NewSlowTargetFilter(region.GetID()))
+	}
+	s := selector.NewReplicaSelector(regionStores, rule.LocationLabels, r.filters...)
 	target := s.SelectTarget(r.cluster, r.cluster.GetStores(), filters...)
 	if target == nil {
 		return 0, 0
 	}
-	return target.GetID(), core.DistinctScore(r.cluster.GetLocationLabels(), regionStores, target)
+	return target.GetID(), core.DistinctScore(rule.LocationLabels, regionStores, target)
 }
 
-// selectWorstPeer returns the worst peer in the region.
-func (r *ReplicaChecker) selectWorstPeer(region *core.RegionInfo) (*metapb.Peer, float64) {
+// selectWorstPeer returns the worst one of the peers in the region.
+func (r *ReplicaChecker) selectWorstPeer(region *core.RegionInfo, peers []*metapb.Peer) (*metapb.Peer, float64) {
+	labels := r.cluster.GetRegionRule(region).LocationLabels
 	regionStores := r.cluster.GetRegionStores(region)
-	s := selector.NewReplicaSelector(regionStores, r.cluster.GetLocationLabels(), r.filters...)
-	worstStore := s.SelectSource(r.cluster, regionStores)
+	candidates := make([]*core.StoreInfo, 0, len(peers))
+	for _, peer := range peers {
+		if store := r.cluster.GetStore(peer.GetStoreId()); store != nil {
+			candidates = append(candidates, store)
+		}
+	}
+	s := selector.NewReplicaSelector(regionStores, labels, r.filters...)
+	worstStore := s.SelectSource(r.cluster, candidates)
 	if worstStore == nil {
 		log.Debug("no worst store", zap.Uint64("region-id", region.GetID()))
 		return nil, 0
 	}
-	return region.GetStorePeer(worstStore.GetID()), core.DistinctScore(r.cluster.GetLocationLabels(), regionStores, worstStore)
+	return region.GetStorePeer(worstStore.GetID()), core.DistinctScore(labels, regionStores, worstStore)
 }
 
-func (r *ReplicaChecker) checkDownPeer(region *core.RegionInfo) *operator.Operator {
+func (r *ReplicaChecker) checkDownPeer(region *core.RegionInfo, rule *rule.Rule) *operator.Operator {
 	if !r.cluster.IsRemoveDownReplicaEnabled() {
 		return nil
 	}
@@ -169,6 +238,10 @@ func (r *ReplicaChecker) checkDownPeer(region *core.RegionInfo) *operator.Operat
 			log.Info("lost the store, maybe you are recovering the PD cluster", zap.Uint64("store-id", peer.GetStoreId()))
 			return nil
 		}
+		// the down peers on the store under maintenance are expected to be back.
+		if store.IsInMaintenance() {
+			continue
+		}
 		if store.DownTime() < r.cluster.GetMaxStoreDownTime() {
 			continue
 		}
@@ -176,18 +249,18 @@ func (r *ReplicaChecker) checkDownPeer(region *core.RegionInfo) *operator.Operat
 			continue
 		}
 
-		return r.fixPeer(region, peer, "down")
+		return r.fixPeer(region, rule, peer, "down")
 	}
 	return nil
 }
 
-func (r *ReplicaChecker) checkOfflinePeer(region *core.RegionInfo) *operator.Operator {
+func (r *ReplicaChecker) checkOfflinePeer(region *core.RegionInfo, rule *rule.Rule) *operator.Operator {
 	if !r.cluster.IsReplaceOfflineReplicaEnabled() {
 		return nil
 	}
 
-	// just skip learner
-	if len(region.GetLearners()) != 0 {
+	// just skip the learners which are not required by the rule
+	if len(region.GetLearners()) > rule.Learners {
 		return nil
 	}
 
@@ -201,53 +274,68 @@ func (r *ReplicaChecker) checkOfflinePeer(region *core.RegionInfo) *operator.Ope
 			continue
 		}
 
-		return r.fixPeer(region, peer, "offline")
+		return r.fixPeer(region, rule, peer, "offline")
 	}
 
 	return nil
 }
 
+// checkMisplacedPeer replaces the peer whose store does not match the label
+// constraints or breaks the isolation level of the placement rule.
+func (r *ReplicaChecker) checkMisplacedPeer(region *core.RegionInfo, rule *rule.Rule) *operator.Operator {
+	regionStores := r.cluster.GetRegionStores(region)
+	limit := rule.IsolationLimit(r.cluster.GetStores())
+	for _, store := range regionStores {
+		if rule.MatchStore(store) && rule.IsIsolated(regionStores, store, limit) {
+			continue
+		}
+		if op := r.fixPeer(region, rule, region.GetStorePeer(store.GetID()), "misplaced"); op != nil {
+			return op
+		}
+	}
+	return nil
+}
+
 func (r *ReplicaChecker) checkBestReplacement(region *core.RegionInfo) *operator.Operator {
 	if !r.cluster.IsLocationReplacementEnabled() {
 		return nil
 	}
 
-	oldPeer, oldScore := r.selectWorstPeer(region)
+	oldPeer, oldScore, storeID, newScore := r.SelectBestReplacement(region)
 	if oldPeer == nil {
-		checkerCounter.WithLabelValues("replica_checker", "all_right").Inc()
+		schedule.Counter(r.cluster, checkerCounter).WithLabelValues("replica_checker", "all_right").Inc()
 		return nil
 	}
-	storeID, newScore := r.SelectBestReplacementStore(region, oldPeer, filter.NewStorageThresholdFilter())
 	if storeID == 0 {
-		checkerCounter.WithLabelValues("replica_checker", "no_replacement_store").Inc()
+		schedule.Counter(r.cluster, checkerCounter).WithLabelValues("replica_checker", "no_replacement_store").Inc()
 		return nil
 	}
 	// Make sure the new peer is better than the old peer.
 	if newScore <= oldScore {
 		log.Debug("no better peer", zap.Uint64("region-id", region.GetID()), zap.Float64("new-score", newScore), zap.Float64("old-score", oldScore))
-		checkerCounter.WithLabelValues("replica_checker", "not_better").Inc()
+		schedule.Counter(r.cluster, checkerCounter).WithLabelValues("replica_checker", "not_better").Inc()
 		return nil
 	}
 	newPeer, err := r.cluster.AllocPeer(storeID)
 	if err != nil {
 		return nil
 	}
-	op, err := operator.CreateMovePeerOperator("move-to-better-location", r.cluster, region, operator.OpReplica, oldPeer.GetStoreId(), newPeer.GetStoreId(), newPeer.GetId())
+	op, err := r.createMovePeerOperator("move-to-better-location", region, oldPeer, newPeer)
 	if err != nil {
-		checkerCounter.WithLabelValues("replica_checker", "create_operator_fail").Inc()
+		schedule.Counter(r.cluster, checkerCounter).WithLabelValues("replica_checker", "create_operator_fail").Inc()
 		return nil
 	}
-	checkerCounter.WithLabelValues("replica_checker", "new_operator").Inc()
+	schedule.Counter(r.cluster, checkerCounter).WithLabelValues("replica_checker", "new_operator").Inc()
 	return op
 }
 
-func (r *ReplicaChecker) fixPeer(region *core.RegionInfo, peer *metapb.Peer, status string) *operator.Operator {
+func (r *ReplicaChecker) fixPeer(region *core.RegionInfo, rule *rule.Rule, peer *metapb.Peer, status string) *operator.Operator {
 	removeExtra := fmt.Sprintf("remove-extra-%s-replica", status)
 	// Check the number of replicas first.
-	if len(region.GetPeers()) > r.cluster.GetMaxReplicas() {
+	if len(region.GetPeers()) > rule.GetReplicas() {
 		op, err := operator.CreateRemovePeerOperator(removeExtra, r.cluster, operator.OpReplica, region, peer.GetStoreId())
 		if err != nil {
-			checkerCounter.WithLabelValues("replica_checker", "create_operator_fail").Inc()
+			schedule.Counter(r.cluster, checkerCounter).WithLabelValues("replica_checker", "create_operator_fail").Inc()
 			return nil
 		}
 		return op
@@ -261,7 +349,7 @@ func (r *ReplicaChecker) fixPeer(region *core.RegionInfo, peer *metapb.Peer, sta
 	if region.GetPendingPeer(peer.GetId()) != nil {
 		op, err := operator.CreateRemovePeerOperator(removePending, r.cluster, operator.OpReplica, region, peer.GetStoreId())
 		if err != nil {
-			checkerCounter.WithLabelValues("replica_checker", "create_operator_fail").Inc()
+			schedule.Counter(r.cluster, checkerCounter).WithLabelValues("replica_checker", "create_operator_fail").Inc()
 			return nil
 		}
 		return op
@@ -278,9 +366,18 @@ func (r *ReplicaChecker) fixPeer(region *core.RegionInfo, peer *metapb.Peer, sta
 	}
 
 	replace := fmt.Sprintf("replace-%s-replica", status)
-	op, err := operator.CreateMovePeerOperator(replace, r.cluster, region, operator.OpReplica, peer.GetStoreId(), newPeer.GetStoreId(), newPeer.GetId())
+	op, err := r.createMovePeerOperator(replace, region, peer, newPeer)
 	if err != nil {
 		return nil
 	}
 	return op
 }
+
+// createMovePeerOperator creates an operator that replaces the old peer with
+// the new peer, the role of the peer is kept.
+func (r *ReplicaChecker) createMovePeerOperator(desc string, region *core.RegionInfo, oldPeer, newPeer *metapb.Peer) (*operator.Operator, error) {
+	if region.GetStoreLearner(oldPeer.GetStoreId()) != nil {
+		return operator.CreateMoveLearnerOperator(desc, region, operator.OpReplica, oldPeer.GetStoreId(), newPeer.GetStoreId(), newPeer.GetId()), nil
+	}
+	return operator.CreateMovePeerOperator(desc, r.cluster, region, operator.OpReplica, oldPeer.GetStoreId(), newPeer.GetStoreId(), newPeer.GetId())
+}
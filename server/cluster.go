@@ -32,7 +32,10 @@ import (
 	"github.com/pingcap/pd/server/core"
 	"github.com/pingcap/pd/server/id"
 	"github.com/pingcap/pd/server/namespace"
+	"github.com/pingcap/pd/server/placement"
+	"github.com/pingcap/pd/server/placement/rule"
 	syncer "github.com/pingcap/pd/server/region_syncer"
+	"github.com/pingcap/pd/server/schedule"
 	"github.com/pingcap/pd/server/statistics"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
@@ -41,6 +44,11 @@ import (
 var (
 	backgroundJobInterval      = time.Minute
 	defaultChangedRegionsLimit = 10000
+	// keySpaceWarnInterval is the min interval to log the holes in the key
+	// space.
+	keySpaceWarnInterval = 10 * time.Minute
+	// maxKeySpaceWarnHoles is the max number of the holes logged at a time.
+	maxKeySpaceWarnHoles = 10
 )
 
 // RaftCluster is used for cluster config management.
@@ -69,12 +77,28 @@ type RaftCluster struct {
 
 	prepareChecker *prepareChecker
 	changedRegions chan *core.RegionInfo
+	// regionStatsBatch applies the heartbeats which only change the
+	// approximate size or keys in batches.
+	regionStatsBatch *regionStatsBatch
 
 	labelLevelStats *statistics.LabelLevelStatistics
 	regionStats     *statistics.RegionStatistics
 	storesStats     *statistics.StoresStats
+	capacityHistory *statistics.StoresCapacityHistory
 	hotSpotCache    *statistics.HotSpotCache
-
+	// hotRegionHistory persists the snapshots of the hot regions, nil means
+	// the hot regions are not persisted.
+	hotRegionHistory *statistics.HotRegionHistory
+	heatmap          *statistics.Heatmap
+	keySpaceAuditor  *statistics.KeySpaceAuditor
+	// keySpaceRegionCount is the region count of the last key space audit,
+	// the holes are only logged when the count is unchanged since then. It
+	// is only accessed by the background jobs.
+	keySpaceRegionCount int
+	// keySpaceWarnTime is the last time the holes are logged.
+	keySpaceWarnTime time.Time
+
+	ruleManager *rule.Manager
 	coordinator *coordinator
 
 	wg           sync.WaitGroup
@@ -145,9 +169,14 @@ func (c *RaftCluster) initCluster(id id.Allocator, opt *config.ScheduleOption, s
 	c.id = id
 	c.labelLevelStats = statistics.NewLabelLevelStatistics()
 	c.storesStats = statistics.NewStoresStats()
+	c.capacityHistory = statistics.NewStoresCapacityHistory(statistics.DefaultCapacitySampleLimit)
 	c.prepareChecker = newPrepareChecker()
 	c.changedRegions = make(chan *core.RegionInfo, defaultChangedRegionsLimit)
 	c.hotSpotCache = statistics.NewHotSpotCache()
+	c.regionStatsBatch = newRegionStatsBatch()
+	c.heatmap = statistics.NewHeatmap(statistics.DefaultHeatmapTimeSlices, statistics.DefaultHeatmapKeyRanges, time.Now())
+	c.keySpaceAuditor = statistics.NewKeySpaceAuditor()
+	c.ruleManager = rule.NewManager(storage)
 }
 
 func (c *RaftCluster) start() error {
@@ -173,17 +202,34 @@ func (c *RaftCluster) start() error {
 		return err
 	}
 
+	if err = c.ruleManager.Initialize(); err != nil {
+		return err
+	}
+
 	c.coordinator = newCoordinator(cluster, c.s.hbStreams, c.s.classifier)
+	placementCfg := &placement.Config{}
+	if _, err = c.storage.LoadPlacementConfig(placementCfg); err != nil {
+		return err
+	}
+	c.coordinator.setPlacementConfig(placementCfg)
+	if historyKV := c.storage.GetOperatorHistoryStorage(); historyKV != nil {
+		c.coordinator.opController.SetOperatorHistory(schedule.NewOperatorHistory(historyKV, schedule.DefaultOperatorHistoryLimit))
+	}
+	if historyKV := c.storage.GetHotRegionHistoryStorage(); historyKV != nil {
+		c.hotRegionHistory = statistics.NewHotRegionHistory(historyKV)
+	}
 	c.regionStats = statistics.NewRegionStatistics(c.s.scheduleOpt, c.s.classifier)
 	c.quit = make(chan struct{})
 
-	c.wg.Add(3)
+	c.wg.Add(5)
 	go c.runCoordinator()
 	failpoint.Inject("highFrequencyClusterJobs", func() {
 		backgroundJobInterval = 100 * time.Microsecond
 	})
 	go c.runBackgroundJobs(backgroundJobInterval)
 	go c.syncRegions()
+	go c.runHotRegionHistoryJob(statistics.HotRegionHistoryInterval)
+	go c.runHeatmapJob()
 	c.running = true
 
 	return nil
@@ -238,6 +284,9 @@ func (c *RaftCluster) runBackgroundJobs(interval time.Duration) {
 		case <-ticker.C:
 			c.checkOperators()
 			c.checkStores()
+			c.coordinator.checkSlowStores()
+			c.coordinator.checkMaintenanceStores()
+			c.checkKeySpace(time.Now())
 			c.collectMetrics()
 			c.coordinator.opController.PruneHistory()
 		}
@@ -295,9 +344,14 @@ func (c *RaftCluster) handleStoreHeartbeat(stats *pdpb.StoreStats) error {
 	if store == nil {
 		return core.NewStoreNotFoundErr(storeID)
 	}
-	newStore := store.Clone(core.SetStoreStats(stats), core.SetLastHeartbeatTS(time.Now()))
+	now := time.Now()
+	newStore := store.Clone(core.SetStoreStats(stats), core.SetLastHeartbeatTS(now))
 	c.core.Stores.SetStore(newStore)
 	c.storesStats.Observe(newStore.GetID(), newStore.GetStoreStats())
+	if last := store.GetLastHeartbeatTS(); !last.IsZero() {
+		c.storesStats.ObserveSlowness(storeID, stats, now.Sub(last), store.GetPendingPeerCount())
+	}
+	c.capacityHistory.Observe(stats, now)
 	c.storesStats.UpdateTotalBytesRate(c.core.Stores)
 	return nil
 }
@@ -317,11 +371,14 @@ func (c *RaftCluster) processRegionHeartbeat(region *core.RegionInfo) error {
 	writeItems := c.CheckWriteStatus(region)
 	readItems := c.CheckReadStatus(region)
 	c.RUnlock()
+	c.heatmap.Observe(region)
+	c.keySpaceAuditor.Observe(region.GetID(), time.Now())
 
 	// Save to storage if meta is updated.
 	// Save to cache if meta or leader is updated, or contains any down/pending peer.
 	// Mark isNew if the region in cache does not have leader.
-	var saveKV, saveCache, isNew bool
+	// Mark updateStats if only the approximate size or keys is updated.
+	var saveKV, saveCache, isNew, updateStats bool
 	if origin == nil {
 		log.Debug("insert new region",
 			zap.Uint64("region-id", region.GetID()),
@@ -374,11 +431,9 @@ func (c *RaftCluster) processRegionHeartbeat(region *core.RegionInfo) error {
 		if len(region.GetPeers()) != len(origin.GetPeers()) {
 			saveKV, saveCache = true, true
 		}
-		if region.GetApproximateSize() != origin.GetApproximateSize() {
-			saveCache = true
-		}
-		if region.GetApproximateKeys() != origin.GetApproximateKeys() {
-			saveCache = true
+		if region.GetApproximateSize() != origin.GetApproximateSize() ||
+			region.GetApproximateKeys() != origin.GetApproximateKeys() {
+			updateStats = true
 		}
 	}
 
@@ -396,7 +451,21 @@ func (c *RaftCluster) processRegionHeartbeat(region *core.RegionInfo) error {
 		default:
 		}
 	}
-	if len(writeItems) == 0 && len(readItems) == 0 && !saveCache && !isNew {
+
+	// The hot spot cache has its own lock, so the flows are updated without
+	// the cluster lock.
+	for _, writeItem := range writeItems {
+		c.hotSpotCache.Update(writeItem)
+	}
+	for _, readItem := range readItems {
+		c.hotSpotCache.Update(readItem)
+	}
+	if !saveCache && !isNew {
+		// The key range and the peers are not changed, so the region tree is
+		// not updated and the size and keys are applied in batches.
+		if updateStats {
+			c.regionStatsBatch.apply(region, c.applyRegionStats)
+		}
 		return nil
 	}
 
@@ -439,14 +508,40 @@ func (c *RaftCluster) processRegionHeartbeat(region *core.RegionInfo) error {
 	if c.regionStats != nil {
 		c.regionStats.Observe(region, c.takeRegionStoresLocked(region))
 	}
+	return nil
+}
 
-	for _, writeItem := range writeItems {
-		c.hotSpotCache.Update(writeItem)
+// applyRegionStats updates the approximate size and keys of the regions in the
+// cache. A region is skipped if the cached one has been changed by another
+// heartbeat since it was checked.
+func (c *RaftCluster) applyRegionStats(regions map[uint64]*core.RegionInfo) {
+	c.Lock()
+	defer c.Unlock()
+	stores := make(map[uint64]struct{})
+	for _, region := range regions {
+		origin := c.core.Regions.GetRegion(region.GetID())
+		if origin == nil || !isRegionStatsChangeOnly(origin, region) {
+			continue
+		}
+		c.core.Regions.UpdateRegionStats(region)
+		for _, p := range region.GetPeers() {
+			stores[p.GetStoreId()] = struct{}{}
+		}
 	}
-	for _, readItem := range readItems {
-		c.hotSpotCache.Update(readItem)
+	for storeID := range stores {
+		c.updateStoreStatusLocked(storeID)
 	}
-	return nil
+}
+
+// isRegionStatsChangeOnly checks if the region has the same epoch, leader and
+// peers as the origin, and neither has any down or pending peer.
+func isRegionStatsChangeOnly(origin, region *core.RegionInfo) bool {
+	r, o := region.GetRegionEpoch(), origin.GetRegionEpoch()
+	return r.GetVersion() == o.GetVersion() && r.GetConfVer() == o.GetConfVer() &&
+		region.GetLeader().GetId() == origin.GetLeader().GetId() &&
+		len(region.GetPeers()) == len(origin.GetPeers()) &&
+		len(region.GetDownPeers()) == 0 && len(region.GetPendingPeers()) == 0 &&
+		len(origin.GetDownPeers()) == 0 && len(origin.GetPendingPeers()) == 0
 }
 
 func (c *RaftCluster) updateStoreStatusLocked(id uint64) {
@@ -700,6 +795,42 @@ func (c *RaftCluster) GetRegionStats(startKey, endKey []byte) *statistics.Region
 	return statistics.GetRegionStats(c.core.Regions, startKey, endKey)
 }
 
+// GetTableStats returns the statistics of a table from cluster.
+func (c *RaftCluster) GetTableStats(tableID int64) *statistics.TableStats {
+	c.RLock()
+	defer c.RUnlock()
+	return statistics.GetTableStats(c.core.Regions, tableID)
+}
+
+// GetTablesStats returns the statistics of the tables from cluster.
+func (c *RaftCluster) GetTablesStats() []*statistics.TableStats {
+	c.RLock()
+	defer c.RUnlock()
+	return statistics.GetTablesStats(c.core.Regions)
+}
+
+// GetCapacityForecast returns the capacity forecast of the stores and the
+// cluster.
+func (c *RaftCluster) GetCapacityForecast() *statistics.CapacityForecasts {
+	c.RLock()
+	defer c.RUnlock()
+	return c.capacityHistory.Forecast(c.core.Stores.GetStores(), c.GetLowSpaceRatio())
+}
+
+// GetTopology returns the stores grouped by the location labels. The regions
+// are checked against the voters of their placement rules.
+func (c *RaftCluster) GetTopology() *statistics.Topology {
+	regionVoters := func(region *core.RegionInfo) int {
+		return c.GetRegionRule(region).Voters
+	}
+	return statistics.BuildTopology(c.GetStores(), c.GetRegions(), c.storesStats, c.GetLocationLabels(), c.GetMaxReplicas(), regionVoters)
+}
+
+// GetStoreSlowScore returns the slowness score of the store.
+func (c *RaftCluster) GetStoreSlowScore(storeID uint64) float64 {
+	return c.storesStats.GetStoreSlowScore(storeID)
+}
+
 // GetStoresStats returns stores' statistics from cluster.
 func (c *RaftCluster) GetStoresStats() *statistics.StoresStats {
 	c.RLock()
@@ -921,6 +1052,20 @@ func (c *RaftCluster) UnblockStore(storeID uint64) {
 	c.core.UnblockStore(storeID)
 }
 
+// EvictRegions stops moving regions to the store.
+func (c *RaftCluster) EvictRegions(storeID uint64) error {
+	c.Lock()
+	defer c.Unlock()
+	return c.core.EvictRegions(storeID)
+}
+
+// UnEvictRegions allows moving regions to the store.
+func (c *RaftCluster) UnEvictRegions(storeID uint64) {
+	c.Lock()
+	defer c.Unlock()
+	c.core.UnEvictRegions(storeID)
+}
+
 // AttachOverloadStatus attaches the overload status to a store.
 func (c *RaftCluster) AttachOverloadStatus(storeID uint64, f func() bool) {
 	c.Lock()
@@ -945,6 +1090,14 @@ func (c *RaftCluster) SetStoreState(storeID uint64, state metapb.StoreState) err
 	return c.putStoreLocked(newStore)
 }
 
+// setPlacementConfig updates the placement constraints used by the checker and
+// the schedulers.
+func (c *RaftCluster) setPlacementConfig(cfg *placement.Config) {
+	c.RLock()
+	defer c.RUnlock()
+	c.coordinator.setPlacementConfig(cfg)
+}
+
 // SetStoreWeight sets up a store's leader/region balance weight.
 func (c *RaftCluster) SetStoreWeight(storeID uint64, leaderWeight, regionWeight float64) error {
 	c.Lock()
@@ -967,12 +1120,86 @@ func (c *RaftCluster) SetStoreWeight(storeID uint64, leaderWeight, regionWeight
 	return c.putStoreLocked(newStore)
 }
 
+// SetStoreMaintenance puts the store into maintenance for ttl. During the
+// maintenance, the down peers on the store are not repaired and no peer is
+// moved to the store. If evictLeader is true, the leaders are also moved
+// away from the store.
+func (c *RaftCluster) SetStoreMaintenance(storeID uint64, ttl time.Duration, evictLeader bool) error {
+	if err := c.setStoreMaintenance(storeID, ttl, evictLeader); err != nil {
+		return err
+	}
+	// Start evicting the leaders at once instead of waiting for the
+	// background check.
+	if c.coordinator != nil {
+		c.coordinator.checkMaintenanceStores()
+	}
+	return nil
+}
+
+func (c *RaftCluster) setStoreMaintenance(storeID uint64, ttl time.Duration, evictLeader bool) error {
+	c.Lock()
+	defer c.Unlock()
+
+	store := c.core.GetStore(storeID)
+	if store == nil {
+		return core.NewStoreNotFoundErr(storeID)
+	}
+	if store.IsTombstone() {
+		return errors.Errorf("store %d is tombstone", storeID)
+	}
+
+	maintenance := &core.StoreMaintenance{
+		Expire:      time.Now().Add(ttl),
+		EvictLeader: evictLeader,
+	}
+	if c.storage != nil {
+		if err := c.storage.SaveStoreMaintenance(storeID, maintenance); err != nil {
+			return err
+		}
+	}
+	log.Warn("store enters maintenance",
+		zap.Uint64("store-id", storeID),
+		zap.Time("expire", maintenance.Expire),
+		zap.Bool("evict-leader", evictLeader))
+	return c.putStoreLocked(store.Clone(core.SetStoreMaintenance(maintenance)))
+}
+
+// RemoveStoreMaintenance ends the maintenance of the store.
+func (c *RaftCluster) RemoveStoreMaintenance(storeID uint64) error {
+	c.Lock()
+	defer c.Unlock()
+
+	store := c.core.GetStore(storeID)
+	if store == nil {
+		return core.NewStoreNotFoundErr(storeID)
+	}
+	if store.GetMaintenance() == nil {
+		return nil
+	}
+
+	if c.storage != nil {
+		if err := c.storage.DeleteStoreMaintenance(storeID); err != nil {
+			return err
+		}
+	}
+	log.Warn("store leaves maintenance", zap.Uint64("store-id", storeID))
+	return c.putStoreLocked(store.Clone(core.SetStoreMaintenance(nil)))
+}
+
 func (c *RaftCluster) putStoreLocked(store *core.StoreInfo) error {
 	if c.storage != nil {
 		if err := c.storage.SaveStore(store.GetMeta()); err != nil {
 			return err
 		}
 	}
+	if old := c.core.GetStore(store.GetID()); c.coordinator != nil && (old == nil || old.GetState() != store.GetState()) {
+		c.coordinator.eventBus.Publish(&schedule.Event{
+			Time:     time.Now(),
+			Kind:     schedule.EventStoreState,
+			StoreIDs: []uint64{store.GetID()},
+			State:    store.GetState().String(),
+		})
+	}
 	c.core.PutStore(store)
 	c.storesStats.CreateRollingStoreStats(store.GetID())
 	return nil
@@ -1053,6 +1280,7 @@ func (c *RaftCluster) deleteStoreLocked(store *core.StoreInfo) error {
 	}
 	c.core.DeleteStore(store)
 	c.storesStats.RemoveRollingStoreStats(store.GetID())
+	c.capacityHistory.Remove(store.GetID())
 	return nil
 }
 
@@ -1066,7 +1294,7 @@ func (c *RaftCluster) checkOperators() {
 			log.Debug("remove operator cause region is merged",
 				zap.Uint64("region-id", op.RegionID()),
 				zap.Stringer("operator", op))
-			opController.RemoveOperator(op)
+			opController.RemoveMergedOperator(op)
 			continue
 		}
 
@@ -1079,6 +1307,107 @@ func (c *RaftCluster) checkOperators() {
 	}
 }
 
+func (c *RaftCluster) runHotRegionHistoryJob(interval time.Duration) {
+	defer logutil.LogPanic()
+	defer c.wg.Done()
+
+	if c.hotRegionHistory == nil {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.quit:
+			log.Info("hot region history job has been stopped")
+			return
+		case <-ticker.C:
+			c.saveHotRegionHistory(time.Now())
+		}
+	}
+}
+
+// saveHotRegionHistory saves a snapshot of the hot read and write regions to
+// the hot region history.
+func (c *RaftCluster) saveHotRegionHistory(now time.Time) {
+	threshold := c.GetHotRegionCacheHitsThreshold()
+	records := statistics.NewHotRegionHistoryRecords(c.RegionWriteStats(), statistics.WriteFlow, threshold, c.GetRegion, now)
+	records = append(records, statistics.NewHotRegionHistoryRecords(c.RegionReadStats(), statistics.ReadFlow, threshold, c.GetRegion, now)...)
+	c.hotRegionHistory.Put(records, now, c.opt.LoadPDServerConfig().HotRegionHistoryRetention.Duration)
+}
+
+// GetHotRegionHistory gets the hot region history, it returns nil if the hot
+// regions are not persisted.
+func (c *RaftCluster) GetHotRegionHistory() *statistics.HotRegionHistory {
+	c.RLock()
+	defer c.RUnlock()
+	return c.hotRegionHistory
+}
+
+func (c *RaftCluster) runHeatmapJob() {
+	defer logutil.LogPanic()
+	defer c.wg.Done()
+
+	for {
+		select {
+		case <-c.quit:
+			log.Info("heatmap job has been stopped")
+			return
+		case <-time.After(c.opt.LoadPDServerConfig().HeatmapBucketInterval.Duration):
+			c.rotateHeatmap(time.Now())
+		}
+	}
+}
+
+// rotateHeatmap ends the current time bucket of the heatmap with the key
+// ranges of the regions in the cluster.
+func (c *RaftCluster) rotateHeatmap(now time.Time) {
+	c.RLock()
+	regions := c.core.Regions.ScanRangeWithEndKey(nil, nil)
+	c.RUnlock()
+	c.heatmap.Rotate(regions, now)
+}
+
+// checkKeySpace audits the key space of the regions and logs the holes found.
+// The holes are expected while the regions are being loaded or reported, so
+// they are only logged when the region count is stable for a cycle, and at
+// most once per keySpaceWarnInterval.
+func (c *RaftCluster) checkKeySpace(now time.Time) {
+	report := c.AuditKeySpace()
+	stable := report.RegionCount == c.keySpaceRegionCount
+	c.keySpaceRegionCount = report.RegionCount
+	if !stable || len(report.Holes) == 0 || now.Sub(c.keySpaceWarnTime) < keySpaceWarnInterval {
+		return
+	}
+	c.keySpaceWarnTime = now
+	log.Warn("key space holes found", zap.Int("region-count", report.RegionCount), zap.Int("hole-count", len(report.Holes)))
+	for i, hole := range report.Holes {
+		if i >= maxKeySpaceWarnHoles {
+			break
+		}
+		log.Warn("key space hole",
+			zap.String("start-key", hole.StartKey),
+			zap.String("end-key", hole.EndKey),
+			zap.Uint64s("region-ids", hole.RegionIDs))
+	}
+}
+
+// AuditKeySpace finds the holes and the stale leaders in the key space of the
+// regions.
+func (c *RaftCluster) AuditKeySpace() *statistics.KeySpaceAuditReport {
+	c.RLock()
+	defer c.RUnlock()
+	return c.keySpaceAuditor.Audit(c.core.Regions, statistics.RegionStaleLeaderThreshold, time.Now())
+}
+
+// GetHeatmap gets the key-space traffic heatmap of the cluster.
+func (c *RaftCluster) GetHeatmap() *statistics.Heatmap {
+	c.RLock()
+	defer c.RUnlock()
+	return c.heatmap
+}
+
 func (c *RaftCluster) collectMetrics() {
 	statsMap := statistics.NewStoreStatisticsMap(c.opt, c.GetNamespaceClassifier())
 	stores := c.GetStores()
@@ -1265,6 +1594,11 @@ func (c *RaftCluster) GetHotRegionScheduleLimit() uint64 {
 	return c.opt.GetHotRegionScheduleLimit(namespace.DefaultNamespace)
 }
 
+// GetSplitScheduleLimit returns the limit for split schedule triggered by load.
+func (c *RaftCluster) GetSplitScheduleLimit() uint64 {
+	return c.opt.GetSplitScheduleLimit()
+}
+
 // GetStoreBalanceRate returns the balance rate of a store.
 func (c *RaftCluster) GetStoreBalanceRate() float64 {
 	return c.opt.GetStoreBalanceRate()
@@ -1330,6 +1664,20 @@ func (c *RaftCluster) GetMaxStoreDownTime() time.Duration {
 	return c.opt.GetMaxStoreDownTime()
 }
 
+// GetRegionRule returns the placement rule which the region should follow. The
+// rule built from the replication options is used if no rule covers the region.
+func (c *RaftCluster) GetRegionRule(region *core.RegionInfo) *rule.Rule {
+	if r := c.ruleManager.GetRuleForRegion(region); r != nil {
+		return r
+	}
+	return rule.NewDefaultRule(c.GetMaxReplicas(), c.GetLocationLabels())
+}
+
+// GetRuleManager returns the manager of the placement rules.
+func (c *RaftCluster) GetRuleManager() *rule.Manager {
+	return c.ruleManager
+}
+
 // GetMaxReplicas returns the number of replicas.
 func (c *RaftCluster) GetMaxReplicas() int {
 	return c.opt.GetMaxReplicas(namespace.DefaultNamespace)
@@ -1350,6 +1698,30 @@ func (c *RaftCluster) GetHotRegionCacheHitsThreshold() int {
 	return c.opt.GetHotRegionCacheHitsThreshold()
 }
 
+// GetHotRegionBalanceDimension gets the primary dimension of the hot region
+// balance.
+func (c *RaftCluster) GetHotRegionBalanceDimension() string {
+	return c.opt.GetHotRegionBalanceDimension()
+}
+
+// GetHotRegionSplitDuration returns how long a region should stay hot before
+// it is split by load.
+func (c *RaftCluster) GetHotRegionSplitDuration() time.Duration {
+	return c.opt.GetHotRegionSplitDuration()
+}
+
+// GetHotRegionSplitBytesThreshold returns the flow bytes per second above which
+// a hot region is split by load.
+func (c *RaftCluster) GetHotRegionSplitBytesThreshold() uint64 {
+	return c.opt.GetHotRegionSplitBytesThreshold()
+}
+
+// GetHotRegionSplitKeysThreshold returns the flow keys per second above which
+// a hot region is split by load.
+func (c *RaftCluster) GetHotRegionSplitKeysThreshold() uint64 {
+	return c.opt.GetHotRegionSplitKeysThreshold()
+}
+
 // IsRaftLearnerEnabled returns if raft learner is enabled.
 func (c *RaftCluster) IsRaftLearnerEnabled() bool {
 	if !c.IsFeatureSupported(RaftLearner) {
@@ -1378,6 +1750,12 @@ func (c *RaftCluster) IsRemoveExtraReplicaEnabled() bool {
 	return c.opt.IsRemoveExtraReplicaEnabled()
 }
 
+// IsSlowStoreEvictionEnabled returns if the leaders are evicted from the slow
+// stores automatically.
+func (c *RaftCluster) IsSlowStoreEvictionEnabled() bool {
+	return c.opt.IsSlowStoreEvictionEnabled()
+}
+
 // IsLocationReplacementEnabled returns if location replace is enabled.
 func (c *RaftCluster) IsLocationReplacementEnabled() bool {
 	return c.opt.IsLocationReplacementEnabled()
@@ -19,6 +19,7 @@ import (
 	"math/rand"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	. "github.com/pingcap/check"
@@ -610,6 +611,94 @@ func (s *testGetStoresSuite) BenchmarkGetStores(c *C) {
 	}
 }
 
+var _ = Suite(&testRegionHeartbeatBenchSuite{})
+
+type testRegionHeartbeatBenchSuite struct{}
+
+const (
+	benchRegionCount     = 10000
+	benchHeartbeatWorker = 16
+)
+
+// benchmarkRegionHeartbeat sends c.N heartbeats of the regions concurrently,
+// the i-th heartbeat is generated from the cached region by update.
+func (s *testRegionHeartbeatBenchSuite) benchmarkRegionHeartbeat(c *C, update func(region *core.RegionInfo, i int) *core.RegionInfo) {
+	_, opt, err := newTestScheduleConfig()
+	c.Assert(err, IsNil)
+	cluster := createTestRaftCluster(mockid.NewIDAllocator(), opt, core.NewStorage(kv.NewMemoryKV()))
+	for _, store := range newTestStores(3) {
+		c.Assert(cluster.putStoreLocked(store), IsNil)
+	}
+	regions := make([]*core.RegionInfo, 0, benchRegionCount)
+	for i := uint64(0); i < benchRegionCount; i++ {
+		peers := []*metapb.Peer{
+			{Id: i*3 + 1, StoreId: 1},
+			{Id: i*3 + 2, StoreId: 2},
+			{Id: i*3 + 3, StoreId: 3},
+		}
+		region := core.NewRegionInfo(&metapb.Region{
+			Id:          i + 1,
+			Peers:       peers,
+			StartKey:    []byte(fmt.Sprintf("%08d", i)),
+			EndKey:      []byte(fmt.Sprintf("%08d", i+1)),
+			RegionEpoch: &metapb.RegionEpoch{ConfVer: 2, Version: 2},
+		}, peers[i%3])
+		c.Assert(cluster.processRegionHeartbeat(region), IsNil)
+		regions = append(regions, region)
+	}
+	heartbeats := make([]*core.RegionInfo, c.N)
+	for i := range heartbeats {
+		heartbeats[i] = update(regions[i%len(regions)], i)
+	}
+
+	c.ResetTimer()
+	var wg sync.WaitGroup
+	next := int64(-1)
+	for w := 0; w < benchHeartbeatWorker; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := atomic.AddInt64(&next, 1); i < int64(len(heartbeats)); i = atomic.AddInt64(&next, 1) {
+				cluster.processRegionHeartbeat(heartbeats[i])
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// BenchmarkRegionHeartbeatFlow benchmarks the heartbeats which only change the
+// flows and make the regions hot, which update the hot spot cache without the
+// cluster lock.
+func (s *testRegionHeartbeatBenchSuite) BenchmarkRegionHeartbeatFlow(c *C) {
+	s.benchmarkRegionHeartbeat(c, func(region *core.RegionInfo, i int) *core.RegionInfo {
+		return region.Clone(core.SetWrittenBytes(uint64(i)<<20), core.SetReadBytes(uint64(i)<<20))
+	})
+}
+
+// BenchmarkRegionHeartbeatSize benchmarks the heartbeats which change the
+// approximate size and keys, which are applied in batches.
+func (s *testRegionHeartbeatBenchSuite) BenchmarkRegionHeartbeatSize(c *C) {
+	s.benchmarkRegionHeartbeat(c, func(region *core.RegionInfo, i int) *core.RegionInfo {
+		return region.Clone(core.SetApproximateSize(int64(i)), core.SetApproximateKeys(int64(i)))
+	})
+}
+
+// BenchmarkRegionHeartbeatPendingPeer benchmarks the heartbeats which change
+// the pending peers, which update the region tree under the cluster lock.
+func (s *testRegionHeartbeatBenchSuite) BenchmarkRegionHeartbeatPendingPeer(c *C) {
+	s.benchmarkRegionHeartbeat(c, func(region *core.RegionInfo, i int) *core.RegionInfo {
+		if i%2 == 1 {
+			return region.Clone(core.WithPendingPeers(nil))
+		}
+		for _, peer := range region.GetPeers() {
+			if peer.GetId() != region.GetLeader().GetId() {
+				return region.Clone(core.WithPendingPeers([]*metapb.Peer{peer}))
+			}
+		}
+		return region
+	})
+}
+
 func (s *testClusterSuite) TestSetScheduleOpt(c *C) {
 	var err error
 	var cleanup func()
@@ -1184,6 +1273,44 @@ func (s *testClusterInfoSuite) TestRegionHeartbeat(c *C) {
 	}
 }
 
+func (s *testClusterInfoSuite) TestRegionStatsHeartbeat(c *C) {
+	_, opt, err := newTestScheduleConfig()
+	c.Assert(err, IsNil)
+	cluster := createTestRaftCluster(mockid.NewIDAllocator(), opt, core.NewStorage(kv.NewMemoryKV()))
+	for _, store := range newTestStores(3) {
+		c.Assert(cluster.putStoreLocked(store), IsNil)
+	}
+	regions := newTestRegions(3, 3)
+	for _, region := range regions {
+		c.Assert(cluster.processRegionHeartbeat(region), IsNil)
+	}
+
+	// The size and keys are applied once the heartbeat returns.
+	var wg sync.WaitGroup
+	for i, region := range regions {
+		regions[i] = region.Clone(core.SetApproximateSize(int64(i+1)*10), core.SetApproximateKeys(int64(i+1)*100))
+		wg.Add(1)
+		go func(region *core.RegionInfo) {
+			defer wg.Done()
+			c.Assert(cluster.processRegionHeartbeat(region), IsNil)
+		}(regions[i])
+	}
+	wg.Wait()
+	checkRegions(c, cluster.core.Regions, regions)
+	c.Assert(cluster.GetStore(1).GetRegionSize(), Equals, int64(60))
+	for _, store := range cluster.core.Stores.GetStores() {
+		c.Assert(store.GetLeaderSize(), Equals, cluster.core.Regions.GetStoreLeaderRegionSize(store.GetID()))
+		c.Assert(store.GetRegionSize(), Equals, cluster.core.Regions.GetStoreRegionSize(store.GetID()))
+	}
+
+	// The region changed by another heartbeat is not overwritten.
+	origin := regions[0]
+	regions[0] = origin.Clone(core.WithIncVersion())
+	c.Assert(cluster.processRegionHeartbeat(regions[0]), IsNil)
+	cluster.applyRegionStats(map[uint64]*core.RegionInfo{origin.GetID(): origin.Clone(core.SetApproximateSize(1))})
+	checkRegions(c, cluster.core.Regions, regions)
+}
+
 func heartbeatRegions(c *C, cluster *RaftCluster, regions []*metapb.Region) {
 	// Heartbeat and check region one by one.
 	for _, region := range regions {
@@ -1216,6 +1343,32 @@ func heartbeatRegions(c *C, cluster *RaftCluster, regions []*metapb.Region) {
 	}
 }
 
+func (s *testClusterInfoSuite) TestCheckKeySpace(c *C) {
+	_, opt, err := newTestScheduleConfig()
+	c.Assert(err, IsNil)
+	cluster := createTestRaftCluster(mockid.NewIDAllocator(), opt, core.NewStorage(kv.NewMemoryKV()))
+	for _, store := range newTestStores(3) {
+		c.Assert(cluster.putStoreLocked(store), IsNil)
+	}
+	regions := newTestRegions(3, 3)
+	now := time.Now()
+
+	// The holes are not logged while the regions are being reported.
+	for _, region := range regions {
+		c.Assert(cluster.processRegionHeartbeat(region), IsNil)
+		cluster.checkKeySpace(now)
+		c.Assert(cluster.keySpaceWarnTime.IsZero(), IsTrue)
+	}
+	cluster.checkKeySpace(now)
+	c.Assert(cluster.keySpaceWarnTime, Equals, now)
+
+	// The log is rate limited.
+	cluster.checkKeySpace(now.Add(time.Minute))
+	c.Assert(cluster.keySpaceWarnTime, Equals, now)
+	cluster.checkKeySpace(now.Add(keySpaceWarnInterval))
+	c.Assert(cluster.keySpaceWarnTime, Equals, now.Add(keySpaceWarnInterval))
+}
+
 func (s *testClusterInfoSuite) TestHeartbeatSplit(c *C) {
 	_, opt, err := newTestScheduleConfig()
 	c.Assert(err, IsNil)
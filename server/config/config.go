@@ -31,6 +31,7 @@ import (
 	"github.com/pingcap/pd/pkg/typeutil"
 	"github.com/pingcap/pd/server/namespace"
 	"github.com/pingcap/pd/server/schedule"
+	"github.com/pingcap/pd/server/schedule/opt"
 	"github.com/pkg/errors"
 	"go.etcd.io/etcd/embed"
 	"go.etcd.io/etcd/pkg/transport"
@@ -198,10 +199,12 @@ const (
 
 	defaultLeaderPriorityCheckInterval = time.Minute
 
-	defaultUseRegionStorage    = true
-	defaultStrictlyMatchLabel  = false
-	defaultEnableGRPCGateway   = true
-	defaultDisableErrorVerbose = true
+	defaultUseRegionStorage          = true
+	defaultHeatmapBucketInterval     = time.Minute
+	defaultHotRegionHistoryRetention = 3 * 24 * time.Hour
+	defaultStrictlyMatchLabel        = false
+	defaultEnableGRPCGateway         = true
+	defaultDisableErrorVerbose       = true
 )
 
 func adjustString(v *string, defValue string) {
@@ -499,6 +502,20 @@ type ScheduleConfig struct {
 	// If the number of times a region hits the hot cache is greater than this
 	// threshold, it is considered a hot region.
 	HotRegionCacheHitsThreshold uint64 `toml:"hot-region-cache-hits-threshold,omitempty" json:"hot-region-cache-hits-threshold"`
+	// HotRegionBalanceDimension is the dimension of the flow, "bytes" or
+	// "keys", which the hot region scheduler balances primarily. The other
+	// dimension is kept from getting worse when moving hot regions.
+	HotRegionBalanceDimension string `toml:"hot-region-balance-dimension,omitempty" json:"hot-region-balance-dimension"`
+	// SplitScheduleLimit is the max coexist split schedules triggered by load.
+	SplitScheduleLimit uint64 `toml:"split-schedule-limit,omitempty" json:"split-schedule-limit"`
+	// HotRegionSplitDuration is how long a region should stay hot before it
+	// is split by load, it is also the minimum interval to split the same
+	// region again.
+	HotRegionSplitDuration typeutil.Duration `toml:"hot-region-split-duration,omitempty" json:"hot-region-split-duration"`
+	// HotRegionSplitBytesThreshold and HotRegionSplitKeysThreshold are the
+	// write or read flow per second above which a hot region is split by load.
+	HotRegionSplitBytesThreshold uint64 `toml:"hot-region-split-bytes-threshold,omitempty" json:"hot-region-split-bytes-threshold"`
+	HotRegionSplitKeysThreshold  uint64 `toml:"hot-region-split-keys-threshold,omitempty" json:"hot-region-split-keys-threshold"`
 	// StoreBalanceRate is the maximum of balance rate for each store.
 	StoreBalanceRate float64 `toml:"store-balance-rate,omitempty" json:"store-balance-rate"`
 	// TolerantSizeRatio is the ratio of buffer size for balance scheduler.
@@ -538,6 +555,9 @@ type ScheduleConfig struct {
 	// DisableNamespaceRelocation is the option to prevent namespace checker
 	// from moving replica to the target namespace.
 	DisableNamespaceRelocation bool `toml:"disable-namespace-relocation" json:"disable-namespace-relocation,string"`
+	// EnableSlowStoreEviction is the option to evict the leaders from the
+	// stores detected as slow by the heartbeats automatically.
+	EnableSlowStoreEviction bool `toml:"enable-slow-store-eviction" json:"enable-slow-store-eviction,string"`
 
 	// Schedulers support for loading customized schedulers
 	Schedulers SchedulerConfigs `toml:"schedulers,omitempty" json:"schedulers-v2"` // json v2 is for the sake of compatible upgrade
@@ -562,6 +582,11 @@ func (c *ScheduleConfig) Clone() *ScheduleConfig {
 		EnableOneWayMerge:            c.EnableOneWayMerge,
 		HotRegionScheduleLimit:       c.HotRegionScheduleLimit,
 		HotRegionCacheHitsThreshold:  c.HotRegionCacheHitsThreshold,
+		HotRegionBalanceDimension:    c.HotRegionBalanceDimension,
+		SplitScheduleLimit:           c.SplitScheduleLimit,
+		HotRegionSplitDuration:       c.HotRegionSplitDuration,
+		HotRegionSplitBytesThreshold: c.HotRegionSplitBytesThreshold,
+		HotRegionSplitKeysThreshold:  c.HotRegionSplitKeysThreshold,
 		StoreBalanceRate:             c.StoreBalanceRate,
 		TolerantSizeRatio:            c.TolerantSizeRatio,
 		LowSpaceRatio:                c.LowSpaceRatio,
@@ -574,6 +599,7 @@ func (c *ScheduleConfig) Clone() *ScheduleConfig {
 		DisableRemoveExtraReplica:    c.DisableRemoveExtraReplica,
 		DisableLocationReplacement:   c.DisableLocationReplacement,
 		DisableNamespaceRelocation:   c.DisableNamespaceRelocation,
+		EnableSlowStoreEviction:      c.EnableSlowStoreEviction,
 		Schedulers:                   schedulers,
 	}
 }
@@ -600,6 +626,11 @@ const (
 	// hot region.
 	defaultHotRegionCacheHitsThreshold = 3
 	defaultSchedulerMaxWaitingOperator = 3
+
+	defaultSplitScheduleLimit           = 4
+	defaultHotRegionSplitDuration       = 10 * time.Minute
+	defaultHotRegionSplitBytesThreshold = 4 * 1024 * 1024
+	defaultHotRegionSplitKeysThreshold  = 4096
 )
 
 func (c *ScheduleConfig) adjust(meta *configMetaData) error {
@@ -636,6 +667,19 @@ func (c *ScheduleConfig) adjust(meta *configMetaData) error {
 	if !meta.IsDefined("hot-region-cache-hits-threshold") {
 		adjustUint64(&c.HotRegionCacheHitsThreshold, defaultHotRegionCacheHitsThreshold)
 	}
+	if !meta.IsDefined("hot-region-balance-dimension") {
+		adjustString(&c.HotRegionBalanceDimension, opt.HotRegionBalanceByBytes)
+	}
+	if !meta.IsDefined("split-schedule-limit") {
+		adjustUint64(&c.SplitScheduleLimit, defaultSplitScheduleLimit)
+	}
+	adjustDuration(&c.HotRegionSplitDuration, defaultHotRegionSplitDuration)
+	if !meta.IsDefined("hot-region-split-bytes-threshold") {
+		adjustUint64(&c.HotRegionSplitBytesThreshold, defaultHotRegionSplitBytesThreshold)
+	}
+	if !meta.IsDefined("hot-region-split-keys-threshold") {
+		adjustUint64(&c.HotRegionSplitKeysThreshold, defaultHotRegionSplitKeysThreshold)
+	}
 	if !meta.IsDefined("tolerant-size-ratio") {
 		adjustFloat64(&c.TolerantSizeRatio, defaultTolerantSizeRatio)
 	}
@@ -646,6 +690,11 @@ func (c *ScheduleConfig) adjust(meta *configMetaData) error {
 	adjustFloat64(&c.LowSpaceRatio, defaultLowSpaceRatio)
 	adjustFloat64(&c.HighSpaceRatio, defaultHighSpaceRatio)
 	adjustSchedulers(&c.Schedulers, defaultSchedulers)
+	for _, schedulerCfg := range c.Schedulers {
+		if schedule.IsSchedulerPDManaged(schedulerCfg.Type) {
+			return errors.Errorf("scheduler %v is managed by PD and can not be configured", schedulerCfg.Type)
+		}
+	}
 
 	return c.Validate()
 }
@@ -664,6 +713,11 @@ func (c *ScheduleConfig) Validate() error {
 	if c.LowSpaceRatio <= c.HighSpaceRatio {
 		return errors.New("low-space-ratio should be larger than high-space-ratio")
 	}
+	switch c.HotRegionBalanceDimension {
+	case "", opt.HotRegionBalanceByBytes, opt.HotRegionBalanceByKeys:
+	default:
+		return errors.Errorf("hot-region-balance-dimension should be %s or %s", opt.HotRegionBalanceByBytes, opt.HotRegionBalanceByKeys)
+	}
 	for _, scheduleConfig := range c.Schedulers {
 		if !schedule.IsSchedulerRegistered(scheduleConfig.Type) {
 			return errors.Errorf("create func of %v is not registered, maybe misspelled", scheduleConfig.Type)
@@ -680,6 +734,11 @@ type SchedulerConfig struct {
 	Type    string   `toml:"type" json:"type"`
 	Args    []string `toml:"args,omitempty" json:"args"`
 	Disable bool     `toml:"disable" json:"disable"`
+	// Paused means the scheduler is created but does not schedule until
+	// PauseExpireTime, which is a unix timestamp in seconds. A zero
+	// PauseExpireTime means the scheduler is paused until it is resumed.
+	Paused          bool  `toml:"paused,omitempty" json:"paused,omitempty"`
+	PauseExpireTime int64 `toml:"pause-expire-time,omitempty" json:"pause-expire-time,omitempty"`
 }
 
 var defaultSchedulers = SchedulerConfigs{
@@ -799,12 +858,20 @@ func (s SecurityConfig) ToTLSConfig() (*tls.Config, error) {
 type PDServerConfig struct {
 	// UseRegionStorage enables the independent region storage.
 	UseRegionStorage bool `toml:"use-region-storage" json:"use-region-storage,string"`
+	// HeatmapBucketInterval is the length of the time buckets of the key-space
+	// traffic heatmap.
+	HeatmapBucketInterval typeutil.Duration `toml:"heatmap-bucket-interval" json:"heatmap-bucket-interval"`
+	// HotRegionHistoryRetention is how long the snapshots of the hot regions
+	// are kept in the hot region history.
+	HotRegionHistoryRetention typeutil.Duration `toml:"hot-region-history-retention" json:"hot-region-history-retention"`
 }
 
 func (c *PDServerConfig) adjust(meta *configMetaData) error {
 	if !meta.IsDefined("use-region-storage") {
 		c.UseRegionStorage = defaultUseRegionStorage
 	}
+	adjustDuration(&c.HeatmapBucketInterval, defaultHeatmapBucketInterval)
+	adjustDuration(&c.HotRegionHistoryRetention, defaultHotRegionHistoryRetention)
 	return nil
 }
 
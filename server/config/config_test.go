@@ -78,6 +78,32 @@ func (s *testConfigSuite) TestReloadConfig(c *C) {
 	c.Assert(newOpt.GetMaxSnapshotCount(), Equals, uint64(10))
 }
 
+func (s *testConfigSuite) TestSchedulerPaused(c *C) {
+	opt, err := newTestScheduleOption()
+	c.Assert(err, IsNil)
+	opt.AddSchedulerCfg("evict-leader", []string{"1"})
+	c.Assert(opt.SetSchedulerPaused("evict-leader-scheduler-1", true, 100), IsNil)
+	c.Assert(opt.SetSchedulerPaused("evict-leader-scheduler-2", true, 100), NotNil)
+	cfg := opt.GetSchedulers()[len(opt.GetSchedulers())-1]
+	c.Assert(cfg.Paused, IsTrue)
+	c.Assert(cfg.PauseExpireTime, Equals, int64(100))
+
+	// The pause state is cleared when the scheduler is removed.
+	c.Assert(opt.SetSchedulerPaused("balance-leader-scheduler", true, 0), IsNil)
+	c.Assert(opt.RemoveSchedulerCfg("balance-leader-scheduler"), IsNil)
+	for _, cfg := range opt.GetSchedulers() {
+		if cfg.Type == "balance-leader" {
+			c.Assert(cfg.Disable, IsTrue)
+			c.Assert(cfg.Paused, IsFalse)
+		}
+	}
+	// A scheduler is added without the stale pause state.
+	opt.AddSchedulerCfg("evict-leader", []string{"1"})
+	cfg = opt.GetSchedulers()[len(opt.GetSchedulers())-1]
+	c.Assert(cfg.Paused, IsFalse)
+	c.Assert(cfg.PauseExpireTime, Equals, int64(0))
+}
+
 func (s *testConfigSuite) TestValidation(c *C) {
 	cfg := NewConfig()
 	c.Assert(cfg.Adjust(nil), IsNil)
@@ -98,6 +124,12 @@ func (s *testConfigSuite) TestValidation(c *C) {
 	c.Assert(cfg.Schedule.Validate(), IsNil)
 	cfg.Schedule.TolerantSizeRatio = -0.6
 	c.Assert(cfg.Schedule.Validate(), NotNil)
+	cfg.Schedule.TolerantSizeRatio = 0
+	c.Assert(cfg.Schedule.HotRegionBalanceDimension, Equals, "bytes")
+	cfg.Schedule.HotRegionBalanceDimension = "keys"
+	c.Assert(cfg.Schedule.Validate(), IsNil)
+	cfg.Schedule.HotRegionBalanceDimension = "qps"
+	c.Assert(cfg.Schedule.Validate(), NotNil)
 }
 
 func (s *testConfigSuite) TestAdjust(c *C) {
@@ -173,6 +205,21 @@ type = "random-merge"
 	err = cfg.Adjust(&meta)
 	c.Assert(err, IsNil)
 
+	// Check the schedulers managed by PD
+	cfgData = `
+name = ""
+lease = 0
+
+[[schedule.schedulers]]
+type = "evict-slow-store"
+args = ["1"]
+`
+	cfg = NewConfig()
+	meta, err = toml.Decode(cfgData, &cfg)
+	c.Assert(err, IsNil)
+	err = cfg.Adjust(&meta)
+	c.Assert(err, NotNil)
+
 	cfgData = `
 [metric]
 interval = "35s"
@@ -23,6 +23,8 @@ import (
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/pd/server/core"
 	"github.com/pingcap/pd/server/schedule"
+	"github.com/pingcap/pd/server/schedule/opt"
+	"github.com/pkg/errors"
 )
 
 // ScheduleOption is a wrapper to access the configuration safely.
@@ -209,6 +211,11 @@ func (o *ScheduleOption) GetHotRegionScheduleLimit(name string) uint64 {
 	return o.Load().HotRegionScheduleLimit
 }
 
+// GetSplitScheduleLimit returns the limit for split schedule triggered by load.
+func (o *ScheduleOption) GetSplitScheduleLimit() uint64 {
+	return o.Load().SplitScheduleLimit
+}
+
 // GetStoreBalanceRate returns the balance rate of a store.
 func (o *ScheduleOption) GetStoreBalanceRate() float64 {
 	return o.Load().StoreBalanceRate
@@ -264,6 +271,12 @@ func (o *ScheduleOption) IsLocationReplacementEnabled() bool {
 	return !o.Load().DisableLocationReplacement
 }
 
+// IsSlowStoreEvictionEnabled returns if the leaders are evicted from the slow
+// stores automatically.
+func (o *ScheduleOption) IsSlowStoreEvictionEnabled() bool {
+	return o.Load().EnableSlowStoreEviction
+}
+
 // IsNamespaceRelocationEnabled returns if namespace relocation is enabled.
 func (o *ScheduleOption) IsNamespaceRelocationEnabled() bool {
 	return !o.Load().DisableNamespaceRelocation
@@ -282,16 +295,16 @@ func (o *ScheduleOption) AddSchedulerCfg(tp string, args []string) {
 		// comparing args is to cover the case that there are schedulers in same type but not with same name
 		// such as two schedulers of type "evict-leader",
 		// one name is "evict-leader-scheduler-1" and the other is "evict-leader-scheduler-2"
-		if reflect.DeepEqual(schedulerCfg, SchedulerConfig{Type: tp, Args: args, Disable: false}) {
-			return
+		if schedulerCfg.Type != tp || !reflect.DeepEqual(schedulerCfg.Args, args) {
+			continue
 		}
-
-		if reflect.DeepEqual(schedulerCfg, SchedulerConfig{Type: tp, Args: args, Disable: true}) {
+		if schedulerCfg.Disable || schedulerCfg.Paused {
 			schedulerCfg.Disable = false
+			schedulerCfg.Paused, schedulerCfg.PauseExpireTime = false, 0
 			v.Schedulers[i] = schedulerCfg
 			o.Store(v)
-			return
 		}
+		return
 	}
 	v.Schedulers = append(v.Schedulers, SchedulerConfig{Type: tp, Args: args, Disable: false})
 	o.Store(v)
@@ -310,6 +323,7 @@ func (o *ScheduleOption) RemoveSchedulerCfg(name string) error {
 		if tmp.GetName() == name {
 			if IsDefaultScheduler(tmp.GetType()) {
 				schedulerCfg.Disable = true
+				schedulerCfg.Paused, schedulerCfg.PauseExpireTime = false, 0
 				v.Schedulers[i] = schedulerCfg
 			} else {
 				v.Schedulers = append(v.Schedulers[:i], v.Schedulers[i+1:]...)
@@ -321,6 +335,30 @@ func (o *ScheduleOption) RemoveSchedulerCfg(name string) error {
 	return nil
 }
 
+// SetSchedulerPaused updates the pause state of the scheduler configuration,
+// it fails if the scheduler is not found.
+func (o *ScheduleOption) SetSchedulerPaused(name string, paused bool, expireTime int64) error {
+	c := o.Load()
+	v := c.Clone()
+	for i, schedulerCfg := range v.Schedulers {
+		// To create a temporary scheduler is just used to get scheduler's name
+		tmp, err := schedule.CreateScheduler(schedulerCfg.Type, schedule.NewOperatorController(nil, nil), schedulerCfg.Args...)
+		if err != nil {
+			return err
+		}
+		if tmp.GetName() == name {
+			schedulerCfg.Paused, schedulerCfg.PauseExpireTime = paused, expireTime
+			if !paused {
+				schedulerCfg.PauseExpireTime = 0
+			}
+			v.Schedulers[i] = schedulerCfg
+			o.Store(v)
+			return nil
+		}
+	}
+	return errors.Errorf("scheduler %s not found", name)
+}
+
 // SetLabelProperty sets the label property.
 func (o *ScheduleOption) SetLabelProperty(typ, labelKey, labelValue string) {
 	cfg := o.LoadLabelPropertyConfig().Clone()
@@ -423,6 +461,8 @@ func (o *ScheduleOption) adjustScheduleCfg(persistentCfg *Config) {
 		for _, ps := range persistentCfg.Schedule.Schedulers {
 			if s.Type == ps.Type && reflect.DeepEqual(s.Args, ps.Args) {
 				scheduleCfg.Schedulers[i].Disable = ps.Disable
+				scheduleCfg.Schedulers[i].Paused = ps.Paused
+				scheduleCfg.Schedulers[i].PauseExpireTime = ps.PauseExpireTime
 				break
 			}
 		}
@@ -450,6 +490,33 @@ func (o *ScheduleOption) GetHotRegionCacheHitsThreshold() int {
 	return int(o.Load().HotRegionCacheHitsThreshold)
 }
 
+// GetHotRegionBalanceDimension returns the dimension of the flow which the hot
+// region scheduler balances primarily.
+func (o *ScheduleOption) GetHotRegionBalanceDimension() string {
+	if dim := o.Load().HotRegionBalanceDimension; dim != "" {
+		return dim
+	}
+	return opt.HotRegionBalanceByBytes
+}
+
+// GetHotRegionSplitDuration returns how long a region should stay hot before
+// it is split by load.
+func (o *ScheduleOption) GetHotRegionSplitDuration() time.Duration {
+	return o.Load().HotRegionSplitDuration.Duration
+}
+
+// GetHotRegionSplitBytesThreshold returns the flow bytes per second above which
+// a hot region is split by load.
+func (o *ScheduleOption) GetHotRegionSplitBytesThreshold() uint64 {
+	return o.Load().HotRegionSplitBytesThreshold
+}
+
+// GetHotRegionSplitKeysThreshold returns the flow keys per second above which
+// a hot region is split by load.
+func (o *ScheduleOption) GetHotRegionSplitKeysThreshold() uint64 {
+	return o.Load().HotRegionSplitKeysThreshold
+}
+
 // CheckLabelProperty checks the label property.
 func (o *ScheduleOption) CheckLabelProperty(typ string, labels []*metapb.StoreLabel) bool {
 	pc := o.labelProperty.Load().(LabelPropertyConfig)
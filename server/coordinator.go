@@ -16,7 +16,12 @@ package server
 import (
 	"context"
 	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pingcap/log"
@@ -24,8 +29,10 @@ import (
 	"github.com/pingcap/pd/server/checker"
 	"github.com/pingcap/pd/server/core"
 	"github.com/pingcap/pd/server/namespace"
+	"github.com/pingcap/pd/server/placement"
 	"github.com/pingcap/pd/server/schedule"
 	"github.com/pingcap/pd/server/schedule/operator"
+	"github.com/pingcap/pd/server/schedule/storelimit"
 	"github.com/pingcap/pd/server/statistics"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
@@ -41,11 +48,19 @@ const (
 	hotRegionScheduleName      = "balance-hot-region-scheduler"
 
 	patrolScanRegionLimit = 128 // It takes about 14 minutes to iterate 1 million regions.
+
+	slowStoreSchedulerType = "evict-slow-store"
+	// maxSlowStoreEvictions is the max number of the slow stores whose leaders
+	// are evicted at the same time.
+	maxSlowStoreEvictions = 1
+
+	maintenanceSchedulerType = "evict-maintenance-store"
 )
 
 var (
 	errSchedulerExisted  = errors.New("scheduler existed")
 	errSchedulerNotFound = errors.New("scheduler not found")
+	errNoProgress        = errors.New("scheduler does not report progress")
 )
 
 // coordinator is used to manage all schedulers and checkers to decide if the region needs to be scheduled.
@@ -61,29 +76,45 @@ type coordinator struct {
 	replicaChecker   *checker.ReplicaChecker
 	namespaceChecker *checker.NamespaceChecker
 	mergeChecker     *checker.MergeChecker
+	placementChecker *checker.PlacementChecker
 	regionScatterer  *schedule.RegionScatterer
 	schedulers       map[string]*scheduleController
 	opController     *schedule.OperatorController
 	classifier       namespace.Classifier
 	hbStreams        *heartbeatStreams
+	eventBus         *schedule.EventBus
+
+	// evictMu serializes the checks of the PD-managed evict schedulers.
+	evictMu sync.Mutex
 }
 
 // newCoordinator creates a new coordinator.
 func newCoordinator(cluster *RaftCluster, hbStreams *heartbeatStreams, classifier namespace.Classifier) *coordinator {
 	ctx, cancel := context.WithCancel(context.Background())
+	eventBus := schedule.NewEventBus()
+	opController := schedule.NewOperatorController(cluster, hbStreams)
+	opController.SetEventBus(eventBus)
+	replicaChecker := checker.NewReplicaChecker(cluster, classifier)
+	replicaChecker.SetOperatorController(opController)
+	placementChecker := checker.NewPlacementChecker(cluster, classifier)
+	placementChecker.SetOperatorController(opController)
+	regionScatterer := schedule.NewRegionScatterer(cluster, classifier)
+	regionScatterer.SetOperatorController(opController)
 	return &coordinator{
 		ctx:              ctx,
 		cancel:           cancel,
 		cluster:          cluster,
-		learnerChecker:   checker.NewLearnerChecker(),
-		replicaChecker:   checker.NewReplicaChecker(cluster, classifier),
+		learnerChecker:   checker.NewLearnerChecker(cluster),
+		replicaChecker:   replicaChecker,
 		namespaceChecker: checker.NewNamespaceChecker(cluster, classifier),
 		mergeChecker:     checker.NewMergeChecker(cluster, classifier),
-		regionScatterer:  schedule.NewRegionScatterer(cluster, classifier),
+		placementChecker: placementChecker,
+		regionScatterer:  regionScatterer,
 		schedulers:       make(map[string]*scheduleController),
-		opController:     schedule.NewOperatorController(cluster, hbStreams),
+		opController:     opController,
 		classifier:       classifier,
 		hbStreams:        hbStreams,
+		eventBus:         eventBus,
 	}
 }
 
@@ -182,6 +213,11 @@ func (c *coordinator) checkRegion(region *core.RegionInfo) bool {
 				return true
 			}
 		}
+		if op := c.placementChecker.Check(region); op != nil {
+			if opController.AddWaitingOperator(op) {
+				return true
+			}
+		}
 	}
 	if c.cluster.IsFeatureSupported(RegionMerge) && opController.OperatorCount(operator.OpMerge) < c.cluster.GetMergeScheduleLimit() {
 		if ops := c.mergeChecker.Check(region); ops != nil {
@@ -194,6 +230,58 @@ func (c *coordinator) checkRegion(region *core.RegionInfo) bool {
 	return false
 }
 
+// dryRunCheckers are the copies of the checkers running on a dry run cluster,
+// which create the operators without allocating IDs or changing the metrics.
+type dryRunCheckers struct {
+	learnerChecker   *checker.LearnerChecker
+	namespaceChecker *checker.NamespaceChecker
+	replicaChecker   *checker.ReplicaChecker
+	placementChecker *checker.PlacementChecker
+	mergeChecker     *checker.MergeChecker
+}
+
+func (c *coordinator) newDryRunCheckers() *dryRunCheckers {
+	cluster := schedule.NewDryRunCluster(c.cluster)
+	return &dryRunCheckers{
+		learnerChecker:   c.learnerChecker.DryRun(cluster),
+		namespaceChecker: c.namespaceChecker.DryRun(cluster),
+		replicaChecker:   c.replicaChecker.DryRun(cluster),
+		placementChecker: c.placementChecker.DryRun(cluster),
+		mergeChecker:     c.mergeChecker.DryRun(cluster),
+	}
+}
+
+// previewOperators returns the operators the checkers would create for the
+// region next. Unlike checkRegion, it ignores the schedule limits and never
+// adds the operators to the operator controller. The checkers run in dry run
+// mode, so the peer IDs of the operators are placeholders.
+func (c *coordinator) previewOperators(region *core.RegionInfo) []*operator.Operator {
+	checkers := c.newDryRunCheckers()
+	if op := checkers.learnerChecker.Check(region); op != nil {
+		return []*operator.Operator{op}
+	}
+	if op := checkers.namespaceChecker.Check(region); op != nil {
+		return []*operator.Operator{op}
+	}
+	if op := checkers.replicaChecker.Check(region); op != nil {
+		return []*operator.Operator{op}
+	}
+	if op := checkers.placementChecker.Check(region); op != nil {
+		return []*operator.Operator{op}
+	}
+	if c.cluster.IsFeatureSupported(RegionMerge) {
+		return checkers.mergeChecker.Check(region)
+	}
+	return nil
+}
+
+// setPlacementConfig updates the placement constraints followed by the
+// placement checker and the schedulers.
+func (c *coordinator) setPlacementConfig(cfg *placement.Config) {
+	c.placementChecker.SetConfig(cfg)
+	c.opController.SetPlacementConfig(cfg)
+}
+
 func (c *coordinator) run() {
 	ticker := time.NewTicker(runSchedulerCheckInterval)
 	defer ticker.Stop()
@@ -211,6 +299,7 @@ func (c *coordinator) run() {
 		}
 	}
 	log.Info("coordinator starts to run schedulers")
+	c.loadStoreLimits()
 
 	k := 0
 	scheduleCfg := c.cluster.opt.Load().Clone()
@@ -229,6 +318,15 @@ func (c *coordinator) run() {
 		log.Info("create scheduler", zap.String("scheduler-name", s.GetName()))
 		if err = c.addScheduler(s, schedulerCfg.Args...); err != nil {
 			log.Error("can not add scheduler", zap.String("scheduler-name", s.GetName()), zap.Error(err))
+		} else if schedulerCfg.Paused {
+			var expire time.Time
+			if schedulerCfg.PauseExpireTime > 0 {
+				expire = time.Unix(schedulerCfg.PauseExpireTime, 0)
+			}
+			c.RLock()
+			c.schedulers[s.GetName()].Pause(expire)
+			c.RUnlock()
+			log.Info("scheduler is paused", zap.String("scheduler-name", s.GetName()), zap.Int64("expire-time", schedulerCfg.PauseExpireTime))
 		}
 
 		// Only records the valid scheduler config.
@@ -251,8 +349,25 @@ func (c *coordinator) run() {
 	go c.drivePushOperator()
 }
 
+// loadStoreLimits loads the store limits saved in storage, so the limits are
+// kept after the leader changes.
+func (c *coordinator) loadStoreLimits() {
+	err := c.cluster.storage.LoadStoreLimits(func(storeID uint64, typeName string, rate float64) {
+		limitType, err := storelimit.ParseType(typeName)
+		if err != nil {
+			log.Warn("skip unknown store limit", zap.Uint64("store-id", storeID), zap.String("type", typeName))
+			return
+		}
+		c.opController.SetStoreLimit(storeID, rate, limitType)
+	})
+	if err != nil {
+		log.Error("can not load store limits", zap.Error(err))
+	}
+}
+
 func (c *coordinator) stop() {
 	c.cancel()
+	c.eventBus.Close()
 }
 
 // Hack to retrieve info from scheduler.
@@ -299,6 +414,19 @@ func (c *coordinator) getSchedulers() []string {
 	return names
 }
 
+func (c *coordinator) getPausedSchedulers() []string {
+	c.RLock()
+	defer c.RUnlock()
+
+	names := make([]string, 0, len(c.schedulers))
+	for name, s := range c.schedulers {
+		if s.IsPaused() {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
 func (c *coordinator) collectSchedulerMetrics() {
 	c.RLock()
 	defer c.RUnlock()
@@ -330,24 +458,30 @@ func (c *coordinator) collectHotSpotMetrics() {
 		stat, ok := status.AsPeer[storeID]
 		if ok {
 			totalWriteBytes := float64(stat.TotalFlowBytes)
+			totalWriteKeys := float64(stat.TotalFlowKeys)
 			hotWriteRegionCount := float64(stat.RegionsCount)
 
 			hotSpotStatusGauge.WithLabelValues(storeAddress, storeLabel, "total_written_bytes_as_peer").Set(totalWriteBytes)
+			hotSpotStatusGauge.WithLabelValues(storeAddress, storeLabel, "total_written_keys_as_peer").Set(totalWriteKeys)
 			hotSpotStatusGauge.WithLabelValues(storeAddress, storeLabel, "hot_write_region_as_peer").Set(hotWriteRegionCount)
 		} else {
 			hotSpotStatusGauge.WithLabelValues(storeAddress, storeLabel, "total_written_bytes_as_peer").Set(0)
+			hotSpotStatusGauge.WithLabelValues(storeAddress, storeLabel, "total_written_keys_as_peer").Set(0)
 			hotSpotStatusGauge.WithLabelValues(storeAddress, storeLabel, "hot_write_region_as_peer").Set(0)
 		}
 
 		stat, ok = status.AsLeader[storeID]
 		if ok {
 			totalWriteBytes := float64(stat.TotalFlowBytes)
+			totalWriteKeys := float64(stat.TotalFlowKeys)
 			hotWriteRegionCount := float64(stat.RegionsCount)
 
 			hotSpotStatusGauge.WithLabelValues(storeAddress, storeLabel, "total_written_bytes_as_leader").Set(totalWriteBytes)
+			hotSpotStatusGauge.WithLabelValues(storeAddress, storeLabel, "total_written_keys_as_leader").Set(totalWriteKeys)
 			hotSpotStatusGauge.WithLabelValues(storeAddress, storeLabel, "hot_write_region_as_leader").Set(hotWriteRegionCount)
 		} else {
 			hotSpotStatusGauge.WithLabelValues(storeAddress, storeLabel, "total_written_bytes_as_leader").Set(0)
+			hotSpotStatusGauge.WithLabelValues(storeAddress, storeLabel, "total_written_keys_as_leader").Set(0)
 			hotSpotStatusGauge.WithLabelValues(storeAddress, storeLabel, "hot_write_region_as_leader").Set(0)
 		}
 	}
@@ -361,12 +495,15 @@ func (c *coordinator) collectHotSpotMetrics() {
 		stat, ok := status.AsLeader[storeID]
 		if ok {
 			totalReadBytes := float64(stat.TotalFlowBytes)
+			totalReadKeys := float64(stat.TotalFlowKeys)
 			hotReadRegionCount := float64(stat.RegionsCount)
 
 			hotSpotStatusGauge.WithLabelValues(storeAddress, storeLabel, "total_read_bytes_as_leader").Set(totalReadBytes)
+			hotSpotStatusGauge.WithLabelValues(storeAddress, storeLabel, "total_read_keys_as_leader").Set(totalReadKeys)
 			hotSpotStatusGauge.WithLabelValues(storeAddress, storeLabel, "hot_read_region_as_leader").Set(hotReadRegionCount)
 		} else {
 			hotSpotStatusGauge.WithLabelValues(storeAddress, storeLabel, "total_read_bytes_as_leader").Set(0)
+			hotSpotStatusGauge.WithLabelValues(storeAddress, storeLabel, "total_read_keys_as_leader").Set(0)
 			hotSpotStatusGauge.WithLabelValues(storeAddress, storeLabel, "hot_read_region_as_leader").Set(0)
 		}
 	}
@@ -394,6 +531,11 @@ func (c *coordinator) addScheduler(scheduler schedule.Scheduler, args ...string)
 	go c.runScheduler(s)
 	c.schedulers[s.GetName()] = s
 	c.cluster.opt.AddSchedulerCfg(s.GetType(), args)
+	c.eventBus.Publish(&schedule.Event{
+		Time:      time.Now(),
+		Kind:      schedule.EventSchedulerAdd,
+		Scheduler: s.GetName(),
+	})
 
 	return nil
 }
@@ -410,10 +552,268 @@ func (c *coordinator) removeScheduler(name string) error {
 	s.Stop()
 	schedulerStatusGauge.WithLabelValues(name, "allow").Set(0)
 	delete(c.schedulers, name)
+	c.eventBus.Publish(&schedule.Event{
+		Time:      time.Now(),
+		Kind:      schedule.EventSchedulerRemove,
+		Scheduler: name,
+	})
 
 	return c.cluster.opt.RemoveSchedulerCfg(name)
 }
 
+// getEvictSchedulers returns the PD-managed evict schedulers of the type by
+// the IDs of the stores they evict the leaders from.
+func (c *coordinator) getEvictSchedulers(tp string) map[uint64]string {
+	prefix := tp + "-scheduler-"
+	schedulers := make(map[uint64]string)
+	for _, name := range c.getSchedulers() {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		storeID, err := strconv.ParseUint(strings.TrimPrefix(name, prefix), 10, 64)
+		if err != nil {
+			continue
+		}
+		schedulers[storeID] = name
+	}
+	return schedulers
+}
+
+// addEvictScheduler adds a PD-managed evict scheduler of the type to evict
+// the leaders from the store.
+func (c *coordinator) addEvictScheduler(tp string, storeID uint64) error {
+	args := []string{strconv.FormatUint(storeID, 10)}
+	s, err := schedule.CreateScheduler(tp, c.opController, args...)
+	if err != nil {
+		return err
+	}
+	return c.addScheduler(s, args...)
+}
+
+// persistEvictSchedulers persists the scheduler config after the PD-managed
+// evict schedulers are changed.
+func (c *coordinator) persistEvictSchedulers() {
+	if err := c.cluster.opt.Persist(c.cluster.storage); err != nil {
+		log.Error("can not persist scheduler config", zap.Error(err))
+	}
+}
+
+// checkSlowStores adds an evict-slow-store scheduler for the store whose
+// slowness score reaches the threshold, and removes the scheduler once the
+// store recovers, the store is removed or the slow-store eviction is
+// disabled.
+func (c *coordinator) checkSlowStores() {
+	c.evictMu.Lock()
+	defer c.evictMu.Unlock()
+
+	enabled := c.cluster.IsSlowStoreEvictionEnabled()
+	evicting := make(map[uint64]struct{})
+	changed := false
+	for storeID, name := range c.getEvictSchedulers(slowStoreSchedulerType) {
+		store := c.cluster.GetStore(storeID)
+		// The score is 0 before the baseline is ready, e.g. after the PD
+		// leader changes, the eviction is kept until the store is scored.
+		score := c.cluster.GetStoreSlowScore(storeID)
+		if enabled && store != nil && !store.IsTombstone() && (score == 0 || score >= statistics.SlowStoreRecoverScore) {
+			evicting[storeID] = struct{}{}
+			continue
+		}
+		if err := c.removeScheduler(name); err != nil {
+			log.Error("can not remove scheduler", zap.String("scheduler-name", name), zap.Error(err))
+			continue
+		}
+		log.Info("stop evicting leaders from slow store", zap.Uint64("store-id", storeID), zap.Float64("slow-score", score))
+		changed = true
+	}
+	if enabled {
+		// The slowest stores are evicted first.
+		stores := c.cluster.GetStores()
+		scores := make(map[uint64]float64, len(stores))
+		for _, store := range stores {
+			scores[store.GetID()] = c.cluster.GetStoreSlowScore(store.GetID())
+		}
+		sort.Slice(stores, func(i, j int) bool {
+			return scores[stores[i].GetID()] > scores[stores[j].GetID()]
+		})
+		for _, store := range stores {
+			if len(evicting) >= maxSlowStoreEvictions {
+				break
+			}
+			storeID, score := store.GetID(), scores[store.GetID()]
+			if _, ok := evicting[storeID]; ok || !store.IsUp() || score < statistics.SlowStoreScoreThreshold {
+				continue
+			}
+			// The store is already blocked by another scheduler, e.g. an
+			// evict-leader scheduler added by the user, or its regions are
+			// being evicted.
+			if store.IsBlocked() || store.IsEvictingRegions() {
+				continue
+			}
+			if err := c.addEvictScheduler(slowStoreSchedulerType, storeID); err != nil {
+				log.Error("can not add scheduler", zap.String("scheduler-type", slowStoreSchedulerType), zap.Uint64("store-id", storeID), zap.Error(err))
+				continue
+			}
+			log.Warn("start evicting leaders from slow store", zap.Uint64("store-id", storeID), zap.Float64("slow-score", score))
+			evicting[storeID] = struct{}{}
+			changed = true
+		}
+	}
+	if changed {
+		c.persistEvictSchedulers()
+	}
+}
+
+// checkMaintenanceStores ends the expired maintenance of the stores, and
+// keeps an evict-maintenance-store scheduler for each store in the
+// maintenance which evicts the leaders. A store whose leaders are already
+// evicted by another scheduler, e.g. an evict-leader scheduler added by the
+// user, does not need one.
+func (c *coordinator) checkMaintenanceStores() {
+	c.evictMu.Lock()
+	defer c.evictMu.Unlock()
+
+	for _, store := range c.cluster.GetStores() {
+		if store.GetMaintenance() == nil || store.IsInMaintenance() {
+			continue
+		}
+		if err := c.cluster.RemoveStoreMaintenance(store.GetID()); err != nil {
+			log.Error("can not end store maintenance", zap.Uint64("store-id", store.GetID()), zap.Error(err))
+		}
+	}
+
+	evicting := make(map[uint64]struct{})
+	changed := false
+	for storeID, name := range c.getEvictSchedulers(maintenanceSchedulerType) {
+		store := c.cluster.GetStore(storeID)
+		if store != nil && !store.IsTombstone() && store.IsInMaintenance() && store.GetMaintenance().EvictLeader {
+			evicting[storeID] = struct{}{}
+			continue
+		}
+		if err := c.removeScheduler(name); err != nil {
+			log.Error("can not remove scheduler", zap.String("scheduler-name", name), zap.Error(err))
+			continue
+		}
+		log.Info("stop evicting leaders from maintenance store", zap.Uint64("store-id", storeID))
+		changed = true
+	}
+	for _, store := range c.cluster.GetStores() {
+		storeID := store.GetID()
+		if _, ok := evicting[storeID]; ok || store.IsTombstone() || !store.IsInMaintenance() || !store.GetMaintenance().EvictLeader {
+			continue
+		}
+		if store.IsBlocked() {
+			continue
+		}
+		if err := c.addEvictScheduler(maintenanceSchedulerType, storeID); err != nil {
+			log.Error("can not add scheduler", zap.String("scheduler-type", maintenanceSchedulerType), zap.Uint64("store-id", storeID), zap.Error(err))
+			continue
+		}
+		log.Info("start evicting leaders from maintenance store", zap.Uint64("store-id", storeID))
+		changed = true
+	}
+	if changed {
+		c.persistEvictSchedulers()
+	}
+}
+
+// pauseScheduler pauses the scheduler for the duration, a zero duration means
+// the scheduler is paused until it is resumed.
+func (c *coordinator) pauseScheduler(name string, d time.Duration) error {
+	c.Lock()
+	defer c.Unlock()
+
+	s, ok := c.schedulers[name]
+	if !ok {
+		return errSchedulerNotFound
+	}
+	var expire time.Time
+	if d > 0 {
+		expire = time.Now().Add(d)
+	}
+	var expireTime int64
+	if !expire.IsZero() {
+		expireTime = expire.Unix()
+	}
+	if err := c.cluster.opt.SetSchedulerPaused(name, true, expireTime); err != nil {
+		return err
+	}
+	s.Pause(expire)
+	return nil
+}
+
+func (c *coordinator) resumeScheduler(name string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	s, ok := c.schedulers[name]
+	if !ok {
+		return errSchedulerNotFound
+	}
+	if err := c.cluster.opt.SetSchedulerPaused(name, false, 0); err != nil {
+		return err
+	}
+	s.Resume()
+	return nil
+}
+
+func (c *coordinator) getSchedulerProgress(name string) (*schedule.Progress, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	s, ok := c.schedulers[name]
+	if !ok {
+		return nil, errSchedulerNotFound
+	}
+	reporter, ok := s.Scheduler.(schedule.ProgressReporter)
+	if !ok {
+		return nil, errNoProgress
+	}
+	return reporter.GetProgress(c.cluster), nil
+}
+
+// SchedulerDiagnosis tells why a scheduler creates no operator. A scheduler
+// is blocked if it is paused or not allowed to schedule, otherwise Trace
+// tells which stores are rejected by the filters and how the candidates are
+// evaluated, if the scheduler traces its decisions.
+type SchedulerDiagnosis struct {
+	Name   string `json:"name"`
+	Paused bool   `json:"paused"`
+	// ScheduleAllowed is the current result of IsScheduleAllowed, and
+	// NotAllowedCount is the number of rounds skipped as it returns false.
+	ScheduleAllowed  bool                    `json:"schedule_allowed"`
+	NotAllowedCount  uint64                  `json:"not_allowed_count"`
+	ScheduleCount    uint64                  `json:"schedule_count"`
+	OperatorCount    uint64                  `json:"operator_count"`
+	LastOperatorTime *time.Time              `json:"last_operator_time,omitempty"`
+	Trace            *schedule.DecisionTrace `json:"trace,omitempty"`
+}
+
+func (c *coordinator) getSchedulerDiagnosis(name string) (*SchedulerDiagnosis, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	s, ok := c.schedulers[name]
+	if !ok {
+		return nil, errSchedulerNotFound
+	}
+	diagnosis := &SchedulerDiagnosis{
+		Name:            name,
+		Paused:          s.IsPaused(),
+		ScheduleAllowed: s.Scheduler.IsScheduleAllowed(c.cluster),
+		NotAllowedCount: atomic.LoadUint64(&s.notAllowedCount),
+		ScheduleCount:   atomic.LoadUint64(&s.scheduleCount),
+		OperatorCount:   atomic.LoadUint64(&s.operatorCount),
+	}
+	if t := atomic.LoadInt64(&s.lastOperatorTime); t != 0 {
+		lastOperatorTime := time.Unix(0, t)
+		diagnosis.LastOperatorTime = &lastOperatorTime
+	}
+	if traced, ok := s.Scheduler.(schedule.DecisionTraced); ok {
+		diagnosis.Trace = traced.GetDecisionTracer().GetTrace()
+	}
+	return diagnosis, nil
+}
+
 func (c *coordinator) runScheduler(s *scheduleController) {
 	defer logutil.LogPanic()
 	defer c.wg.Done()
@@ -427,6 +827,9 @@ func (c *coordinator) runScheduler(s *scheduleController) {
 		case <-timer.C:
 			timer.Reset(s.GetInterval())
 			if !s.AllowSchedule() {
+				if !s.IsPaused() {
+					atomic.AddUint64(&s.notAllowedCount, 1)
+				}
 				continue
 			}
 			if op := s.Schedule(); op != nil {
@@ -451,6 +854,14 @@ type scheduleController struct {
 	nextInterval time.Duration
 	ctx          context.Context
 	cancel       context.CancelFunc
+	// pausedUntil is the unix nano time until which the scheduler is paused,
+	// 0 means not paused.
+	pausedUntil int64
+	// The counters below are used to diagnose the scheduler.
+	notAllowedCount  uint64
+	scheduleCount    uint64
+	operatorCount    uint64
+	lastOperatorTime int64
 }
 
 // newScheduleController creates a new scheduleController.
@@ -476,10 +887,13 @@ func (s *scheduleController) Stop() {
 }
 
 func (s *scheduleController) Schedule() []*operator.Operator {
+	atomic.AddUint64(&s.scheduleCount, 1)
 	for i := 0; i < maxScheduleRetries; i++ {
 		// If we have schedule, reset interval to the minimal interval.
 		if op := scheduleByNamespace(s.cluster, s.classifier, s.Scheduler); op != nil {
 			s.nextInterval = s.Scheduler.GetMinInterval()
+			atomic.AddUint64(&s.operatorCount, uint64(len(op)))
+			atomic.StoreInt64(&s.lastOperatorTime, time.Now().UnixNano())
 			return op
 		}
 	}
@@ -494,5 +908,29 @@ func (s *scheduleController) GetInterval() time.Duration {
 
 // AllowSchedule returns if a scheduler is allowed to schedule.
 func (s *scheduleController) AllowSchedule() bool {
+	if s.IsPaused() {
+		return false
+	}
 	return s.Scheduler.IsScheduleAllowed(s.cluster)
 }
+
+// Pause stops the scheduler from scheduling until the expire time. A zero
+// expire time means the scheduler is paused until it is resumed.
+func (s *scheduleController) Pause(expire time.Time) {
+	until := int64(math.MaxInt64)
+	if !expire.IsZero() {
+		until = expire.UnixNano()
+	}
+	atomic.StoreInt64(&s.pausedUntil, until)
+}
+
+// Resume lets the paused scheduler schedule again.
+func (s *scheduleController) Resume() {
+	atomic.StoreInt64(&s.pausedUntil, 0)
+}
+
+// IsPaused returns if the scheduler is paused. The scheduler is resumed
+// automatically once the pause expires.
+func (s *scheduleController) IsPaused() bool {
+	return time.Now().UnixNano() < atomic.LoadInt64(&s.pausedUntil)
+}
@@ -15,6 +15,7 @@ package server
 
 import (
 	"fmt"
+	"math"
 	"math/rand"
 	"path/filepath"
 	"time"
@@ -31,9 +32,12 @@ import (
 	"github.com/pingcap/pd/server/id"
 	"github.com/pingcap/pd/server/kv"
 	"github.com/pingcap/pd/server/namespace"
+	"github.com/pingcap/pd/server/placement"
+	"github.com/pingcap/pd/server/placement/rule"
 	syncer "github.com/pingcap/pd/server/region_syncer"
 	"github.com/pingcap/pd/server/schedule"
 	"github.com/pingcap/pd/server/schedule/operator"
+	"github.com/pingcap/pd/server/schedule/storelimit"
 	"github.com/pingcap/pd/server/schedulers"
 )
 
@@ -330,6 +334,121 @@ func (s *testCoordinatorSuite) TestCheckRegion(c *C) {
 	c.Assert(co.checkRegion(tc.GetRegion(1)), IsFalse)
 }
 
+func (s *testCoordinatorSuite) TestExplainRegionPlacement(c *C) {
+	_, opt, err := newTestScheduleConfig()
+	c.Assert(err, IsNil)
+	tc := newTestCluster(opt)
+	hbStreams := getHeartBeatStreams(c, tc)
+	defer hbStreams.Close()
+
+	co := newCoordinator(tc.RaftCluster, hbStreams, namespace.DefaultClassifier)
+	for i := uint64(1); i <= 4; i++ {
+		c.Assert(tc.addRegionStore(i, int(i)), IsNil)
+	}
+	c.Assert(tc.addLeaderRegion(1, 2, 3), IsNil)
+
+	id, err := tc.allocID()
+	c.Assert(err, IsNil)
+	exp := co.explainRegionPlacement(tc.GetRegion(1))
+	c.Assert(exp.RegionID, Equals, uint64(1))
+	c.Assert(exp.Rule.ID, Equals, rule.DefaultRuleID)
+	c.Assert(exp.Statuses, HasLen, 2)
+	c.Assert(exp.Statuses[0].Constraint, Equals, "voters=3")
+	c.Assert(exp.Statuses[0].Satisfied, IsFalse)
+	c.Assert(exp.Statuses[1].Satisfied, IsTrue)
+	c.Assert(exp.IsolationLevel, Equals, 0)
+	c.Assert(exp.RunningOperator, IsNil)
+	c.Assert(exp.NextOperators, HasLen, 1)
+	testutil.CheckAddPeer(c, exp.NextOperators[0], operator.OpReplica, 1)
+	// The explanation never adds operators or allocates IDs.
+	c.Assert(co.opController.GetOperator(1), IsNil)
+	c.Assert(exp.NextOperators[0].Step(0).(operator.AddLearner).PeerID, Equals, uint64(math.MaxUint64-1))
+	nextID, err := tc.allocID()
+	c.Assert(err, IsNil)
+	c.Assert(nextID, Equals, id+1)
+
+	r := &rule.Rule{ID: "r", Voters: 2, LabelConstraints: []rule.LabelConstraint{{Key: "zone", Op: rule.Exists}}}
+	c.Assert(tc.GetRuleManager().SetRule(r), IsNil)
+	cfg, err := placement.ParseConfig("count()>=3")
+	c.Assert(err, IsNil)
+	co.placementChecker.SetConfig(cfg)
+	exp = co.explainRegionPlacement(tc.GetRegion(1))
+	c.Assert(exp.Rule.ID, Equals, "r")
+	c.Assert(exp.Statuses, HasLen, 4)
+	c.Assert(exp.Statuses[0].Satisfied, IsTrue)
+	c.Assert(exp.Statuses[2].Source, Equals, PlacementSourceRule)
+	c.Assert(exp.Statuses[2].Satisfied, IsFalse)
+	c.Assert(exp.Statuses[2].Detail, Equals, "mismatched stores [2 3]")
+	c.Assert(exp.Statuses[3].Source, Equals, PlacementSourceConstraint)
+	c.Assert(exp.Statuses[3].Satisfied, IsFalse)
+}
+
+func (s *testCoordinatorSuite) TestPlanSchedule(c *C) {
+	_, opt, err := newTestScheduleConfig()
+	c.Assert(err, IsNil)
+	tc := newTestCluster(opt)
+	hbStreams := getHeartBeatStreams(c, tc)
+	defer hbStreams.Close()
+
+	co := newCoordinator(tc.RaftCluster, hbStreams, namespace.DefaultClassifier)
+	for i := uint64(1); i <= 4; i++ {
+		c.Assert(tc.addRegionStore(i, int(i)), IsNil)
+	}
+	c.Assert(tc.addLeaderRegion(1, 4), IsNil)
+	c.Assert(tc.addLeaderRegion(2, 1, 2, 3), IsNil)
+
+	id, err := tc.allocID()
+	c.Assert(err, IsNil)
+	// Region 1 lacks replicas.
+	planned, err := co.planSchedule(nil, nil, 16)
+	c.Assert(err, IsNil)
+	var replicaOp *PlannedOperator
+	for _, p := range planned {
+		if p.Source == "replica-checker" {
+			replicaOp = p
+		}
+	}
+	c.Assert(replicaOp, NotNil)
+	testutil.CheckAddPeer(c, replicaOp.Operator, operator.OpReplica, 1)
+	c.Assert(replicaOp.Influence, HasLen, 1)
+	inf := replicaOp.Influence[0]
+	c.Assert(inf.StoreID, Equals, uint64(1))
+	c.Assert(inf.RegionCount, Equals, int64(1))
+	c.Assert(inf.RegionSize, Equals, int64(10))
+	c.Assert(inf.RegionScoreAfter, Greater, inf.RegionScoreBefore)
+	c.Assert(co.opController.GetOperators(), HasLen, 0)
+
+	// The schedulers and checkers allocate placeholder peer IDs.
+	planned, err = co.planSchedule([]config.SchedulerConfig{{Type: "shuffle-region"}}, []string{PlanCheckerReplica}, 16)
+	c.Assert(err, IsNil)
+	c.Assert(planned, HasLen, 2)
+	for _, p := range planned {
+		c.Assert(p.Operator.Step(0).(operator.AddLearner).PeerID, Greater, uint64(math.MaxUint64-16))
+	}
+	nextID, err := tc.allocID()
+	c.Assert(err, IsNil)
+	c.Assert(nextID, Equals, id+1)
+
+	planned, err = co.planSchedule([]config.SchedulerConfig{{Type: "evict-leader", Args: []string{"1"}}}, nil, 16)
+	c.Assert(err, IsNil)
+	c.Assert(planned, HasLen, 1)
+	c.Assert(planned[0].Source, Equals, "evict-leader-scheduler-1")
+	c.Assert(planned[0].Operator.RegionID(), Equals, uint64(2))
+	c.Assert(planned[0].Influence[0].StoreID, Equals, uint64(1))
+	c.Assert(planned[0].Influence[0].LeaderCount, Equals, int64(-1))
+	// The plan neither dispatches the operators nor blocks the stores.
+	c.Assert(co.opController.GetOperators(), HasLen, 0)
+	c.Assert(tc.GetStore(1).IsBlocked(), IsFalse)
+
+	planned, err = co.planSchedule(nil, []string{PlanCheckerMerge}, 16)
+	c.Assert(err, IsNil)
+	c.Assert(planned, HasLen, 0)
+	_, err = co.planSchedule(nil, []string{"unknown"}, 16)
+	c.Assert(err, NotNil)
+	_, err = co.planSchedule([]config.SchedulerConfig{{Type: "unknown"}}, nil, 16)
+	c.Assert(err, NotNil)
+}
+
 func (s *testCoordinatorSuite) TestReplica(c *C) {
 	// Turn off balance.
 	cfg, opt, err := newTestScheduleConfig()
@@ -603,6 +722,137 @@ func (s *testCoordinatorSuite) TestAddScheduler(c *C) {
 	waitNoResponse(c, stream)
 }
 
+func (s *testCoordinatorSuite) TestSlowStoreEviction(c *C) {
+	cfg, opt, err := newTestScheduleConfig()
+	c.Assert(err, IsNil)
+	cfg.EnableSlowStoreEviction = true
+
+	tc := newTestCluster(opt)
+	hbStreams := getHeartBeatStreams(c, tc)
+	defer hbStreams.Close()
+	co := newCoordinator(tc.RaftCluster, hbStreams, namespace.DefaultClassifier)
+	co.run()
+	defer co.wg.Wait()
+	defer co.stop()
+
+	for id := uint64(1); id <= 3; id++ {
+		c.Assert(tc.addLeaderStore(id, 1), IsNil)
+	}
+	heartbeat := func(storeID uint64, interval time.Duration, count int) {
+		for i := 0; i < count; i++ {
+			tc.storesStats.ObserveSlowness(storeID, &pdpb.StoreStats{StoreId: storeID}, interval, 0)
+		}
+	}
+	hasScheduler := func(storeID uint64) bool {
+		_, ok := co.schedulers[fmt.Sprintf("evict-slow-store-scheduler-%d", storeID)]
+		return ok
+	}
+	for id := uint64(1); id <= 3; id++ {
+		heartbeat(id, 10*time.Second, 5)
+	}
+	co.checkSlowStores()
+	c.Assert(hasScheduler(1), IsFalse)
+
+	// The heartbeats of store 1 and store 2 are delayed, and only the slowest
+	// store is evicted at the same time.
+	heartbeat(1, time.Minute, 3)
+	heartbeat(2, 40*time.Second, 3)
+	c.Assert(tc.GetStoreSlowScore(1), Equals, float64(6))
+	co.checkSlowStores()
+	c.Assert(hasScheduler(1), IsTrue)
+	c.Assert(hasScheduler(2), IsFalse)
+
+	// Store 1 recovers and then store 2 is evicted.
+	heartbeat(1, 10*time.Second, 3)
+	c.Assert(tc.GetStoreSlowScore(1), Equals, float64(1))
+	co.checkSlowStores()
+	c.Assert(hasScheduler(1), IsFalse)
+	c.Assert(hasScheduler(2), IsTrue)
+
+	// The store blocked by an evict-leader scheduler is skipped.
+	heartbeat(2, 10*time.Second, 3)
+	co.checkSlowStores()
+	c.Assert(hasScheduler(2), IsFalse)
+	c.Assert(tc.BlockStore(3), IsNil)
+	heartbeat(3, time.Minute, 3)
+	co.checkSlowStores()
+	c.Assert(hasScheduler(3), IsFalse)
+	tc.UnblockStore(3)
+	co.checkSlowStores()
+	c.Assert(hasScheduler(3), IsTrue)
+
+	// The eviction is stopped if the option is disabled.
+	newCfg := opt.Load().Clone()
+	newCfg.EnableSlowStoreEviction = false
+	opt.Store(newCfg)
+	co.checkSlowStores()
+	c.Assert(hasScheduler(3), IsFalse)
+}
+
+func (s *testCoordinatorSuite) TestStoreMaintenance(c *C) {
+	_, opt, err := newTestScheduleConfig()
+	c.Assert(err, IsNil)
+
+	tc := newTestCluster(opt)
+	hbStreams := getHeartBeatStreams(c, tc)
+	defer hbStreams.Close()
+	co := newCoordinator(tc.RaftCluster, hbStreams, namespace.DefaultClassifier)
+	tc.coordinator = co
+	co.run()
+	defer co.wg.Wait()
+	defer co.stop()
+
+	for id := uint64(1); id <= 3; id++ {
+		c.Assert(tc.addLeaderStore(id, 1), IsNil)
+	}
+	hasScheduler := func(storeID uint64) bool {
+		co.RLock()
+		defer co.RUnlock()
+		_, ok := co.schedulers[fmt.Sprintf("evict-maintenance-store-scheduler-%d", storeID)]
+		return ok
+	}
+
+	// The eviction starts once the maintenance is set.
+	c.Assert(tc.SetStoreMaintenance(1, time.Hour, true), IsNil)
+	c.Assert(tc.SetStoreMaintenance(2, time.Hour, false), IsNil)
+	c.Assert(tc.SetStoreMaintenance(4, time.Hour, false), NotNil)
+	c.Assert(hasScheduler(1), IsTrue)
+	c.Assert(hasScheduler(2), IsFalse)
+	c.Assert(tc.GetStore(1).IsBlocked(), IsTrue)
+
+	// The maintenance is persisted.
+	stores := core.NewStoresInfo()
+	c.Assert(tc.storage.LoadStores(stores), IsNil)
+	c.Assert(stores.GetStore(1).GetMaintenance().EvictLeader, IsTrue)
+	c.Assert(stores.GetStore(2).IsInMaintenance(), IsTrue)
+
+	// The eviction is stopped when the maintenance is removed.
+	c.Assert(tc.RemoveStoreMaintenance(1), IsNil)
+	co.checkMaintenanceStores()
+	c.Assert(hasScheduler(1), IsFalse)
+	c.Assert(tc.GetStore(1).GetMaintenance(), IsNil)
+	testutil.WaitUntil(c, func(c *C) bool {
+		return !tc.GetStore(1).IsBlocked()
+	})
+
+	// The expired maintenance is removed.
+	c.Assert(tc.SetStoreMaintenance(2, -time.Second, true), IsNil)
+	co.checkMaintenanceStores()
+	c.Assert(hasScheduler(2), IsFalse)
+	c.Assert(tc.GetStore(2).GetMaintenance(), IsNil)
+	stores = core.NewStoresInfo()
+	c.Assert(tc.storage.LoadStores(stores), IsNil)
+	c.Assert(stores.GetStore(2).GetMaintenance(), IsNil)
+
+	// The leaders of store 3 are already evicted by another scheduler.
+	c.Assert(tc.BlockStore(3), IsNil)
+	c.Assert(tc.SetStoreMaintenance(3, time.Hour, true), IsNil)
+	c.Assert(hasScheduler(3), IsFalse)
+	tc.UnblockStore(3)
+	co.checkMaintenanceStores()
+	c.Assert(hasScheduler(3), IsTrue)
+}
+
 func (s *testCoordinatorSuite) TestPersistScheduler(c *C) {
 	cfg, opt, err := newTestScheduleConfig()
 	c.Assert(err, IsNil)
@@ -694,6 +944,89 @@ func (s *testCoordinatorSuite) TestPersistScheduler(c *C) {
 	c.Assert(co.schedulers, HasLen, 3)
 }
 
+func (s *testCoordinatorSuite) TestPauseScheduler(c *C) {
+	_, opt, err := newTestScheduleConfig()
+	c.Assert(err, IsNil)
+	tc := newTestCluster(opt)
+	hbStreams := getHeartBeatStreams(c, tc)
+	defer hbStreams.Close()
+
+	co := newCoordinator(tc.RaftCluster, hbStreams, namespace.DefaultClassifier)
+	co.run()
+	c.Assert(tc.addLeaderStore(1, 1), IsNil)
+
+	gls, err := schedule.CreateScheduler("grant-leader", co.opController, "1")
+	c.Assert(err, IsNil)
+	c.Assert(co.addScheduler(gls, "1"), IsNil)
+	sc := co.schedulers["grant-leader-scheduler-1"]
+	c.Assert(sc.AllowSchedule(), IsTrue)
+
+	c.Assert(co.pauseScheduler("grant-leader-scheduler-1", 0), IsNil)
+	c.Assert(sc.IsPaused(), IsTrue)
+	c.Assert(sc.AllowSchedule(), IsFalse)
+	c.Assert(co.getPausedSchedulers(), DeepEquals, []string{"grant-leader-scheduler-1"})
+	c.Assert(co.resumeScheduler("grant-leader-scheduler-1"), IsNil)
+	c.Assert(sc.AllowSchedule(), IsTrue)
+	c.Assert(co.pauseScheduler("not-found", 0), NotNil)
+
+	// The pause expires.
+	sc.Pause(time.Now().Add(-time.Second))
+	c.Assert(sc.IsPaused(), IsFalse)
+	c.Assert(co.pauseScheduler("grant-leader-scheduler-1", time.Hour), IsNil)
+	c.Assert(co.pauseScheduler("balance-leader-scheduler", 0), IsNil)
+	c.Assert(co.cluster.opt.Persist(co.cluster.storage), IsNil)
+	co.stop()
+	co.wg.Wait()
+
+	// The pause state is restored from the persisted config.
+	_, newOpt, err := newTestScheduleConfig()
+	c.Assert(err, IsNil)
+	c.Assert(newOpt.Reload(tc.storage), IsNil)
+	tc.RaftCluster.opt = newOpt
+	co = newCoordinator(tc.RaftCluster, hbStreams, namespace.DefaultClassifier)
+	co.run()
+	defer co.wg.Wait()
+	defer co.stop()
+	c.Assert(co.schedulers["grant-leader-scheduler-1"].IsPaused(), IsTrue)
+	c.Assert(co.schedulers["balance-leader-scheduler"].IsPaused(), IsTrue)
+	c.Assert(co.schedulers["balance-region-scheduler"].IsPaused(), IsFalse)
+	for _, cfg := range newOpt.GetSchedulers() {
+		switch cfg.Type {
+		case "grant-leader":
+			c.Assert(cfg.PauseExpireTime, Greater, time.Now().Unix())
+		case "balance-leader":
+			c.Assert(cfg.Paused, IsTrue)
+			c.Assert(cfg.PauseExpireTime, Equals, int64(0))
+		}
+	}
+	// Re-adding the config of a paused scheduler does not duplicate it.
+	newOpt.AddSchedulerCfg("grant-leader", []string{"1"})
+	c.Assert(newOpt.GetSchedulers(), HasLen, 5)
+}
+
+func (s *testCoordinatorSuite) TestPersistStoreLimit(c *C) {
+	_, opt, err := newTestScheduleConfig()
+	c.Assert(err, IsNil)
+	tc := newTestCluster(opt)
+	hbStreams := getHeartBeatStreams(c, tc)
+	defer hbStreams.Close()
+
+	c.Assert(tc.addLeaderStore(1, 1), IsNil)
+	c.Assert(tc.addLeaderStore(2, 1), IsNil)
+	c.Assert(tc.storage.SaveStoreLimit(1, storelimit.AddPeer.String(), 2), IsNil)
+	c.Assert(tc.storage.SaveStoreLimit(2, storelimit.RemovePeer.String(), 4), IsNil)
+	c.Assert(tc.storage.SaveStoreLimit(2, "unknown", 4), IsNil)
+
+	// The saved limits are loaded once the coordinator runs, for example
+	// after the leader changes.
+	co := newCoordinator(tc.RaftCluster, hbStreams, namespace.DefaultClassifier)
+	co.run()
+	defer co.wg.Wait()
+	defer co.stop()
+	c.Assert(co.opController.GetAllStoresLimit(storelimit.AddPeer), DeepEquals, map[uint64]float64{1: 2})
+	c.Assert(co.opController.GetAllStoresLimit(storelimit.RemovePeer), DeepEquals, map[uint64]float64{2: 4})
+}
+
 func (s *testCoordinatorSuite) TestRestart(c *C) {
 	// Turn off balance, we test add replica only.
 	cfg, opt, err := newTestScheduleConfig()
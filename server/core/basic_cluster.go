@@ -84,6 +84,16 @@ func (bc *BasicCluster) UnblockStore(storeID uint64) {
 	bc.Stores.UnblockStore(storeID)
 }
 
+// EvictRegions stops moving regions to the store.
+func (bc *BasicCluster) EvictRegions(storeID uint64) error {
+	return bc.Stores.EvictRegions(storeID)
+}
+
+// UnEvictRegions allows moving regions to the store.
+func (bc *BasicCluster) UnEvictRegions(storeID uint64) {
+	bc.Stores.UnEvictRegions(storeID)
+}
+
 // AttachOverloadStatus attaches the overload status to a store.
 func (bc *BasicCluster) AttachOverloadStatus(storeID uint64, f func() bool) {
 	bc.Stores.AttachOverloadStatus(storeID, f)
@@ -151,5 +161,8 @@ type StoreSetController interface {
 	BlockStore(id uint64) error
 	UnblockStore(id uint64)
 
+	EvictRegions(id uint64) error
+	UnEvictRegions(id uint64)
+
 	AttachOverloadStatus(id uint64, f func() bool)
 }
@@ -30,12 +30,16 @@ var (
 	// StoreBlockedCode is an error due to requesting an operation that is invalid due to a store being in a blocked state
 	StoreBlockedCode = storeStateCode.Child("state.store.blocked")
 
+	// StoreEvictingRegionsCode is an error due to evicting the regions from a store which is already being evicted
+	StoreEvictingRegionsCode = storeStateCode.Child("state.store.evicting_regions")
+
 	// StoreTombstonedCode is an invalid operation was attempted on a store which is in a removed state.
 	StoreTombstonedCode = storeStateCode.Child("state.store.tombstoned").SetHTTP(http.StatusGone)
 )
 
-var _ errcode.ErrorCode = (*StoreTombstonedErr)(nil) // assert implements interface
-var _ errcode.ErrorCode = (*StoreBlockedErr)(nil)    // assert implements interface
+var _ errcode.ErrorCode = (*StoreTombstonedErr)(nil)      // assert implements interface
+var _ errcode.ErrorCode = (*StoreBlockedErr)(nil)         // assert implements interface
+var _ errcode.ErrorCode = (*StoreEvictingRegionsErr)(nil) // assert implements interface
 
 // StoreErr can be newtyped or embedded in your own error
 type StoreErr struct {
@@ -61,3 +65,13 @@ func (e StoreBlockedErr) Error() string {
 
 // Code returns StoreBlockedCode
 func (e StoreBlockedErr) Code() errcode.Code { return StoreBlockedCode }
+
+// StoreEvictingRegionsErr has a Code() of StoreEvictingRegionsCode
+type StoreEvictingRegionsErr StoreErr
+
+func (e StoreEvictingRegionsErr) Error() string {
+	return fmt.Sprintf("store %v is evicting regions", e.StoreID)
+}
+
+// Code returns StoreEvictingRegionsCode
+func (e StoreEvictingRegionsErr) Code() errcode.Code { return StoreEvictingRegionsCode }
@@ -0,0 +1,49 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	regionQueueDepthGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "storage",
+			Name:      "region_queue_depth",
+			Help:      "The number of the regions waiting to be written to the storage.",
+		})
+
+	regionQueueDroppedCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "storage",
+			Name:      "region_queue_dropped_total",
+			Help:      "Counter of the region updates dropped as the queue is full.",
+		})
+
+	regionFlushDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: "pd",
+			Subsystem: "storage",
+			Name:      "region_flush_duration_seconds",
+			Help:      "Bucketed histogram of the time (s) of writing a batch of regions to the storage.",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 16),
+		})
+)
+
+func init() {
+	prometheus.MustRegister(regionQueueDepthGauge)
+	prometheus.MustRegister(regionFlushDuration)
+	prometheus.MustRegister(regionQueueDroppedCounter)
+}
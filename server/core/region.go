@@ -491,6 +491,24 @@ func (r *RegionsInfo) SetRegion(region *RegionInfo) []*metapb.Region {
 	return r.AddRegion(region)
 }
 
+// UpdateRegionStats replaces the cached region with the region which only
+// changes the approximate size and keys. The region tree is not updated as the
+// key range is not changed.
+func (r *RegionsInfo) UpdateRegionStats(region *RegionInfo) {
+	if r.regions.Get(region.GetID()) == nil {
+		return
+	}
+	r.regions.Put(region)
+	for _, peer := range region.meta.GetPeers() {
+		storeID := peer.GetStoreId()
+		for _, rm := range []*regionMap{r.leaders[storeID], r.followers[storeID], r.learners[storeID], r.pendingPeers[storeID]} {
+			if rm.Get(region.GetID()) != nil {
+				rm.Put(region)
+			}
+		}
+	}
+}
+
 // Length returns the RegionsInfo length
 func (r *RegionsInfo) Length() int {
 	return r.regions.Len()
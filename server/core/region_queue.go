@@ -0,0 +1,182 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/log"
+	"github.com/pingcap/pd/server/kv"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// regionQueueBatchSize is the max number of the regions written at a
+	// time, and a full batch is flushed at once.
+	regionQueueBatchSize = 256
+	// regionQueueHighWater is the max number of the pending regions, the
+	// updates of the other regions are dropped above it.
+	regionQueueHighWater = 64 * regionQueueBatchSize
+)
+
+// regionQueueMaxDelay is the max time a region waits in the queue before it is
+// flushed.
+var regionQueueMaxDelay = time.Second
+
+var errRegionQueueFull = errors.New("region queue is full")
+
+// regionWriteQueue is the write-behind queue of the region metadata. The
+// updates of a region are coalesced, and the pending regions are written in
+// batches when a batch is full or the oldest one has waited for
+// regionQueueMaxDelay.
+type regionWriteQueue struct {
+	mu sync.Mutex
+	// pending is the regions waiting to be written, and flushing is the
+	// regions being written. A nil region means the region is deleted.
+	pending  map[uint64]*metapb.Region
+	flushing map[uint64]*metapb.Region
+	timer    *time.Timer
+
+	// flushMu serializes the flushes, so that the batches are written in order.
+	flushMu sync.Mutex
+	write   func(regions map[uint64]*metapb.Region) error
+}
+
+func newRegionWriteQueue(write func(regions map[uint64]*metapb.Region) error) *regionWriteQueue {
+	return &regionWriteQueue{
+		pending: make(map[uint64]*metapb.Region),
+		write:   write,
+	}
+}
+
+// put adds the region to the queue, a nil region means the region is deleted.
+// The update is dropped if the pending and flushing regions reach the
+// high-water mark and the region is not pending, so a slow storage does not
+// pile up the regions in memory.
+func (q *regionWriteQueue) put(regionID uint64, region *metapb.Region) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.pending[regionID]; !ok && len(q.pending)+len(q.flushing) >= regionQueueHighWater {
+		regionQueueDroppedCounter.Inc()
+		return errors.WithStack(errRegionQueueFull)
+	}
+	q.pending[regionID] = region
+	regionQueueDepthGauge.Set(float64(len(q.pending)))
+	q.scheduleLocked()
+	return nil
+}
+
+// scheduleLocked schedules a background flush if any region is pending, a
+// full batch is flushed at once.
+func (q *regionWriteQueue) scheduleLocked() {
+	if len(q.pending) == 0 {
+		return
+	}
+	if q.timer == nil {
+		q.timer = time.AfterFunc(regionQueueMaxDelay, q.backgroundFlush)
+	}
+	if len(q.pending) >= regionQueueBatchSize {
+		q.timer.Reset(0)
+	}
+}
+
+// get returns the region in the queue, ok is false if the region is not in
+// the queue.
+func (q *regionWriteQueue) get(regionID uint64) (region *metapb.Region, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if region, ok = q.pending[regionID]; ok {
+		return region, ok
+	}
+	region, ok = q.flushing[regionID]
+	return region, ok
+}
+
+func (q *regionWriteQueue) backgroundFlush() {
+	if err := q.flushBatch(); err != nil {
+		log.Error("flush regions meet error", zap.Error(err))
+	}
+}
+
+// flush writes the pending regions in batches. The number of the batches is
+// bounded by the regions pending when it is called, so it does not run
+// forever while the regions keep coming.
+func (q *regionWriteQueue) flush() error {
+	q.mu.Lock()
+	batches := (len(q.pending) + regionQueueBatchSize - 1) / regionQueueBatchSize
+	q.mu.Unlock()
+	for i := 0; i < batches; i++ {
+		if err := q.flushBatch(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flushBatch writes at most regionQueueBatchSize pending regions. The regions
+// failed to be written are put back to the queue unless they are updated
+// again, or the member is not the leader anymore.
+func (q *regionWriteQueue) flushBatch() error {
+	q.flushMu.Lock()
+	defer q.flushMu.Unlock()
+
+	q.mu.Lock()
+	if q.timer != nil {
+		q.timer.Stop()
+		q.timer = nil
+	}
+	if len(q.pending) == 0 {
+		q.mu.Unlock()
+		return nil
+	}
+	regions := make(map[uint64]*metapb.Region, regionQueueBatchSize)
+	for id, region := range q.pending {
+		if len(regions) >= regionQueueBatchSize {
+			break
+		}
+		regions[id] = region
+		delete(q.pending, id)
+	}
+	q.flushing = regions
+	regionQueueDepthGauge.Set(float64(len(q.pending)))
+	q.mu.Unlock()
+
+	start := time.Now()
+	err := q.write(regions)
+	regionFlushDuration.Observe(time.Since(start).Seconds())
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.flushing = nil
+	switch {
+	case err == nil:
+	case errors.Cause(err) == kv.ErrNotLeader:
+		// The next leader loads the regions from the storage and gets the
+		// latest ones from the heartbeats.
+		log.Warn("drop the pending regions as the leadership is lost", zap.Int("count", len(regions)+len(q.pending)))
+		q.pending = make(map[uint64]*metapb.Region)
+	default:
+		for id, region := range regions {
+			if _, ok := q.pending[id]; !ok {
+				q.pending[id] = region
+			}
+		}
+	}
+	regionQueueDepthGauge.Set(float64(len(q.pending)))
+	q.scheduleLocked()
+	return err
+}
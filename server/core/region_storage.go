@@ -14,105 +14,26 @@
 package core
 
 import (
-	"context"
 	"math"
-	"sync"
-	"time"
 
 	"github.com/pingcap/kvproto/pkg/metapb"
-	"github.com/pingcap/log"
 	"github.com/pingcap/pd/server/kv"
 	"github.com/pkg/errors"
-	"go.uber.org/zap"
 )
 
-var dirtyFlushTick = time.Second
-
-// RegionStorage is used to save regions.
+// RegionStorage is the local storage of the regions. The regions are written
+// to it by the region write queue of Storage.
 type RegionStorage struct {
 	*kv.LeveldbKV
-	mu           sync.RWMutex
-	batchRegions map[string]*metapb.Region
-	batchSize    int
-	cacheSize    int
-	flushRate    time.Duration
-	flushTime    time.Time
-	ctx          context.Context
-	cancel       context.CancelFunc
 }
 
-const (
-	//DefaultFlushRegionRate is the ttl to sync the regions to region storage.
-	defaultFlushRegionRate = 3 * time.Second
-	//DefaultBatchSize is the batch size to save the regions to region storage.
-	defaultBatchSize = 100
-)
-
 // NewRegionStorage returns a region storage that is used to save regions.
 func NewRegionStorage(path string) (*RegionStorage, error) {
 	levelDB, err := kv.NewLeveldbKV(path)
 	if err != nil {
 		return nil, err
 	}
-	ctx, cancel := context.WithCancel(context.Background())
-	s := &RegionStorage{
-		LeveldbKV:    levelDB,
-		batchSize:    defaultBatchSize,
-		flushRate:    defaultFlushRegionRate,
-		batchRegions: make(map[string]*metapb.Region, defaultBatchSize),
-		flushTime:    time.Now().Add(defaultFlushRegionRate),
-		ctx:          ctx,
-		cancel:       cancel,
-	}
-	s.backgroundFlush()
-	return s, nil
-}
-
-func (s *RegionStorage) backgroundFlush() {
-	ticker := time.NewTicker(dirtyFlushTick)
-	var (
-		isFlush bool
-		err     error
-	)
-	go func() {
-		defer ticker.Stop()
-		for {
-			select {
-			case <-ticker.C:
-				s.mu.RLock()
-				isFlush = s.flushTime.Before(time.Now())
-				s.mu.RUnlock()
-				if !isFlush {
-					continue
-				}
-				if err = s.FlushRegion(); err != nil {
-					log.Error("flush regions meet error", zap.Error(err))
-				}
-			case <-s.ctx.Done():
-				return
-			}
-		}
-	}()
-}
-
-// SaveRegion saves one region to storage.
-func (s *RegionStorage) SaveRegion(region *metapb.Region) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	if s.cacheSize < s.batchSize-1 {
-		s.batchRegions[regionPath(region.GetId())] = region
-		s.cacheSize++
-
-		s.flushTime = time.Now().Add(s.flushRate)
-		return nil
-	}
-	s.batchRegions[regionPath(region.GetId())] = region
-	err := s.flush()
-
-	if err != nil {
-		return err
-	}
-	return nil
+	return &RegionStorage{LeveldbKV: levelDB}, nil
 }
 
 func deleteRegion(kv kv.Base, region *metapb.Region) error {
@@ -158,28 +79,7 @@ func loadRegions(kv kv.Base, regions *RegionsInfo) error {
 	}
 }
 
-// FlushRegion saves the cache region to region storage.
-func (s *RegionStorage) FlushRegion() error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	return s.flush()
-}
-
-func (s *RegionStorage) flush() error {
-	if err := s.SaveRegions(s.batchRegions); err != nil {
-		return err
-	}
-	s.cacheSize = 0
-	s.batchRegions = make(map[string]*metapb.Region, s.batchSize)
-	return nil
-}
-
 // Close closes the kv.
 func (s *RegionStorage) Close() error {
-	err := s.FlushRegion()
-	if err != nil {
-		log.Error("meet error before close the region storage", zap.Error(err))
-	}
-	s.cancel()
 	return errors.WithStack(s.LeveldbKV.Close())
 }
@@ -57,6 +57,26 @@ func (s *testRegionMapSuite) TestRegionMap(c *C) {
 	s.check(c, rm, 2, 3)
 }
 
+func (s *testRegionMapSuite) TestUpdateRegionStats(c *C) {
+	peers := []*metapb.Peer{{Id: 11, StoreId: 1}, {Id: 12, StoreId: 2}}
+	meta := &metapb.Region{Id: 1, StartKey: []byte("a"), EndKey: []byte("b"), Peers: peers}
+	regions := NewRegionsInfo()
+	regions.AddRegion(NewRegionInfo(meta, peers[0], SetApproximateSize(10), SetApproximateKeys(10)))
+
+	region := NewRegionInfo(meta, peers[0], SetApproximateSize(30), SetApproximateKeys(20))
+	regions.UpdateRegionStats(region)
+	c.Assert(regions.GetRegion(1), Equals, region)
+	c.Assert(regions.SearchRegion([]byte("a")), Equals, region)
+	c.Assert(regions.GetStoreLeaderRegionSize(1), Equals, int64(30))
+	c.Assert(regions.GetStoreFollowerRegionSize(2), Equals, int64(30))
+	c.Assert(regions.GetStoreRegionCount(2), Equals, 1)
+
+	// The region not in the cache is ignored.
+	regions.UpdateRegionStats(NewRegionInfo(&metapb.Region{Id: 2}, nil))
+	c.Assert(regions.GetRegion(2), IsNil)
+	c.Assert(regions.Length(), Equals, 1)
+}
+
 func (s *testRegionMapSuite) regionInfo(id uint64) *RegionInfo {
 	return &RegionInfo{
 		meta: &metapb.Region{
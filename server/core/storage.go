@@ -19,12 +19,15 @@ import (
 	"math"
 	"path"
 	"strconv"
+	"strings"
 	"sync/atomic"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/log"
 	"github.com/pingcap/pd/server/kv"
 	"github.com/pkg/errors"
+	"go.uber.org/zap"
 )
 
 const (
@@ -32,6 +35,9 @@ const (
 	configPath   = "config"
 	schedulePath = "schedule"
 	gcPath       = "gc"
+
+	placementPath = "placement"
+	rulesPath     = "rules"
 )
 
 const (
@@ -44,13 +50,22 @@ type Storage struct {
 	kv.Base
 	regionStorage    *RegionStorage
 	useRegionStorage int32
+	// regionQueue writes the regions to the region storage or the default
+	// storage in the background.
+	regionQueue *regionWriteQueue
+	// operatorHistoryKV is the local storage of the operator history.
+	operatorHistoryKV *kv.LeveldbKV
+	// hotRegionHistoryKV is the local storage of the hot region history.
+	hotRegionHistoryKV *kv.LeveldbKV
 }
 
 // NewStorage creates Storage instance with Base.
 func NewStorage(base kv.Base) *Storage {
-	return &Storage{
+	s := &Storage{
 		Base: base,
 	}
+	s.regionQueue = newRegionWriteQueue(s.writeRegions)
+	return s
 }
 
 // SetRegionStorage sets the region storage.
@@ -64,14 +79,97 @@ func (s *Storage) GetRegionStorage() *RegionStorage {
 	return s.regionStorage
 }
 
-// SwitchToRegionStorage switches to the region storage.
+// SetOperatorHistoryStorage sets the storage of the operator history.
+func (s *Storage) SetOperatorHistoryStorage(operatorHistoryKV *kv.LeveldbKV) *Storage {
+	s.operatorHistoryKV = operatorHistoryKV
+	return s
+}
+
+// GetOperatorHistoryStorage gets the storage of the operator history, it
+// returns nil if the storage is not set.
+func (s *Storage) GetOperatorHistoryStorage() kv.ReverseBase {
+	if s.operatorHistoryKV == nil {
+		return nil
+	}
+	return s.operatorHistoryKV
+}
+
+// SetHotRegionHistoryStorage sets the storage of the hot region history.
+func (s *Storage) SetHotRegionHistoryStorage(hotRegionHistoryKV *kv.LeveldbKV) *Storage {
+	s.hotRegionHistoryKV = hotRegionHistoryKV
+	return s
+}
+
+// GetHotRegionHistoryStorage gets the storage of the hot region history, it
+// returns nil if the storage is not set.
+func (s *Storage) GetHotRegionHistoryStorage() kv.ReverseBase {
+	if s.hotRegionHistoryKV == nil {
+		return nil
+	}
+	return s.hotRegionHistoryKV
+}
+
+// SwitchToRegionStorage switches to the region storage. The regions in the
+// queue are flushed to the previous storage first.
 func (s *Storage) SwitchToRegionStorage() {
-	atomic.StoreInt32(&s.useRegionStorage, 1)
+	s.switchRegionStorage(1)
 }
 
-// SwitchToDefaultStorage switches to the to default storage.
+// SwitchToDefaultStorage switches to the to default storage. The regions in
+// the queue are flushed to the previous storage first.
 func (s *Storage) SwitchToDefaultStorage() {
-	atomic.StoreInt32(&s.useRegionStorage, 0)
+	s.switchRegionStorage(0)
+}
+
+func (s *Storage) switchRegionStorage(use int32) {
+	if err := s.regionQueue.flush(); err != nil {
+		log.Error("flush regions before switching the storage meet error", zap.Error(err))
+	}
+	atomic.StoreInt32(&s.useRegionStorage, use)
+}
+
+// regionKV returns the storage of the regions, which is the default storage if
+// the region storage is not used or not set.
+func (s *Storage) regionKV() kv.Base {
+	if atomic.LoadInt32(&s.useRegionStorage) > 0 && s.regionStorage != nil {
+		return s.regionStorage
+	}
+	return s.Base
+}
+
+// writeRegions writes a batch of regions to the storage of the regions, a nil
+// region means the region is deleted. The regions are written in batches if
+// the storage supports.
+func (s *Storage) writeRegions(regions map[uint64]*metapb.Region) error {
+	base := s.regionKV()
+	if batchBase, ok := base.(kv.BatchBase); ok {
+		saves := make(map[string]string, len(regions))
+		var removes []string
+		for id, region := range regions {
+			if region == nil {
+				removes = append(removes, regionPath(id))
+				continue
+			}
+			value, err := proto.Marshal(region)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			saves[regionPath(id)] = string(value)
+		}
+		return batchBase.WriteBatch(saves, removes)
+	}
+	for id, region := range regions {
+		var err error
+		if region == nil {
+			err = base.Remove(regionPath(id))
+		} else {
+			err = saveProto(base, regionPath(id), region)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (s *Storage) storePath(storeID uint64) string {
@@ -95,6 +193,14 @@ func (s *Storage) storeRegionWeightPath(storeID uint64) string {
 	return path.Join(schedulePath, "store_weight", fmt.Sprintf("%020d", storeID), "region")
 }
 
+func (s *Storage) storeMaintenancePath(storeID uint64) string {
+	return path.Join(schedulePath, "store_maintenance", fmt.Sprintf("%020d", storeID))
+}
+
+func (s *Storage) storeLimitPath(storeID uint64, limitType string) string {
+	return path.Join(schedulePath, "store_limit", fmt.Sprintf("%020d", storeID), limitType)
+}
+
 // LoadMeta loads cluster meta from storage.
 func (s *Storage) LoadMeta(meta *metapb.Cluster) (bool, error) {
 	return loadProto(s.Base, clusterPath, meta)
@@ -120,36 +226,38 @@ func (s *Storage) DeleteStore(store *metapb.Store) error {
 	return s.Remove(s.storePath(store.GetId()))
 }
 
-// LoadRegion loads one regoin from storage.
+// LoadRegion loads one regoin from storage, the region in the queue is
+// returned if it has not been written.
 func (s *Storage) LoadRegion(regionID uint64, region *metapb.Region) (bool, error) {
-	if atomic.LoadInt32(&s.useRegionStorage) > 0 {
-		return loadProto(s.regionStorage, regionPath(regionID), region)
+	if queued, ok := s.regionQueue.get(regionID); ok {
+		if queued == nil {
+			return false, nil
+		}
+		region.Reset()
+		proto.Merge(region, queued)
+		return true, nil
 	}
-	return loadProto(s.Base, regionPath(regionID), region)
+	return loadProto(s.regionKV(), regionPath(regionID), region)
 }
 
 // LoadRegions loads all regions from storage to RegionsInfo.
 func (s *Storage) LoadRegions(regions *RegionsInfo) error {
-	if atomic.LoadInt32(&s.useRegionStorage) > 0 {
-		return loadRegions(s.regionStorage, regions)
+	if err := s.regionQueue.flush(); err != nil {
+		return err
 	}
-	return loadRegions(s.Base, regions)
+	return loadRegions(s.regionKV(), regions)
 }
 
-// SaveRegion saves one region to storage.
+// SaveRegion saves one region to storage. The region is written in the
+// background, and the updates of the same region are coalesced. It fails if
+// too many regions are waiting to be written.
 func (s *Storage) SaveRegion(region *metapb.Region) error {
-	if atomic.LoadInt32(&s.useRegionStorage) > 0 {
-		return s.regionStorage.SaveRegion(region)
-	}
-	return saveProto(s.Base, regionPath(region.GetId()), region)
+	return s.regionQueue.put(region.GetId(), region)
 }
 
-// DeleteRegion deletes one region from storage.
+// DeleteRegion deletes one region from storage in the background.
 func (s *Storage) DeleteRegion(region *metapb.Region) error {
-	if atomic.LoadInt32(&s.useRegionStorage) > 0 {
-		return deleteRegion(s.regionStorage, region)
-	}
-	return deleteRegion(s.Base, region)
+	return s.regionQueue.put(region.GetId(), nil)
 }
 
 // SaveConfig stores marshalable cfg to the configPath.
@@ -177,6 +285,68 @@ func (s *Storage) LoadConfig(cfg interface{}) (bool, error) {
 	return true, nil
 }
 
+// SavePlacementConfig stores marshalable placement cfg to the placementPath.
+func (s *Storage) SavePlacementConfig(cfg interface{}) error {
+	value, err := json.Marshal(cfg)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return s.Save(placementPath, string(value))
+}
+
+// LoadPlacementConfig loads placement config from placementPath then unmarshal
+// it to cfg.
+func (s *Storage) LoadPlacementConfig(cfg interface{}) (bool, error) {
+	value, err := s.Load(placementPath)
+	if err != nil {
+		return false, err
+	}
+	if value == "" {
+		return false, nil
+	}
+	err = json.Unmarshal([]byte(value), cfg)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	return true, nil
+}
+
+// SaveRule stores a placement rule to the rulesPath.
+func (s *Storage) SaveRule(ruleKey string, rule interface{}) error {
+	value, err := json.Marshal(rule)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return s.Save(path.Join(rulesPath, ruleKey), string(value))
+}
+
+// DeleteRule removes a placement rule from storage.
+func (s *Storage) DeleteRule(ruleKey string) error {
+	return s.Remove(path.Join(rulesPath, ruleKey))
+}
+
+// LoadRules loads all placement rules from storage, f is called for each rule
+// with its key and the marshaled value.
+func (s *Storage) LoadRules(f func(k, v string)) error {
+	prefix := rulesPath + "/"
+	// '0' is the next character of '/', it is used as the end of the range.
+	endKey := rulesPath + "0"
+	nextKey := prefix
+	for {
+		keys, values, err := s.LoadRange(nextKey, endKey, minKVRangeLimit)
+		if err != nil {
+			return err
+		}
+		for i := range keys {
+			f(strings.TrimPrefix(keys[i], prefix), values[i])
+		}
+		if len(keys) < minKVRangeLimit {
+			return nil
+		}
+		nextKey = keys[len(keys)-1] + "\x00"
+	}
+}
+
 // LoadStores loads all stores from storage to StoresInfo.
 func (s *Storage) LoadStores(stores *StoresInfo) error {
 	nextID := uint64(0)
@@ -200,7 +370,11 @@ func (s *Storage) LoadStores(stores *StoresInfo) error {
 			if err != nil {
 				return err
 			}
-			newStoreInfo := NewStoreInfo(store, SetLeaderWeight(leaderWeight), SetRegionWeight(regionWeight))
+			maintenance, err := s.loadStoreMaintenance(store.GetId())
+			if err != nil {
+				return err
+			}
+			newStoreInfo := NewStoreInfo(store, SetLeaderWeight(leaderWeight), SetRegionWeight(regionWeight), SetStoreMaintenance(maintenance))
 
 			nextID = store.GetId() + 1
 			stores.SetStore(newStoreInfo)
@@ -221,6 +395,71 @@ func (s *Storage) SaveStoreWeight(storeID uint64, leader, region float64) error
 	return s.Save(s.storeRegionWeightPath(storeID), regionValue)
 }
 
+// SaveStoreMaintenance saves the maintenance of a store.
+func (s *Storage) SaveStoreMaintenance(storeID uint64, maintenance *StoreMaintenance) error {
+	value, err := json.Marshal(maintenance)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return s.Save(s.storeMaintenancePath(storeID), string(value))
+}
+
+// DeleteStoreMaintenance deletes the maintenance of a store.
+func (s *Storage) DeleteStoreMaintenance(storeID uint64) error {
+	return s.Remove(s.storeMaintenancePath(storeID))
+}
+
+func (s *Storage) loadStoreMaintenance(storeID uint64) (*StoreMaintenance, error) {
+	value, err := s.Load(s.storeMaintenancePath(storeID))
+	if err != nil || value == "" {
+		return nil, err
+	}
+	maintenance := &StoreMaintenance{}
+	if err := json.Unmarshal([]byte(value), maintenance); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return maintenance, nil
+}
+
+// SaveStoreLimit saves a store's limit of the type to storage.
+func (s *Storage) SaveStoreLimit(storeID uint64, limitType string, rate float64) error {
+	return s.Save(s.storeLimitPath(storeID, limitType), strconv.FormatFloat(rate, 'f', -1, 64))
+}
+
+// LoadStoreLimits loads all store limits from storage, f is called for each
+// limit with the store ID, the limit type and the rate.
+func (s *Storage) LoadStoreLimits(f func(storeID uint64, limitType string, rate float64)) error {
+	prefix := path.Join(schedulePath, "store_limit") + "/"
+	// '0' is the next character of '/', it is used as the end of the range.
+	endKey := path.Join(schedulePath, "store_limit") + "0"
+	nextKey := prefix
+	for {
+		keys, values, err := s.LoadRange(nextKey, endKey, minKVRangeLimit)
+		if err != nil {
+			return err
+		}
+		for i := range keys {
+			parts := strings.Split(strings.TrimPrefix(keys[i], prefix), "/")
+			if len(parts) != 2 {
+				continue
+			}
+			storeID, err := strconv.ParseUint(parts[0], 10, 64)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			rate, err := strconv.ParseFloat(values[i], 64)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			f(storeID, parts[1], rate)
+		}
+		if len(keys) < minKVRangeLimit {
+			return nil
+		}
+		nextKey = keys[len(keys)-1] + "\x00"
+	}
+}
+
 func (s *Storage) loadFloatWithDefaultValue(path string, def float64) (float64, error) {
 	res, err := s.Load(path)
 	if err != nil {
@@ -236,16 +475,26 @@ func (s *Storage) loadFloatWithDefaultValue(path string, def float64) (float64,
 	return val, nil
 }
 
-// Flush flushes the dirty region to storage.
+// Flush writes the regions in the queue to storage.
 func (s *Storage) Flush() error {
-	if s.regionStorage != nil {
-		return s.regionStorage.FlushRegion()
-	}
-	return nil
+	return s.regionQueue.flush()
 }
 
 // Close closes the s.
 func (s *Storage) Close() error {
+	if err := s.Flush(); err != nil {
+		log.Error("flush regions before closing the storage meet error", zap.Error(err))
+	}
+	if s.operatorHistoryKV != nil {
+		if err := s.operatorHistoryKV.Close(); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if s.hotRegionHistoryKV != nil {
+		if err := s.hotRegionHistoryKV.Close(); err != nil {
+			return errors.WithStack(err)
+		}
+	}
 	if s.regionStorage != nil {
 		return s.regionStorage.Close()
 	}
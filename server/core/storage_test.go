@@ -16,6 +16,8 @@ package core
 import (
 	"fmt"
 	"math"
+	"sync"
+	"time"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/kvproto/pkg/metapb"
@@ -118,6 +120,53 @@ func (s *testKVSuite) TestStoreWeight(c *C) {
 	}
 }
 
+func (s *testKVSuite) TestStoreMaintenance(c *C) {
+	storage := NewStorage(kv.NewMemoryKV())
+	const n = 3
+
+	mustSaveStores(c, storage, n)
+	expire := time.Now().Add(time.Hour)
+	c.Assert(storage.SaveStoreMaintenance(1, &StoreMaintenance{Expire: expire, EvictLeader: true}), IsNil)
+	c.Assert(storage.SaveStoreMaintenance(2, &StoreMaintenance{Expire: expire}), IsNil)
+	c.Assert(storage.DeleteStoreMaintenance(2), IsNil)
+	cache := NewStoresInfo()
+	c.Assert(storage.LoadStores(cache), IsNil)
+	c.Assert(cache.GetStore(0).GetMaintenance(), IsNil)
+	c.Assert(cache.GetStore(0).IsInMaintenance(), IsFalse)
+	maintenance := cache.GetStore(1).GetMaintenance()
+	c.Assert(maintenance.Expire.Equal(expire), IsTrue)
+	c.Assert(maintenance.EvictLeader, IsTrue)
+	c.Assert(cache.GetStore(1).IsInMaintenance(), IsTrue)
+	c.Assert(cache.GetStore(2).GetMaintenance(), IsNil)
+
+	// The expired maintenance is loaded but not in effect.
+	c.Assert(storage.SaveStoreMaintenance(2, &StoreMaintenance{Expire: time.Now().Add(-time.Minute)}), IsNil)
+	c.Assert(storage.LoadStores(cache), IsNil)
+	c.Assert(cache.GetStore(2).GetMaintenance(), NotNil)
+	c.Assert(cache.GetStore(2).IsInMaintenance(), IsFalse)
+}
+
+func (s *testKVSuite) TestStoreLimit(c *C) {
+	storage := NewStorage(kv.NewMemoryKV())
+	c.Assert(storage.SaveStoreLimit(1, "add-peer", 1.5), IsNil)
+	c.Assert(storage.SaveStoreLimit(1, "remove-peer", 2), IsNil)
+	c.Assert(storage.SaveStoreLimit(2, "add-peer", 3), IsNil)
+	c.Assert(storage.SaveStoreLimit(1, "add-peer", 0.5), IsNil)
+
+	limits := make(map[uint64]map[string]float64)
+	err := storage.LoadStoreLimits(func(storeID uint64, limitType string, rate float64) {
+		if limits[storeID] == nil {
+			limits[storeID] = make(map[string]float64)
+		}
+		limits[storeID][limitType] = rate
+	})
+	c.Assert(err, IsNil)
+	c.Assert(limits, DeepEquals, map[uint64]map[string]float64{
+		1: {"add-peer": 0.5, "remove-peer": 2},
+		2: {"add-peer": 3},
+	})
+}
+
 func mustSaveRegions(c *C, s *Storage, n int) []*metapb.Region {
 	regions := make([]*metapb.Region, 0, n)
 	for i := 0; i < n; i++ {
@@ -159,6 +208,103 @@ func (s *testKVSuite) TestLoadRegionsExceedRangeLimit(c *C) {
 	}
 }
 
+func (s *testKVSuite) TestRegionQueue(c *C) {
+	base := &KVWithSaveError{Base: kv.NewMemoryKV()}
+	storage := NewStorage(base)
+	loadBase := func(id uint64) (*metapb.Region, bool) {
+		region := &metapb.Region{}
+		ok, err := loadProto(base, regionPath(id), region)
+		c.Assert(err, IsNil)
+		return region, ok
+	}
+
+	// The updates of a region are coalesced, and the region in the queue is
+	// loaded before it is written.
+	region := newTestRegionMeta(1)
+	c.Assert(storage.SaveRegion(region), IsNil)
+	region = newTestRegionMeta(1)
+	region.RegionEpoch = &metapb.RegionEpoch{Version: 2}
+	c.Assert(storage.SaveRegion(region), IsNil)
+	_, ok := loadBase(1)
+	c.Assert(ok, IsFalse)
+	loaded := &metapb.Region{}
+	ok, err := storage.LoadRegion(1, loaded)
+	c.Assert(ok, IsTrue)
+	c.Assert(err, IsNil)
+	c.Assert(loaded, DeepEquals, region)
+	c.Assert(storage.Flush(), IsNil)
+	loaded, ok = loadBase(1)
+	c.Assert(ok, IsTrue)
+	c.Assert(loaded, DeepEquals, region)
+
+	// The deleted region is not loaded.
+	c.Assert(storage.DeleteRegion(region), IsNil)
+	ok, err = storage.LoadRegion(1, loaded)
+	c.Assert(ok, IsFalse)
+	c.Assert(err, IsNil)
+	c.Assert(storage.Flush(), IsNil)
+	_, ok = loadBase(1)
+	c.Assert(ok, IsFalse)
+
+	// The regions failed to be written are kept in the queue.
+	base.fail = true
+	c.Assert(storage.SaveRegion(newTestRegionMeta(2)), IsNil)
+	c.Assert(storage.Flush(), NotNil)
+	ok, err = storage.LoadRegion(2, loaded)
+	c.Assert(ok, IsTrue)
+	c.Assert(err, IsNil)
+	base.fail = false
+	c.Assert(storage.Flush(), IsNil)
+	_, ok = loadBase(2)
+	c.Assert(ok, IsTrue)
+
+	// A full batch is written in the background.
+	regions := mustSaveRegions(c, storage, regionQueueBatchSize)
+	for i := 0; i < 100; i++ {
+		if _, ok = loadBase(regions[len(regions)-1].GetId()); ok {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	c.Assert(ok, IsTrue)
+}
+
+func (s *testKVSuite) TestRegionQueueBatches(c *C) {
+	base := &KVWithBatches{Base: kv.NewMemoryKV()}
+	storage := NewStorage(base)
+
+	// The regions are written in capped batches.
+	base.setError(errors.New("write failed"))
+	regions := mustSaveRegions(c, storage, regionQueueBatchSize*2+1)
+	c.Assert(storage.Flush(), NotNil)
+	base.setError(nil)
+	c.Assert(storage.Flush(), IsNil)
+	total := 0
+	for _, n := range base.getBatches() {
+		c.Assert(n, LessEqual, regionQueueBatchSize)
+		total += n
+	}
+	c.Assert(total, Equals, len(regions))
+
+	// The updates of the new regions are dropped above the high-water mark.
+	base.setError(errors.New("write failed"))
+	mustSaveRegions(c, storage, regionQueueHighWater)
+	c.Assert(storage.SaveRegion(newTestRegionMeta(regionQueueHighWater)), NotNil)
+	c.Assert(storage.SaveRegion(newTestRegionMeta(1)), IsNil)
+
+	// The pending regions are dropped once the leadership is lost, they may be
+	// dropped by a background flush before Flush is called.
+	base.setError(kv.ErrNotLeader)
+	if err := storage.Flush(); err != nil {
+		c.Assert(errors.Cause(err), Equals, kv.ErrNotLeader)
+	}
+	loaded := &metapb.Region{}
+	ok, err := storage.LoadRegion(regionQueueHighWater-1, loaded)
+	c.Assert(err, IsNil)
+	c.Assert(ok, IsFalse)
+	c.Assert(storage.SaveRegion(newTestRegionMeta(regionQueueHighWater)), IsNil)
+}
+
 func (s *testKVSuite) TestLoadGCSafePoint(c *C) {
 	storage := NewStorage(kv.NewMemoryKV())
 	testData := []uint64{0, 1, 2, 233, 2333, 23333333333, math.MaxUint64}
@@ -194,3 +340,54 @@ func newTestRegionMeta(regionID uint64) *metapb.Region {
 		EndKey:   []byte(fmt.Sprintf("%20d", regionID+1)),
 	}
 }
+
+type KVWithBatches struct {
+	kv.Base
+	sync.Mutex
+	err     error
+	batches []int
+}
+
+func (kv *KVWithBatches) setError(err error) {
+	kv.Lock()
+	defer kv.Unlock()
+	kv.err = err
+}
+
+func (kv *KVWithBatches) getBatches() []int {
+	kv.Lock()
+	defer kv.Unlock()
+	return kv.batches
+}
+
+func (kv *KVWithBatches) WriteBatch(saves map[string]string, removes []string) error {
+	kv.Lock()
+	defer kv.Unlock()
+	if kv.err != nil {
+		return kv.err
+	}
+	kv.batches = append(kv.batches, len(saves)+len(removes))
+	for key, value := range saves {
+		if err := kv.Base.Save(key, value); err != nil {
+			return err
+		}
+	}
+	for _, key := range removes {
+		if err := kv.Base.Remove(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type KVWithSaveError struct {
+	kv.Base
+	fail bool
+}
+
+func (kv *KVWithSaveError) Save(key, value string) error {
+	if kv.fail {
+		return errors.New("save failed")
+	}
+	return kv.Base.Save(key, value)
+}
@@ -41,6 +41,21 @@ type StoreInfo struct {
 	leaderWeight     float64
 	regionWeight     float64
 	overloaded       func() bool
+	// maintenance is the planned maintenance of the store, nil means the
+	// store is not in maintenance.
+	maintenance *StoreMaintenance
+	// evictingRegions means that the peers are being moved out of the store,
+	// no region is moved to it.
+	evictingRegions bool
+}
+
+// StoreMaintenance is the planned maintenance of a store, e.g. a reboot. The
+// down peers of the store are not repaired and no new peer is added to the
+// store until the maintenance expires.
+type StoreMaintenance struct {
+	Expire time.Time `json:"expire"`
+	// EvictLeader moves the leaders away from the store.
+	EvictLeader bool `json:"evict_leader"`
 }
 
 // NewStoreInfo creates StoreInfo with meta data.
@@ -72,6 +87,8 @@ func (s *StoreInfo) Clone(opts ...StoreCreateOption) *StoreInfo {
 		leaderWeight:     s.leaderWeight,
 		regionWeight:     s.regionWeight,
 		overloaded:       s.overloaded,
+		maintenance:      s.maintenance,
+		evictingRegions:  s.evictingRegions,
 	}
 
 	for _, opt := range opts {
@@ -85,6 +102,23 @@ func (s *StoreInfo) IsBlocked() bool {
 	return s.blocked
 }
 
+// IsEvictingRegions returns if the regions are being evicted from the store.
+func (s *StoreInfo) IsEvictingRegions() bool {
+	return s.evictingRegions
+}
+
+// GetMaintenance returns the maintenance of the store, which is nil if the
+// maintenance is not set.
+func (s *StoreInfo) GetMaintenance() *StoreMaintenance {
+	return s.maintenance
+}
+
+// IsInMaintenance checks if the maintenance of the store is set and not
+// expired.
+func (s *StoreInfo) IsInMaintenance() bool {
+	return s.maintenance != nil && time.Now().Before(s.maintenance.Expire)
+}
+
 // IsOverloaded returns if the store is overloaded.
 func (s *StoreInfo) IsOverloaded() bool {
 	if s.overloaded == nil {
@@ -540,6 +574,30 @@ func (s *StoresInfo) UnblockStore(storeID uint64) {
 	s.stores[storeID] = store.Clone(SetStoreUnBlock())
 }
 
+// EvictRegions marks a StoreInfo with storeID as evicting regions.
+func (s *StoresInfo) EvictRegions(storeID uint64) errcode.ErrorCode {
+	op := errcode.Op("store.evict_regions")
+	store, ok := s.stores[storeID]
+	if !ok {
+		return op.AddTo(NewStoreNotFoundErr(storeID))
+	}
+	if store.IsEvictingRegions() {
+		return op.AddTo(StoreEvictingRegionsErr{StoreID: storeID})
+	}
+	s.stores[storeID] = store.Clone(SetStoreEvictRegions())
+	return nil
+}
+
+// UnEvictRegions clears the evicting mark of a StoreInfo with storeID.
+func (s *StoresInfo) UnEvictRegions(storeID uint64) {
+	store, ok := s.stores[storeID]
+	if !ok {
+		log.Fatal("store stops evicting regions, but it is not found",
+			zap.Uint64("store-id", storeID))
+	}
+	s.stores[storeID] = store.Clone(SetStoreUnEvictRegions())
+}
+
 // AttachOverloadStatus attaches the overload status to a store.
 func (s *StoresInfo) AttachOverloadStatus(storeID uint64, f func() bool) {
 	if store, ok := s.stores[storeID]; ok {
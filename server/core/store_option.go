@@ -16,6 +16,7 @@ package core
 import (
 	"time"
 
+	"github.com/gogo/protobuf/proto"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
 )
@@ -44,10 +45,13 @@ func SetStoreVersion(version string) StoreCreateOption {
 	}
 }
 
-// SetStoreState sets the state for the store.
+// SetStoreState sets the state for the store. The meta is copied so that the
+// state of the original store is kept.
 func SetStoreState(state metapb.StoreState) StoreCreateOption {
 	return func(store *StoreInfo) {
-		store.meta.State = state
+		meta := proto.Clone(store.meta).(*metapb.Store)
+		meta.State = state
+		store.meta = meta
 	}
 }
 
@@ -65,6 +69,20 @@ func SetStoreUnBlock() StoreCreateOption {
 	}
 }
 
+// SetStoreEvictRegions stops moving regions to the store.
+func SetStoreEvictRegions() StoreCreateOption {
+	return func(store *StoreInfo) {
+		store.evictingRegions = true
+	}
+}
+
+// SetStoreUnEvictRegions allows moving regions to the store.
+func SetStoreUnEvictRegions() StoreCreateOption {
+	return func(store *StoreInfo) {
+		store.evictingRegions = false
+	}
+}
+
 // SetLeaderCount sets the leader count for the store.
 func SetLeaderCount(leaderCount int) StoreCreateOption {
 	return func(store *StoreInfo) {
@@ -134,3 +152,11 @@ func SetOverloadStatus(f func() bool) StoreCreateOption {
 		store.overloaded = f
 	}
 }
+
+// SetStoreMaintenance sets the maintenance of the store, nil means the
+// maintenance ends.
+func SetStoreMaintenance(maintenance *StoreMaintenance) StoreCreateOption {
+	return func(store *StoreInfo) {
+		store.maintenance = maintenance
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/pd/server/core"
 	"github.com/pingcap/pd/server/schedule"
 	"github.com/pingcap/pd/server/schedule/operator"
+	"github.com/pingcap/pd/server/schedule/storelimit"
 	"github.com/pingcap/pd/server/statistics"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
@@ -37,12 +38,27 @@ var (
 	ErrOperatorNotFound = errors.New("operator not found")
 	// ErrAddOperator is error info for already have an operator when adding operator
 	ErrAddOperator = errors.New("failed to add operator, maybe already have one")
+	// ErrAddOperatorBatch is error info for the operator batch cannot be admitted
+	ErrAddOperatorBatch = errors.New("failed to add operator batch, maybe some regions already have operators, the epochs are stale or the store limits are exceeded")
 	// ErrRegionNotAdjacent is error info for region not adjacent
 	ErrRegionNotAdjacent = errors.New("two regions are not adjacent")
+	// ErrOperatorHistoryNotEnabled is error info for operator history not enabled
+	ErrOperatorHistoryNotEnabled = errors.New("operator history is not enabled")
+	// ErrHotRegionHistoryNotEnabled is error info for hot region history not enabled
+	ErrHotRegionHistoryNotEnabled = errors.New("hot region history is not enabled")
+	// ErrSchedulerManagedByPD is error info for the scheduler which can only
+	// be added by PD itself
+	ErrSchedulerManagedByPD = func(name string) error {
+		return errors.Errorf("scheduler %v is managed by PD", name)
+	}
 	// ErrRegionNotFound is error info for region not found
 	ErrRegionNotFound = func(regionID uint64) error {
 		return errors.Errorf("region %v not found", regionID)
 	}
+	// ErrOperatorBatchNotFound is error info for operator batch not found
+	ErrOperatorBatchNotFound = func(id uint64) error {
+		return errors.Errorf("operator batch %v not found", id)
+	}
 	// ErrRegionAbnormalPeer is error info for region has abonormal peer
 	ErrRegionAbnormalPeer = func(regionID uint64) error {
 		return errors.Errorf("region %v has abnormal peer", regionID)
@@ -92,6 +108,15 @@ func (h *Handler) GetSchedulers() ([]string, error) {
 	return c.getSchedulers(), nil
 }
 
+// GetPausedSchedulers returns all names of paused schedulers.
+func (h *Handler) GetPausedSchedulers() ([]string, error) {
+	c, err := h.getCoordinator()
+	if err != nil {
+		return nil, err
+	}
+	return c.getPausedSchedulers(), nil
+}
+
 // GetStores returns all stores in the cluster.
 func (h *Handler) GetStores() ([]*core.StoreInfo, error) {
 	cluster := h.s.GetRaftCluster()
@@ -119,6 +144,50 @@ func (h *Handler) GetHotWriteRegions() *statistics.StoreHotRegionInfos {
 	return c.getHotWriteRegions()
 }
 
+// GetHotRegionHistory returns the hot peers in the hot region history
+// matching the filter, the latest first.
+func (h *Handler) GetHotRegionHistory(filter *statistics.HotRegionHistoryFilter) ([]*statistics.HotRegionHistoryRecord, error) {
+	cluster := h.s.GetRaftCluster()
+	if cluster == nil {
+		return nil, errors.WithStack(ErrNotBootstrapped)
+	}
+	history := cluster.GetHotRegionHistory()
+	if history == nil {
+		return nil, errors.WithStack(ErrHotRegionHistoryNotEnabled)
+	}
+	return history.Query(filter)
+}
+
+// GetCapacityForecast returns the projected days until the stores and the
+// cluster reach the low space ratio.
+func (h *Handler) GetCapacityForecast() (*statistics.CapacityForecasts, error) {
+	cluster := h.s.GetRaftCluster()
+	if cluster == nil {
+		return nil, errors.WithStack(ErrNotBootstrapped)
+	}
+	return cluster.GetCapacityForecast(), nil
+}
+
+// AuditKeySpace returns the holes and the stale leaders in the key space of
+// the regions.
+func (h *Handler) AuditKeySpace() (*statistics.KeySpaceAuditReport, error) {
+	cluster := h.s.GetRaftCluster()
+	if cluster == nil {
+		return nil, errors.WithStack(ErrNotBootstrapped)
+	}
+	return cluster.AuditKeySpace(), nil
+}
+
+// GetHeatmap returns the key-space traffic heatmap of the time buckets which
+// end in the time window [start, end].
+func (h *Handler) GetHeatmap(start, end time.Time) (*statistics.HeatmapMatrix, error) {
+	cluster := h.s.GetRaftCluster()
+	if cluster == nil {
+		return nil, errors.WithStack(ErrNotBootstrapped)
+	}
+	return cluster.GetHeatmap().GetMatrix(start, end), nil
+}
+
 // GetHotReadRegions gets all hot read regions stats.
 func (h *Handler) GetHotReadRegions() *statistics.StoreHotRegionInfos {
 	c, err := h.getCoordinator()
@@ -170,6 +239,9 @@ func (h *Handler) AddScheduler(name string, args ...string) error {
 	if err != nil {
 		return err
 	}
+	if schedule.IsSchedulerPDManaged(name) {
+		return ErrSchedulerManagedByPD(name)
+	}
 	s, err := schedule.CreateScheduler(name, c.opController, args...)
 	if err != nil {
 		return err
@@ -197,6 +269,55 @@ func (h *Handler) RemoveScheduler(name string) error {
 	return err
 }
 
+// GetSchedulerProgress returns the progress of a scheduler which has a
+// definite goal.
+func (h *Handler) GetSchedulerProgress(name string) (*schedule.Progress, error) {
+	c, err := h.getCoordinator()
+	if err != nil {
+		return nil, err
+	}
+	return c.getSchedulerProgress(name)
+}
+
+// GetSchedulerDiagnosis returns the diagnosis of a scheduler, which tells
+// why it creates no operator.
+func (h *Handler) GetSchedulerDiagnosis(name string) (*SchedulerDiagnosis, error) {
+	c, err := h.getCoordinator()
+	if err != nil {
+		return nil, err
+	}
+	return c.getSchedulerDiagnosis(name)
+}
+
+// PauseScheduler pauses a scheduler for the given seconds, 0 means the
+// scheduler is paused until it is resumed.
+func (h *Handler) PauseScheduler(name string, seconds int64) error {
+	c, err := h.getCoordinator()
+	if err != nil {
+		return err
+	}
+	if err = c.pauseScheduler(name, time.Duration(seconds)*time.Second); err != nil {
+		log.Error("can not pause scheduler", zap.String("scheduler-name", name), zap.Error(err))
+	} else if err = h.opt.Persist(c.cluster.storage); err != nil {
+		log.Error("can not persist scheduler config", zap.Error(err))
+	}
+	return err
+}
+
+// ResumeScheduler resumes a paused scheduler.
+func (h *Handler) ResumeScheduler(name string) error {
+	c, err := h.getCoordinator()
+	if err != nil {
+		return err
+	}
+	if err = c.resumeScheduler(name); err != nil {
+		log.Error("can not resume scheduler", zap.String("scheduler-name", name), zap.Error(err))
+	} else if err = h.opt.Persist(c.cluster.storage); err != nil {
+		log.Error("can not persist scheduler config", zap.Error(err))
+	}
+	return err
+}
+
 // AddBalanceLeaderScheduler adds a balance-leader-scheduler.
 func (h *Handler) AddBalanceLeaderScheduler() error {
 	return h.AddScheduler("balance-leader")
@@ -237,6 +358,15 @@ func (h *Handler) AddEvictLeaderScheduler(storeID uint64) error {
 	return h.AddScheduler("evict-leader", strconv.FormatUint(storeID, 10))
 }
 
+// AddEvictRegionScheduler adds an evict-region-scheduler.
+func (h *Handler) AddEvictRegionScheduler(storeIDs ...uint64) error {
+	args := make([]string, 0, len(storeIDs))
+	for _, id := range storeIDs {
+		args = append(args, strconv.FormatUint(id, 10))
+	}
+	return h.AddScheduler("evict-region", args...)
+}
+
 // AddShuffleLeaderScheduler adds a shuffle-leader-scheduler.
 func (h *Handler) AddShuffleLeaderScheduler() error {
 	return h.AddScheduler("shuffle-leader")
@@ -257,6 +387,11 @@ func (h *Handler) AddRandomMergeScheduler() error {
 	return h.AddScheduler("random-merge")
 }
 
+// AddSplitHotRegionScheduler adds a split-hot-region-scheduler.
+func (h *Handler) AddSplitHotRegionScheduler() error {
+	return h.AddScheduler("split-hot-region")
+}
+
 // GetOperator returns the region operator.
 func (h *Handler) GetOperator(regionID uint64) (*operator.Operator, error) {
 	c, err := h.getCoordinator()
@@ -299,7 +434,7 @@ func (h *Handler) RemoveOperator(regionID uint64) error {
 		return ErrOperatorNotFound
 	}
 
-	c.opController.RemoveOperator(op)
+	c.opController.CancelOperator(op)
 	return nil
 }
 
@@ -360,57 +495,97 @@ func (h *Handler) GetHistory(start time.Time) ([]operator.OpHistory, error) {
 	return c.opController.GetHistory(start), nil
 }
 
-// SetAllStoresLimit is used to set limit of all stores.
-func (h *Handler) SetAllStoresLimit(rate float64) error {
+// GetOperatorHistory returns the ended operators matching the filter, the
+// latest first.
+func (h *Handler) GetOperatorHistory(filter *schedule.OperatorHistoryFilter) ([]*schedule.OperatorHistoryRecord, error) {
+	c, err := h.getCoordinator()
+	if err != nil {
+		return nil, err
+	}
+	history := c.opController.GetOperatorHistory()
+	if history == nil {
+		return nil, errors.WithStack(ErrOperatorHistoryNotEnabled)
+	}
+	return history.Query(filter)
+}
+
+// SubscribeEvents subscribes the scheduling events matching the filter. The
+// subscriber is closed once the current leader steps down, and the caller
+// should close it once it is not used.
+func (h *Handler) SubscribeEvents(filter schedule.EventFilter) (*schedule.EventSubscriber, error) {
+	c, err := h.getCoordinator()
+	if err != nil {
+		return nil, err
+	}
+	return c.eventBus.Subscribe(filter, schedule.DefaultEventBufferSize), nil
+}
+
+// SetAllStoresLimit is used to set the limit of the type for all stores,
+// the limits are saved to storage.
+func (h *Handler) SetAllStoresLimit(rate float64, limitType storelimit.Type) error {
 	c, err := h.getCoordinator()
 	if err != nil {
 		return err
 	}
-	c.opController.SetAllStoresLimit(rate)
+	for _, store := range c.cluster.GetStores() {
+		if store.IsTombstone() {
+			continue
+		}
+		if err := c.cluster.storage.SaveStoreLimit(store.GetID(), limitType.String(), rate); err != nil {
+			return err
+		}
+	}
+	c.opController.SetAllStoresLimit(rate, limitType)
 	return nil
 }
 
-// GetAllStoresLimit is used to get limit of all stores.
-func (h *Handler) GetAllStoresLimit() (map[uint64]float64, error) {
+// GetAllStoresLimit is used to get the limit of the type of all stores.
+func (h *Handler) GetAllStoresLimit(limitType storelimit.Type) (map[uint64]float64, error) {
 	c, err := h.getCoordinator()
 	if err != nil {
 		return nil, err
 	}
-	return c.opController.GetAllStoresLimit(), nil
+	return c.opController.GetAllStoresLimit(limitType), nil
 }
 
-// SetStoreLimit is used to set the limit of a store.
-func (h *Handler) SetStoreLimit(storeID uint64, rate float64) error {
+// SetStoreLimit is used to set the limit of the type for a store, the limit
+// is saved to storage.
+func (h *Handler) SetStoreLimit(storeID uint64, rate float64, limitType storelimit.Type) error {
 	c, err := h.getCoordinator()
 	if err != nil {
 		return err
 	}
-	c.opController.SetStoreLimit(storeID, rate)
+	if err := c.cluster.storage.SaveStoreLimit(storeID, limitType.String(), rate); err != nil {
+		return err
+	}
+	c.opController.SetStoreLimit(storeID, rate, limitType)
 	return nil
 }
 
 // AddTransferLeaderOperator adds an operator to transfer leader to the store.
 func (h *Handler) AddTransferLeaderOperator(regionID uint64, storeID uint64) error {
+	return h.addOperators(h.newTransferLeaderOperators(regionID, storeID))
+}
+
+// newTransferLeaderOperators creates the operators to transfer leader to the store.
+func (h *Handler) newTransferLeaderOperators(regionID uint64, storeID uint64) ([]*operator.Operator, error) {
 	c, err := h.getCoordinator()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	region := c.cluster.GetRegion(regionID)
 	if region == nil {
-		return ErrRegionNotFound(regionID)
+		return nil, ErrRegionNotFound(regionID)
 	}
 
 	newLeader := region.GetStoreVoter(storeID)
 	if newLeader == nil {
-		return errors.Errorf("region has no voter in store %v", storeID)
+		return nil, errors.Errorf("region has no voter in store %v", storeID)
 	}
 
 	op := operator.CreateTransferLeaderOperator("admin-transfer-leader", region, region.GetLeader().GetStoreId(), newLeader.GetStoreId(), operator.OpAdmin)
-	if ok := c.opController.AddOperator(op); !ok {
-		return errors.WithStack(ErrAddOperator)
-	}
-	return nil
+	return []*operator.Operator{op}, nil
 }
 
 // AddTransferRegionOperator adds an operator to transfer region to the stores.
@@ -419,34 +594,41 @@ func (h *Handler) AddTransferRegionOperator(regionID uint64, storeIDs map[uint64
 	if err != nil {
 		return err
 	}
+	return h.addOperators(h.newTransferRegionOperators(c.cluster, regionID, storeIDs))
+}
+
+// newTransferRegionOperators creates the operators to transfer region to the
+// stores, the new peers are allocated from the cluster.
+func (h *Handler) newTransferRegionOperators(cluster schedule.Cluster, regionID uint64, storeIDs map[uint64]struct{}) ([]*operator.Operator, error) {
+	c, err := h.getCoordinator()
+	if err != nil {
+		return nil, err
+	}
 
 	region := c.cluster.GetRegion(regionID)
 	if region == nil {
-		return ErrRegionNotFound(regionID)
+		return nil, ErrRegionNotFound(regionID)
 	}
 
 	if len(storeIDs) > c.cluster.GetMaxReplicas() {
-		return errors.Errorf("the number of stores is %v, beyond the max replicas", len(storeIDs))
+		return nil, errors.Errorf("the number of stores is %v, beyond the max replicas", len(storeIDs))
 	}
 
 	for id := range storeIDs {
 		store := c.cluster.GetStore(id)
 		if store == nil {
-			return core.NewStoreNotFoundErr(id)
+			return nil, core.NewStoreNotFoundErr(id)
 		}
 		if store.IsTombstone() {
-			return errcode.Op("operator.add").AddTo(core.StoreTombstonedErr{StoreID: id})
+			return nil, errcode.Op("operator.add").AddTo(core.StoreTombstonedErr{StoreID: id})
 		}
 	}
 
-	op, err := operator.CreateMoveRegionOperator("admin-move-region", c.cluster, region, operator.OpAdmin, storeIDs)
+	op, err := operator.CreateMoveRegionOperator("admin-move-region", cluster, region, operator.OpAdmin, storeIDs)
 	if err != nil {
-		return err
-	}
-	if ok := c.opController.AddOperator(op); !ok {
-		return errors.WithStack(ErrAddOperator)
+		return nil, err
 	}
-	return nil
+	return []*operator.Operator{op}, nil
 }
 
 // AddTransferPeerOperator adds an operator to transfer peer.
@@ -455,38 +637,45 @@ func (h *Handler) AddTransferPeerOperator(regionID uint64, fromStoreID, toStoreI
 	if err != nil {
 		return err
 	}
+	return h.addOperators(h.newTransferPeerOperators(c.cluster, regionID, fromStoreID, toStoreID))
+}
+
+// newTransferPeerOperators creates the operators to transfer peer, the new peer
+// is allocated from the cluster.
+func (h *Handler) newTransferPeerOperators(cluster schedule.Cluster, regionID uint64, fromStoreID, toStoreID uint64) ([]*operator.Operator, error) {
+	c, err := h.getCoordinator()
+	if err != nil {
+		return nil, err
+	}
 
 	region := c.cluster.GetRegion(regionID)
 	if region == nil {
-		return ErrRegionNotFound(regionID)
+		return nil, ErrRegionNotFound(regionID)
 	}
 
 	oldPeer := region.GetStorePeer(fromStoreID)
 	if oldPeer == nil {
-		return errors.Errorf("region has no peer in store %v", fromStoreID)
+		return nil, errors.Errorf("region has no peer in store %v", fromStoreID)
 	}
 
 	toStore := c.cluster.GetStore(toStoreID)
 	if toStore == nil {
-		return core.NewStoreNotFoundErr(toStoreID)
+		return nil, core.NewStoreNotFoundErr(toStoreID)
 	}
 	if toStore.IsTombstone() {
-		return errcode.Op("operator.add").AddTo(core.StoreTombstonedErr{StoreID: toStoreID})
+		return nil, errcode.Op("operator.add").AddTo(core.StoreTombstonedErr{StoreID: toStoreID})
 	}
 
-	newPeer, err := c.cluster.AllocPeer(toStoreID)
+	newPeer, err := cluster.AllocPeer(toStoreID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	op, err := operator.CreateMovePeerOperator("admin-move-peer", c.cluster, region, operator.OpAdmin, fromStoreID, toStoreID, newPeer.GetId())
+	op, err := operator.CreateMovePeerOperator("admin-move-peer", cluster, region, operator.OpAdmin, fromStoreID, toStoreID, newPeer.GetId())
 	if err != nil {
-		return err
-	}
-	if ok := c.opController.AddOperator(op); !ok {
-		return errors.WithStack(ErrAddOperator)
+		return nil, err
 	}
-	return nil
+	return []*operator.Operator{op}, nil
 }
 
 // checkAdminAddPeerOperator checks adminAddPeer operator with given region ID and store ID.
@@ -518,132 +707,152 @@ func (h *Handler) checkAdminAddPeerOperator(regionID uint64, toStoreID uint64) (
 
 // AddAddPeerOperator adds an operator to add peer.
 func (h *Handler) AddAddPeerOperator(regionID uint64, toStoreID uint64) error {
-	c, region, err := h.checkAdminAddPeerOperator(regionID, toStoreID)
+	c, err := h.getCoordinator()
 	if err != nil {
 		return err
 	}
+	return h.addOperators(h.newAddPeerOperators(c.cluster, regionID, toStoreID))
+}
 
-	newPeer, err := c.cluster.AllocPeer(toStoreID)
+// newAddPeerOperators creates the operators to add peer, the new peer is
+// allocated from the cluster.
+func (h *Handler) newAddPeerOperators(cluster schedule.Cluster, regionID uint64, toStoreID uint64) ([]*operator.Operator, error) {
+	_, region, err := h.checkAdminAddPeerOperator(regionID, toStoreID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	op := operator.CreateAddPeerOperator("admin-add-peer", c.cluster, region, newPeer.GetId(), toStoreID, operator.OpAdmin)
-	if ok := c.opController.AddOperator(op); !ok {
-		return errors.WithStack(ErrAddOperator)
+	newPeer, err := cluster.AllocPeer(toStoreID)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+
+	op := operator.CreateAddPeerOperator("admin-add-peer", cluster, region, newPeer.GetId(), toStoreID, operator.OpAdmin)
+	return []*operator.Operator{op}, nil
 }
 
 // AddAddLearnerOperator adds an operator to add learner.
 func (h *Handler) AddAddLearnerOperator(regionID uint64, toStoreID uint64) error {
-	c, region, err := h.checkAdminAddPeerOperator(regionID, toStoreID)
+	c, err := h.getCoordinator()
 	if err != nil {
 		return err
 	}
+	return h.addOperators(h.newAddLearnerOperators(c.cluster, regionID, toStoreID))
+}
+
+// newAddLearnerOperators creates the operators to add learner, the new learner
+// is allocated from the cluster.
+func (h *Handler) newAddLearnerOperators(cluster schedule.Cluster, regionID uint64, toStoreID uint64) ([]*operator.Operator, error) {
+	c, region, err := h.checkAdminAddPeerOperator(regionID, toStoreID)
+	if err != nil {
+		return nil, err
+	}
 
 	if !c.cluster.IsRaftLearnerEnabled() {
-		return ErrOperatorNotFound
+		return nil, ErrOperatorNotFound
 	}
 
-	newPeer, err := c.cluster.AllocPeer(toStoreID)
+	newPeer, err := cluster.AllocPeer(toStoreID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	op := operator.CreateAddLearnerOperator("admin-add-learner", c.cluster, region, newPeer.GetId(), toStoreID, operator.OpAdmin)
-	if ok := c.opController.AddOperator(op); !ok {
-		return errors.WithStack(ErrAddOperator)
-	}
-	return nil
+	op := operator.CreateAddLearnerOperator("admin-add-learner", cluster, region, newPeer.GetId(), toStoreID, operator.OpAdmin)
+	return []*operator.Operator{op}, nil
 }
 
 // AddRemovePeerOperator adds an operator to remove peer.
 func (h *Handler) AddRemovePeerOperator(regionID uint64, fromStoreID uint64) error {
+	return h.addOperators(h.newRemovePeerOperators(regionID, fromStoreID))
+}
+
+// newRemovePeerOperators creates the operators to remove peer.
+func (h *Handler) newRemovePeerOperators(regionID uint64, fromStoreID uint64) ([]*operator.Operator, error) {
 	c, err := h.getCoordinator()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	region := c.cluster.GetRegion(regionID)
 	if region == nil {
-		return ErrRegionNotFound(regionID)
+		return nil, ErrRegionNotFound(regionID)
 	}
 
 	if region.GetStorePeer(fromStoreID) == nil {
-		return errors.Errorf("region has no peer in store %v", fromStoreID)
+		return nil, errors.Errorf("region has no peer in store %v", fromStoreID)
 	}
 
 	op, err := operator.CreateRemovePeerOperator("admin-remove-peer", c.cluster, operator.OpAdmin, region, fromStoreID)
 	if err != nil {
-		return err
-	}
-	if ok := c.opController.AddOperator(op); !ok {
-		return errors.WithStack(ErrAddOperator)
+		return nil, err
 	}
-	return nil
+	return []*operator.Operator{op}, nil
 }
 
 // AddMergeRegionOperator adds an operator to merge region.
 func (h *Handler) AddMergeRegionOperator(regionID uint64, targetID uint64) error {
+	return h.addOperators(h.newMergeRegionOperators(regionID, targetID))
+}
+
+// newMergeRegionOperators creates the operators to merge region.
+func (h *Handler) newMergeRegionOperators(regionID uint64, targetID uint64) ([]*operator.Operator, error) {
 	c, err := h.getCoordinator()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	region := c.cluster.GetRegion(regionID)
 	if region == nil {
-		return ErrRegionNotFound(regionID)
+		return nil, ErrRegionNotFound(regionID)
 	}
 
 	target := c.cluster.GetRegion(targetID)
 	if target == nil {
-		return ErrRegionNotFound(targetID)
+		return nil, ErrRegionNotFound(targetID)
 	}
 
 	if len(region.GetDownPeers()) > 0 || len(region.GetPendingPeers()) > 0 || len(region.GetLearners()) > 0 ||
-		len(region.GetPeers()) != c.cluster.GetMaxReplicas() {
-		return ErrRegionAbnormalPeer(regionID)
+		len(region.GetPeers()) != c.cluster.GetRegionRule(region).GetReplicas() {
+		return nil, ErrRegionAbnormalPeer(regionID)
 	}
 
 	if len(target.GetDownPeers()) > 0 || len(target.GetPendingPeers()) > 0 || len(target.GetLearners()) > 0 ||
-		len(target.GetMeta().GetPeers()) != c.cluster.GetMaxReplicas() {
-		return ErrRegionAbnormalPeer(targetID)
+		len(target.GetMeta().GetPeers()) != c.cluster.GetRegionRule(target).GetReplicas() {
+		return nil, ErrRegionAbnormalPeer(targetID)
 	}
 
 	// for the case first region (start key is nil) with the last region (end key is nil) but not adjacent
 	if (!bytes.Equal(region.GetStartKey(), target.GetEndKey()) || len(region.GetStartKey()) == 0) &&
 		(!bytes.Equal(region.GetEndKey(), target.GetStartKey()) || len(region.GetEndKey()) == 0) {
-		return ErrRegionNotAdjacent
+		return nil, ErrRegionNotAdjacent
 	}
 
 	ops, err := operator.CreateMergeRegionOperator("admin-merge-region", c.cluster, region, target, operator.OpAdmin)
 	if err != nil {
-		return err
-	}
-	if ok := c.opController.AddOperator(ops...); !ok {
-		return errors.WithStack(ErrAddOperator)
+		return nil, err
 	}
-	return nil
+	return ops, nil
 }
 
 // AddSplitRegionOperator adds an operator to split a region.
 func (h *Handler) AddSplitRegionOperator(regionID uint64, policy string) error {
+	return h.addOperators(h.newSplitRegionOperators(regionID, policy))
+}
+
+// newSplitRegionOperators creates the operators to split a region.
+func (h *Handler) newSplitRegionOperators(regionID uint64, policy string) ([]*operator.Operator, error) {
 	c, err := h.getCoordinator()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	region := c.cluster.GetRegion(regionID)
 	if region == nil {
-		return ErrRegionNotFound(regionID)
+		return nil, ErrRegionNotFound(regionID)
 	}
 
 	op := operator.CreateSplitRegionOperator("admin-split-region", region, operator.OpAdmin, policy)
-	if ok := c.opController.AddOperator(op); !ok {
-		return errors.WithStack(ErrAddOperator)
-	}
-	return nil
+	return []*operator.Operator{op}, nil
 }
 
 // AddScatterRegionOperator adds an operator to scatter a region.
@@ -652,26 +861,201 @@ func (h *Handler) AddScatterRegionOperator(regionID uint64) error {
 	if err != nil {
 		return err
 	}
+	return h.addOperators(h.newScatterRegionOperators(c.cluster, regionID))
+}
+
+// newScatterRegionOperators creates the operators to scatter a region, the new
+// peers are allocated from the cluster.
+func (h *Handler) newScatterRegionOperators(cluster schedule.Cluster, regionID uint64) ([]*operator.Operator, error) {
+	c, err := h.getCoordinator()
+	if err != nil {
+		return nil, err
+	}
 
 	region := c.cluster.GetRegion(regionID)
 	if region == nil {
-		return ErrRegionNotFound(regionID)
+		return nil, ErrRegionNotFound(regionID)
 	}
 
-	op, err := c.regionScatterer.Scatter(region)
+	scatterer := c.regionScatterer
+	if dryRun, ok := cluster.(*schedule.DryRunCluster); ok {
+		scatterer = scatterer.DryRun(dryRun)
+	}
+	op, err := scatterer.Scatter(region)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if op == nil {
+		return nil, nil
+	}
+	return []*operator.Operator{op}, nil
+}
+
+// addOperators adds the operators created by admin.
+func (h *Handler) addOperators(ops []*operator.Operator, err error) error {
+	if err != nil {
+		return err
+	}
+	if len(ops) == 0 {
 		return nil
 	}
-	if ok := c.opController.AddOperator(op); !ok {
+	c, err := h.getCoordinator()
+	if err != nil {
+		return err
+	}
+	if ok := c.opController.AddOperator(ops...); !ok {
 		return errors.WithStack(ErrAddOperator)
 	}
 	return nil
 }
 
+// OperatorSpec describes an operator created by admin. The fields used depend
+// on the name, which is the same as the operators added one by one.
+type OperatorSpec struct {
+	Name           string   `json:"name"`
+	RegionID       uint64   `json:"region_id,omitempty"`
+	StoreID        uint64   `json:"store_id,omitempty"`
+	FromStoreID    uint64   `json:"from_store_id,omitempty"`
+	ToStoreID      uint64   `json:"to_store_id,omitempty"`
+	ToStoreIDs     []uint64 `json:"to_store_ids,omitempty"`
+	SourceRegionID uint64   `json:"source_region_id,omitempty"`
+	TargetRegionID uint64   `json:"target_region_id,omitempty"`
+	Policy         string   `json:"policy,omitempty"`
+	// ConfVer and Version are the epoch of the region which the operator is
+	// created for, the source region for merge-region. Zero means the field
+	// is not checked.
+	ConfVer uint64 `json:"conf_ver,omitempty"`
+	Version uint64 `json:"version,omitempty"`
+}
+
+// checkEpoch checks if the region of the spec still has the epoch in the spec.
+func (spec *OperatorSpec) checkEpoch(c *RaftCluster) error {
+	if spec.ConfVer == 0 && spec.Version == 0 {
+		return nil
+	}
+	regionID := spec.RegionID
+	if spec.Name == "merge-region" {
+		regionID = spec.SourceRegionID
+	}
+	region := c.GetRegion(regionID)
+	if region == nil {
+		return ErrRegionNotFound(regionID)
+	}
+	epoch := region.GetRegionEpoch()
+	if (spec.ConfVer != 0 && spec.ConfVer != epoch.GetConfVer()) ||
+		(spec.Version != 0 && spec.Version != epoch.GetVersion()) {
+		return errors.Errorf("region %d epoch is changed, expected conf_ver %d and version %d, but got conf_ver %d and version %d",
+			regionID, spec.ConfVer, spec.Version, epoch.GetConfVer(), epoch.GetVersion())
+	}
+	return nil
+}
+
+// newOperatorsBySpec creates the operators of the spec, the new peers are
+// allocated from the cluster.
+func (h *Handler) newOperatorsBySpec(cluster schedule.Cluster, spec *OperatorSpec) ([]*operator.Operator, error) {
+	switch spec.Name {
+	case "transfer-leader":
+		return h.newTransferLeaderOperators(spec.RegionID, spec.ToStoreID)
+	case "transfer-region":
+		if len(spec.ToStoreIDs) == 0 {
+			return nil, errors.New("missing store ids to transfer region to")
+		}
+		storeIDs := make(map[uint64]struct{}, len(spec.ToStoreIDs))
+		for _, id := range spec.ToStoreIDs {
+			storeIDs[id] = struct{}{}
+		}
+		return h.newTransferRegionOperators(cluster, spec.RegionID, storeIDs)
+	case "transfer-peer":
+		return h.newTransferPeerOperators(cluster, spec.RegionID, spec.FromStoreID, spec.ToStoreID)
+	case "add-peer":
+		return h.newAddPeerOperators(cluster, spec.RegionID, spec.StoreID)
+	case "add-learner":
+		return h.newAddLearnerOperators(cluster, spec.RegionID, spec.StoreID)
+	case "remove-peer":
+		return h.newRemovePeerOperators(spec.RegionID, spec.StoreID)
+	case "merge-region":
+		return h.newMergeRegionOperators(spec.SourceRegionID, spec.TargetRegionID)
+	case "split-region":
+		return h.newSplitRegionOperators(spec.RegionID, spec.Policy)
+	case "scatter-region":
+		return h.newScatterRegionOperators(cluster, spec.RegionID)
+	}
+	return nil, errors.Errorf("unknown operator %s", spec.Name)
+}
+
+// AddOperatorBatch creates the operators and validates them against the
+// epochs in the specs and the store limits together. Either all of them are
+// added or none of them, and the ID of the batch is returned.
+func (h *Handler) AddOperatorBatch(specs []*OperatorSpec) (uint64, error) {
+	c, err := h.getCoordinator()
+	if err != nil {
+		return 0, err
+	}
+	// The operators are created on a dry run cluster and checked first, so
+	// no peer ID is allocated for a batch which is rejected.
+	ops, err := h.newOperatorBatch(c, schedule.NewDryRunCluster(c.cluster), specs)
+	if err != nil {
+		return 0, err
+	}
+	if !c.opController.CheckOperatorBatch(ops...) {
+		return 0, errors.WithStack(ErrAddOperatorBatch)
+	}
+	if ops, err = h.newOperatorBatch(c, c.cluster, specs); err != nil {
+		return 0, err
+	}
+	id, ok := c.opController.AddOperatorBatch(ops...)
+	if !ok {
+		return 0, errors.WithStack(ErrAddOperatorBatch)
+	}
+	return id, nil
+}
+
+// newOperatorBatch creates the operators of the specs, the new peers are
+// allocated from the cluster.
+func (h *Handler) newOperatorBatch(c *coordinator, cluster schedule.Cluster, specs []*OperatorSpec) ([]*operator.Operator, error) {
+	var ops []*operator.Operator
+	for i, spec := range specs {
+		if err := spec.checkEpoch(c.cluster); err != nil {
+			return nil, errors.Wrapf(err, "invalid operator %d", i)
+		}
+		created, err := h.newOperatorsBySpec(cluster, spec)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid operator %d", i)
+		}
+		ops = append(ops, created...)
+	}
+	if len(ops) == 0 {
+		return nil, errors.New("no operator is created")
+	}
+	return ops, nil
+}
+
+// GetOperatorBatch gets the progress of the operator batch.
+func (h *Handler) GetOperatorBatch(id uint64) (*schedule.OperatorBatchStatus, error) {
+	c, err := h.getCoordinator()
+	if err != nil {
+		return nil, err
+	}
+	status := c.opController.GetOperatorBatchStatus(id)
+	if status == nil {
+		return nil, ErrOperatorBatchNotFound(id)
+	}
+	return status, nil
+}
+
+// CancelOperatorBatch cancels the running operators of the batch.
+func (h *Handler) CancelOperatorBatch(id uint64) error {
+	c, err := h.getCoordinator()
+	if err != nil {
+		return err
+	}
+	if !c.opController.CancelOperatorBatch(id) {
+		return ErrOperatorBatchNotFound(id)
+	}
+	return nil
+}
+
 // GetDownPeerRegions gets the region with down peer.
 func (h *Handler) GetDownPeerRegions() ([]*core.RegionInfo, error) {
 	c := h.s.GetRaftCluster()
@@ -716,3 +1100,44 @@ func (h *Handler) GetIncorrectNamespaceRegions() ([]*core.RegionInfo, error) {
 	}
 	return c.GetRegionStatsByType(statistics.IncorrectNamespace), nil
 }
+
+// PlanSchedule runs the schedulers and checkers once and returns the operators
+// they create without dispatching them.
+func (h *Handler) PlanSchedule(schedulers []config.SchedulerConfig, checkers []string, limit int) ([]*PlannedOperator, error) {
+	c, err := h.getCoordinator()
+	if err != nil {
+		return nil, err
+	}
+	return c.planSchedule(schedulers, checkers, limit)
+}
+
+// ExplainRegionPlacement explains the placement of the region.
+func (h *Handler) ExplainRegionPlacement(regionID uint64) (*RegionPlacementExplanation, error) {
+	c, err := h.getCoordinator()
+	if err != nil {
+		return nil, err
+	}
+	region := c.cluster.GetRegion(regionID)
+	if region == nil {
+		return nil, ErrRegionNotFound(regionID)
+	}
+	return c.explainRegionPlacement(region), nil
+}
+
+// ExplainRangePlacement explains the placement of at most limit regions which
+// overlap with the key range [startKey, endKey). An empty endKey means the
+// range is unbounded.
+func (h *Handler) ExplainRangePlacement(startKey, endKey []byte, limit int) ([]*RegionPlacementExplanation, error) {
+	c, err := h.getCoordinator()
+	if err != nil {
+		return nil, err
+	}
+	var exps []*RegionPlacementExplanation
+	for _, region := range c.cluster.ScanRegions(startKey, limit) {
+		if len(endKey) > 0 && bytes.Compare(region.GetStartKey(), endKey) >= 0 {
+			break
+		}
+		exps = append(exps, c.explainRegionPlacement(region))
+	}
+	return exps, nil
+}
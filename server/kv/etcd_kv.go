@@ -31,15 +31,24 @@ const (
 	kvSlowRequestTime = time.Second * 1
 	requestTimeout    = 10 * time.Second
 	slowRequestTime   = 1 * time.Second
+	// maxTxnOps is the max number of the operations in a transaction, which
+	// is the default --max-txn-ops of etcd.
+	maxTxnOps = 128
 )
 
 var (
 	errTxnFailed = errors.New("failed to commit transaction")
+	// ErrNotLeader is the error of the batch which is not written as the
+	// member is not the leader.
+	ErrNotLeader = errors.New("not leader")
 )
 
 type etcdKVBase struct {
 	client   *clientv3.Client
 	rootPath string
+	// member is the leader value of the member, the batches are only written
+	// when the member is the leader if it is not empty.
+	member string
 }
 
 // NewEtcdKVBase creates a new etcd kv.
@@ -50,6 +59,17 @@ func NewEtcdKVBase(client *clientv3.Client, rootPath string) *etcdKVBase {
 	}
 }
 
+// NewLeaderEtcdKVBase creates a new etcd kv whose batches are only written
+// when the member is the leader, as the batches may be written in the
+// background after the leadership is lost.
+func NewLeaderEtcdKVBase(client *clientv3.Client, rootPath string, member string) *etcdKVBase {
+	return &etcdKVBase{
+		client:   client,
+		rootPath: rootPath,
+		member:   member,
+	}
+}
+
 func (kv *etcdKVBase) Load(key string) (string, error) {
 	key = path.Join(kv.rootPath, key)
 
@@ -114,6 +134,42 @@ func (kv *etcdKVBase) Remove(key string) error {
 	return nil
 }
 
+// WriteBatch saves the key-values and removes the keys. The operations are
+// split into the transactions of at most maxTxnOps operations, so the batch
+// is not written atomically.
+func (kv *etcdKVBase) WriteBatch(saves map[string]string, removes []string) error {
+	ops := make([]clientv3.Op, 0, len(saves)+len(removes))
+	for key, value := range saves {
+		ops = append(ops, clientv3.OpPut(path.Join(kv.rootPath, key), value))
+	}
+	for _, key := range removes {
+		ops = append(ops, clientv3.OpDelete(path.Join(kv.rootPath, key)))
+	}
+	var cmps []clientv3.Cmp
+	if kv.member != "" {
+		cmps = append(cmps, clientv3.Compare(clientv3.Value(path.Join(kv.rootPath, "leader")), "=", kv.member))
+	}
+	for len(ops) > 0 {
+		n := len(ops)
+		if n > maxTxnOps {
+			n = maxTxnOps
+		}
+		resp, err := NewSlowLogTxn(kv.client).If(cmps...).Then(ops[:n]...).Commit()
+		if err != nil {
+			log.Error("write batch to etcd meet error", zap.Error(err))
+			return errors.WithStack(err)
+		}
+		if !resp.Succeeded {
+			if kv.member != "" {
+				return errors.WithStack(ErrNotLeader)
+			}
+			return errors.WithStack(errTxnFailed)
+		}
+		ops = ops[n:]
+	}
+	return nil
+}
+
 // SlowLogTxn wraps etcd transaction and log slow one.
 type SlowLogTxn struct {
 	clientv3.Txn
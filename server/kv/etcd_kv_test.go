@@ -24,6 +24,7 @@ import (
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/pd/pkg/tempurl"
+	"github.com/pkg/errors"
 	"go.etcd.io/etcd/clientv3"
 	"go.etcd.io/etcd/embed"
 )
@@ -91,6 +92,45 @@ func (s *testEtcdKVSuite) TestEtcdKV(c *C) {
 	cleanConfig(cfg)
 }
 
+func (s *testEtcdKVSuite) TestWriteBatch(c *C) {
+	cfg := newTestSingleConfig()
+	etcd, err := embed.StartEtcd(cfg)
+	c.Assert(err, IsNil)
+	defer cleanConfig(cfg)
+	defer etcd.Close()
+
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints: []string{cfg.LCUrls[0].String()},
+	})
+	c.Assert(err, IsNil)
+	rootPath := path.Join("/pd", strconv.FormatUint(100, 10))
+	kv := NewEtcdKVBase(client, rootPath)
+
+	// The batch is split into several transactions.
+	saves := make(map[string]string)
+	for i := 0; i < maxTxnOps*2+1; i++ {
+		saves[fmt.Sprintf("test/key%04d", i)] = fmt.Sprintf("val%d", i)
+	}
+	c.Assert(kv.Save("test/removed", "val"), IsNil)
+	c.Assert(kv.WriteBatch(saves, []string{"test/removed"}), IsNil)
+	keys, _, err := kv.LoadRange("test/", "test/zzz", 1000)
+	c.Assert(err, IsNil)
+	c.Assert(keys, HasLen, len(saves))
+	v, err := kv.Load("test/key0256")
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, "val256")
+
+	// The batch is only written when the member is the leader.
+	leaderKV := NewLeaderEtcdKVBase(client, rootPath, "member")
+	err = leaderKV.WriteBatch(map[string]string{"test/leader": "val"}, nil)
+	c.Assert(errors.Cause(err), Equals, ErrNotLeader)
+	c.Assert(kv.Save("leader", "member"), IsNil)
+	c.Assert(leaderKV.WriteBatch(map[string]string{"test/leader": "val"}, nil), IsNil)
+	v, err = kv.Load("test/leader")
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, "val")
+}
+
 func newTestSingleConfig() *embed.Config {
 	cfg := embed.NewConfig()
 	cfg.Name = "test_etcd"
@@ -20,3 +20,19 @@ type Base interface {
 	Save(key, value string) error
 	Remove(key string) error
 }
+
+// BatchBase is a Base which writes a batch of the key-values at a time.
+type BatchBase interface {
+	Base
+	// WriteBatch saves the key-values and removes the keys.
+	WriteBatch(saves map[string]string, removes []string) error
+}
+
+// ReverseBase is a Base which can also load the key-values in the reverse
+// order.
+type ReverseBase interface {
+	Base
+	// LoadRangeReverse loads the key-values in [key, endKey), the largest key
+	// first.
+	LoadRangeReverse(key, endKey string, limit int) (keys []string, values []string, err error)
+}
@@ -62,6 +62,20 @@ func (kv *LeveldbKV) LoadRange(startKey, endKey string, limit int) ([]string, []
 	return keys, values, nil
 }
 
+// LoadRangeReverse gets a range of value for a given key range, the largest
+// key first.
+func (kv *LeveldbKV) LoadRangeReverse(startKey, endKey string, limit int) ([]string, []string, error) {
+	iter := kv.NewIterator(&util.Range{Start: []byte(startKey), Limit: []byte(endKey)}, nil)
+	keys := make([]string, 0, limit)
+	values := make([]string, 0, limit)
+	for ok := iter.Last(); ok && len(keys) < limit; ok = iter.Prev() {
+		keys = append(keys, string(iter.Key()))
+		values = append(values, string(iter.Value()))
+	}
+	iter.Release()
+	return keys, values, errors.WithStack(iter.Error())
+}
+
 // Save stores a key-value pair.
 func (kv *LeveldbKV) Save(key, value string) error {
 	return errors.WithStack(kv.Put([]byte(key), []byte(value), nil))
@@ -72,6 +86,18 @@ func (kv *LeveldbKV) Remove(key string) error {
 	return errors.WithStack(kv.Delete([]byte(key), nil))
 }
 
+// WriteBatch saves the key-values and removes the keys in a batch.
+func (kv *LeveldbKV) WriteBatch(saves map[string]string, removes []string) error {
+	batch := new(leveldb.Batch)
+	for key, value := range saves {
+		batch.Put([]byte(key), []byte(value))
+	}
+	for _, key := range removes {
+		batch.Delete([]byte(key))
+	}
+	return errors.WithStack(kv.Write(batch, nil))
+}
+
 // SaveRegions stores some regions.
 func (kv *LeveldbKV) SaveRegions(regions map[string]*metapb.Region) error {
 	batch := new(leveldb.Batch)
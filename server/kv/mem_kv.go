@@ -25,7 +25,7 @@ type memoryKV struct {
 }
 
 // NewMemoryKV returns an in-memory kvBase for testing.
-func NewMemoryKV() Base {
+func NewMemoryKV() ReverseBase {
 	return &memoryKV{
 		tree: btree.New(2),
 	}
@@ -62,6 +62,26 @@ func (kv *memoryKV) LoadRange(key, endKey string, limit int) ([]string, []string
 	return keys, values, nil
 }
 
+func (kv *memoryKV) LoadRangeReverse(key, endKey string, limit int) ([]string, []string, error) {
+	kv.RLock()
+	defer kv.RUnlock()
+	keys := make([]string, 0, limit)
+	values := make([]string, 0, limit)
+	kv.tree.DescendLessOrEqual(memoryKVItem{endKey, ""}, func(item btree.Item) bool {
+		k := item.(memoryKVItem).key
+		if k == endKey {
+			return true
+		}
+		if k < key {
+			return false
+		}
+		keys = append(keys, k)
+		values = append(values, item.(memoryKVItem).value)
+		return len(keys) < limit
+	})
+	return keys, values, nil
+}
+
 func (kv *memoryKV) Save(key, value string) error {
 	kv.Lock()
 	defer kv.Unlock()
@@ -69,6 +89,18 @@ func (kv *memoryKV) Save(key, value string) error {
 	return nil
 }
 
+func (kv *memoryKV) WriteBatch(saves map[string]string, removes []string) error {
+	kv.Lock()
+	defer kv.Unlock()
+	for key, value := range saves {
+		kv.tree.ReplaceOrInsert(memoryKVItem{key, value})
+	}
+	for _, key := range removes {
+		kv.tree.Delete(memoryKVItem{key, ""})
+	}
+	return nil
+}
+
 func (kv *memoryKV) Remove(key string) error {
 	kv.Lock()
 	defer kv.Unlock()
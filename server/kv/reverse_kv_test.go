@@ -0,0 +1,60 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kv
+
+import (
+	"io/ioutil"
+	"os"
+
+	. "github.com/pingcap/check"
+)
+
+type testReverseKVSuite struct{}
+
+var _ = Suite(&testReverseKVSuite{})
+
+func (s *testReverseKVSuite) TestMemoryKV(c *C) {
+	s.testLoadRangeReverse(c, NewMemoryKV())
+}
+
+func (s *testReverseKVSuite) TestLeveldbKV(c *C) {
+	dir, err := ioutil.TempDir("/tmp", "test_leveldb")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+	kv, err := NewLeveldbKV(dir)
+	c.Assert(err, IsNil)
+	defer kv.Close()
+	s.testLoadRangeReverse(c, kv)
+}
+
+func (s *testReverseKVSuite) testLoadRangeReverse(c *C, kv ReverseBase) {
+	keys := []string{"test/key1", "test/key2", "test/key3", "test/key4", "test/key5"}
+	vals := []string{"val1", "val2", "val3", "val4", "val5"}
+	for i := range keys {
+		c.Assert(kv.Save(keys[i], vals[i]), IsNil)
+	}
+
+	ks, vs, err := kv.LoadRangeReverse(keys[0], "test/zzz", 100)
+	c.Assert(err, IsNil)
+	c.Assert(ks, DeepEquals, []string{keys[4], keys[3], keys[2], keys[1], keys[0]})
+	c.Assert(vs, DeepEquals, []string{vals[4], vals[3], vals[2], vals[1], vals[0]})
+	// The end key is excluded.
+	ks, vs, err = kv.LoadRangeReverse(keys[1], keys[4], 2)
+	c.Assert(err, IsNil)
+	c.Assert(ks, DeepEquals, []string{keys[3], keys[2]})
+	c.Assert(vs, DeepEquals, []string{vals[3], vals[2]})
+	ks, _, err = kv.LoadRangeReverse(keys[1], keys[2], 100)
+	c.Assert(err, IsNil)
+	c.Assert(ks, DeepEquals, []string{keys[1]})
+}
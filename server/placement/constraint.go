@@ -13,28 +13,67 @@
 
 package placement
 
-import "github.com/pingcap/pd/server/core"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/pd/server/core"
+)
 
 // Config is consist of a list of constraints.
 type Config struct {
-	Constraints []*Constraint
+	Constraints []*Constraint `json:"constraints"`
+}
+
+// String returns the expression form of the config, constraints are separated
+// by ';'.
+func (c *Config) String() string {
+	exprs := make([]string, 0, len(c.Constraints))
+	for _, constraint := range c.Constraints {
+		exprs = append(exprs, constraint.String())
+	}
+	return strings.Join(exprs, ";")
+}
+
+// Score sums up the scores of all the violated constraints. It returns 0 if all
+// constraints are satisfied, otherwise a negative number which is closer to 0
+// means the region is closer to the expected placement.
+func (c *Config) Score(region *core.RegionInfo, cluster Cluster) int {
+	var score int
+	for _, constraint := range c.Constraints {
+		if s := constraint.Score(region, cluster); s < 0 {
+			score += s
+		}
+	}
+	return score
 }
 
 // Constraint represents a user-defined region placement rule. Each constraint
 // is configured as an expression, for example 'count(zone:z1,rack:r1,host)>=3'.
 type Constraint struct {
-	Function string   // One of "count", "label_values", "count_leader", "isolation_level".
-	Filters  []Filter // "key:value" formed parameters.
-	Labels   []string // "key" formed parameters.
-	Op       string   // One of "<", "<=", "=", ">=", ">".
-	Value    int      // Expected expression evaluate value.
+	Function string   `json:"function"` // One of "count", "label_values", "count_leader", "isolation_level".
+	Filters  []Filter `json:"filters"`  // "key:value" formed parameters.
+	Labels   []string `json:"labels"`   // "key" formed parameters.
+	Op       string   `json:"op"`       // One of "<", "<=", "=", ">=", ">".
+	Value    int      `json:"value"`    // Expected expression evaluate value.
+}
+
+// String returns the expression form of the constraint.
+func (c Constraint) String() string {
+	args := make([]string, 0, len(c.Filters)+len(c.Labels))
+	for _, f := range c.Filters {
+		args = append(args, f.Key+":"+f.Value)
+	}
+	args = append(args, c.Labels...)
+	return fmt.Sprintf("%s(%s)%s%d", c.Function, strings.Join(args, ","), c.Op, c.Value)
 }
 
 // Filter is used for filtering replicas of a region. The form in the
 // configuration is "key:value", which appears in the function argument of the
 // expression.
 type Filter struct {
-	Key, Value string
+	Key   string `json:"key"`
+	Value string `json:"value"`
 }
 
 var functionList = []string{"count", "label_values", "count_leader", "isolation_level"}
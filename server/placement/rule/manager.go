@@ -0,0 +1,145 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rule
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// Manager is responsible for the lifecycle of all placement rules. The
+// rules are persisted in the storage, and the regions are matched against them
+// by key range.
+type Manager struct {
+	sync.RWMutex
+	storage *core.Storage
+	rules   map[string]*Rule
+	// sorted keeps the rules in the order of descending priority.
+	sorted []*Rule
+	// tree indexes the rules by key range to match the regions.
+	tree *ruleTree
+}
+
+// NewManager creates a Manager instance.
+func NewManager(storage *core.Storage) *Manager {
+	return &Manager{
+		storage: storage,
+		rules:   make(map[string]*Rule),
+		tree:    newRuleTree(nil),
+	}
+}
+
+// Initialize loads the rules from storage.
+func (m *Manager) Initialize() error {
+	m.Lock()
+	defer m.Unlock()
+	rules := make(map[string]*Rule)
+	var loadErr error
+	err := m.storage.LoadRules(func(k, v string) {
+		rule := &Rule{}
+		if err := json.Unmarshal([]byte(v), rule); err != nil {
+			loadErr = errors.WithStack(err)
+			return
+		}
+		if err := rule.Adjust(); err != nil {
+			log.Error("ignore invalid placement rule", zap.String("key", k), zap.Error(err))
+			return
+		}
+		rules[rule.ID] = rule
+	})
+	if err != nil {
+		return err
+	}
+	if loadErr != nil {
+		return loadErr
+	}
+	m.rules = rules
+	m.sortRules()
+	return nil
+}
+
+// GetRule returns the rule with the ID.
+func (m *Manager) GetRule(id string) *Rule {
+	m.RLock()
+	defer m.RUnlock()
+	return m.rules[id]
+}
+
+// GetAllRules returns all rules in the order of descending priority.
+func (m *Manager) GetAllRules() []*Rule {
+	m.RLock()
+	defer m.RUnlock()
+	rules := make([]*Rule, len(m.sorted))
+	copy(rules, m.sorted)
+	return rules
+}
+
+// SetRule inserts or updates a rule.
+func (m *Manager) SetRule(rule *Rule) error {
+	if err := rule.Adjust(); err != nil {
+		return err
+	}
+	m.Lock()
+	defer m.Unlock()
+	if err := m.storage.SaveRule(rule.ID, rule); err != nil {
+		return err
+	}
+	m.rules[rule.ID] = rule
+	m.sortRules()
+	log.Info("placement rule updated", zap.Stringer("rule", rule))
+	return nil
+}
+
+// DeleteRule removes a rule.
+func (m *Manager) DeleteRule(id string) error {
+	m.Lock()
+	defer m.Unlock()
+	if _, ok := m.rules[id]; !ok {
+		return nil
+	}
+	if err := m.storage.DeleteRule(id); err != nil {
+		return err
+	}
+	delete(m.rules, id)
+	m.sortRules()
+	log.Info("placement rule deleted", zap.String("rule-id", id))
+	return nil
+}
+
+// GetRuleForRegion returns the rule which the region should follow. If more
+// than one rules overlap with the region, the one with the highest priority is
+// chosen. It returns nil if no rule overlaps with the region.
+func (m *Manager) GetRuleForRegion(region *core.RegionInfo) *Rule {
+	m.RLock()
+	defer m.RUnlock()
+	return m.tree.getRule(region.GetStartKey(), region.GetEndKey())
+}
+
+func (m *Manager) sortRules() {
+	sorted := make([]*Rule, 0, len(m.rules))
+	for _, rule := range m.rules {
+		sorted = append(sorted, rule)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return higherPriority(sorted[i], sorted[j])
+	})
+	m.sorted = sorted
+	m.tree = newRuleTree(sorted)
+}
@@ -0,0 +1,208 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rule
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/pingcap/pd/server/core"
+	"github.com/pkg/errors"
+)
+
+// DefaultRuleID is the ID of the rule built from the cluster-wide replication
+// options, which is used when no rule covers a region.
+const DefaultRuleID = "default"
+
+// LabelConstraintOp defines how a LabelConstraint matches a store.
+type LabelConstraintOp string
+
+const (
+	// In restricts the store label value should in the value list.
+	In LabelConstraintOp = "in"
+	// NotIn restricts the store label value should not in the value list.
+	NotIn LabelConstraintOp = "notIn"
+	// Exists restricts the store should have the label.
+	Exists LabelConstraintOp = "exists"
+	// NotExists restricts the store should not have the label.
+	NotExists LabelConstraintOp = "notExists"
+)
+
+func validateOp(op LabelConstraintOp) bool {
+	return op == In || op == NotIn || op == Exists || op == NotExists
+}
+
+// LabelConstraint is used to filter the stores which a rule's replicas can be
+// placed on.
+type LabelConstraint struct {
+	Key    string            `json:"key"`
+	Op     LabelConstraintOp `json:"op"`
+	Values []string          `json:"values,omitempty"`
+}
+
+// MatchStore checks if the store matches the constraint.
+func (c *LabelConstraint) MatchStore(store *core.StoreInfo) bool {
+	value := store.GetLabelValue(c.Key)
+	switch c.Op {
+	case In:
+		return value != "" && c.hasValue(value)
+	case NotIn:
+		return value == "" || !c.hasValue(value)
+	case Exists:
+		return value != ""
+	case NotExists:
+		return value == ""
+	}
+	return false
+}
+
+func (c *LabelConstraint) hasValue(value string) bool {
+	for _, v := range c.Values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Rule describes how the regions in a key range should be replicated. The keys
+// are hex encoded in the JSON form, an empty end key means the range is not
+// bounded.
+type Rule struct {
+	ID               string            `json:"id"`
+	Priority         int               `json:"priority"`
+	StartKeyHex      string            `json:"start_key"`
+	EndKeyHex        string            `json:"end_key"`
+	Voters           int               `json:"voters"`
+	Learners         int               `json:"learners"`
+	LabelConstraints []LabelConstraint `json:"label_constraints,omitempty"`
+	LocationLabels   []string          `json:"location_labels,omitempty"`
+	IsolationLevel   string            `json:"isolation_level,omitempty"`
+
+	StartKey []byte `json:"-"`
+	EndKey   []byte `json:"-"`
+}
+
+// NewDefaultRule creates the rule which covers the whole key space with the
+// given replication options.
+func NewDefaultRule(maxReplicas int, locationLabels []string) *Rule {
+	return &Rule{
+		ID:             DefaultRuleID,
+		Voters:         maxReplicas,
+		LocationLabels: locationLabels,
+	}
+}
+
+func (r *Rule) String() string {
+	return fmt.Sprintf("rule %s [%s, %s) voters: %d learners: %d", r.ID, r.StartKeyHex, r.EndKeyHex, r.Voters, r.Learners)
+}
+
+// Adjust decodes the key range and checks if the rule is valid.
+func (r *Rule) Adjust() error {
+	if r.ID == "" {
+		return errors.New("rule id should not be empty")
+	}
+	var err error
+	if r.StartKey, err = hex.DecodeString(r.StartKeyHex); err != nil {
+		return errors.Errorf("start key should be hex encoded: %s", r.StartKeyHex)
+	}
+	if r.EndKey, err = hex.DecodeString(r.EndKeyHex); err != nil {
+		return errors.Errorf("end key should be hex encoded: %s", r.EndKeyHex)
+	}
+	if len(r.EndKey) > 0 && bytes.Compare(r.EndKey, r.StartKey) <= 0 {
+		return errors.Errorf("end key should be greater than start key")
+	}
+	if r.Voters <= 0 {
+		return errors.Errorf("voters should be positive, got %d", r.Voters)
+	}
+	if r.Learners < 0 {
+		return errors.Errorf("learners should not be negative, got %d", r.Learners)
+	}
+	for _, c := range r.LabelConstraints {
+		if c.Key == "" || !validateOp(c.Op) {
+			return errors.Errorf("invalid label constraint %+v", c)
+		}
+	}
+	if r.IsolationLevel != "" && !r.hasLocationLabel(r.IsolationLevel) {
+		return errors.Errorf("isolation level %s should be one of the location labels", r.IsolationLevel)
+	}
+	return nil
+}
+
+func (r *Rule) hasLocationLabel(label string) bool {
+	for _, l := range r.LocationLabels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// GetReplicas returns the total number of replicas required by the rule.
+func (r *Rule) GetReplicas() int {
+	return r.Voters + r.Learners
+}
+
+// MatchStore checks if the store satisfies all label constraints of the rule.
+func (r *Rule) MatchStore(store *core.StoreInfo) bool {
+	for i := range r.LabelConstraints {
+		if !r.LabelConstraints[i].MatchStore(store) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsolationLimit returns the max replicas allowed to share a value of the
+// isolation level, which is the replicas divided by the number of the values
+// of the stores matching the label constraints, rounded up.
+func (r *Rule) IsolationLimit(stores []*core.StoreInfo) int {
+	values := make(map[string]struct{})
+	if r.IsolationLevel != "" {
+		for _, store := range stores {
+			if !store.IsTombstone() && r.MatchStore(store) {
+				values[store.GetLabelValue(r.IsolationLevel)] = struct{}{}
+			}
+		}
+	}
+	if len(values) == 0 {
+		return r.GetReplicas()
+	}
+	return (r.GetReplicas() + len(values) - 1) / len(values)
+}
+
+// IsIsolated checks if placing a replica on the store keeps the replicas
+// isolated at the isolation level, the stores are the ones which hold the
+// other replicas. At most limit replicas can share a value of the isolation
+// level, see IsolationLimit.
+func (r *Rule) IsIsolated(stores []*core.StoreInfo, store *core.StoreInfo, limit int) bool {
+	if r.IsolationLevel == "" {
+		return true
+	}
+	value := store.GetLabelValue(r.IsolationLevel)
+	count := 1
+	for _, s := range stores {
+		if s.GetID() != store.GetID() && s.GetLabelValue(r.IsolationLevel) == value {
+			count++
+		}
+	}
+	return count <= limit
+}
+
+// OverlapRegion checks if the rule's key range overlaps with the region.
+func (r *Rule) OverlapRegion(region *core.RegionInfo) bool {
+	return (len(r.EndKey) == 0 || bytes.Compare(region.GetStartKey(), r.EndKey) < 0) &&
+		(len(region.GetEndKey()) == 0 || bytes.Compare(r.StartKey, region.GetEndKey()) < 0)
+}
@@ -0,0 +1,226 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rule
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/kv"
+)
+
+func TestRule(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testRuleSuite{})
+
+type testRuleSuite struct{}
+
+func newStore(id uint64, labels ...string) *core.StoreInfo {
+	store := &metapb.Store{Id: id}
+	for i := 0; i+1 < len(labels); i += 2 {
+		store.Labels = append(store.Labels, &metapb.StoreLabel{Key: labels[i], Value: labels[i+1]})
+	}
+	return core.NewStoreInfo(store)
+}
+
+func newRegion(start, end string) *core.RegionInfo {
+	return core.NewRegionInfo(&metapb.Region{StartKey: []byte(start), EndKey: []byte(end)}, nil)
+}
+
+func (s *testRuleSuite) TestAdjust(c *C) {
+	valid := &Rule{ID: "r", StartKeyHex: "61", EndKeyHex: "62", Voters: 3, LocationLabels: []string{"zone"}, IsolationLevel: "zone"}
+	c.Assert(valid.Adjust(), IsNil)
+	c.Assert(valid.StartKey, DeepEquals, []byte("a"))
+	c.Assert(valid.EndKey, DeepEquals, []byte("b"))
+
+	invalids := []*Rule{
+		{StartKeyHex: "61", Voters: 3},
+		{ID: "r", StartKeyHex: "xx", Voters: 3},
+		{ID: "r", StartKeyHex: "62", EndKeyHex: "61", Voters: 3},
+		{ID: "r", Voters: 0},
+		{ID: "r", Voters: 3, Learners: -1},
+		{ID: "r", Voters: 3, LabelConstraints: []LabelConstraint{{Key: "zone", Op: "foo"}}},
+		{ID: "r", Voters: 3, IsolationLevel: "zone"},
+	}
+	for _, r := range invalids {
+		c.Assert(r.Adjust(), NotNil)
+	}
+}
+
+func (s *testRuleSuite) TestMatchStore(c *C) {
+	ssd := newStore(1, "disk", "ssd", "zone", "z1")
+	hdd := newStore(2, "disk", "hdd")
+	none := newStore(3)
+
+	cases := []struct {
+		constraint LabelConstraint
+		matches    []bool
+	}{
+		{LabelConstraint{Key: "disk", Op: In, Values: []string{"ssd"}}, []bool{true, false, false}},
+		{LabelConstraint{Key: "disk", Op: NotIn, Values: []string{"ssd"}}, []bool{false, true, true}},
+		{LabelConstraint{Key: "zone", Op: Exists}, []bool{true, false, false}},
+		{LabelConstraint{Key: "zone", Op: NotExists}, []bool{false, true, true}},
+	}
+	for _, t := range cases {
+		rule := &Rule{LabelConstraints: []LabelConstraint{t.constraint}}
+		for i, store := range []*core.StoreInfo{ssd, hdd, none} {
+			c.Assert(rule.MatchStore(store), Equals, t.matches[i])
+		}
+	}
+}
+
+func (s *testRuleSuite) TestIsIsolated(c *C) {
+	stores := []*core.StoreInfo{
+		newStore(1, "zone", "z1", "host", "h1"),
+		newStore(2, "zone", "z1", "host", "h2"),
+	}
+	rule := &Rule{Voters: 3, LocationLabels: []string{"zone", "host"}}
+	c.Assert(rule.IsIsolated(stores, newStore(3, "zone", "z1", "host", "h3"), 1), IsTrue)
+	rule.IsolationLevel = "host"
+	c.Assert(rule.IsIsolated(stores, newStore(3, "zone", "z1", "host", "h3"), 1), IsTrue)
+	c.Assert(rule.IsIsolated(stores, newStore(3, "zone", "z2", "host", "h1"), 1), IsFalse)
+	c.Assert(rule.IsIsolated(stores, stores[0], 1), IsTrue)
+	rule.IsolationLevel = "zone"
+	c.Assert(rule.IsIsolated(stores, newStore(3, "zone", "z1", "host", "h3"), 1), IsFalse)
+	c.Assert(rule.IsIsolated(stores, newStore(3, "zone", "z2", "host", "h3"), 1), IsTrue)
+}
+
+func (s *testRuleSuite) TestIsolationLimit(c *C) {
+	var stores []*core.StoreInfo
+	for i := uint64(1); i <= 6; i++ {
+		stores = append(stores, newStore(i, "zone", fmt.Sprintf("z%d", (i+1)/2), "host", fmt.Sprintf("h%d", i)))
+	}
+	rule := &Rule{Voters: 5, LocationLabels: []string{"zone", "host"}}
+	c.Assert(rule.IsolationLimit(stores), Equals, 5)
+
+	// 5 voters over 3 zones allow 2 voters in a zone.
+	rule.IsolationLevel = "zone"
+	limit := rule.IsolationLimit(stores)
+	c.Assert(limit, Equals, 2)
+	regionStores := []*core.StoreInfo{stores[0], stores[1], stores[2], stores[4]}
+	c.Assert(rule.IsIsolated(regionStores, stores[3], limit), IsTrue)
+	c.Assert(rule.IsIsolated(regionStores, stores[5], limit), IsTrue)
+	regionStores = append(regionStores, stores[3])
+	for _, store := range regionStores {
+		c.Assert(rule.IsIsolated(regionStores, store, limit), IsTrue)
+	}
+	c.Assert(rule.IsIsolated(regionStores[:4], stores[1], limit), IsTrue)
+	c.Assert(rule.IsIsolated(append(regionStores[:4:4], stores[5]), newStore(7, "zone", "z3"), limit), IsFalse)
+
+	// The stores not matching the label constraints are not counted.
+	rule.LabelConstraints = []LabelConstraint{{Key: "zone", Op: NotIn, Values: []string{"z3"}}}
+	c.Assert(rule.IsolationLimit(stores), Equals, 3)
+	rule.Voters = 3
+	c.Assert(rule.IsolationLimit(stores[:2]), Equals, 3)
+}
+
+func (s *testRuleSuite) TestManager(c *C) {
+	storage := core.NewStorage(kv.NewMemoryKV())
+	m := NewManager(storage)
+	c.Assert(m.Initialize(), IsNil)
+	c.Assert(m.GetRuleForRegion(newRegion("a", "b")), IsNil)
+
+	c.Assert(m.SetRule(&Rule{ID: "all", Voters: 3}), IsNil)
+	c.Assert(m.SetRule(&Rule{ID: "t1", Priority: 1, StartKeyHex: "61", EndKeyHex: "63", Voters: 5}), IsNil)
+	c.Assert(m.SetRule(&Rule{ID: "t2", Priority: 2, StartKeyHex: "62", Voters: 1}), IsNil)
+	c.Assert(m.SetRule(&Rule{ID: "bad", Voters: 0}), NotNil)
+	c.Assert(m.GetAllRules(), HasLen, 3)
+
+	cases := []struct {
+		start, end string
+		ruleID     string
+	}{
+		{"", "a", "all"},
+		{"a", "b", "t1"},
+		{"a", "c", "t2"}, // Overlaps with both t1 and t2.
+		{"c", "d", "t2"},
+		{"c", "", "t2"},
+		{"0", "1", "all"},
+	}
+	for _, t := range cases {
+		c.Assert(m.GetRuleForRegion(newRegion(t.start, t.end)).ID, Equals, t.ruleID)
+	}
+
+	// The rules should be reloaded from storage.
+	m = NewManager(storage)
+	c.Assert(m.Initialize(), IsNil)
+	c.Assert(m.GetAllRules(), HasLen, 3)
+	c.Assert(m.GetRule("t1").Voters, Equals, 5)
+	c.Assert(m.GetRule("t1").StartKey, DeepEquals, []byte("a"))
+
+	c.Assert(m.DeleteRule("t2"), IsNil)
+	c.Assert(m.DeleteRule("t2"), IsNil)
+	c.Assert(m.GetRule("t2"), IsNil)
+	c.Assert(m.GetRuleForRegion(newRegion("c", "d")).ID, Equals, "all")
+	m = NewManager(storage)
+	c.Assert(m.Initialize(), IsNil)
+	c.Assert(m.GetAllRules(), HasLen, 2)
+}
+
+func (s *testRuleSuite) TestRuleTree(c *C) {
+	c.Assert(newRuleTree(nil).getRule([]byte("a"), []byte("b")), IsNil)
+
+	// The rule with the smaller ID wins if the priorities are the same.
+	rules := []*Rule{
+		{ID: "b", StartKey: []byte("b"), EndKey: []byte("d")},
+		{ID: "a", StartKey: []byte("c"), EndKey: []byte("e")},
+	}
+	sort.Slice(rules, func(i, j int) bool { return higherPriority(rules[i], rules[j]) })
+	tree := newRuleTree(rules)
+	c.Assert(tree.getRule([]byte("b"), []byte("c")).ID, Equals, "b")
+	c.Assert(tree.getRule([]byte("b"), []byte("cc")).ID, Equals, "a")
+	c.Assert(tree.getRule([]byte("d"), []byte("")).ID, Equals, "a")
+	c.Assert(tree.getRule([]byte("e"), []byte("")), IsNil)
+	c.Assert(tree.getRule([]byte(""), []byte("b")), IsNil)
+
+	// The tree matches the rules in the same way as matching them one by one.
+	r := rand.New(rand.NewSource(1))
+	key := func() []byte { return []byte{byte('a' + r.Intn(26))} }
+	rules = rules[:0]
+	for i := 0; i < 20; i++ {
+		start, end := key(), key()
+		if bytes.Compare(start, end) > 0 {
+			start, end = end, start
+		}
+		if bytes.Equal(start, end) {
+			end = nil
+		}
+		rules = append(rules, &Rule{ID: fmt.Sprintf("r%d", i), Priority: r.Intn(3), StartKey: start, EndKey: end})
+	}
+	sort.Slice(rules, func(i, j int) bool { return higherPriority(rules[i], rules[j]) })
+	tree = newRuleTree(rules)
+	for i := 0; i < 200; i++ {
+		start, end := key(), key()
+		if bytes.Compare(start, end) >= 0 {
+			end = nil
+		}
+		region := newRegion(string(start), string(end))
+		var expected *Rule
+		for _, rule := range rules {
+			if rule.OverlapRegion(region) {
+				expected = rule
+				break
+			}
+		}
+		c.Assert(tree.getRule(start, end), Equals, expected)
+	}
+}
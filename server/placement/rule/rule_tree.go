@@ -0,0 +1,108 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rule
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/google/btree"
+)
+
+const ruleTreeDegree = 32
+
+var _ btree.Item = &ruleSegment{}
+
+// ruleSegment is a key range [startKey, next segment's startKey) covered by
+// the same rules. The rules are in the order of descending priority.
+type ruleSegment struct {
+	startKey []byte
+	rules    []*Rule
+}
+
+// Less returns true if the segment start key is less than the other.
+func (s *ruleSegment) Less(other btree.Item) bool {
+	return bytes.Compare(s.startKey, other.(*ruleSegment).startKey) < 0
+}
+
+// ruleTree indexes the rules by key range. The key space is split into the
+// segments at the boundaries of the rules, so a key is looked up in
+// O(log(n)) instead of matching all the rules.
+type ruleTree struct {
+	tree *btree.BTree
+}
+
+// newRuleTree builds the tree of the rules, the rules should be in the order
+// of descending priority.
+func newRuleTree(rules []*Rule) *ruleTree {
+	var keys [][]byte
+	keys = append(keys, []byte(""))
+	for _, rule := range rules {
+		keys = append(keys, rule.StartKey)
+		if len(rule.EndKey) > 0 {
+			keys = append(keys, rule.EndKey)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i], keys[j]) < 0 })
+
+	t := &ruleTree{tree: btree.New(ruleTreeDegree)}
+	for i, key := range keys {
+		if i > 0 && bytes.Equal(key, keys[i-1]) {
+			continue
+		}
+		segment := &ruleSegment{startKey: key}
+		for _, rule := range rules {
+			if bytes.Compare(rule.StartKey, key) <= 0 && (len(rule.EndKey) == 0 || bytes.Compare(key, rule.EndKey) < 0) {
+				segment.rules = append(segment.rules, rule)
+			}
+		}
+		t.tree.ReplaceOrInsert(segment)
+	}
+	return t
+}
+
+// getRule returns the rule with the highest priority among the rules which
+// overlap with [startKey, endKey), an empty endKey means the range is not
+// bounded. It returns nil if no rule overlaps with the range.
+func (t *ruleTree) getRule(startKey, endKey []byte) *Rule {
+	var first *ruleSegment
+	t.tree.DescendLessOrEqual(&ruleSegment{startKey: startKey}, func(i btree.Item) bool {
+		first = i.(*ruleSegment)
+		return false
+	})
+	if first == nil {
+		return nil
+	}
+	var best *Rule
+	t.tree.AscendGreaterOrEqual(first, func(i btree.Item) bool {
+		segment := i.(*ruleSegment)
+		if segment != first && len(endKey) > 0 && bytes.Compare(segment.startKey, endKey) >= 0 {
+			return false
+		}
+		if len(segment.rules) > 0 && (best == nil || higherPriority(segment.rules[0], best)) {
+			best = segment.rules[0]
+		}
+		return true
+	})
+	return best
+}
+
+// higherPriority returns true if the rule a takes precedence over b, the rule
+// with the smaller ID wins if the priorities are the same.
+func higherPriority(a, b *Rule) bool {
+	if a.Priority != b.Priority {
+		return a.Priority > b.Priority
+	}
+	return a.ID < b.ID
+}
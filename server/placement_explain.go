@@ -0,0 +1,164 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/placement/rule"
+	"github.com/pingcap/pd/server/schedule/operator"
+	"github.com/pingcap/pd/server/statistics"
+)
+
+// The sources of the placement requirements.
+const (
+	PlacementSourceRule       = "rule"
+	PlacementSourceConstraint = "constraint"
+)
+
+// PlacementStatus is the evaluation result of a placement requirement on a
+// region.
+type PlacementStatus struct {
+	Source     string `json:"source"`
+	Constraint string `json:"constraint"`
+	Satisfied  bool   `json:"satisfied"`
+	Detail     string `json:"detail,omitempty"`
+}
+
+// RegionPlacementExplanation explains why a region is placed where it is and
+// what the checkers are going to do with it.
+type RegionPlacementExplanation struct {
+	RegionID uint64     `json:"region_id"`
+	StartKey string     `json:"start_key"`
+	EndKey   string     `json:"end_key"`
+	Rule     *rule.Rule `json:"rule"`
+	// Statuses contains the requirements of the rule and the placement
+	// constraints, as well as whether the region satisfies them.
+	Statuses []PlacementStatus `json:"statuses"`
+	// IsolationLevel is the isolation level of the region's stores, level i
+	// (i > 0) means the stores are isolated by IsolationLabel, which is the
+	// i-th location label, and 0 means the stores are not isolated at all.
+	IsolationLevel int    `json:"isolation_level"`
+	IsolationLabel string `json:"isolation_label,omitempty"`
+	// WorstStore is the store which the replica checker would replace first,
+	// ReplacementStore is the best store to replace it. The replica checker
+	// only moves the replica if ReplacementScore is greater than WorstScore.
+	WorstStore       uint64  `json:"worst_store,omitempty"`
+	WorstScore       float64 `json:"worst_score"`
+	ReplacementStore uint64  `json:"replacement_store,omitempty"`
+	ReplacementScore float64 `json:"replacement_score"`
+	// RunningOperator is the operator which is running on the region now.
+	RunningOperator *operator.Operator `json:"running_operator,omitempty"`
+	// NextOperators are the operators the checkers would create next.
+	NextOperators []*operator.Operator `json:"next_operators,omitempty"`
+}
+
+// explainRegionPlacement evaluates the placement of the region. The operators
+// created by the checkers are only reported, they are never executed.
+func (c *coordinator) explainRegionPlacement(region *core.RegionInfo) *RegionPlacementExplanation {
+	r := c.cluster.GetRegionRule(region)
+	exp := &RegionPlacementExplanation{
+		RegionID: region.GetID(),
+		StartKey: string(core.HexRegionKey(region.GetStartKey())),
+		EndKey:   string(core.HexRegionKey(region.GetEndKey())),
+		Rule:     r,
+	}
+	exp.Statuses = append(exp.Statuses, c.explainRule(region, r)...)
+	for _, constraint := range c.placementChecker.GetConfig().Constraints {
+		score := constraint.Score(region, c.cluster)
+		exp.Statuses = append(exp.Statuses, PlacementStatus{
+			Source:     PlacementSourceConstraint,
+			Constraint: constraint.String(),
+			Satisfied:  score >= 0,
+			Detail:     fmt.Sprintf("score %d", score),
+		})
+	}
+
+	exp.IsolationLevel = statistics.GetRegionLabelIsolationLevel(c.cluster.GetRegionStores(region), r.LocationLabels)
+	if exp.IsolationLevel > 0 {
+		exp.IsolationLabel = r.LocationLabels[exp.IsolationLevel-1]
+	}
+
+	oldPeer, oldScore, storeID, newScore := c.replicaChecker.SelectBestReplacement(region)
+	if oldPeer != nil {
+		exp.WorstStore, exp.WorstScore = oldPeer.GetStoreId(), oldScore
+		exp.ReplacementStore, exp.ReplacementScore = storeID, newScore
+	}
+
+	exp.RunningOperator = c.opController.GetOperator(region.GetID())
+	exp.NextOperators = c.previewOperators(region)
+	return exp
+}
+
+// explainRule checks the region against each requirement of the rule.
+func (c *coordinator) explainRule(region *core.RegionInfo, r *rule.Rule) []PlacementStatus {
+	voters, learners := len(region.GetVoters()), len(region.GetLearners())
+	statuses := []PlacementStatus{
+		{
+			Source:     PlacementSourceRule,
+			Constraint: fmt.Sprintf("voters=%d", r.Voters),
+			Satisfied:  voters == r.Voters,
+			Detail:     fmt.Sprintf("%d voters", voters),
+		},
+		{
+			Source:     PlacementSourceRule,
+			Constraint: fmt.Sprintf("learners=%d", r.Learners),
+			Satisfied:  learners == r.Learners,
+			Detail:     fmt.Sprintf("%d learners", learners),
+		},
+	}
+
+	stores := c.cluster.GetRegionStores(region)
+	for i := range r.LabelConstraints {
+		constraint := &r.LabelConstraints[i]
+		var mismatched []uint64
+		for _, store := range stores {
+			if !constraint.MatchStore(store) {
+				mismatched = append(mismatched, store.GetID())
+			}
+		}
+		statuses = append(statuses, PlacementStatus{
+			Source:     PlacementSourceRule,
+			Constraint: fmt.Sprintf("%s %s [%s]", constraint.Key, constraint.Op, strings.Join(constraint.Values, ",")),
+			Satisfied:  len(mismatched) == 0,
+			Detail:     storesDetail("mismatched", mismatched),
+		})
+	}
+
+	if r.IsolationLevel != "" {
+		var notIsolated []uint64
+		limit := r.IsolationLimit(c.cluster.GetStores())
+		for _, store := range stores {
+			if !r.IsIsolated(stores, store, limit) {
+				notIsolated = append(notIsolated, store.GetID())
+			}
+		}
+		statuses = append(statuses, PlacementStatus{
+			Source:     PlacementSourceRule,
+			Constraint: fmt.Sprintf("isolation_level=%s", r.IsolationLevel),
+			Satisfied:  len(notIsolated) == 0,
+			Detail:     storesDetail("not isolated", notIsolated),
+		})
+	}
+	return statuses
+}
+
+func storesDetail(reason string, storeIDs []uint64) string {
+	if len(storeIDs) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s stores %v", reason, storeIDs)
+}
@@ -0,0 +1,71 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"sync"
+
+	"github.com/pingcap/pd/server/core"
+)
+
+// regionStatsBatch applies the regions whose heartbeat only changes the
+// approximate size or keys to the cache in batches. A heartbeat joins the
+// pending batch, and the first heartbeat which finds no batch being applied
+// applies all the pending regions at once, so that the concurrent heartbeats
+// share one acquisition of the cluster lock. The heartbeat returns after its
+// region is applied, so the cache is never stale.
+type regionStatsBatch struct {
+	sync.Mutex
+	cond    *sync.Cond
+	pending map[uint64]*core.RegionInfo
+	// gen is the generation of the pending batch, and applied is the latest
+	// generation which has been applied.
+	gen      uint64
+	applied  uint64
+	applying bool
+}
+
+func newRegionStatsBatch() *regionStatsBatch {
+	b := &regionStatsBatch{
+		pending: make(map[uint64]*core.RegionInfo),
+		gen:     1,
+	}
+	b.cond = sync.NewCond(b)
+	return b
+}
+
+// apply adds the region to the pending batch, and returns after the batch is
+// applied by f.
+func (b *regionStatsBatch) apply(region *core.RegionInfo, f func(regions map[uint64]*core.RegionInfo)) {
+	b.Lock()
+	defer b.Unlock()
+	b.pending[region.GetID()] = region
+	gen := b.gen
+	for b.applied < gen {
+		if b.applying {
+			b.cond.Wait()
+			continue
+		}
+		regions := b.pending
+		b.pending = make(map[uint64]*core.RegionInfo)
+		b.gen++
+		b.applying = true
+		b.Unlock()
+		f(regions)
+		b.Lock()
+		b.applied = gen
+		b.applying = false
+		b.cond.Broadcast()
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/schedule/filter"
+	"github.com/pingcap/pd/server/schedule/opt"
+)
+
+// DefaultDecisionTraceLimit is the default number of the latest candidate
+// evaluations kept by a DecisionTracer.
+const DefaultDecisionTraceLimit = 32
+
+// The directions of the filter rejections.
+const (
+	RejectSource = "source"
+	RejectTarget = "target"
+)
+
+// The results of the candidate evaluations.
+const (
+	// EvaluationSkip means the stores are considered balanced for the region,
+	// so no operator is created.
+	EvaluationSkip = "skip"
+	// EvaluationOperator means an operator is created for the region.
+	EvaluationOperator = "operator"
+)
+
+// FilterRejection counts how many times a store is rejected by a filter.
+type FilterRejection struct {
+	Direction string `json:"direction"`
+	Filter    string `json:"filter"`
+	StoreID   uint64 `json:"store_id"`
+	Count     uint64 `json:"count"`
+}
+
+// CandidateEvaluation is an evaluation of moving a region from the source
+// store to the target store. The scores are the scores of the stores after
+// the region is moved.
+type CandidateEvaluation struct {
+	Time        time.Time `json:"time"`
+	RegionID    uint64    `json:"region_id"`
+	SourceStore uint64    `json:"source_store"`
+	TargetStore uint64    `json:"target_store"`
+	SourceScore float64   `json:"source_score"`
+	TargetScore float64   `json:"target_score"`
+	Result      string    `json:"result"`
+}
+
+// DecisionTrace is a snapshot of the decisions traced by a DecisionTracer.
+type DecisionTrace struct {
+	// Rejections are counted in the latest scheduling round, and sorted by
+	// count in descending order.
+	Rejections []FilterRejection `json:"rejections"`
+	// Evaluations are the latest candidate evaluations, the latest first.
+	Evaluations []CandidateEvaluation `json:"evaluations"`
+}
+
+type rejectionKey struct {
+	direction string
+	filter    string
+	storeID   uint64
+}
+
+// DecisionTracer counts the stores rejected by the filters in each scheduling
+// round and keeps the latest candidate evaluations of a scheduler.
+type DecisionTracer struct {
+	sync.Mutex
+	limit       int
+	rejections  map[rejectionKey]uint64
+	evaluations []CandidateEvaluation
+}
+
+// NewDecisionTracer creates a DecisionTracer which keeps at most limit
+// candidate evaluations.
+func NewDecisionTracer(limit int) *DecisionTracer {
+	return &DecisionTracer{
+		limit:      limit,
+		rejections: make(map[rejectionKey]uint64),
+	}
+}
+
+// StartRound starts a new scheduling round and clears the rejections of the
+// last round, the scheduler calls it each time it schedules.
+func (t *DecisionTracer) StartRound() {
+	t.Lock()
+	defer t.Unlock()
+	t.rejections = make(map[rejectionKey]uint64)
+}
+
+// TraceFilters wraps the filters to count the stores they reject.
+func (t *DecisionTracer) TraceFilters(filters ...filter.Filter) []filter.Filter {
+	traced := make([]filter.Filter, 0, len(filters))
+	for _, f := range filters {
+		traced = append(traced, &tracedFilter{Filter: f, tracer: t})
+	}
+	return traced
+}
+
+func (t *DecisionTracer) recordRejection(direction, filterType string, storeID uint64) {
+	t.Lock()
+	defer t.Unlock()
+	t.rejections[rejectionKey{direction: direction, filter: filterType, storeID: storeID}]++
+}
+
+// RecordEvaluation records a candidate evaluation, the oldest evaluation is
+// dropped if there are more than limit evaluations.
+func (t *DecisionTracer) RecordEvaluation(e CandidateEvaluation) {
+	t.Lock()
+	defer t.Unlock()
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	t.evaluations = append(t.evaluations, e)
+	if len(t.evaluations) > t.limit {
+		t.evaluations = t.evaluations[len(t.evaluations)-t.limit:]
+	}
+}
+
+// GetTrace returns a snapshot of the traced decisions.
+func (t *DecisionTracer) GetTrace() *DecisionTrace {
+	t.Lock()
+	defer t.Unlock()
+	trace := &DecisionTrace{
+		Rejections:  make([]FilterRejection, 0, len(t.rejections)),
+		Evaluations: make([]CandidateEvaluation, 0, len(t.evaluations)),
+	}
+	for key, count := range t.rejections {
+		trace.Rejections = append(trace.Rejections, FilterRejection{
+			Direction: key.direction,
+			Filter:    key.filter,
+			StoreID:   key.storeID,
+			Count:     count,
+		})
+	}
+	sort.Slice(trace.Rejections, func(i, j int) bool {
+		a, b := trace.Rejections[i], trace.Rejections[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.StoreID != b.StoreID {
+			return a.StoreID < b.StoreID
+		}
+		if a.Direction != b.Direction {
+			return a.Direction < b.Direction
+		}
+		return a.Filter < b.Filter
+	})
+	for i := len(t.evaluations) - 1; i >= 0; i-- {
+		trace.Evaluations = append(trace.Evaluations, t.evaluations[i])
+	}
+	return trace
+}
+
+// tracedFilter records the stores rejected by the filter to the tracer.
+type tracedFilter struct {
+	filter.Filter
+	tracer *DecisionTracer
+}
+
+func (f *tracedFilter) Source(opt opt.Options, store *core.StoreInfo) bool {
+	if f.Filter.Source(opt, store) {
+		f.tracer.recordRejection(RejectSource, f.Type(), store.GetID())
+		return true
+	}
+	return false
+}
+
+func (f *tracedFilter) Target(opt opt.Options, store *core.StoreInfo) bool {
+	if f.Filter.Target(opt, store) {
+		f.tracer.recordRejection(RejectTarget, f.Type(), store.GetID())
+		return true
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/pd/pkg/mock/mockcluster"
+	"github.com/pingcap/pd/pkg/mock/mockoption"
+	"github.com/pingcap/pd/server/schedule/filter"
+)
+
+var _ = Suite(&testDecisionTracerSuite{})
+
+type testDecisionTracerSuite struct{}
+
+func (s *testDecisionTracerSuite) TestTraceFilters(c *C) {
+	opt := mockoption.NewScheduleOptions()
+	tc := mockcluster.NewCluster(opt)
+	tc.AddRegionStore(1, 1)
+	tc.AddRegionStore(2, 1)
+	tc.AddRegionStore(3, 1)
+	tc.SetStoreOffline(3)
+
+	blacklist := filter.NewBlacklistStoreFilter(filter.BlacklistSource | filter.BlacklistTarget)
+	blacklist.Add(1)
+	tracer := NewDecisionTracer(DefaultDecisionTraceLimit)
+	filters := tracer.TraceFilters(blacklist, filter.StoreStateFilter{MoveRegion: true})
+	for i := 0; i < 2; i++ {
+		for _, store := range tc.GetStores() {
+			filter.Source(tc, store, filters)
+			filter.Target(tc, store, filters)
+		}
+	}
+
+	// Only the first filter which rejects the store is counted.
+	trace := tracer.GetTrace()
+	c.Assert(trace.Rejections, DeepEquals, []FilterRejection{
+		{Direction: RejectSource, Filter: blacklist.Type(), StoreID: 1, Count: 2},
+		{Direction: RejectTarget, Filter: blacklist.Type(), StoreID: 1, Count: 2},
+		{Direction: RejectTarget, Filter: filter.StoreStateFilter{}.Type(), StoreID: 3, Count: 2},
+	})
+	c.Assert(trace.Evaluations, HasLen, 0)
+
+	// The rejections are counted per round.
+	tracer.StartRound()
+	c.Assert(tracer.GetTrace().Rejections, HasLen, 0)
+	filter.Target(tc, tc.GetStore(1), filters)
+	c.Assert(tracer.GetTrace().Rejections, DeepEquals, []FilterRejection{
+		{Direction: RejectTarget, Filter: blacklist.Type(), StoreID: 1, Count: 1},
+	})
+}
+
+func (s *testDecisionTracerSuite) TestRecordEvaluation(c *C) {
+	tracer := NewDecisionTracer(3)
+	for i := uint64(1); i <= 5; i++ {
+		tracer.RecordEvaluation(CandidateEvaluation{RegionID: i, Result: EvaluationSkip})
+	}
+
+	// Only the latest 3 evaluations are kept, the latest first.
+	trace := tracer.GetTrace()
+	c.Assert(trace.Evaluations, HasLen, 3)
+	for i, e := range trace.Evaluations {
+		c.Assert(e.RegionID, Equals, uint64(5-i))
+		c.Assert(e.Time.IsZero(), IsFalse)
+	}
+	c.Assert(trace.Rejections, HasLen, 0)
+}
@@ -0,0 +1,84 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"math"
+	"sync/atomic"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DryRunCluster wraps a cluster to preview the operators without side
+// effects. The peer IDs are allocated locally instead of from the ID
+// allocator, and the metrics of the checkers and schedulers running on it
+// are discarded.
+type DryRunCluster struct {
+	Cluster
+	lastPeerID uint64
+}
+
+// NewDryRunCluster creates a DryRunCluster on the cluster.
+func NewDryRunCluster(cluster Cluster) *DryRunCluster {
+	return &DryRunCluster{
+		Cluster:    cluster,
+		lastPeerID: math.MaxUint64,
+	}
+}
+
+// AllocPeer allocates a placeholder peer. The IDs count down from the max
+// uint64 so they never conflict with the real ones.
+func (c *DryRunCluster) AllocPeer(storeID uint64) (*metapb.Peer, error) {
+	return &metapb.Peer{
+		Id:      atomic.AddUint64(&c.lastPeerID, ^uint64(0)),
+		StoreId: storeID,
+	}, nil
+}
+
+// IsDryRun returns true if the cluster is a DryRunCluster or is built on one.
+func IsDryRun(cluster Cluster) bool {
+	switch c := cluster.(type) {
+	case *DryRunCluster:
+		return true
+	case *RangeCluster:
+		return IsDryRun(c.Cluster)
+	default:
+		return false
+	}
+}
+
+// CounterVec is the counter vector used by the checkers and schedulers.
+type CounterVec interface {
+	WithLabelValues(lvs ...string) prometheus.Counter
+}
+
+// discardCounterVec drops all the events.
+type discardCounterVec struct{}
+
+func (discardCounterVec) WithLabelValues(lvs ...string) prometheus.Counter {
+	return discardCounter
+}
+
+// discardCounter is never registered, so the value is not exported.
+var discardCounter = prometheus.NewCounter(prometheus.CounterOpts{Name: "discard"})
+
+// Counter returns the counter vector to record the events happened on the
+// cluster. The events of a dry run are discarded.
+func Counter(cluster Cluster, vec *prometheus.CounterVec) CounterVec {
+	if IsDryRun(cluster) {
+		return discardCounterVec{}
+	}
+	return vec
+}
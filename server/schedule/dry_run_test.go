@@ -0,0 +1,54 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"math"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/pd/pkg/mock/mockcluster"
+	"github.com/pingcap/pd/pkg/mock/mockoption"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ = Suite(&testDryRunSuite{})
+
+type testDryRunSuite struct{}
+
+func (s *testDryRunSuite) TestDryRunCluster(c *C) {
+	tc := mockcluster.NewCluster(mockoption.NewScheduleOptions())
+	id, err := tc.Alloc()
+	c.Assert(err, IsNil)
+
+	cluster := NewDryRunCluster(tc)
+	for i := uint64(1); i <= 3; i++ {
+		peer, err := cluster.AllocPeer(i)
+		c.Assert(err, IsNil)
+		c.Assert(peer.GetId(), Equals, math.MaxUint64-i)
+		c.Assert(peer.GetStoreId(), Equals, i)
+	}
+	// The ID allocator is not used.
+	nextID, err := tc.Alloc()
+	c.Assert(err, IsNil)
+	c.Assert(nextID, Equals, id+1)
+
+	c.Assert(IsDryRun(tc), IsFalse)
+	c.Assert(IsDryRun(cluster), IsTrue)
+	c.Assert(IsDryRun(GenRangeCluster(cluster, []byte(""), []byte(""))), IsTrue)
+
+	vec := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test"}, []string{"type"})
+	c.Assert(Counter(tc, vec), Equals, vec)
+	c.Assert(Counter(cluster, vec), Equals, discardCounterVec{})
+	Counter(cluster, vec).WithLabelValues("a", "b").Inc()
+}
@@ -0,0 +1,242 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/pd/server/schedule/operator"
+	"github.com/pkg/errors"
+)
+
+// DefaultEventBufferSize is the default number of the events buffered for a
+// subscriber. The events are dropped if the subscriber cannot keep up.
+const DefaultEventBufferSize = 1024
+
+// EventKind is the kind of a scheduling event.
+type EventKind string
+
+// The kinds of the scheduling events.
+const (
+	EventOperatorCreate   EventKind = "operator-create"
+	EventOperatorDispatch EventKind = "operator-dispatch"
+	EventOperatorFinish   EventKind = "operator-finish"
+	EventOperatorTimeout  EventKind = "operator-timeout"
+	EventOperatorReplace  EventKind = "operator-replace"
+	EventOperatorCancel   EventKind = "operator-cancel"
+	EventSchedulerAdd     EventKind = "scheduler-add"
+	EventSchedulerRemove  EventKind = "scheduler-remove"
+	EventStoreState       EventKind = "store-state"
+)
+
+// EventKinds are all the kinds of the scheduling events.
+var EventKinds = []EventKind{
+	EventOperatorCreate,
+	EventOperatorDispatch,
+	EventOperatorFinish,
+	EventOperatorTimeout,
+	EventOperatorReplace,
+	EventOperatorCancel,
+	EventSchedulerAdd,
+	EventSchedulerRemove,
+	EventStoreState,
+}
+
+// ParseEventKind parses the name of an event kind.
+func ParseEventKind(name string) (EventKind, error) {
+	for _, kind := range EventKinds {
+		if string(kind) == name {
+			return kind, nil
+		}
+	}
+	return "", errors.Errorf("unknown event kind %s", name)
+}
+
+var operatorEndEventKinds = map[pdpb.OperatorStatus]EventKind{
+	pdpb.OperatorStatus_SUCCESS: EventOperatorFinish,
+	pdpb.OperatorStatus_TIMEOUT: EventOperatorTimeout,
+	pdpb.OperatorStatus_REPLACE: EventOperatorReplace,
+	pdpb.OperatorStatus_CANCEL:  EventOperatorCancel,
+}
+
+// Event is a scheduling event.
+type Event struct {
+	Time     time.Time `json:"time"`
+	Kind     EventKind `json:"kind"`
+	RegionID uint64    `json:"region_id,omitempty"`
+	// StoreIDs are the stores touched by the operator, or the store whose
+	// state is changed.
+	StoreIDs []uint64 `json:"store_ids,omitempty"`
+	// Operator is the description of the operator.
+	Operator string `json:"operator,omitempty"`
+	// Step is the step sent to the region of the operator.
+	Step      string `json:"step,omitempty"`
+	Scheduler string `json:"scheduler,omitempty"`
+	// State is the new state of the store.
+	State string `json:"state,omitempty"`
+}
+
+// NewOperatorEvent creates an event of the operator.
+func NewOperatorEvent(kind EventKind, op *operator.Operator) *Event {
+	e := &Event{
+		Time:     time.Now(),
+		Kind:     kind,
+		RegionID: op.RegionID(),
+		Operator: op.Desc(),
+	}
+	stores := make(map[uint64]struct{})
+	for i := 0; i < op.Len(); i++ {
+		for _, storeID := range stepStores(op.Step(i)) {
+			if _, ok := stores[storeID]; !ok {
+				stores[storeID] = struct{}{}
+				e.StoreIDs = append(e.StoreIDs, storeID)
+			}
+		}
+	}
+	return e
+}
+
+// EventFilter filters the events, the zero value of a field matches all the
+// events.
+type EventFilter struct {
+	RegionID uint64
+	StoreID  uint64
+	// Kinds matches the events which have any of the kinds.
+	Kinds []EventKind
+}
+
+func (f *EventFilter) match(e *Event) bool {
+	if f.RegionID != 0 && e.RegionID != f.RegionID {
+		return false
+	}
+	if f.StoreID != 0 {
+		found := false
+		for _, id := range e.StoreIDs {
+			if id == f.StoreID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(f.Kinds) == 0 {
+		return true
+	}
+	for _, kind := range f.Kinds {
+		if e.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// EventSubscriber receives the events matching its filter.
+type EventSubscriber struct {
+	bus    *EventBus
+	id     uint64
+	filter EventFilter
+	ch     chan *Event
+}
+
+// Events returns the channel of the events, it is closed once the subscriber
+// is closed or the event bus is closed.
+func (s *EventSubscriber) Events() <-chan *Event {
+	return s.ch
+}
+
+// Close unsubscribes the events.
+func (s *EventSubscriber) Close() {
+	s.bus.unsubscribe(s.id)
+}
+
+// EventBus publishes the scheduling events to the subscribers. A nil EventBus
+// drops all the events.
+type EventBus struct {
+	sync.Mutex
+	closed      bool
+	nextID      uint64
+	subscribers map[uint64]*EventSubscriber
+}
+
+// NewEventBus creates an EventBus.
+func NewEventBus() *EventBus {
+	return &EventBus{
+		subscribers: make(map[uint64]*EventSubscriber),
+	}
+}
+
+// Subscribe subscribes the events matching the filter. At most bufferSize
+// events are buffered, and the later events are dropped until the buffered
+// ones are received.
+func (b *EventBus) Subscribe(filter EventFilter, bufferSize int) *EventSubscriber {
+	b.Lock()
+	defer b.Unlock()
+	s := &EventSubscriber{
+		bus:    b,
+		id:     b.nextID,
+		filter: filter,
+		ch:     make(chan *Event, bufferSize),
+	}
+	b.nextID++
+	if b.closed {
+		close(s.ch)
+		return s
+	}
+	b.subscribers[s.id] = s
+	return s
+}
+
+func (b *EventBus) unsubscribe(id uint64) {
+	b.Lock()
+	defer b.Unlock()
+	if s, ok := b.subscribers[id]; ok {
+		delete(b.subscribers, id)
+		close(s.ch)
+	}
+}
+
+// Publish sends the event to the subscribers without blocking.
+func (b *EventBus) Publish(e *Event) {
+	if b == nil {
+		return
+	}
+	b.Lock()
+	defer b.Unlock()
+	for _, s := range b.subscribers {
+		if !s.filter.match(e) {
+			continue
+		}
+		select {
+		case s.ch <- e:
+		default:
+			eventDroppedCounter.Inc()
+		}
+	}
+}
+
+// Close closes all the subscribers, and the later subscribers are closed
+// once they subscribe.
+func (b *EventBus) Close() {
+	b.Lock()
+	defer b.Unlock()
+	b.closed = true
+	for id, s := range b.subscribers {
+		delete(b.subscribers, id)
+		close(s.ch)
+	}
+}
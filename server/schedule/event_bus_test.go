@@ -0,0 +1,109 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/pkg/mock/mockcluster"
+	"github.com/pingcap/pd/pkg/mock/mockhbstream"
+	"github.com/pingcap/pd/pkg/mock/mockoption"
+	"github.com/pingcap/pd/server/schedule/operator"
+)
+
+var _ = Suite(&testEventBusSuite{})
+
+type testEventBusSuite struct{}
+
+func (s *testEventBusSuite) TestFilter(c *C) {
+	bus := NewEventBus()
+	all := bus.Subscribe(EventFilter{}, 4)
+	region := bus.Subscribe(EventFilter{RegionID: 1}, 10)
+	store := bus.Subscribe(EventFilter{StoreID: 2, Kinds: []EventKind{EventOperatorFinish, EventStoreState}}, 10)
+
+	bus.Publish(&Event{Kind: EventOperatorCreate, RegionID: 1, StoreIDs: []uint64{1, 2}})
+	bus.Publish(&Event{Kind: EventOperatorFinish, RegionID: 2, StoreIDs: []uint64{2}})
+	bus.Publish(&Event{Kind: EventSchedulerAdd, Scheduler: "balance-leader-scheduler"})
+	bus.Publish(&Event{Kind: EventStoreState, StoreIDs: []uint64{3}})
+
+	c.Assert(all.Events(), HasLen, 4)
+	c.Assert(region.Events(), HasLen, 1)
+	c.Assert((<-region.Events()).Kind, Equals, EventOperatorCreate)
+	c.Assert(store.Events(), HasLen, 1)
+	c.Assert((<-store.Events()).RegionID, Equals, uint64(2))
+
+	// The events are dropped if the buffer is full.
+	bus.Publish(&Event{Kind: EventOperatorCreate, RegionID: 3})
+	c.Assert(all.Events(), HasLen, 4)
+
+	region.Close()
+	_, ok := <-region.Events()
+	c.Assert(ok, IsFalse)
+	bus.Close()
+	for range all.Events() {
+	}
+	_, ok = <-store.Events()
+	c.Assert(ok, IsFalse)
+	_, ok = <-bus.Subscribe(EventFilter{}, 10).Events()
+	c.Assert(ok, IsFalse)
+
+	kind, err := ParseEventKind("store-state")
+	c.Assert(err, IsNil)
+	c.Assert(kind, Equals, EventStoreState)
+	_, err = ParseEventKind("foo")
+	c.Assert(err, NotNil)
+}
+
+func (s *testEventBusSuite) TestOperatorEvents(c *C) {
+	opt := mockoption.NewScheduleOptions()
+	tc := mockcluster.NewCluster(opt)
+	oc := NewOperatorController(tc, mockhbstream.NewHeartbeatStream())
+	bus := NewEventBus()
+	oc.SetEventBus(bus)
+	sub := bus.Subscribe(EventFilter{}, 10)
+	tc.AddLeaderStore(1, 1)
+	tc.AddLeaderStore(2, 0)
+	tc.AddLeaderRegion(1, 1)
+
+	op1 := operator.NewOperator("test", 1, &metapb.RegionEpoch{}, operator.OpRegion, operator.AddPeer{ToStore: 2, PeerID: 2})
+	c.Assert(oc.AddOperator(op1), IsTrue)
+	op2 := operator.NewOperator("test", 1, &metapb.RegionEpoch{}, operator.OpRegion|operator.OpAdmin, operator.AddPeer{ToStore: 2, PeerID: 3})
+	c.Assert(oc.AddOperator(op2), IsTrue)
+	oc.CancelOperator(op2)
+
+	expects := []struct {
+		kind     EventKind
+		operator *operator.Operator
+		step     string
+	}{
+		{EventOperatorCreate, op1, ""},
+		{EventOperatorDispatch, nil, "add peer 2 on store 2"},
+		{EventOperatorReplace, op1, ""},
+		{EventOperatorCreate, op2, ""},
+		{EventOperatorDispatch, nil, "add peer 3 on store 2"},
+		{EventOperatorCancel, op2, ""},
+	}
+	c.Assert(sub.Events(), HasLen, len(expects))
+	for _, expect := range expects {
+		e := <-sub.Events()
+		c.Assert(e.Kind, Equals, expect.kind)
+		c.Assert(e.RegionID, Equals, uint64(1))
+		c.Assert(e.StoreIDs, DeepEquals, []uint64{2})
+		if expect.operator != nil {
+			c.Assert(e.Operator, Equals, expect.operator.Desc())
+		} else {
+			c.Assert(e.Step, Equals, expect.step)
+		}
+	}
+}
@@ -16,9 +16,12 @@ package filter
 import (
 	"fmt"
 
+	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/pd/pkg/cache"
 	"github.com/pingcap/pd/server/core"
 	"github.com/pingcap/pd/server/namespace"
+	"github.com/pingcap/pd/server/placement"
+	"github.com/pingcap/pd/server/placement/rule"
 	"github.com/pingcap/pd/server/schedule/opt"
 )
 
@@ -126,7 +129,8 @@ func (f *overloadFilter) Target(opt opt.Options, store *core.StoreInfo) bool {
 
 type stateFilter struct{}
 
-// NewStateFilter creates a Filter that filters all stores that are not UP.
+// NewStateFilter creates a Filter that filters all stores that are not UP. The
+// stores under maintenance are also filtered as the target.
 func NewStateFilter() Filter {
 	return &stateFilter{}
 }
@@ -140,7 +144,7 @@ func (f *stateFilter) Source(opt opt.Options, store *core.StoreInfo) bool {
 }
 
 func (f *stateFilter) Target(opt opt.Options, store *core.StoreInfo) bool {
-	return !store.IsUp()
+	return !store.IsUp() || store.IsInMaintenance()
 }
 
 type healthFilter struct{}
@@ -349,6 +353,79 @@ func (f *namespaceFilter) Target(opt opt.Options, store *core.StoreInfo) bool {
 	return f.filter(store)
 }
 
+// ruleFilter ensures the target store satisfies the placement rule.
+type ruleFilter struct {
+	rule   *rule.Rule
+	stores []*core.StoreInfo
+	limit  int
+}
+
+// NewRuleFilter creates a Filter that filters all stores which do not match the
+// label constraints of the rule, or break the isolation level of the rule with
+// the stores which hold the other replicas. The clusterStores are used to count
+// the values of the isolation level.
+func NewRuleFilter(rule *rule.Rule, stores, clusterStores []*core.StoreInfo) Filter {
+	return &ruleFilter{
+		rule:   rule,
+		stores: stores,
+		limit:  rule.IsolationLimit(clusterStores),
+	}
+}
+
+func (f *ruleFilter) Type() string {
+	return "rule-filter"
+}
+
+func (f *ruleFilter) Source(opt opt.Options, store *core.StoreInfo) bool {
+	return false
+}
+
+func (f *ruleFilter) Target(opt opt.Options, store *core.StoreInfo) bool {
+	return !f.rule.MatchStore(store) || !f.rule.IsIsolated(f.stores, store, f.limit)
+}
+
+// placementFilter ensures that moving a replica to the target store does not
+// violate more placement constraints.
+type placementFilter struct {
+	config    *placement.Config
+	cluster   placement.Cluster
+	region    *core.RegionInfo
+	source    *core.StoreInfo
+	safeScore int
+}
+
+// NewPlacementFilter creates a Filter that filters all stores which make the
+// region violate more placement constraints than before if the replica on the
+// source store is moved to them.
+func NewPlacementFilter(config *placement.Config, cluster placement.Cluster, region *core.RegionInfo, source *core.StoreInfo) Filter {
+	return &placementFilter{
+		config:    config,
+		cluster:   cluster,
+		region:    region,
+		source:    source,
+		safeScore: config.Score(region, cluster),
+	}
+}
+
+func (f *placementFilter) Type() string {
+	return "placement-filter"
+}
+
+func (f *placementFilter) Source(opt opt.Options, store *core.StoreInfo) bool {
+	return false
+}
+
+func (f *placementFilter) Target(opt opt.Options, store *core.StoreInfo) bool {
+	if len(f.config.Constraints) == 0 {
+		return false
+	}
+	newRegion := f.region.Clone(
+		core.WithRemoveStorePeer(f.source.GetID()),
+		core.WithAddPeer(&metapb.Peer{StoreId: store.GetID()}),
+	)
+	return f.config.Score(newRegion, f.cluster) < f.safeScore
+}
+
 type rejectLeaderFilter struct{}
 
 // NewRejectLeaderFilter creates a Filter that filters stores that marked as
@@ -417,6 +494,10 @@ func (f StoreStateFilter) Target(opts opt.Options, store *core.StoreInfo) bool {
 	}
 
 	if f.MoveRegion {
+		// do not move regions to the stores being evicted or under maintenance.
+		if store.IsEvictingRegions() || store.IsInMaintenance() {
+			return true
+		}
 		// only target consider the pending peers because pending more means the disk is slower.
 		if opts.GetMaxPendingPeerCount() > 0 && store.GetPendingPeerCount() > int(opts.GetMaxPendingPeerCount()) {
 			return true
@@ -20,6 +20,7 @@ import (
 	"github.com/pingcap/pd/pkg/mock/mockcluster"
 	"github.com/pingcap/pd/pkg/mock/mockoption"
 	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/placement"
 )
 
 func Test(t *testing.T) {
@@ -44,3 +45,39 @@ func (s *testFiltersSuite) TestPendingPeerFilter(c *C) {
 	c.Assert(filter.Source(tc, newStore), IsFalse)
 	c.Assert(filter.Target(tc, newStore), IsFalse)
 }
+
+func (s *testFiltersSuite) TestPlacementFilter(c *C) {
+	opt := mockoption.NewScheduleOptions()
+	tc := mockcluster.NewCluster(opt)
+	tc.AddLabelsStore(1, 1, map[string]string{"zone": "z1"})
+	tc.AddLabelsStore(2, 1, map[string]string{"zone": "z1"})
+	tc.AddLabelsStore(3, 1, map[string]string{"zone": "z1"})
+	tc.AddLabelsStore(4, 1, map[string]string{"zone": "z2"})
+	tc.AddLabelsStore(5, 1, map[string]string{"zone": "z2"})
+	tc.AddLeaderRegion(1, 1, 2, 4)
+	region := tc.GetRegion(1)
+
+	cfg, err := placement.ParseConfig("count(zone:z2)>=1")
+	c.Assert(err, IsNil)
+	filter := NewPlacementFilter(cfg, tc, region, tc.GetStore(4))
+	c.Assert(filter.Source(tc, tc.GetStore(4)), IsFalse)
+	c.Assert(filter.Target(tc, tc.GetStore(3)), IsTrue)
+	c.Assert(filter.Target(tc, tc.GetStore(5)), IsFalse)
+	filter = NewPlacementFilter(cfg, tc, region, tc.GetStore(1))
+	c.Assert(filter.Target(tc, tc.GetStore(3)), IsFalse)
+	c.Assert(filter.Target(tc, tc.GetStore(5)), IsFalse)
+
+	// A move which does not make it worse is allowed for a region violating the
+	// constraints.
+	cfg, err = placement.ParseConfig("count(zone:z2)>=2")
+	c.Assert(err, IsNil)
+	filter = NewPlacementFilter(cfg, tc, region, tc.GetStore(1))
+	c.Assert(filter.Target(tc, tc.GetStore(3)), IsFalse)
+	filter = NewPlacementFilter(cfg, tc, region, tc.GetStore(4))
+	c.Assert(filter.Target(tc, tc.GetStore(3)), IsTrue)
+	c.Assert(filter.Target(tc, tc.GetStore(5)), IsFalse)
+
+	// No store is filtered without constraints.
+	filter = NewPlacementFilter(&placement.Config{}, tc, region, tc.GetStore(4))
+	c.Assert(filter.Target(tc, tc.GetStore(3)), IsFalse)
+}
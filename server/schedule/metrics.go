@@ -57,6 +57,14 @@ var (
 			Name:      "store_limit",
 			Help:      "Limit of store.",
 		}, []string{"store", "type"})
+
+	eventDroppedCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "schedule",
+			Name:      "events_dropped_count",
+			Help:      "Counter of scheduling events dropped because the subscribers cannot keep up.",
+		})
 )
 
 func init() {
@@ -65,4 +73,5 @@ func init() {
 	prometheus.MustRegister(operatorWaitDuration)
 	prometheus.MustRegister(storeLimitGauge)
 	prometheus.MustRegister(operatorWaitCounter)
+	prometheus.MustRegister(eventDroppedCounter)
 }
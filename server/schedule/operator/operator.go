@@ -26,19 +26,31 @@ import (
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/pingcap/log"
+	"github.com/pingcap/pd/pkg/typeutil"
 	"github.com/pingcap/pd/server/core"
 	"github.com/pingcap/pd/server/schedule/opt"
+	"github.com/pingcap/pd/server/schedule/storelimit"
 	"github.com/pingcap/pd/server/statistics"
 	"go.uber.org/zap"
 )
 
 const (
-	// LeaderOperatorWaitTime is the duration that when a leader operator lives
-	// longer than it, the operator will be considered timeout.
+	// LeaderOperatorWaitTime is the duration that when a step which transfers
+	// the leader, promotes a learner or removes a peer runs longer than it, the
+	// operator will be considered timeout.
 	LeaderOperatorWaitTime = 10 * time.Second
-	// RegionOperatorWaitTime is the duration that when a region operator lives
-	// longer than it, the operator will be considered timeout.
+	// RegionOperatorWaitTime is the duration that when a step which merges or
+	// splits a region runs longer than it, the operator will be considered
+	// timeout.
 	RegionOperatorWaitTime = 10 * time.Minute
+	// SnapshotOperatorWaitTime is the base duration that when a step which
+	// sends a snapshot to add a peer runs longer than it, the operator will be
+	// considered timeout. It is extended by SnapshotWaitTimePerMB for each MB of
+	// the region.
+	SnapshotOperatorWaitTime = 2 * time.Minute
+	// SnapshotWaitTimePerMB is the extra wait time of a step which sends a
+	// snapshot for each MB of the region.
+	SnapshotWaitTimePerMB = 3 * time.Second
 	// RegionInfluence represents the influence of a operator step, which is used by ratelimit.
 	RegionInfluence int64 = 1000
 	// smallRegionInfluence represents the influence of a operator step
@@ -83,7 +95,32 @@ type StoreInfluence struct {
 	RegionCount int64
 	LeaderSize  int64
 	LeaderCount int64
-	StepCost    int64
+	// StepCost is the cost of the steps charged to each type of the store
+	// limits.
+	StepCost map[storelimit.Type]int64
+}
+
+// AddStepCost adds the cost of a step to the store limit of the type.
+func (s *StoreInfluence) AddStepCost(limitType storelimit.Type, regionSize int64) {
+	var cost int64
+	if regionSize > smallRegionThreshold {
+		cost = RegionInfluence
+	} else if regionSize <= smallRegionThreshold && regionSize > core.EmptyRegionApproximateSize {
+		cost = smallRegionInfluence
+	}
+	if cost == 0 {
+		return
+	}
+	if s.StepCost == nil {
+		s.StepCost = make(map[storelimit.Type]int64)
+	}
+	s.StepCost[limitType] += cost
+}
+
+// GetStepCost returns the cost of the steps charged to the store limit of
+// the type.
+func (s *StoreInfluence) GetStepCost(limitType storelimit.Type) int64 {
+	return s.StepCost[limitType]
 }
 
 // ResourceSize returns delta size of leader/region by influence.
@@ -103,6 +140,17 @@ type OpStep interface {
 	fmt.Stringer
 	IsFinish(region *core.RegionInfo) bool
 	Influence(opInfluence OpInfluence, region *core.RegionInfo)
+	// Timeout returns how long the step can run on a region of the size.
+	Timeout(regionSize int64) time.Duration
+}
+
+// snapshotStepTimeout returns the timeout of a step which sends a snapshot of
+// a region of the size.
+func snapshotStepTimeout(regionSize int64) time.Duration {
+	if regionSize < 0 {
+		regionSize = 0
+	}
+	return SnapshotOperatorWaitTime + time.Duration(regionSize)*SnapshotWaitTimePerMB
 }
 
 // TransferLeader is an OpStep that transfers a region's leader.
@@ -130,6 +178,11 @@ func (tl TransferLeader) Influence(opInfluence OpInfluence, region *core.RegionI
 	to.LeaderCount++
 }
 
+// Timeout returns how long the step can run.
+func (tl TransferLeader) Timeout(regionSize int64) time.Duration {
+	return LeaderOperatorWaitTime
+}
+
 // AddPeer is an OpStep that adds a region peer.
 type AddPeer struct {
 	ToStore, PeerID uint64
@@ -158,11 +211,12 @@ func (ap AddPeer) Influence(opInfluence OpInfluence, region *core.RegionInfo) {
 	regionSize := region.GetApproximateSize()
 	to.RegionSize += regionSize
 	to.RegionCount++
-	if regionSize > smallRegionThreshold {
-		to.StepCost += RegionInfluence
-	} else if regionSize <= smallRegionThreshold && regionSize > core.EmptyRegionApproximateSize {
-		to.StepCost += smallRegionInfluence
-	}
+	to.AddStepCost(storelimit.AddPeer, regionSize)
+}
+
+// Timeout returns how long the step can run.
+func (ap AddPeer) Timeout(regionSize int64) time.Duration {
+	return snapshotStepTimeout(regionSize)
 }
 
 // AddLearner is an OpStep that adds a region learner peer.
@@ -193,11 +247,12 @@ func (al AddLearner) Influence(opInfluence OpInfluence, region *core.RegionInfo)
 	regionSize := region.GetApproximateSize()
 	to.RegionSize += regionSize
 	to.RegionCount++
-	if regionSize > smallRegionThreshold {
-		to.StepCost += RegionInfluence
-	} else if regionSize <= smallRegionThreshold && regionSize > core.EmptyRegionApproximateSize {
-		to.StepCost += smallRegionInfluence
-	}
+	to.AddStepCost(storelimit.AddPeer, regionSize)
+}
+
+// Timeout returns how long the step can run.
+func (al AddLearner) Timeout(regionSize int64) time.Duration {
+	return snapshotStepTimeout(regionSize)
 }
 
 // PromoteLearner is an OpStep that promotes a region learner peer to normal voter.
@@ -223,6 +278,11 @@ func (pl PromoteLearner) IsFinish(region *core.RegionInfo) bool {
 // Influence calculates the store difference that current step makes.
 func (pl PromoteLearner) Influence(opInfluence OpInfluence, region *core.RegionInfo) {}
 
+// Timeout returns how long the step can run.
+func (pl PromoteLearner) Timeout(regionSize int64) time.Duration {
+	return LeaderOperatorWaitTime
+}
+
 // RemovePeer is an OpStep that removes a region peer.
 type RemovePeer struct {
 	FromStore uint64
@@ -241,8 +301,15 @@ func (rp RemovePeer) IsFinish(region *core.RegionInfo) bool {
 func (rp RemovePeer) Influence(opInfluence OpInfluence, region *core.RegionInfo) {
 	from := opInfluence.GetStoreInfluence(rp.FromStore)
 
-	from.RegionSize -= region.GetApproximateSize()
+	regionSize := region.GetApproximateSize()
+	from.RegionSize -= regionSize
 	from.RegionCount--
+	from.AddStepCost(storelimit.RemovePeer, regionSize)
+}
+
+// Timeout returns how long the step can run.
+func (rp RemovePeer) Timeout(regionSize int64) time.Duration {
+	return LeaderOperatorWaitTime
 }
 
 // MergeRegion is an OpStep that merge two regions.
@@ -283,6 +350,11 @@ func (mr MergeRegion) Influence(opInfluence OpInfluence, region *core.RegionInfo
 	}
 }
 
+// Timeout returns how long the step can run.
+func (mr MergeRegion) Timeout(regionSize int64) time.Duration {
+	return RegionOperatorWaitTime
+}
+
 // SplitRegion is an OpStep that splits a region.
 type SplitRegion struct {
 	StartKey, EndKey []byte
@@ -309,6 +381,11 @@ func (sr SplitRegion) Influence(opInfluence OpInfluence, region *core.RegionInfo
 	}
 }
 
+// Timeout returns how long the step can run.
+func (sr SplitRegion) Timeout(regionSize int64) time.Duration {
+	return RegionOperatorWaitTime
+}
+
 // AddLightPeer is an OpStep that adds a region peer without considering the influence.
 type AddLightPeer struct {
 	ToStore, PeerID uint64
@@ -338,6 +415,11 @@ func (ap AddLightPeer) Influence(opInfluence OpInfluence, region *core.RegionInf
 	to.RegionCount++
 }
 
+// Timeout returns how long the step can run.
+func (ap AddLightPeer) Timeout(regionSize int64) time.Duration {
+	return snapshotStepTimeout(regionSize)
+}
+
 // AddLightLearner is an OpStep that adds a region learner peer without considering the influence.
 type AddLightLearner struct {
 	ToStore, PeerID uint64
@@ -367,6 +449,11 @@ func (al AddLightLearner) Influence(opInfluence OpInfluence, region *core.Region
 	to.RegionCount++
 }
 
+// Timeout returns how long the step can run.
+func (al AddLightLearner) Timeout(regionSize int64) time.Duration {
+	return snapshotStepTimeout(regionSize)
+}
+
 // Operator contains execution steps generated by scheduler.
 type Operator struct {
 	desc        string
@@ -378,8 +465,11 @@ type Operator struct {
 	createTime  time.Time
 	// startTime is used to record the start time of an operator which is added into running operators.
 	startTime time.Time
-	stepTime  int64
-	level     core.PriorityLevel
+	// stepsTime records the finish time of the steps in unix nanoseconds, a
+	// step starts once the previous one is finished.
+	stepsTime       []int64
+	approximateSize int64
+	level           core.PriorityLevel
 }
 
 // NewOperator creates a new operator.
@@ -391,7 +481,7 @@ func NewOperator(desc string, regionID uint64, regionEpoch *metapb.RegionEpoch,
 		kind:        kind,
 		steps:       steps,
 		createTime:  time.Now(),
-		stepTime:    time.Now().UnixNano(),
+		stepsTime:   make([]int64, len(steps)),
 		level:       core.NormalPriority,
 	}
 }
@@ -452,6 +542,11 @@ func (o *Operator) RunningTime() time.Duration {
 	return time.Since(o.startTime)
 }
 
+// GetCreateTime gets the create time for operator.
+func (o *Operator) GetCreateTime() time.Time {
+	return o.createTime
+}
+
 // SetStartTime sets the start time for operator.
 func (o *Operator) SetStartTime(t time.Time) {
 	o.startTime = t
@@ -462,6 +557,17 @@ func (o *Operator) GetStartTime() time.Time {
 	return o.startTime
 }
 
+// SetApproximateSize sets the approximate size of the region, which decides
+// the timeout of the steps sending snapshots.
+func (o *Operator) SetApproximateSize(size int64) {
+	atomic.StoreInt64(&o.approximateSize, size)
+}
+
+// GetApproximateSize gets the approximate size of the region.
+func (o *Operator) GetApproximateSize() int64 {
+	return atomic.LoadInt64(&o.approximateSize)
+}
+
 // Len returns the operator's steps count.
 func (o *Operator) Len() int {
 	return len(o.steps)
@@ -480,10 +586,11 @@ func (o *Operator) Step(i int) OpStep {
 func (o *Operator) Check(region *core.RegionInfo) OpStep {
 	for step := atomic.LoadInt32(&o.currentStep); int(step) < len(o.steps); step++ {
 		if o.steps[int(step)].IsFinish(region) {
+			now := time.Now()
 			operatorStepDuration.WithLabelValues(reflect.TypeOf(o.steps[int(step)]).Name()).
-				Observe(time.Since(time.Unix(0, atomic.LoadInt64(&o.stepTime))).Seconds())
+				Observe(now.Sub(o.stepStartTime(int(step))).Seconds())
+			atomic.StoreInt64(&o.stepsTime[int(step)], now.UnixNano())
 			atomic.StoreInt32(&o.currentStep, step+1)
-			atomic.StoreInt64(&o.stepTime, time.Now().UnixNano())
 		} else {
 			return o.steps[int(step)]
 		}
@@ -501,29 +608,46 @@ func (o *Operator) GetPriorityLevel() core.PriorityLevel {
 	return o.level
 }
 
+// GetPriorityClass gets the priority class, which is decided by the kind.
+func (o *Operator) GetPriorityClass() PriorityClass {
+	return PriorityClassOf(o.kind)
+}
+
 // IsFinish checks if all steps are finished.
 func (o *Operator) IsFinish() bool {
 	return atomic.LoadInt32(&o.currentStep) >= int32(len(o.steps))
 }
 
-// IsTimeout checks the operator's create time and determines if it is timeout.
-func (o *Operator) IsTimeout() bool {
-	var timeout bool
-	if o.IsFinish() {
-		return false
+// stepStartTime returns the start time of the i-th step. The first step
+// starts with the operator, or it is considered to start once the operator is
+// created if the operator is not started yet.
+func (o *Operator) stepStartTime(i int) time.Time {
+	if i > 0 {
+		return time.Unix(0, atomic.LoadInt64(&o.stepsTime[i-1]))
 	}
 	if o.startTime.IsZero() {
-		return false
+		return o.createTime
 	}
-	if o.kind&OpRegion != 0 {
-		timeout = time.Since(o.startTime) > RegionOperatorWaitTime
-	} else {
-		timeout = time.Since(o.startTime) > LeaderOperatorWaitTime
+	return o.startTime
+}
+
+// CurrentStep returns the step which is running, it returns nil if the
+// operator is finished.
+func (o *Operator) CurrentStep() OpStep {
+	return o.Step(int(atomic.LoadInt32(&o.currentStep)))
+}
+
+// IsTimeout checks the start time of the current step and determines if the
+// step runs longer than its timeout.
+func (o *Operator) IsTimeout() bool {
+	if o.startTime.IsZero() {
+		return false
 	}
-	if timeout {
-		return true
+	step := int(atomic.LoadInt32(&o.currentStep))
+	if step >= len(o.steps) {
+		return false
 	}
-	return false
+	return time.Since(o.stepStartTime(step)) > o.steps[step].Timeout(o.GetApproximateSize())
 }
 
 // UnfinishedInfluence calculates the store difference which unfinished operator steps make.
@@ -542,6 +666,51 @@ func (o *Operator) TotalInfluence(opInfluence OpInfluence, region *core.RegionIn
 	}
 }
 
+// StepProgress is the progress of an operator step.
+type StepProgress struct {
+	Step string `json:"step"`
+	// StartTime is nil if the step is not started, and FinishTime is nil if
+	// the step is not finished.
+	StartTime  *time.Time        `json:"start_time,omitempty"`
+	FinishTime *time.Time        `json:"finish_time,omitempty"`
+	Timeout    typeutil.Duration `json:"timeout"`
+}
+
+func (p StepProgress) String() string {
+	s := p.Step
+	if p.StartTime != nil {
+		s += fmt.Sprintf(" (startAt:%s", p.StartTime)
+		if p.FinishTime != nil {
+			s += fmt.Sprintf(", finishAt:%s", p.FinishTime)
+		}
+		s += ")"
+	}
+	return s
+}
+
+// Progress returns the progress of the steps.
+func (o *Operator) Progress() []StepProgress {
+	current := int(atomic.LoadInt32(&o.currentStep))
+	size := o.GetApproximateSize()
+	progress := make([]StepProgress, 0, len(o.steps))
+	for i, step := range o.steps {
+		p := StepProgress{
+			Step:    step.String(),
+			Timeout: typeutil.NewDuration(step.Timeout(size)),
+		}
+		if i <= current && !o.startTime.IsZero() {
+			start := o.stepStartTime(i)
+			p.StartTime = &start
+		}
+		if i < current {
+			finish := time.Unix(0, atomic.LoadInt64(&o.stepsTime[i]))
+			p.FinishTime = &finish
+		}
+		progress = append(progress, p)
+	}
+	return progress
+}
+
 // OpHistory is used to log and visualize completed operators.
 type OpHistory struct {
 	FinishTime time.Time
@@ -705,6 +874,15 @@ func CreateMovePeerOperator(desc string, cluster Cluster, region *core.RegionInf
 	return NewOperator(desc, region.GetID(), region.GetRegionEpoch(), removeKind|kind|OpRegion, steps...), nil
 }
 
+// CreateMoveLearnerOperator creates an operator that replaces an old learner with a new learner.
+func CreateMoveLearnerOperator(desc string, region *core.RegionInfo, kind OpKind, oldStore, newStore uint64, peerID uint64) *Operator {
+	steps := []OpStep{
+		AddLearner{ToStore: newStore, PeerID: peerID},
+		RemovePeer{FromStore: oldStore},
+	}
+	return NewOperator(desc, region.GetID(), region.GetRegionEpoch(), kind|OpRegion, steps...)
+}
+
 // CreateMoveLeaderOperator creates an operator that replaces an old leader with a new leader.
 func CreateMoveLeaderOperator(desc string, cluster Cluster, region *core.RegionInfo, kind OpKind, oldStore, newStore uint64, peerID uint64) (*Operator, error) {
 	removeKind, steps, err := removePeerSteps(cluster, region, oldStore, []uint64{newStore})
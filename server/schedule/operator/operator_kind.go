@@ -33,6 +33,8 @@ const (
 	OpBalance                      // Initiated by balancers.
 	OpMerge                        // Initiated by merge checkers or merge schedulers.
 	OpRange                        // Initiated by range scheduler.
+	OpSplit                        // Initiated by split schedulers.
+	OpRepair                       // Repairing the missing, down or offline replicas.
 	opMax
 )
 
@@ -46,6 +48,8 @@ var flagToName = map[OpKind]string{
 	OpBalance:   "balance",
 	OpMerge:     "merge",
 	OpRange:     "range",
+	OpSplit:     "split",
+	OpRepair:    "repair",
 }
 
 var nameToFlag = map[string]OpKind{
@@ -58,6 +62,8 @@ var nameToFlag = map[string]OpKind{
 	"balance":    OpBalance,
 	"merge":      OpMerge,
 	"range":      OpRange,
+	"split":      OpSplit,
+	"repair":     OpRepair,
 }
 
 func (k OpKind) String() string {
@@ -86,3 +92,52 @@ func ParseOperatorKind(str string) (OpKind, error) {
 	return k, nil
 
 }
+
+// PriorityClass is the class of an operator. The operators of a higher class
+// preempt the operators of the lower classes on the same region, and they are
+// promoted from the waiting operators and take the store limit first.
+type PriorityClass int
+
+// Priority classes from the lowest to the highest.
+const (
+	// BalanceClass is the class of the operators balancing the cluster.
+	BalanceClass PriorityClass = iota
+	// HotClass is the class of the operators scheduling the hot regions.
+	HotClass
+	// AdminClass is the class of the operators initiated by admin.
+	AdminClass
+	// RepairClass is the class of the operators repairing the replicas, such
+	// as replacing a down replica.
+	RepairClass
+	// PriorityClassCount is the number of the priority classes.
+	PriorityClassCount
+)
+
+func (c PriorityClass) String() string {
+	switch c {
+	case BalanceClass:
+		return "balance"
+	case HotClass:
+		return "hot"
+	case AdminClass:
+		return "admin"
+	case RepairClass:
+		return "repair"
+	}
+	return "unknown"
+}
+
+// PriorityClassOf returns the priority class of the operators of the kind.
+// The other operators of the replica checkers, such as moving a replica to a
+// better location, are in the balance class.
+func PriorityClassOf(kind OpKind) PriorityClass {
+	switch {
+	case kind&OpRepair != 0:
+		return RepairClass
+	case kind&OpAdmin != 0:
+		return AdminClass
+	case kind&OpHotRegion != 0:
+		return HotClass
+	}
+	return BalanceClass
+}
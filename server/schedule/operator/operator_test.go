@@ -22,6 +22,7 @@ import (
 	. "github.com/pingcap/check"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/schedule/storelimit"
 )
 
 func Test(t *testing.T) {
@@ -99,11 +100,12 @@ func (s *testOperatorSuite) TestOperator(c *C) {
 	s.checkSteps(c, op, steps)
 	c.Assert(op.Check(region), Equals, RemovePeer{FromStore: 2})
 	c.Assert(atomic.LoadInt32(&op.currentStep), Equals, int32(2))
-	op.startTime = time.Now()
+	op.startTime = time.Now().Add(-RegionOperatorWaitTime - time.Second)
 	c.Assert(op.IsTimeout(), IsFalse)
-	op.startTime = op.startTime.Add(-LeaderOperatorWaitTime - time.Second)
+	// The remove peer step starts once the transfer leader step is finished.
+	op.stepsTime[1] = time.Now().Add(-LeaderOperatorWaitTime + time.Second).UnixNano()
 	c.Assert(op.IsTimeout(), IsFalse)
-	op.startTime = op.startTime.Add(-RegionOperatorWaitTime - time.Second)
+	op.stepsTime[1] = time.Now().Add(-LeaderOperatorWaitTime - time.Second).UnixNano()
 	c.Assert(op.IsTimeout(), IsTrue)
 	res, err := json.Marshal(op)
 	c.Assert(err, IsNil)
@@ -118,6 +120,45 @@ func (s *testOperatorSuite) TestOperator(c *C) {
 	c.Assert(op.IsTimeout(), IsTrue)
 }
 
+func (s *testOperatorSuite) TestStepTimeout(c *C) {
+	c.Assert(TransferLeader{FromStore: 1, ToStore: 2}.Timeout(96), Equals, LeaderOperatorWaitTime)
+	c.Assert(RemovePeer{FromStore: 1}.Timeout(96), Equals, LeaderOperatorWaitTime)
+	c.Assert(MergeRegion{}.Timeout(96), Equals, RegionOperatorWaitTime)
+	c.Assert(AddLearner{ToStore: 1, PeerID: 1}.Timeout(0), Equals, SnapshotOperatorWaitTime)
+	c.Assert(AddLearner{ToStore: 1, PeerID: 1}.Timeout(96), Equals, SnapshotOperatorWaitTime+96*SnapshotWaitTimePerMB)
+
+	// The step sending a snapshot of a large region runs longer.
+	op := s.newTestOperator(1, OpRegion, AddLearner{ToStore: 3, PeerID: 3}, PromoteLearner{ToStore: 3, PeerID: 3})
+	op.startTime = time.Now().Add(-SnapshotOperatorWaitTime - time.Second)
+	op.SetApproximateSize(96)
+	c.Assert(op.IsTimeout(), IsFalse)
+	op.SetApproximateSize(0)
+	c.Assert(op.IsTimeout(), IsTrue)
+}
+
+func (s *testOperatorSuite) TestProgress(c *C) {
+	region := s.newTestRegion(1, 1, [2]uint64{1, 1}, [2]uint64{2, 2})
+	op := s.newTestOperator(1, OpLeader|OpRegion, AddPeer{ToStore: 1, PeerID: 1}, TransferLeader{FromStore: 1, ToStore: 2})
+	progress := op.Progress()
+	c.Assert(progress, HasLen, 2)
+	for _, p := range progress {
+		c.Assert(p.StartTime, IsNil)
+		c.Assert(p.FinishTime, IsNil)
+	}
+
+	op.SetApproximateSize(50)
+	op.startTime = time.Now()
+	c.Assert(op.Check(region), Equals, TransferLeader{FromStore: 1, ToStore: 2})
+	progress = op.Progress()
+	c.Assert(progress[0].Step, Equals, AddPeer{ToStore: 1, PeerID: 1}.String())
+	c.Assert(progress[0].Timeout.Duration, Equals, SnapshotOperatorWaitTime+50*SnapshotWaitTimePerMB)
+	c.Assert(*progress[0].StartTime, Equals, op.startTime)
+	c.Assert(progress[0].FinishTime, NotNil)
+	c.Assert(*progress[1].StartTime, Equals, *progress[0].FinishTime)
+	c.Assert(progress[1].FinishTime, IsNil)
+	c.Assert(progress[1].Timeout.Duration, Equals, LeaderOperatorWaitTime)
+}
+
 func (s *testOperatorSuite) TestInfluence(c *C) {
 	region := s.newTestRegion(1, 1, [2]uint64{1, 1}, [2]uint64{2, 2})
 	opInfluence := OpInfluence{StoresInfluence: make(map[uint64]*StoreInfluence)}
@@ -131,7 +172,7 @@ func (s *testOperatorSuite) TestInfluence(c *C) {
 		LeaderCount: 0,
 		RegionSize:  50,
 		RegionCount: 1,
-		StepCost:    1000,
+		StepCost:    map[storelimit.Type]int64{storelimit.AddPeer: 1000},
 	})
 
 	TransferLeader{FromStore: 1, ToStore: 2}.Influence(opInfluence, region)
@@ -140,14 +181,13 @@ func (s *testOperatorSuite) TestInfluence(c *C) {
 		LeaderCount: -1,
 		RegionSize:  0,
 		RegionCount: 0,
-		StepCost:    0,
 	})
 	c.Assert(*storeOpInfluence[2], DeepEquals, StoreInfluence{
 		LeaderSize:  50,
 		LeaderCount: 1,
 		RegionSize:  50,
 		RegionCount: 1,
-		StepCost:    1000,
+		StepCost:    map[storelimit.Type]int64{storelimit.AddPeer: 1000},
 	})
 
 	RemovePeer{FromStore: 1}.Influence(opInfluence, region)
@@ -156,14 +196,14 @@ func (s *testOperatorSuite) TestInfluence(c *C) {
 		LeaderCount: -1,
 		RegionSize:  -50,
 		RegionCount: -1,
-		StepCost:    0,
+		StepCost:    map[storelimit.Type]int64{storelimit.RemovePeer: 1000},
 	})
 	c.Assert(*storeOpInfluence[2], DeepEquals, StoreInfluence{
 		LeaderSize:  50,
 		LeaderCount: 1,
 		RegionSize:  50,
 		RegionCount: 1,
-		StepCost:    1000,
+		StepCost:    map[storelimit.Type]int64{storelimit.AddPeer: 1000},
 	})
 
 	MergeRegion{IsPassive: false}.Influence(opInfluence, region)
@@ -172,14 +212,14 @@ func (s *testOperatorSuite) TestInfluence(c *C) {
 		LeaderCount: -1,
 		RegionSize:  -50,
 		RegionCount: -1,
-		StepCost:    0,
+		StepCost:    map[storelimit.Type]int64{storelimit.RemovePeer: 1000},
 	})
 	c.Assert(*storeOpInfluence[2], DeepEquals, StoreInfluence{
 		LeaderSize:  50,
 		LeaderCount: 1,
 		RegionSize:  50,
 		RegionCount: 1,
-		StepCost:    1000,
+		StepCost:    map[storelimit.Type]int64{storelimit.AddPeer: 1000},
 	})
 
 	MergeRegion{IsPassive: true}.Influence(opInfluence, region)
@@ -188,14 +228,14 @@ func (s *testOperatorSuite) TestInfluence(c *C) {
 		LeaderCount: -2,
 		RegionSize:  -50,
 		RegionCount: -2,
-		StepCost:    0,
+		StepCost:    map[storelimit.Type]int64{storelimit.RemovePeer: 1000},
 	})
 	c.Assert(*storeOpInfluence[2], DeepEquals, StoreInfluence{
 		LeaderSize:  50,
 		LeaderCount: 1,
 		RegionSize:  50,
 		RegionCount: 0,
-		StepCost:    1000,
+		StepCost:    map[storelimit.Type]int64{storelimit.AddPeer: 1000},
 	})
 }
 
@@ -210,3 +250,16 @@ func (s *testOperatorSuite) TestOperatorKind(c *C) {
 	_, err = ParseOperatorKind("foobar")
 	c.Assert(err, NotNil)
 }
+
+func (s *testOperatorSuite) TestPriorityClass(c *C) {
+	c.Assert(PriorityClassOf(OpRegion|OpBalance), Equals, BalanceClass)
+	c.Assert(PriorityClassOf(OpRegion|OpMerge), Equals, BalanceClass)
+	c.Assert(PriorityClassOf(OpLeader|OpHotRegion), Equals, HotClass)
+	c.Assert(PriorityClassOf(OpRegion|OpAdmin), Equals, AdminClass)
+	c.Assert(PriorityClassOf(OpRegion|OpReplica), Equals, BalanceClass)
+	c.Assert(PriorityClassOf(OpRegion|OpReplica|OpRepair), Equals, RepairClass)
+	c.Assert(PriorityClassOf(OpAdmin|OpReplica|OpRepair), Equals, RepairClass)
+	c.Assert(RepairClass.String(), Equals, "repair")
+	op := s.newTestOperator(1, OpLeader|OpHotRegion)
+	c.Assert(op.GetPriorityClass(), Equals, AdminClass)
+}
@@ -0,0 +1,171 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/pd/server/schedule/operator"
+)
+
+// maxFinishedOperatorBatches is the max number of the finished batches kept.
+const maxFinishedOperatorBatches = 64
+
+// BatchOperatorStatus is the status of an operator in a batch.
+type BatchOperatorStatus struct {
+	RegionID uint64 `json:"region_id"`
+	Desc     string `json:"desc"`
+	Status   string `json:"status"`
+}
+
+// OperatorBatchStatus is the progress of an operator batch.
+type OperatorBatchStatus struct {
+	ID         uint64    `json:"id"`
+	CreateTime time.Time `json:"create_time"`
+	Total      int       `json:"total"`
+	Running    int       `json:"running"`
+	Success    int       `json:"success"`
+	// Failed is the number of the operators which are cancelled, replaced or
+	// timeout.
+	Failed    int                    `json:"failed"`
+	Finished  bool                   `json:"finished"`
+	Operators []*BatchOperatorStatus `json:"operators"`
+}
+
+// operatorBatch is a batch of operators which are admitted all or none.
+type operatorBatch struct {
+	id         uint64
+	createTime time.Time
+	ops        []*operator.Operator
+	// ended records the status of the ended operators.
+	ended map[*operator.Operator]pdpb.OperatorStatus
+}
+
+func (b *operatorBatch) isFinished() bool {
+	return len(b.ended) == len(b.ops)
+}
+
+func (b *operatorBatch) status() *OperatorBatchStatus {
+	s := &OperatorBatchStatus{
+		ID:         b.id,
+		CreateTime: b.createTime,
+		Total:      len(b.ops),
+		Finished:   b.isFinished(),
+		Operators:  make([]*BatchOperatorStatus, 0, len(b.ops)),
+	}
+	for _, op := range b.ops {
+		status, ok := b.ended[op]
+		switch {
+		case !ok:
+			status = pdpb.OperatorStatus_RUNNING
+			s.Running++
+		case status == pdpb.OperatorStatus_SUCCESS:
+			s.Success++
+		default:
+			s.Failed++
+		}
+		s.Operators = append(s.Operators, &BatchOperatorStatus{
+			RegionID: op.RegionID(),
+			Desc:     op.Desc(),
+			Status:   status.String(),
+		})
+	}
+	return s
+}
+
+// operatorBatches tracks the operator batches until they are finished, and
+// keeps the latest finished batches.
+type operatorBatches struct {
+	sync.Mutex
+	nextID   uint64
+	batches  map[uint64]*operatorBatch
+	finished []uint64
+	// running maps the running operators to their batches.
+	running map[*operator.Operator]*operatorBatch
+}
+
+func newOperatorBatches() *operatorBatches {
+	return &operatorBatches{
+		nextID:  1,
+		batches: make(map[uint64]*operatorBatch),
+		running: make(map[*operator.Operator]*operatorBatch),
+	}
+}
+
+// put creates a batch of the operators and returns its ID.
+func (bs *operatorBatches) put(ops []*operator.Operator) uint64 {
+	bs.Lock()
+	defer bs.Unlock()
+	b := &operatorBatch{
+		id:         bs.nextID,
+		createTime: time.Now(),
+		ops:        ops,
+		ended:      make(map[*operator.Operator]pdpb.OperatorStatus),
+	}
+	bs.nextID++
+	bs.batches[b.id] = b
+	for _, op := range ops {
+		bs.running[op] = b
+	}
+	return b.id
+}
+
+func (bs *operatorBatches) getStatus(id uint64) *OperatorBatchStatus {
+	bs.Lock()
+	defer bs.Unlock()
+	b, ok := bs.batches[id]
+	if !ok {
+		return nil
+	}
+	return b.status()
+}
+
+// getRunningOperators returns the operators of the batch which are not ended.
+func (bs *operatorBatches) getRunningOperators(id uint64) ([]*operator.Operator, bool) {
+	bs.Lock()
+	defer bs.Unlock()
+	b, ok := bs.batches[id]
+	if !ok {
+		return nil, false
+	}
+	var ops []*operator.Operator
+	for _, op := range b.ops {
+		if _, ok := b.ended[op]; !ok {
+			ops = append(ops, op)
+		}
+	}
+	return ops, true
+}
+
+// end records the status of the ended operator if it is in a batch.
+func (bs *operatorBatches) end(op *operator.Operator, status pdpb.OperatorStatus) {
+	bs.Lock()
+	defer bs.Unlock()
+	b, ok := bs.running[op]
+	if !ok {
+		return
+	}
+	delete(bs.running, op)
+	b.ended[op] = status
+	if !b.isFinished() {
+		return
+	}
+	bs.finished = append(bs.finished, b.id)
+	if len(bs.finished) > maxFinishedOperatorBatches {
+		delete(bs.batches, bs.finished[0])
+		bs.finished = bs.finished[1:]
+	}
+}
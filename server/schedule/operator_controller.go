@@ -21,14 +21,16 @@ import (
 	"sync"
 	"time"
 
-	"github.com/juju/ratelimit"
 	"github.com/pingcap/kvproto/pkg/eraftpb"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/pingcap/log"
 	"github.com/pingcap/pd/pkg/cache"
 	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/placement"
+	"github.com/pingcap/pd/server/schedule/filter"
 	"github.com/pingcap/pd/server/schedule/operator"
+	"github.com/pingcap/pd/server/schedule/storelimit"
 	"go.uber.org/zap"
 )
 
@@ -47,6 +49,9 @@ var (
 	PushOperatorTickInterval = 500 * time.Millisecond
 	// StoreBalanceBaseTime represents the base time of balance rate.
 	StoreBalanceBaseTime float64 = 60
+	// slowTargetRemainTime is how long a store is avoided to add a peer of a
+	// region after it fails to add the peer in time.
+	slowTargetRemainTime = 10 * time.Minute
 )
 
 // HeartbeatStreams is an interface of async region heartbeat.
@@ -63,11 +68,24 @@ type OperatorController struct {
 	histories *list.List
 	counts    map[operator.OpKind]uint64
 	opRecords *OperatorRecords
+	// opHistory persists the ended operators, nil means the operators are
+	// not persisted.
+	opHistory *OperatorHistory
+	// eventBus publishes the operator events, nil means the events are
+	// dropped.
+	eventBus *EventBus
+	// placementConfig is the placement constraints which the schedulers
+	// should not break when they select the target stores.
+	placementConfig *placement.Config
 	// TODO: Need to clean up the unused store ID.
-	storesLimit     map[uint64]*ratelimit.Bucket
+	storesLimit     map[uint64]map[storelimit.Type]*storelimit.StoreLimit
 	wop             WaitingOperator
 	wopStatus       *WaitingOperatorStatus
 	opNotifierQueue operatorQueue
+	batches         *operatorBatches
+	// slowTargets records the stores which fail to add a peer of a region in
+	// time, the value is a map from the store ID to struct{}.
+	slowTargets *cache.TTL
 }
 
 // NewOperatorController creates a OperatorController.
@@ -79,10 +97,13 @@ func NewOperatorController(cluster Cluster, hbStreams HeartbeatStreams) *Operato
 		histories:       list.New(),
 		counts:          make(map[operator.OpKind]uint64),
 		opRecords:       NewOperatorRecords(),
-		storesLimit:     make(map[uint64]*ratelimit.Bucket),
+		storesLimit:     make(map[uint64]map[storelimit.Type]*storelimit.StoreLimit),
 		wop:             NewRandBuckets(),
 		wopStatus:       NewWaitingOperatorStatus(),
 		opNotifierQueue: make(operatorQueue, 0),
+		batches:         newOperatorBatches(),
+		slowTargets:     cache.NewTTL(time.Minute, slowTargetRemainTime),
+		placementConfig: &placement.Config{},
 	}
 }
 
@@ -102,6 +123,7 @@ func (oc *OperatorController) Dispatch(region *core.RegionInfo, source string) {
 			operatorDuration.WithLabelValues(op.Desc()).Observe(op.RunningTime().Seconds())
 			oc.pushHistory(op)
 			oc.opRecords.Put(op, pdpb.OperatorStatus_SUCCESS)
+			oc.recordEndedOperator(op, pdpb.OperatorStatus_SUCCESS)
 			oc.RemoveOperator(op)
 			oc.PromoteWaitingOperator()
 		} else if timeout {
@@ -222,6 +244,77 @@ func (oc *OperatorController) AddOperator(ops ...*operator.Operator) bool {
 	return true
 }
 
+// AddOperatorBatch adds the operators to the running operators as a batch.
+// Either all of them are added or none of them, and the ID of the batch is
+// returned if they are added.
+func (oc *OperatorController) AddOperatorBatch(ops ...*operator.Operator) (uint64, bool) {
+	oc.Lock()
+	defer oc.Unlock()
+
+	if !oc.checkOperatorBatchLocked(ops...) {
+		for _, op := range ops {
+			operatorCounter.WithLabelValues(op.Desc(), "canceled").Inc()
+			oc.opRecords.Put(op, pdpb.OperatorStatus_CANCEL)
+		}
+		return 0, false
+	}
+	id := oc.batches.put(ops)
+	for _, op := range ops {
+		oc.addOperatorLocked(op)
+	}
+	return id, true
+}
+
+// CheckOperatorBatch checks if the operators can be added as a batch by
+// AddOperatorBatch now, without adding them.
+func (oc *OperatorController) CheckOperatorBatch(ops ...*operator.Operator) bool {
+	oc.Lock()
+	defer oc.Unlock()
+	return oc.checkOperatorBatchLocked(ops...)
+}
+
+func (oc *OperatorController) checkOperatorBatchLocked(ops ...*operator.Operator) bool {
+	if len(ops) == 0 {
+		return false
+	}
+	regions := make(map[uint64]struct{}, len(ops))
+	for _, op := range ops {
+		if _, ok := regions[op.RegionID()]; ok {
+			log.Debug("duplicated region in batch, cancel add operators", zap.Uint64("region-id", op.RegionID()))
+			return false
+		}
+		regions[op.RegionID()] = struct{}{}
+	}
+	return !oc.exceedStoreLimit(ops...) && oc.checkAddOperator(ops...)
+}
+
+// GetOperatorBatchStatus gets the progress of the operator batch, it returns
+// nil if the batch is not found.
+func (oc *OperatorController) GetOperatorBatchStatus(id uint64) *OperatorBatchStatus {
+	return oc.batches.getStatus(id)
+}
+
+// CancelOperatorBatch cancels the running operators of the batch. It returns
+// false if the batch is not found.
+func (oc *OperatorController) CancelOperatorBatch(id uint64) bool {
+	oc.Lock()
+	defer oc.Unlock()
+	ops, ok := oc.batches.getRunningOperators(id)
+	if !ok {
+		return false
+	}
+	for _, op := range ops {
+		if oc.operators[op.RegionID()] != op {
+			continue
+		}
+		operatorCounter.WithLabelValues(op.Desc(), "cancel").Inc()
+		oc.removeOperatorLocked(op)
+		oc.opRecords.Put(op, pdpb.OperatorStatus_CANCEL)
+		oc.recordEndedOperator(op, pdpb.OperatorStatus_CANCEL)
+	}
+	return true
+}
+
 // PromoteWaitingOperator promotes operators from waiting operators.
 func (oc *OperatorController) PromoteWaitingOperator() {
 	oc.Lock()
@@ -275,7 +368,12 @@ func (oc *OperatorController) checkAddOperator(ops ...*operator.Operator) bool {
 	return true
 }
 
+// isHigherPriorityOperator checks the priority class first, the priority level
+// is only compared between the operators of the same class.
 func isHigherPriorityOperator(new, old *operator.Operator) bool {
+	if newClass, oldClass := new.GetPriorityClass(), old.GetPriorityClass(); newClass != oldClass {
+		return newClass > oldClass
+	}
 	return new.GetPriorityLevel() > old.GetPriorityLevel()
 }
 
@@ -284,32 +382,40 @@ func (oc *OperatorController) addOperatorLocked(op *operator.Operator) bool {
 
 	log.Info("add operator", zap.Uint64("region-id", regionID), zap.Reflect("operator", op))
 
+	region := oc.cluster.GetRegion(regionID)
+	if region != nil {
+		op.SetApproximateSize(region.GetApproximateSize())
+	}
+
 	// If there is an old operator, replace it. The priority should be checked
 	// already.
 	if old, ok := oc.operators[regionID]; ok {
 		log.Info("replace old operator", zap.Uint64("region-id", regionID), zap.Reflect("operator", old))
 		operatorCounter.WithLabelValues(old.Desc(), "replaced").Inc()
 		oc.opRecords.Put(old, pdpb.OperatorStatus_REPLACE)
+		oc.recordEndedOperator(old, pdpb.OperatorStatus_REPLACE)
 		oc.removeOperatorLocked(old)
 	}
 
 	oc.operators[regionID] = op
 	op.SetStartTime(time.Now())
+	oc.eventBus.Publish(NewOperatorEvent(EventOperatorCreate, op))
 	operatorCounter.WithLabelValues(op.Desc(), "start").Inc()
 	operatorWaitDuration.WithLabelValues(op.Desc()).Observe(op.ElapsedTime().Seconds())
 	opInfluence := NewTotalOpInfluence([]*operator.Operator{op}, oc.cluster)
 	for storeID := range opInfluence.StoresInfluence {
-		stepCost := opInfluence.GetStoreInfluence(storeID).StepCost
-		if stepCost == 0 {
-			continue
+		for limitType, stepCost := range opInfluence.GetStoreInfluence(storeID).StepCost {
+			if stepCost == 0 {
+				continue
+			}
+			storeLimitGauge.WithLabelValues(strconv.FormatUint(storeID, 10), "take-"+limitType.String()).Set(float64(stepCost) / float64(operator.RegionInfluence))
+			oc.getOrCreateStoreLimit(storeID, limitType).Take(stepCost)
 		}
-		storeLimitGauge.WithLabelValues(strconv.FormatUint(storeID, 10), "take").Set(float64(stepCost) / float64(operator.RegionInfluence))
-		oc.storesLimit[storeID].Take(stepCost)
 	}
 	oc.updateCounts(oc.operators)
 
 	var step operator.OpStep
-	if region := oc.cluster.GetRegion(op.RegionID()); region != nil {
+	if region != nil {
 		if step = op.Check(region); step != nil {
 			oc.SendScheduleCommand(region, step, DispatchFromCreate)
 		}
@@ -327,12 +433,85 @@ func (oc *OperatorController) RemoveOperator(op *operator.Operator) {
 	oc.removeOperatorLocked(op)
 }
 
+// RemoveMergedOperator removes a operator whose region is merged and will not
+// heartbeat anymore. The operator succeeds if the region is merged by its own
+// MergeRegion step, otherwise it is cancelled.
+func (oc *OperatorController) RemoveMergedOperator(op *operator.Operator) {
+	oc.Lock()
+	defer oc.Unlock()
+	status := pdpb.OperatorStatus_CANCEL
+	if step, ok := op.CurrentStep().(operator.MergeRegion); ok && !step.IsPassive {
+		status = pdpb.OperatorStatus_SUCCESS
+	}
+	oc.removeOperatorLocked(op)
+	oc.opRecords.Put(op, status)
+	oc.recordEndedOperator(op, status)
+}
+
 // RemoveTimeoutOperator removes a operator which is timeout from the running operators.
 func (oc *OperatorController) RemoveTimeoutOperator(op *operator.Operator) {
 	oc.Lock()
 	defer oc.Unlock()
 	operatorCounter.WithLabelValues(op.Desc(), "timeout").Inc()
+	if storeID := addPeerTarget(op.CurrentStep()); storeID != 0 {
+		oc.putSlowTarget(op.RegionID(), storeID)
+	}
 	oc.removeOperatorLocked(op)
+	oc.recordEndedOperator(op, pdpb.OperatorStatus_TIMEOUT)
+}
+
+// addPeerTarget returns the store of the step which adds a peer, or 0 if the
+// step does not add a peer.
+func addPeerTarget(step operator.OpStep) uint64 {
+	switch s := step.(type) {
+	case operator.AddPeer:
+		return s.ToStore
+	case operator.AddLightPeer:
+		return s.ToStore
+	case operator.AddLearner:
+		return s.ToStore
+	case operator.AddLightLearner:
+		return s.ToStore
+	}
+	return 0
+}
+
+func (oc *OperatorController) putSlowTarget(regionID, storeID uint64) {
+	log.Info("store fails to add peer in time", zap.Uint64("region-id", regionID), zap.Uint64("store-id", storeID))
+	stores := map[uint64]struct{}{storeID: {}}
+	if v, ok := oc.slowTargets.Get(regionID); ok {
+		for id := range v.(map[uint64]struct{}) {
+			stores[id] = struct{}{}
+		}
+	}
+	oc.slowTargets.Put(regionID, stores)
+}
+
+// GetSlowTargets returns the stores which failed to add a peer of the region
+// in time recently, a new peer should be added to other stores. The returned
+// map must not be modified.
+func (oc *OperatorController) GetSlowTargets(regionID uint64) map[uint64]struct{} {
+	v, ok := oc.slowTargets.Get(regionID)
+	if !ok {
+		return nil
+	}
+	return v.(map[uint64]struct{})
+}
+
+// NewSlowTargetFilter creates a filter which excludes the slow targets of the
+// region as the targets.
+func (oc *OperatorController) NewSlowTargetFilter(regionID uint64) filter.Filter {
+	return filter.NewExcludedFilter(nil, oc.GetSlowTargets(regionID))
+}
+
+// CancelOperator removes a operator which is cancelled from the running operators.
+func (oc *OperatorController) CancelOperator(op *operator.Operator) {
+	oc.Lock()
+	defer oc.Unlock()
+	operatorCounter.WithLabelValues(op.Desc(), "cancel").Inc()
+	oc.removeOperatorLocked(op)
+	oc.opRecords.Put(op, pdpb.OperatorStatus_CANCEL)
+	oc.recordEndedOperator(op, pdpb.OperatorStatus_CANCEL)
 }
 
 // GetOperatorStatus gets the operator and its status with the specify id.
@@ -385,6 +564,13 @@ func (oc *OperatorController) GetWaitingOperators() []*operator.Operator {
 // SendScheduleCommand sends a command to the region.
 func (oc *OperatorController) SendScheduleCommand(region *core.RegionInfo, step operator.OpStep, source string) {
 	log.Info("send schedule command", zap.Uint64("region-id", region.GetID()), zap.Stringer("step", step), zap.String("source", source))
+	oc.eventBus.Publish(&Event{
+		Time:     time.Now(),
+		Kind:     EventOperatorDispatch,
+		RegionID: region.GetID(),
+		StoreIDs: stepStores(step),
+		Step:     step.String(),
+	})
 	switch st := step.(type) {
 	case operator.TransferLeader:
 		cmd := &pdpb.RegionHeartbeatResponse{
@@ -505,6 +691,57 @@ func (oc *OperatorController) pushHistory(op *operator.Operator) {
 	}
 }
 
+// SetOperatorHistory sets the history to persist the ended operators.
+func (oc *OperatorController) SetOperatorHistory(h *OperatorHistory) {
+	oc.Lock()
+	defer oc.Unlock()
+	oc.opHistory = h
+}
+
+// SetEventBus sets the event bus to publish the operator events.
+func (oc *OperatorController) SetEventBus(b *EventBus) {
+	oc.Lock()
+	defer oc.Unlock()
+	oc.eventBus = b
+}
+
+// SetPlacementConfig sets the placement constraints followed by the schedulers.
+func (oc *OperatorController) SetPlacementConfig(cfg *placement.Config) {
+	oc.Lock()
+	defer oc.Unlock()
+	oc.placementConfig = cfg
+}
+
+// GetPlacementConfig returns the placement constraints followed by the
+// schedulers.
+func (oc *OperatorController) GetPlacementConfig() *placement.Config {
+	oc.RLock()
+	defer oc.RUnlock()
+	return oc.placementConfig
+}
+
+// GetOperatorHistory gets the history of the ended operators, it returns nil
+// if the operators are not persisted.
+func (oc *OperatorController) GetOperatorHistory() *OperatorHistory {
+	oc.RLock()
+	defer oc.RUnlock()
+	return oc.opHistory
+}
+
+// recordEndedOperator records the status of the ended operator to its batch,
+// publishes it and persists it. The operators cancelled before they are
+// started are not persisted.
+func (oc *OperatorController) recordEndedOperator(op *operator.Operator, status pdpb.OperatorStatus) {
+	oc.batches.end(op, status)
+	if kind, ok := operatorEndEventKinds[status]; ok {
+		oc.eventBus.Publish(NewOperatorEvent(kind, op))
+	}
+	if oc.opHistory == nil || op.GetStartTime().IsZero() {
+		return
+	}
+	oc.opHistory.Put(op, status)
+}
+
 // PruneHistory prunes a part of operators' history.
 func (oc *OperatorController) PruneHistory() {
 	oc.Lock()
@@ -621,7 +858,7 @@ type OperatorWithStatus struct {
 
 // MarshalJSON returns the status of operator as a JSON string
 func (o *OperatorWithStatus) MarshalJSON() ([]byte, error) {
-	return []byte(`"` + fmt.Sprintf("status: %s, operator: %s", o.Status.String(), o.Op.String()) + `"`), nil
+	return []byte(`"` + fmt.Sprintf("status: %s, operator: %s, progress: %v", o.Status.String(), o.Op.String(), o.Op.Progress()) + `"`), nil
 }
 
 // OperatorRecords remains the operator and its status for a while.
@@ -658,72 +895,96 @@ func (o *OperatorRecords) Put(op *operator.Operator, status pdpb.OperatorStatus)
 }
 
 // exceedStoreLimit returns true if the store exceeds the cost limit after adding the operator. Otherwise, returns false.
+// The repair operators can also take the cost reserved for them.
 func (oc *OperatorController) exceedStoreLimit(ops ...*operator.Operator) bool {
 	opInfluence := NewTotalOpInfluence(ops, oc.cluster)
-	for storeID := range opInfluence.StoresInfluence {
-		stepCost := opInfluence.GetStoreInfluence(storeID).StepCost
-		if stepCost == 0 {
-			continue
+	class := operator.BalanceClass
+	for _, op := range ops {
+		if c := op.GetPriorityClass(); c > class {
+			class = c
 		}
+	}
+	for storeID := range opInfluence.StoresInfluence {
+		for limitType, stepCost := range opInfluence.GetStoreInfluence(storeID).StepCost {
+			if stepCost == 0 {
+				continue
+			}
 
-		available := oc.getOrCreateStoreLimit(storeID).Available()
-		storeLimitGauge.WithLabelValues(strconv.FormatUint(storeID, 10), "available").Set(float64(available) / float64(operator.RegionInfluence))
-		if available < stepCost {
-			return true
+			limit := oc.getOrCreateStoreLimit(storeID, limitType)
+			available := limit.Available()
+			storeLimitGauge.WithLabelValues(strconv.FormatUint(storeID, 10), "available-"+limitType.String()).Set(float64(available) / float64(operator.RegionInfluence))
+			if class == operator.RepairClass {
+				available += limit.Reserved()
+			}
+			if available < stepCost {
+				return true
+			}
 		}
 	}
 	return false
 }
 
-// SetAllStoresLimit is used to set limit of all stores.
-func (oc *OperatorController) SetAllStoresLimit(rate float64) {
+// SetAllStoresLimit is used to set the limit of the type for all stores.
+func (oc *OperatorController) SetAllStoresLimit(rate float64, limitType storelimit.Type) {
 	oc.Lock()
 	defer oc.Unlock()
-	for storeID := range oc.storesLimit {
-		oc.newStoreLimit(storeID, rate)
+	for _, store := range oc.cluster.GetStores() {
+		if !store.IsTombstone() {
+			oc.newStoreLimit(store.GetID(), rate, limitType)
+		}
 	}
 }
 
-// SetStoreLimit is used to set the limit of a store.
-func (oc *OperatorController) SetStoreLimit(storeID uint64, rate float64) {
+// SetStoreLimit is used to set the limit of the type for a store.
+func (oc *OperatorController) SetStoreLimit(storeID uint64, rate float64, limitType storelimit.Type) {
 	oc.Lock()
 	defer oc.Unlock()
-	oc.newStoreLimit(storeID, rate)
-}
-
-// newStoreLimit is used to create the limit of a store.
-func (oc *OperatorController) newStoreLimit(storeID uint64, rate float64) {
-	capacity := operator.RegionInfluence
-	if rate > 1 {
-		capacity = int64(rate * float64(operator.RegionInfluence))
-	}
-	rate *= float64(operator.RegionInfluence)
-	oc.storesLimit[storeID] = ratelimit.NewBucketWithRate(rate, capacity)
+	oc.newStoreLimit(storeID, rate, limitType)
 }
 
-// getOrCreateStoreLimit is used to get or create the limit of a store.
-func (oc *OperatorController) getOrCreateStoreLimit(storeID uint64) *ratelimit.Bucket {
-	if oc.storesLimit[storeID] == nil {
-		rate := oc.cluster.GetStoreBalanceRate() / StoreBalanceBaseTime
-		oc.newStoreLimit(storeID, rate)
+// newStoreLimit is used to create the limit of the type for a store.
+func (oc *OperatorController) newStoreLimit(storeID uint64, rate float64, limitType storelimit.Type) {
+	limits, ok := oc.storesLimit[storeID]
+	if !ok {
+		limits = make(map[storelimit.Type]*storelimit.StoreLimit)
+		oc.storesLimit[storeID] = limits
 		oc.cluster.AttachOverloadStatus(storeID, func() bool {
 			oc.RLock()
 			defer oc.RUnlock()
-			return oc.storesLimit[storeID].Available() < operator.RegionInfluence
+			// The store is overloaded once any type of its limits is used up.
+			for _, limit := range oc.storesLimit[storeID] {
+				if limit.Available() < operator.RegionInfluence {
+					return true
+				}
+			}
+			return false
 		})
 	}
-	return oc.storesLimit[storeID]
+	limits[limitType] = storelimit.NewStoreLimit(rate, operator.RegionInfluence)
 }
 
-// GetAllStoresLimit is used to get limit of all stores.
-func (oc *OperatorController) GetAllStoresLimit() map[uint64]float64 {
+// getOrCreateStoreLimit is used to get or create the limit of the type for a
+// store.
+func (oc *OperatorController) getOrCreateStoreLimit(storeID uint64, limitType storelimit.Type) *storelimit.StoreLimit {
+	if oc.storesLimit[storeID][limitType] == nil {
+		rate := oc.cluster.GetStoreBalanceRate() / StoreBalanceBaseTime
+		oc.newStoreLimit(storeID, rate, limitType)
+	}
+	return oc.storesLimit[storeID][limitType]
+}
+
+// GetAllStoresLimit is used to get the limit of the type of all stores.
+func (oc *OperatorController) GetAllStoresLimit(limitType storelimit.Type) map[uint64]float64 {
 	oc.RLock()
 	defer oc.RUnlock()
 	ret := make(map[uint64]float64)
-	for storeID, limit := range oc.storesLimit {
+	for storeID, limits := range oc.storesLimit {
 		store := oc.cluster.GetStore(storeID)
-		if !store.IsTombstone() {
-			ret[storeID] = limit.Rate() / float64(operator.RegionInfluence)
+		if store == nil || store.IsTombstone() {
+			continue
+		}
+		if limit, ok := limits[limitType]; ok {
+			ret[storeID] = limit.Rate()
 		}
 	}
 	return ret
@@ -24,7 +24,10 @@ import (
 	"github.com/pingcap/pd/pkg/mock/mockcluster"
 	"github.com/pingcap/pd/pkg/mock/mockhbstream"
 	"github.com/pingcap/pd/pkg/mock/mockoption"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/namespace"
 	"github.com/pingcap/pd/server/schedule/operator"
+	"github.com/pingcap/pd/server/schedule/storelimit"
 )
 
 func Test(t *testing.T) {
@@ -98,6 +101,132 @@ func (t *testOperatorControllerSuite) TestOperatorStatus(c *C) {
 	c.Assert(oc.GetOperatorStatus(2).Status, Equals, pdpb.OperatorStatus_SUCCESS)
 }
 
+func (t *testOperatorControllerSuite) TestSlowTargets(c *C) {
+	opt := mockoption.NewScheduleOptions()
+	tc := mockcluster.NewCluster(opt)
+	oc := NewOperatorController(tc, mockhbstream.NewHeartbeatStream())
+	tc.AddLeaderStore(1, 2)
+	tc.AddLeaderStore(2, 0)
+	tc.AddLeaderStore(3, 0)
+	tc.AddLeaderRegion(1, 1, 2)
+
+	// The store is avoided once it fails to add a learner in time.
+	op := operator.NewOperator("test", 1, &metapb.RegionEpoch{}, operator.OpRegion, operator.AddLearner{ToStore: 3, PeerID: 3})
+	c.Assert(oc.AddOperator(op), IsTrue)
+	c.Assert(op.GetApproximateSize(), Equals, tc.GetRegion(1).GetApproximateSize())
+	oc.Dispatch(tc.GetRegion(1), "test")
+	c.Assert(oc.GetOperatorStatus(1).Status, Equals, pdpb.OperatorStatus_RUNNING)
+	op.SetStartTime(time.Now().Add(-time.Hour))
+	oc.Dispatch(tc.GetRegion(1), "test")
+	c.Assert(oc.GetOperatorStatus(1).Status, Equals, pdpb.OperatorStatus_TIMEOUT)
+	c.Assert(oc.GetSlowTargets(1), DeepEquals, map[uint64]struct{}{3: {}})
+
+	// The timeout steps which do not add peers are not recorded.
+	op = operator.NewOperator("test", 1, &metapb.RegionEpoch{}, operator.OpLeader, operator.TransferLeader{FromStore: 1, ToStore: 2})
+	c.Assert(oc.AddOperator(op), IsTrue)
+	op.SetStartTime(time.Now().Add(-time.Hour))
+	oc.Dispatch(tc.GetRegion(1), "test")
+	c.Assert(oc.GetOperatorStatus(1).Status, Equals, pdpb.OperatorStatus_TIMEOUT)
+	c.Assert(oc.GetSlowTargets(1), DeepEquals, map[uint64]struct{}{3: {}})
+	c.Assert(oc.GetSlowTargets(2), IsNil)
+
+	// The slow targets are filtered out when selecting the targets of the region.
+	c.Assert(oc.NewSlowTargetFilter(1).Target(tc, tc.GetStore(3)), IsTrue)
+	c.Assert(oc.NewSlowTargetFilter(2).Target(tc, tc.GetStore(3)), IsFalse)
+	tc.AddLeaderStore(4, 0)
+	scatterer := NewRegionScatterer(tc, namespace.DefaultClassifier)
+	scatterer.SetOperatorController(oc)
+	stores := scatterer.collectAvailableStores(tc.GetRegion(1))
+	c.Assert(stores, HasLen, 1)
+	c.Assert(stores[4], NotNil)
+}
+
+func (t *testOperatorControllerSuite) TestStoreLimit(c *C) {
+	opt := mockoption.NewScheduleOptions()
+	tc := mockcluster.NewCluster(opt)
+	oc := NewOperatorController(tc, mockhbstream.NewHeartbeatStream())
+	tc.AddLeaderStore(1, 0)
+	tc.AddLeaderStore(2, 0)
+	for i := uint64(1); i <= 10; i++ {
+		tc.AddLeaderRegion(i, 1)
+		tc.AddLeaderRegion(i+10, 1, 2)
+	}
+
+	// The regions are small, so 5 peers can be added to store 2 at most.
+	oc.SetStoreLimit(2, 1, storelimit.AddPeer)
+	for i := uint64(1); i <= 5; i++ {
+		op := operator.NewOperator("test", i, &metapb.RegionEpoch{}, operator.OpRegion, operator.AddPeer{ToStore: 2, PeerID: i})
+		c.Assert(oc.AddOperator(op), IsTrue)
+	}
+	op := operator.NewOperator("test", 6, &metapb.RegionEpoch{}, operator.OpRegion, operator.AddPeer{ToStore: 2, PeerID: 6})
+	c.Assert(oc.AddOperator(op), IsFalse)
+
+	// Removing peers from store 2 is limited separately.
+	for i := uint64(11); i <= 15; i++ {
+		op := operator.NewOperator("test", i, &metapb.RegionEpoch{}, operator.OpRegion, operator.RemovePeer{FromStore: 2})
+		c.Assert(oc.AddOperator(op), IsTrue)
+	}
+	oc.SetStoreLimit(2, 1, storelimit.RemovePeer)
+	c.Assert(oc.GetAllStoresLimit(storelimit.AddPeer)[2], Equals, 1.0)
+	c.Assert(oc.GetAllStoresLimit(storelimit.RemovePeer)[2], Equals, 1.0)
+	c.Assert(oc.GetAllStoresLimit(storelimit.RemovePeer), Not(HasKey), uint64(1))
+
+	oc.SetAllStoresLimit(2, storelimit.RemovePeer)
+	c.Assert(oc.GetAllStoresLimit(storelimit.RemovePeer), DeepEquals, map[uint64]float64{1: 2, 2: 2})
+}
+
+func (t *testOperatorControllerSuite) TestStoreLimitReserved(c *C) {
+	opt := mockoption.NewScheduleOptions()
+	tc := mockcluster.NewCluster(opt)
+	oc := NewOperatorController(tc, mockhbstream.NewHeartbeatStream())
+	tc.AddLeaderStore(1, 0)
+	tc.AddLeaderStore(2, 0)
+	for i := uint64(1); i <= 20; i++ {
+		tc.AddLeaderRegion(i, 1)
+	}
+
+	oc.SetStoreLimit(2, 1, storelimit.AddPeer)
+	for i := uint64(1); i <= 5; i++ {
+		op := operator.NewOperator("test", i, &metapb.RegionEpoch{}, operator.OpRegion|operator.OpBalance, operator.AddPeer{ToStore: 2, PeerID: i})
+		c.Assert(oc.AddOperator(op), IsTrue)
+	}
+	// The balancers use up the limit, but the reserved cost is left for the
+	// repair operators.
+	op := operator.NewOperator("test", 6, &metapb.RegionEpoch{}, operator.OpRegion|operator.OpBalance, operator.AddPeer{ToStore: 2, PeerID: 6})
+	c.Assert(oc.AddOperator(op), IsFalse)
+	op = operator.NewOperator("test", 6, &metapb.RegionEpoch{}, operator.OpRegion|operator.OpAdmin, operator.AddPeer{ToStore: 2, PeerID: 6})
+	c.Assert(oc.AddOperator(op), IsFalse)
+	for i := uint64(6); i <= 10; i++ {
+		op := operator.NewOperator("test", i, &metapb.RegionEpoch{}, operator.OpRegion|operator.OpReplica|operator.OpRepair, operator.AddPeer{ToStore: 2, PeerID: i})
+		c.Assert(oc.AddOperator(op), IsTrue)
+	}
+	op = operator.NewOperator("test", 11, &metapb.RegionEpoch{}, operator.OpRegion|operator.OpReplica|operator.OpRepair, operator.AddPeer{ToStore: 2, PeerID: 11})
+	c.Assert(oc.AddOperator(op), IsFalse)
+}
+
+func (t *testOperatorControllerSuite) TestPriorityClassPreemption(c *C) {
+	opt := mockoption.NewScheduleOptions()
+	tc := mockcluster.NewCluster(opt)
+	oc := NewOperatorController(tc, mockhbstream.NewHeartbeatStream())
+	tc.AddLeaderStore(1, 0)
+	tc.AddLeaderStore(2, 0)
+	tc.AddLeaderRegion(1, 1, 2)
+
+	newOp := func(kind operator.OpKind, level core.PriorityLevel) *operator.Operator {
+		op := operator.NewOperator("test", 1, &metapb.RegionEpoch{}, kind, operator.TransferLeader{FromStore: 1, ToStore: 2})
+		op.SetPriorityLevel(level)
+		return op
+	}
+	c.Assert(oc.AddOperator(newOp(operator.OpLeader|operator.OpBalance, core.HighPriority)), IsTrue)
+	// The priority level is only compared in the same class.
+	c.Assert(oc.AddOperator(newOp(operator.OpLeader|operator.OpBalance, core.NormalPriority)), IsFalse)
+	c.Assert(oc.AddOperator(newOp(operator.OpLeader|operator.OpHotRegion, core.LowPriority)), IsTrue)
+	c.Assert(oc.AddOperator(newOp(operator.OpLeader|operator.OpReplica|operator.OpRepair, core.LowPriority)), IsTrue)
+	c.Assert(oc.AddOperator(newOp(operator.OpLeader|operator.OpAdmin, core.HighPriority)), IsFalse)
+	c.Assert(oc.AddOperator(newOp(operator.OpLeader|operator.OpReplica|operator.OpRepair, core.NormalPriority)), IsTrue)
+	c.Assert(oc.GetOperator(1).GetPriorityClass(), Equals, operator.RepairClass)
+}
+
 func (t *testOperatorControllerSuite) TestPollDispatchRegion(c *C) {
 	opt := mockoption.NewScheduleOptions()
 	tc := mockcluster.NewCluster(opt)
@@ -143,3 +272,83 @@ func (t *testOperatorControllerSuite) TestPollDispatchRegion(c *C) {
 	c.Assert(next, IsTrue)
 	c.Assert(r.GetID(), Equals, region2.GetID())
 }
+
+func (t *testOperatorControllerSuite) TestOperatorBatch(c *C) {
+	opt := mockoption.NewScheduleOptions()
+	tc := mockcluster.NewCluster(opt)
+	oc := NewOperatorController(tc, mockhbstream.NewHeartbeatStream())
+	tc.AddLeaderStore(1, 0)
+	tc.AddLeaderStore(2, 0)
+	for i := uint64(1); i <= 10; i++ {
+		tc.AddLeaderRegion(i, 1)
+	}
+	newOp := func(regionID uint64) *operator.Operator {
+		return operator.NewOperator("test", regionID, &metapb.RegionEpoch{}, operator.OpRegion|operator.OpAdmin, operator.AddPeer{ToStore: 2, PeerID: regionID})
+	}
+	oc.SetStoreLimit(2, 1, storelimit.AddPeer)
+
+	// The regions must be different.
+	c.Assert(oc.CheckOperatorBatch(newOp(1), newOp(1)), IsFalse)
+	_, ok := oc.AddOperatorBatch(newOp(1), newOp(1))
+	c.Assert(ok, IsFalse)
+	// The store limit allows 5 small regions, so none of the 6 is added.
+	var ops []*operator.Operator
+	for i := uint64(1); i <= 6; i++ {
+		ops = append(ops, newOp(i))
+	}
+	c.Assert(oc.CheckOperatorBatch(ops...), IsFalse)
+	_, ok = oc.AddOperatorBatch(ops...)
+	c.Assert(ok, IsFalse)
+	c.Assert(oc.GetOperators(), HasLen, 0)
+
+	c.Assert(oc.CheckOperatorBatch(ops[:3]...), IsTrue)
+	c.Assert(oc.GetOperators(), HasLen, 0)
+	id, ok := oc.AddOperatorBatch(ops[:3]...)
+	c.Assert(ok, IsTrue)
+	c.Assert(oc.GetOperators(), HasLen, 3)
+	status := oc.GetOperatorBatchStatus(id)
+	c.Assert(status.Total, Equals, 3)
+	c.Assert(status.Running, Equals, 3)
+	c.Assert(status.Finished, IsFalse)
+
+	// A region of the batch already has an operator.
+	id2, ok := oc.AddOperatorBatch(newOp(3), newOp(4))
+	c.Assert(ok, IsFalse)
+	c.Assert(id2, Equals, uint64(0))
+
+	ApplyOperator(tc, ops[0])
+	oc.Dispatch(tc.GetRegion(1), "test")
+	status = oc.GetOperatorBatchStatus(id)
+	c.Assert(status.Success, Equals, 1)
+	c.Assert(status.Running, Equals, 2)
+	c.Assert(status.Operators[0].Status, Equals, "SUCCESS")
+
+	c.Assert(oc.CancelOperatorBatch(id), IsTrue)
+	c.Assert(oc.GetOperators(), HasLen, 0)
+	status = oc.GetOperatorBatchStatus(id)
+	c.Assert(status.Success, Equals, 1)
+	c.Assert(status.Failed, Equals, 2)
+	c.Assert(status.Finished, IsTrue)
+	c.Assert(status.Operators[2].Status, Equals, "CANCEL")
+
+	c.Assert(oc.GetOperatorBatchStatus(100), IsNil)
+	c.Assert(oc.CancelOperatorBatch(100), IsFalse)
+
+	// The operators of the merged regions are ended too, the merge source
+	// succeeds and the others are cancelled.
+	merge := operator.NewOperator("test", 5, &metapb.RegionEpoch{}, operator.OpMerge, operator.MergeRegion{
+		FromRegion: tc.GetRegion(5).GetMeta(),
+		ToRegion:   tc.GetRegion(6).GetMeta(),
+	})
+	id, ok = oc.AddOperatorBatch(merge, newOp(7))
+	c.Assert(ok, IsTrue)
+	oc.RemoveMergedOperator(merge)
+	oc.RemoveMergedOperator(oc.GetOperator(7))
+	c.Assert(oc.GetOperators(), HasLen, 0)
+	status = oc.GetOperatorBatchStatus(id)
+	c.Assert(status.Success, Equals, 1)
+	c.Assert(status.Failed, Equals, 1)
+	c.Assert(status.Finished, IsTrue)
+	c.Assert(oc.GetOperatorStatus(5).Status, Equals, pdpb.OperatorStatus_SUCCESS)
+	c.Assert(oc.GetOperatorStatus(7).Status, Equals, pdpb.OperatorStatus_CANCEL)
+}
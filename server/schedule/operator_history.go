@@ -0,0 +1,319 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/log"
+	"github.com/pingcap/pd/pkg/typeutil"
+	"github.com/pingcap/pd/server/kv"
+	"github.com/pingcap/pd/server/schedule/operator"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	operatorHistoryPath     = "operator_history"
+	operatorHistoryIndexKey = "operator_history_index"
+	// DefaultOperatorHistoryLimit is the default max number of operators kept
+	// in the operator history.
+	DefaultOperatorHistoryLimit = 10000
+	operatorHistoryQueryBatch   = 128
+	operatorHistoryMaxPending   = 1024
+)
+
+// OperatorHistoryRecord is an operator in the operator history.
+type OperatorHistoryRecord struct {
+	ID       uint64   `json:"id"`
+	RegionID uint64   `json:"region_id"`
+	Desc     string   `json:"desc"`
+	Kind     string   `json:"kind"`
+	Steps    []string `json:"steps"`
+	// Progress records the start and finish time of the steps.
+	Progress []operator.StepProgress `json:"progress"`
+	// Stores are the stores touched by the steps.
+	Stores []uint64 `json:"stores"`
+	// Status is the reason why the operator ends, which is one of SUCCESS,
+	// TIMEOUT, CANCEL and REPLACE.
+	Status     string    `json:"status"`
+	CreateTime time.Time `json:"create_time"`
+	StartTime  time.Time `json:"start_time"`
+	FinishTime time.Time `json:"finish_time"`
+	// WaitDuration is the time the operator waits before it is started,
+	// RunningDuration is the time it runs after it is started.
+	WaitDuration    typeutil.Duration `json:"wait_duration"`
+	RunningDuration typeutil.Duration `json:"running_duration"`
+}
+
+func newOperatorHistoryRecord(op *operator.Operator, status pdpb.OperatorStatus, now time.Time) *OperatorHistoryRecord {
+	record := &OperatorHistoryRecord{
+		RegionID:     op.RegionID(),
+		Desc:         op.Desc(),
+		Kind:         op.Kind().String(),
+		Status:       status.String(),
+		CreateTime:   op.GetCreateTime(),
+		StartTime:    op.GetStartTime(),
+		FinishTime:   now,
+		WaitDuration: typeutil.NewDuration(op.GetStartTime().Sub(op.GetCreateTime())),
+		Progress:     op.Progress(),
+	}
+	record.RunningDuration = typeutil.NewDuration(now.Sub(op.GetStartTime()))
+	stores := make(map[uint64]struct{})
+	for i := 0; i < op.Len(); i++ {
+		step := op.Step(i)
+		record.Steps = append(record.Steps, step.String())
+		for _, storeID := range stepStores(step) {
+			if _, ok := stores[storeID]; !ok {
+				stores[storeID] = struct{}{}
+				record.Stores = append(record.Stores, storeID)
+			}
+		}
+	}
+	return record
+}
+
+func stepStores(step operator.OpStep) []uint64 {
+	switch s := step.(type) {
+	case operator.TransferLeader:
+		return []uint64{s.FromStore, s.ToStore}
+	case operator.AddPeer:
+		return []uint64{s.ToStore}
+	case operator.AddLightPeer:
+		return []uint64{s.ToStore}
+	case operator.AddLearner:
+		return []uint64{s.ToStore}
+	case operator.AddLightLearner:
+		return []uint64{s.ToStore}
+	case operator.PromoteLearner:
+		return []uint64{s.ToStore}
+	case operator.RemovePeer:
+		return []uint64{s.FromStore}
+	}
+	return nil
+}
+
+func (r *OperatorHistoryRecord) hasStore(storeID uint64) bool {
+	for _, id := range r.Stores {
+		if id == storeID {
+			return true
+		}
+	}
+	return false
+}
+
+// OperatorHistoryFilter filters the records in the operator history, the
+// zero value of a field matches all the records.
+type OperatorHistoryFilter struct {
+	RegionID uint64
+	StoreID  uint64
+	// Kind matches the records which have any of the kinds.
+	Kind   operator.OpKind
+	Status string
+	// Start and End are the time window of the finish time.
+	Start time.Time
+	End   time.Time
+	// Limit is the max number of the latest records to return.
+	Limit int
+}
+
+func (f *OperatorHistoryFilter) match(r *OperatorHistoryRecord) bool {
+	if f.RegionID != 0 && r.RegionID != f.RegionID {
+		return false
+	}
+	if f.StoreID != 0 && !r.hasStore(f.StoreID) {
+		return false
+	}
+	if f.Kind != 0 {
+		kind, err := operator.ParseOperatorKind(r.Kind)
+		if err != nil || kind&f.Kind == 0 {
+			return false
+		}
+	}
+	if f.Status != "" && r.Status != f.Status {
+		return false
+	}
+	if !f.Start.IsZero() && r.FinishTime.Before(f.Start) {
+		return false
+	}
+	if !f.End.IsZero() && r.FinishTime.After(f.End) {
+		return false
+	}
+	return true
+}
+
+// OperatorHistory persists the ended operators, only the latest limit
+// operators are kept. The operators are put into a pending queue and written
+// to the storage in the background, so the callers holding the operator
+// controller lock do not wait for the storage.
+type OperatorHistory struct {
+	sync.Mutex
+	kv     kv.ReverseBase
+	limit  uint64
+	nextID uint64
+	// pending is the records waiting to be written in the order of the IDs,
+	// a record is removed from it after it is written.
+	pending  []*OperatorHistoryRecord
+	flushing bool
+}
+
+// NewOperatorHistory creates an OperatorHistory which saves the operators to
+// kv.
+func NewOperatorHistory(kv kv.ReverseBase, limit int) *OperatorHistory {
+	if limit < 1 {
+		limit = 1
+	}
+	h := &OperatorHistory{
+		kv:    kv,
+		limit: uint64(limit),
+	}
+	h.reload()
+	return h
+}
+
+func operatorHistoryKey(id uint64) string {
+	return path.Join(operatorHistoryPath, fmt.Sprintf("%020d", id))
+}
+
+func (h *OperatorHistory) reload() {
+	v, err := h.kv.Load(operatorHistoryIndexKey)
+	if err != nil {
+		log.Warn("load operator history index failed", zap.String("error", err.Error()))
+	}
+	if v != "" {
+		h.nextID, err = strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			log.Error("load operator history index failed", zap.Error(err))
+		}
+	}
+}
+
+// Put saves the operator which ends with the status to the history. The
+// operator is dropped if too many operators are waiting to be written.
+func (h *OperatorHistory) Put(op *operator.Operator, status pdpb.OperatorStatus) {
+	record := newOperatorHistoryRecord(op, status, time.Now())
+	h.Lock()
+	defer h.Unlock()
+	if len(h.pending) >= operatorHistoryMaxPending {
+		log.Warn("drop operator history as too many are pending", zap.Uint64("region-id", op.RegionID()))
+		return
+	}
+	record.ID = h.nextID
+	h.nextID++
+	h.pending = append(h.pending, record)
+	if !h.flushing {
+		h.flushing = true
+		go h.flush()
+	}
+}
+
+// flush writes the pending records one by one until none is pending.
+func (h *OperatorHistory) flush() {
+	for {
+		h.Lock()
+		if len(h.pending) == 0 {
+			h.flushing = false
+			h.Unlock()
+			return
+		}
+		record := h.pending[0]
+		h.Unlock()
+
+		if err := h.save(record); err != nil {
+			log.Warn("save operator history failed", zap.Uint64("region-id", record.RegionID), zap.Error(err))
+		}
+		if record.ID >= h.limit {
+			if err := h.kv.Remove(operatorHistoryKey(record.ID - h.limit)); err != nil {
+				log.Warn("remove operator history failed", zap.Error(err))
+			}
+		}
+
+		h.Lock()
+		h.pending = h.pending[1:]
+		h.Unlock()
+	}
+}
+
+func (h *OperatorHistory) save(record *OperatorHistoryRecord) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err = h.kv.Save(operatorHistoryKey(record.ID), string(value)); err != nil {
+		return err
+	}
+	return h.kv.Save(operatorHistoryIndexKey, strconv.FormatUint(record.ID+1, 10))
+}
+
+// Query returns the records matching the filter, the latest first. The
+// records are loaded from the latest one and the loading stops once the limit
+// is reached. The lock is only held to take the pending records, so a query
+// does not block Put.
+func (h *OperatorHistory) Query(filter *OperatorHistoryFilter) ([]*OperatorHistoryRecord, error) {
+	h.Lock()
+	pending := append([]*OperatorHistoryRecord(nil), h.pending...)
+	nextID := h.nextID
+	h.Unlock()
+
+	var firstID uint64
+	if nextID > h.limit {
+		firstID = nextID - h.limit
+	}
+	var records []*OperatorHistoryRecord
+	for i := len(pending) - 1; i >= 0; i-- {
+		if pending[i].ID < firstID || !filter.match(pending[i]) {
+			continue
+		}
+		records = append(records, pending[i])
+		if filter.Limit > 0 && len(records) >= filter.Limit {
+			return records, nil
+		}
+	}
+
+	// The pending records may be written already, skip them in the storage.
+	lastID := nextID
+	if len(pending) > 0 {
+		lastID = pending[0].ID
+	}
+	startKey, endKey := operatorHistoryKey(firstID), operatorHistoryKey(lastID)
+	for {
+		keys, values, err := h.kv.LoadRangeReverse(startKey, endKey, operatorHistoryQueryBatch)
+		if err != nil {
+			return nil, err
+		}
+		for _, value := range values {
+			record := &OperatorHistoryRecord{}
+			if err := json.Unmarshal([]byte(value), record); err != nil {
+				return nil, errors.WithStack(err)
+			}
+			if !filter.match(record) {
+				continue
+			}
+			records = append(records, record)
+			if filter.Limit > 0 && len(records) >= filter.Limit {
+				return records, nil
+			}
+		}
+		if len(keys) < operatorHistoryQueryBatch {
+			return records, nil
+		}
+		endKey = keys[len(keys)-1]
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/pd/pkg/mock/mockcluster"
+	"github.com/pingcap/pd/pkg/mock/mockhbstream"
+	"github.com/pingcap/pd/pkg/mock/mockoption"
+	"github.com/pingcap/pd/pkg/testutil"
+	"github.com/pingcap/pd/server/kv"
+	"github.com/pingcap/pd/server/schedule/operator"
+)
+
+var _ = Suite(&testOperatorHistorySuite{})
+
+type testOperatorHistorySuite struct{}
+
+func (s *testOperatorHistorySuite) TestPutAndQuery(c *C) {
+	base := kv.NewMemoryKV()
+	h := NewOperatorHistory(base, 3)
+	start := time.Now()
+	ops := []*operator.Operator{
+		operator.NewOperator("test", 1, &metapb.RegionEpoch{}, operator.OpLeader, operator.TransferLeader{FromStore: 1, ToStore: 2}),
+		operator.NewOperator("test", 2, &metapb.RegionEpoch{}, operator.OpRegion, operator.AddPeer{ToStore: 3, PeerID: 3}, operator.RemovePeer{FromStore: 1}),
+		operator.NewOperator("test", 3, &metapb.RegionEpoch{}, operator.OpLeader, operator.TransferLeader{FromStore: 2, ToStore: 3}),
+		operator.NewOperator("test", 4, &metapb.RegionEpoch{}, operator.OpRegion|operator.OpReplica, operator.AddPeer{ToStore: 4, PeerID: 4}),
+	}
+	statuses := []pdpb.OperatorStatus{
+		pdpb.OperatorStatus_SUCCESS,
+		pdpb.OperatorStatus_TIMEOUT,
+		pdpb.OperatorStatus_SUCCESS,
+		pdpb.OperatorStatus_CANCEL,
+	}
+	for i, op := range ops {
+		op.SetStartTime(time.Now())
+		h.Put(op, statuses[i])
+	}
+
+	// Only the latest 3 operators are kept.
+	records, err := h.Query(&OperatorHistoryFilter{})
+	c.Assert(err, IsNil)
+	c.Assert(records, HasLen, 3)
+	c.Assert(records[0].RegionID, Equals, uint64(4))
+	c.Assert(records[0].Status, Equals, "CANCEL")
+	c.Assert(records[0].Kind, Equals, "region,replica")
+	c.Assert(records[2].RegionID, Equals, uint64(2))
+	c.Assert(records[2].Steps, DeepEquals, []string{"add peer 3 on store 3", "remove peer on store 1"})
+	c.Assert(records[2].Stores, DeepEquals, []uint64{3, 1})
+
+	records, err = h.Query(&OperatorHistoryFilter{StoreID: 3})
+	c.Assert(err, IsNil)
+	c.Assert(records, HasLen, 2)
+	records, err = h.Query(&OperatorHistoryFilter{Kind: operator.OpLeader})
+	c.Assert(err, IsNil)
+	c.Assert(records, HasLen, 1)
+	c.Assert(records[0].RegionID, Equals, uint64(3))
+	records, err = h.Query(&OperatorHistoryFilter{Status: "TIMEOUT"})
+	c.Assert(err, IsNil)
+	c.Assert(records, HasLen, 1)
+	c.Assert(records[0].RegionID, Equals, uint64(2))
+	records, err = h.Query(&OperatorHistoryFilter{RegionID: 4, Start: start, End: time.Now()})
+	c.Assert(err, IsNil)
+	c.Assert(records, HasLen, 1)
+	records, err = h.Query(&OperatorHistoryFilter{End: start.Add(-time.Second)})
+	c.Assert(err, IsNil)
+	c.Assert(records, HasLen, 0)
+	records, err = h.Query(&OperatorHistoryFilter{Limit: 1})
+	c.Assert(err, IsNil)
+	c.Assert(records, HasLen, 1)
+	c.Assert(records[0].RegionID, Equals, uint64(4))
+
+	// The history is reloaded from the storage.
+	waitOperatorHistoryFlushed(c, h)
+	h = NewOperatorHistory(base, 3)
+	h.Put(ops[0], pdpb.OperatorStatus_REPLACE)
+	records, err = h.Query(&OperatorHistoryFilter{})
+	c.Assert(err, IsNil)
+	c.Assert(records, HasLen, 3)
+	c.Assert(records[0].ID, Equals, uint64(4))
+	c.Assert(records[0].Status, Equals, "REPLACE")
+	c.Assert(records[2].RegionID, Equals, uint64(3))
+}
+
+func (s *testOperatorHistorySuite) TestRecordEndedOperators(c *C) {
+	opt := mockoption.NewScheduleOptions()
+	tc := mockcluster.NewCluster(opt)
+	oc := NewOperatorController(tc, mockhbstream.NewHeartbeatStream())
+	oc.SetOperatorHistory(NewOperatorHistory(kv.NewMemoryKV(), DefaultOperatorHistoryLimit))
+	tc.AddLeaderStore(1, 2)
+	tc.AddLeaderStore(2, 0)
+	tc.AddLeaderRegion(1, 1, 2)
+	tc.AddLeaderRegion(2, 1, 2)
+	tc.AddLeaderRegion(3, 1, 2)
+	steps := []operator.OpStep{
+		operator.RemovePeer{FromStore: 2},
+		operator.AddPeer{ToStore: 2, PeerID: 4},
+	}
+	op1 := operator.NewOperator("test", 1, &metapb.RegionEpoch{}, operator.OpRegion, steps...)
+	op2 := operator.NewOperator("test", 2, &metapb.RegionEpoch{}, operator.OpRegion, steps...)
+	op3 := operator.NewOperator("test", 3, &metapb.RegionEpoch{}, operator.OpRegion, steps...)
+	op1.SetStartTime(time.Now().Add(-10 * time.Minute))
+	oc.SetOperator(op1)
+	op2.SetStartTime(time.Now())
+	oc.SetOperator(op2)
+	op3.SetStartTime(time.Now())
+	oc.SetOperator(op3)
+
+	oc.Dispatch(tc.GetRegion(1), "test")
+	ApplyOperator(tc, op2)
+	oc.Dispatch(tc.GetRegion(2), "test")
+	oc.CancelOperator(op3)
+	// The operator is not recorded if it is cancelled before started.
+	oc.AddOperator(operator.NewOperator("test", 100, &metapb.RegionEpoch{}, operator.OpRegion, steps...))
+
+	records, err := oc.GetOperatorHistory().Query(&OperatorHistoryFilter{})
+	c.Assert(err, IsNil)
+	c.Assert(records, HasLen, 3)
+	c.Assert(records[0].RegionID, Equals, uint64(3))
+	c.Assert(records[0].Status, Equals, "CANCEL")
+	c.Assert(records[1].RegionID, Equals, uint64(2))
+	c.Assert(records[1].Status, Equals, "SUCCESS")
+	c.Assert(records[2].RegionID, Equals, uint64(1))
+	c.Assert(records[2].Status, Equals, "TIMEOUT")
+	c.Assert(records[2].RunningDuration.Duration >= 10*time.Minute, IsTrue)
+	c.Assert(oc.GetOperatorStatus(3).Status, Equals, pdpb.OperatorStatus_CANCEL)
+}
+
+func (s *testOperatorHistorySuite) TestQueryWithoutBlocking(c *C) {
+	base := &blockingKV{ReverseBase: kv.NewMemoryKV(), unblock: make(chan struct{})}
+	h := NewOperatorHistory(base, DefaultOperatorHistoryLimit)
+	newOp := func(regionID uint64) *operator.Operator {
+		op := operator.NewOperator("test", regionID, &metapb.RegionEpoch{}, operator.OpLeader, operator.TransferLeader{FromStore: 1, ToStore: 2})
+		op.SetStartTime(time.Now())
+		return op
+	}
+
+	// Put and Query do not wait for the storage.
+	base.block()
+	for i := uint64(1); i <= 3; i++ {
+		h.Put(newOp(i), pdpb.OperatorStatus_SUCCESS)
+	}
+	records, err := h.Query(&OperatorHistoryFilter{})
+	c.Assert(err, IsNil)
+	c.Assert(records, HasLen, 3)
+	c.Assert(records[0].RegionID, Equals, uint64(3))
+	base.release()
+	waitOperatorHistoryFlushed(c, h)
+
+	// The records are loaded from the latest one until the limit is reached.
+	for i := uint64(4); i <= 3*operatorHistoryQueryBatch; i++ {
+		h.Put(newOp(i), pdpb.OperatorStatus_SUCCESS)
+	}
+	waitOperatorHistoryFlushed(c, h)
+	base.loads = 0
+	records, err = h.Query(&OperatorHistoryFilter{Limit: operatorHistoryQueryBatch + 1})
+	c.Assert(err, IsNil)
+	c.Assert(records, HasLen, operatorHistoryQueryBatch+1)
+	c.Assert(records[0].RegionID, Equals, uint64(3*operatorHistoryQueryBatch))
+	c.Assert(records[operatorHistoryQueryBatch].RegionID, Equals, uint64(2*operatorHistoryQueryBatch))
+	c.Assert(base.loads, Equals, 2)
+}
+
+func waitOperatorHistoryFlushed(c *C, h *OperatorHistory) {
+	testutil.WaitUntil(c, func(c *C) bool {
+		h.Lock()
+		defer h.Unlock()
+		return !h.flushing
+	})
+}
+
+// blockingKV blocks the saves until it is released, and counts the reverse
+// loads.
+type blockingKV struct {
+	kv.ReverseBase
+	unblock chan struct{}
+	loads   int
+}
+
+func (kv *blockingKV) block() {
+	kv.unblock = make(chan struct{})
+}
+
+func (kv *blockingKV) release() {
+	close(kv.unblock)
+}
+
+func (kv *blockingKV) Save(key, value string) error {
+	<-kv.unblock
+	return kv.ReverseBase.Save(key, value)
+}
+
+func (kv *blockingKV) LoadRangeReverse(key, endKey string, limit int) ([]string, []string, error) {
+	kv.loads++
+	return kv.ReverseBase.LoadRangeReverse(key, endKey, limit)
+}
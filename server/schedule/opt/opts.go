@@ -26,6 +26,7 @@ type Options interface {
 	GetReplicaScheduleLimit() uint64
 	GetMergeScheduleLimit() uint64
 	GetHotRegionScheduleLimit() uint64
+	GetSplitScheduleLimit() uint64
 
 	// store limit
 	GetStoreBalanceRate() float64
@@ -43,6 +44,10 @@ type Options interface {
 	GetStrictlyMatchLabel() bool
 
 	GetHotRegionCacheHitsThreshold() int
+	GetHotRegionBalanceDimension() string
+	GetHotRegionSplitDuration() time.Duration
+	GetHotRegionSplitBytesThreshold() uint64
+	GetHotRegionSplitKeysThreshold() uint64
 	GetTolerantSizeRatio() float64
 	GetLowSpaceRatio() float64
 	GetHighSpaceRatio() float64
@@ -65,3 +70,10 @@ const (
 	// have any region leaders.
 	RejectLeader = "reject-leader"
 )
+
+// The dimensions of the flow which the hot region scheduler balances
+// primarily.
+const (
+	HotRegionBalanceByBytes = "bytes"
+	HotRegionBalanceByKeys  = "keys"
+)
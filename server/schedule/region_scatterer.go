@@ -20,6 +20,7 @@ import (
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/pd/server/core"
 	"github.com/pingcap/pd/server/namespace"
+	"github.com/pingcap/pd/server/placement/rule"
 	"github.com/pingcap/pd/server/schedule/filter"
 	"github.com/pingcap/pd/server/schedule/operator"
 	"github.com/pkg/errors"
@@ -46,6 +47,16 @@ func (s *selectedStores) put(id uint64) bool {
 	return true
 }
 
+func (s *selectedStores) clone() *selectedStores {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cloned := newSelectedStores()
+	for id := range s.stores {
+		cloned.stores[id] = struct{}{}
+	}
+	return cloned
+}
+
 func (s *selectedStores) reset() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -68,6 +79,9 @@ type RegionScatterer struct {
 	classifier namespace.Classifier
 	filters    []filter.Filter
 	selected   *selectedStores
+	// opController provides the stores which failed to add a peer in time,
+	// nil means no store is avoided.
+	opController *OperatorController
 }
 
 // NewRegionScatterer creates a region scatterer.
@@ -83,13 +97,37 @@ func NewRegionScatterer(cluster Cluster, classifier namespace.Classifier) *Regio
 	}
 }
 
+// SetOperatorController sets the operator controller, the stores which failed
+// to add a peer of a region in time are not selected for the region.
+func (r *RegionScatterer) SetOperatorController(opController *OperatorController) {
+	r.opController = opController
+}
+
+// DryRun returns a copy of the scatterer running on the dry run cluster. The
+// stores selected by the copy are not recorded by the scatterer.
+func (r *RegionScatterer) DryRun(cluster *DryRunCluster) *RegionScatterer {
+	return &RegionScatterer{
+		cluster:      cluster,
+		classifier:   r.classifier,
+		filters:      r.filters,
+		selected:     r.selected.clone(),
+		opController: r.opController,
+	}
+}
+
 // Scatter relocates the region.
 func (r *RegionScatterer) Scatter(region *core.RegionInfo) (*operator.Operator, error) {
 	if r.cluster.IsRegionHot(region) {
 		return nil, errors.Errorf("region %d is a hot region", region.GetID())
 	}
+	return r.ScatterSplitRegion(region)
+}
 
-	if len(region.GetPeers()) != r.cluster.GetMaxReplicas() {
+// ScatterSplitRegion relocates a region split by load. Unlike Scatter, it does
+// not refuse hot regions, as the halves of a hot region are likely to be hot.
+func (r *RegionScatterer) ScatterSplitRegion(region *core.RegionInfo) (*operator.Operator, error) {
+	rule := r.cluster.GetRegionRule(region)
+	if len(region.GetVoters()) != rule.Voters || len(region.GetLearners()) != rule.Learners {
 		return nil, errors.Errorf("the number replicas of region %d is not expected", region.GetID())
 	}
 
@@ -97,16 +135,18 @@ func (r *RegionScatterer) Scatter(region *core.RegionInfo) (*operator.Operator,
 		return nil, errors.Errorf("region %d has no leader", region.GetID())
 	}
 
-	return r.scatterRegion(region), nil
+	return r.scatterRegion(region, rule), nil
 }
 
-func (r *RegionScatterer) scatterRegion(region *core.RegionInfo) *operator.Operator {
+// scatterRegion relocates the voters of the region, the learners are left to
+// the checkers as they do not serve the reads and writes.
+func (r *RegionScatterer) scatterRegion(region *core.RegionInfo, rule *rule.Rule) *operator.Operator {
 	stores := r.collectAvailableStores(region)
 	var (
 		targetPeers   []*metapb.Peer
 		replacedPeers []*metapb.Peer
 	)
-	for _, peer := range region.GetPeers() {
+	for _, peer := range region.GetVoters() {
 		if len(stores) == 0 {
 			// Reset selected stores if we have no available stores.
 			r.selected.reset()
@@ -119,7 +159,7 @@ func (r *RegionScatterer) scatterRegion(region *core.RegionInfo) *operator.Opera
 			replacedPeers = append(replacedPeers, peer)
 			continue
 		}
-		newPeer := r.selectPeerToReplace(stores, region, peer)
+		newPeer := r.selectPeerToReplace(stores, region, rule, peer)
 		if newPeer == nil {
 			targetPeers = append(targetPeers, peer)
 			replacedPeers = append(replacedPeers, peer)
@@ -138,15 +178,23 @@ func (r *RegionScatterer) scatterRegion(region *core.RegionInfo) *operator.Opera
 	return op
 }
 
-func (r *RegionScatterer) selectPeerToReplace(stores map[uint64]*core.StoreInfo, region *core.RegionInfo, oldPeer *metapb.Peer) *metapb.Peer {
+func (r *RegionScatterer) selectPeerToReplace(stores map[uint64]*core.StoreInfo, region *core.RegionInfo, rule *rule.Rule, oldPeer *metapb.Peer) *metapb.Peer {
 	// scoreGuard guarantees that the distinct score will not decrease.
 	regionStores := r.cluster.GetRegionStores(region)
 	sourceStore := r.cluster.GetStore(oldPeer.GetStoreId())
-	scoreGuard := filter.NewDistinctScoreFilter(r.cluster.GetLocationLabels(), regionStores, sourceStore)
+	scoreGuard := filter.NewDistinctScoreFilter(rule.LocationLabels, regionStores, sourceStore)
+	// ruleGuard guarantees that the new peer still satisfies the placement rule.
+	otherStores := make([]*core.StoreInfo, 0, len(regionStores))
+	for _, store := range regionStores {
+		if store.GetID() != sourceStore.GetID() {
+			otherStores = append(otherStores, store)
+		}
+	}
+	ruleGuard := filter.NewRuleFilter(rule, otherStores, r.cluster.GetStores())
 
 	candidates := make([]*core.StoreInfo, 0, len(stores))
 	for _, store := range stores {
-		if scoreGuard.Target(r.cluster, store) {
+		if scoreGuard.Target(r.cluster, store) || ruleGuard.Target(r.cluster, store) {
 			continue
 		}
 		candidates = append(candidates, store)
@@ -172,6 +220,9 @@ func (r *RegionScatterer) collectAvailableStores(region *core.RegionInfo) map[ui
 		filter.NewNamespaceFilter(r.classifier, namespace),
 	}
 	filters = append(filters, r.filters...)
+	if r.opController != nil {
+		filters = append(filters, r.opController.NewSlowTargetFilter(region.GetID()))
+	}
 
 	stores := r.cluster.GetStores()
 	targets := make(map[uint64]*core.StoreInfo, len(stores))
@@ -18,8 +18,10 @@ import (
 
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/log"
+	"github.com/pingcap/pd/pkg/typeutil"
 	"github.com/pingcap/pd/server/core"
 	"github.com/pingcap/pd/server/namespace"
+	"github.com/pingcap/pd/server/placement/rule"
 	"github.com/pingcap/pd/server/schedule/operator"
 	"github.com/pingcap/pd/server/schedule/opt"
 	"github.com/pingcap/pd/server/statistics"
@@ -40,10 +42,35 @@ type Scheduler interface {
 	IsScheduleAllowed(cluster Cluster) bool
 }
 
+// ProgressReporter is implemented by the schedulers which have a definite
+// goal, for example draining the regions of a store, to report how far they
+// are from the goal.
+type ProgressReporter interface {
+	GetProgress(cluster Cluster) *Progress
+}
+
+// DecisionTraced is implemented by the schedulers which trace their
+// decisions, to tell why they do not create operators.
+type DecisionTraced interface {
+	GetDecisionTracer() *DecisionTracer
+}
+
+// Progress describes the remaining work of a scheduler.
+type Progress struct {
+	RemainingRegions int               `json:"remaining_regions"`
+	RemainingBytes   uint64            `json:"remaining_bytes"`
+	ETA              typeutil.Duration `json:"eta"`
+}
+
 // CreateSchedulerFunc is for creating scheduler.
 type CreateSchedulerFunc func(opController *OperatorController, args []string) (Scheduler, error)
 
-var schedulerMap = make(map[string]CreateSchedulerFunc)
+var (
+	schedulerMap = make(map[string]CreateSchedulerFunc)
+	// pdManagedSchedulers are the scheduler types which are added and removed
+	// by PD itself.
+	pdManagedSchedulers = make(map[string]struct{})
+)
 
 // RegisterScheduler binds a scheduler creator. It should be called in init()
 // func of a package.
@@ -54,6 +81,21 @@ func RegisterScheduler(name string, createFn CreateSchedulerFunc) {
 	schedulerMap[name] = createFn
 }
 
+// RegisterPDManagedScheduler binds a creator of the scheduler which is added
+// and removed by PD itself, the users can not add it. It should be called in
+// init() func of a package.
+func RegisterPDManagedScheduler(name string, createFn CreateSchedulerFunc) {
+	RegisterScheduler(name, createFn)
+	pdManagedSchedulers[name] = struct{}{}
+}
+
+// IsSchedulerPDManaged checks if the named scheduler type is added and removed
+// by PD itself.
+func IsSchedulerPDManaged(name string) bool {
+	_, ok := pdManagedSchedulers[name]
+	return ok
+}
+
 // IsSchedulerRegistered check where the named scheduler type is registered.
 func IsSchedulerRegistered(name string) bool {
 	_, ok := schedulerMap[name]
@@ -80,6 +122,8 @@ type Cluster interface {
 
 	// get config methods
 	GetOpt() namespace.ScheduleOptions
+	// GetRegionRule returns the placement rule which the region should follow.
+	GetRegionRule(region *core.RegionInfo) *rule.Rule
 	// TODO: it should be removed. Schedulers don't need to know anything
 	// about peers.
 	AllocPeer(storeID uint64) (*metapb.Peer, error)
@@ -0,0 +1,101 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storelimit
+
+import (
+	"github.com/juju/ratelimit"
+	"github.com/pkg/errors"
+)
+
+// Type indicates the type of a store limit.
+type Type int
+
+const (
+	// AddPeer limits the peers added to a store, such as the snapshots sent
+	// to it.
+	AddPeer Type = iota
+	// RemovePeer limits the peers removed from a store.
+	RemovePeer
+)
+
+// TypeNameValue maps the names of the store limit types to the types.
+var TypeNameValue = map[string]Type{
+	"add-peer":    AddPeer,
+	"remove-peer": RemovePeer,
+}
+
+// Types are all the store limit types.
+var Types = []Type{AddPeer, RemovePeer}
+
+func (t Type) String() string {
+	switch t {
+	case AddPeer:
+		return "add-peer"
+	case RemovePeer:
+		return "remove-peer"
+	}
+	return "unknown"
+}
+
+// ParseType parses the name of a store limit type.
+func ParseType(name string) (Type, error) {
+	if t, ok := TypeNameValue[name]; ok {
+		return t, nil
+	}
+	return AddPeer, errors.Errorf("unknown store limit type %s", name)
+}
+
+// StoreLimit limits the cost of the operator steps on a store.
+type StoreLimit struct {
+	bucket          *ratelimit.Bucket
+	regionInfluence int64
+	reserved        int64
+}
+
+// NewStoreLimit creates a StoreLimit which allows rate regions per second.
+// The cost of a region is regionInfluence. The same cost as the capacity is
+// reserved for the repair operators.
+func NewStoreLimit(rate float64, regionInfluence int64) *StoreLimit {
+	capacity := regionInfluence
+	if rate > 1 {
+		capacity = int64(rate * float64(regionInfluence))
+	}
+	return &StoreLimit{
+		bucket:          ratelimit.NewBucketWithRate(rate*float64(regionInfluence), capacity),
+		regionInfluence: regionInfluence,
+		reserved:        capacity,
+	}
+}
+
+// Available returns the available cost.
+func (l *StoreLimit) Available() int64 {
+	return l.bucket.Available()
+}
+
+// Reserved returns the cost reserved for the repair operators. The repair
+// operators can still take it after the available cost is used up, so that
+// the other operators cannot starve them.
+func (l *StoreLimit) Reserved() int64 {
+	return l.reserved
+}
+
+// Rate returns the number of regions allowed per second.
+func (l *StoreLimit) Rate() float64 {
+	return l.bucket.Rate() / float64(l.regionInfluence)
+}
+
+// Take takes the cost from the limit.
+func (l *StoreLimit) Take(cost int64) {
+	l.bucket.Take(cost)
+}
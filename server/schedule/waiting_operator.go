@@ -36,50 +36,69 @@ type Bucket struct {
 	ops    []*operator.Operator
 }
 
-// RandBuckets is an implementation of waiting operators
+// RandBuckets is an implementation of waiting operators. The operators of a
+// higher priority class are always got first, and the operators of the same
+// class are got randomly by the weights of their priority levels.
 type RandBuckets struct {
+	classes []*classBuckets
+}
+
+// classBuckets maintains the operators of a priority class.
+type classBuckets struct {
 	totalWeight float64
 	buckets     []*Bucket
 }
 
 // NewRandBuckets creates a random buckets.
 func NewRandBuckets() *RandBuckets {
-	var buckets []*Bucket
-	for i := 0; i < len(PriorityWeight); i++ {
-		buckets = append(buckets, &Bucket{
-			weight: PriorityWeight[i],
-		})
+	var classes []*classBuckets
+	for c := 0; c < int(operator.PriorityClassCount); c++ {
+		var buckets []*Bucket
+		for i := 0; i < len(PriorityWeight); i++ {
+			buckets = append(buckets, &Bucket{
+				weight: PriorityWeight[i],
+			})
+		}
+		classes = append(classes, &classBuckets{buckets: buckets})
 	}
-	return &RandBuckets{buckets: buckets}
+	return &RandBuckets{classes: classes}
 }
 
 // PutOperator puts an operator into the random buckets.
 func (b *RandBuckets) PutOperator(op *operator.Operator) {
+	class := b.classes[op.GetPriorityClass()]
 	priority := op.GetPriorityLevel()
-	bucket := b.buckets[priority]
+	bucket := class.buckets[priority]
 	if len(bucket.ops) == 0 {
-		b.totalWeight += bucket.weight
+		class.totalWeight += bucket.weight
 	}
 	bucket.ops = append(bucket.ops, op)
 }
 
-// ListOperator lists all operator in the random buckets.
+// ListOperator lists all operator in the random buckets, the operators of a
+// higher priority class first.
 func (b *RandBuckets) ListOperator() []*operator.Operator {
 	var ops []*operator.Operator
-	for i := range b.buckets {
-		bucket := b.buckets[i]
-		for j := range bucket.ops {
-			ops = append(ops, bucket.ops[j])
+	for c := len(b.classes) - 1; c >= 0; c-- {
+		for _, bucket := range b.classes[c].buckets {
+			ops = append(ops, bucket.ops...)
 		}
 	}
 	return ops
 }
 
-// GetOperator gets an operator from the random buckets.
+// GetOperator gets an operator from the random buckets of the highest
+// priority class which has operators.
 func (b *RandBuckets) GetOperator() []*operator.Operator {
-	if b.totalWeight == 0 {
-		return nil
+	for c := len(b.classes) - 1; c >= 0; c-- {
+		if b.classes[c].totalWeight != 0 {
+			return b.classes[c].getOperator()
+		}
 	}
+	return nil
+}
+
+func (b *classBuckets) getOperator() []*operator.Operator {
 	r := rand.Float64()
 	var sum float64
 	for i := range b.buckets {
@@ -106,3 +106,24 @@ func (s *testWaitingOperatorSuite) TestRandomBucketsWithMergeRegion(c *C) {
 		c.Assert(rb.GetOperator(), IsNil)
 	}
 }
+
+func (s *testWaitingOperatorSuite) TestPriorityClass(c *C) {
+	rb := NewRandBuckets()
+	kinds := []operator.OpKind{operator.OpBalance, operator.OpReplica | operator.OpRepair, operator.OpHotRegion, operator.OpAdmin}
+	for i, kind := range kinds {
+		op := operator.NewOperator("test", uint64(i), &metapb.RegionEpoch{}, operator.OpRegion|kind, operator.RemovePeer{FromStore: 1})
+		op.SetPriorityLevel(core.HighPriority)
+		if kind == operator.OpBalance {
+			// The priority level does not matter across the classes.
+			op.SetPriorityLevel(core.LowPriority)
+		}
+		rb.PutOperator(op)
+	}
+	c.Assert(rb.ListOperator(), HasLen, 4)
+	for _, class := range []operator.PriorityClass{operator.RepairClass, operator.AdminClass, operator.HotClass, operator.BalanceClass} {
+		ops := rb.GetOperator()
+		c.Assert(ops, HasLen, 1)
+		c.Assert(ops[0].GetPriorityClass(), Equals, class)
+	}
+	c.Assert(rb.GetOperator(), IsNil)
+}
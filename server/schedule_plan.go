@@ -0,0 +1,189 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"sort"
+
+	"github.com/pingcap/pd/server/config"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/schedule"
+	"github.com/pingcap/pd/server/schedule/operator"
+	"github.com/pkg/errors"
+)
+
+// The checkers which can be run by a schedule plan.
+const (
+	PlanCheckerLearner   = "learner"
+	PlanCheckerNamespace = "namespace"
+	PlanCheckerReplica   = "replica"
+	PlanCheckerPlacement = "placement"
+	PlanCheckerMerge     = "merge"
+)
+
+var planCheckers = []string{
+	PlanCheckerLearner,
+	PlanCheckerNamespace,
+	PlanCheckerReplica,
+	PlanCheckerPlacement,
+	PlanCheckerMerge,
+}
+
+// StoreInfluence is the influence of an operator on a store, the scores are
+// the store's leader and region scores before and after the operator is
+// finished.
+type StoreInfluence struct {
+	StoreID           uint64  `json:"store_id"`
+	LeaderCount       int64   `json:"leader_count"`
+	LeaderSize        int64   `json:"leader_size"`
+	RegionCount       int64   `json:"region_count"`
+	RegionSize        int64   `json:"region_size"`
+	LeaderScoreBefore float64 `json:"leader_score_before"`
+	LeaderScoreAfter  float64 `json:"leader_score_after"`
+	RegionScoreBefore float64 `json:"region_score_before"`
+	RegionScoreAfter  float64 `json:"region_score_after"`
+}
+
+// PlannedOperator is an operator created by a schedule plan. Source is the
+// name of the scheduler or checker which creates it.
+type PlannedOperator struct {
+	Source    string             `json:"source"`
+	Operator  *operator.Operator `json:"operator"`
+	Influence []StoreInfluence   `json:"influence"`
+}
+
+// planSchedule runs the schedulers once and the checkers on each region
+// without an operator, then returns the operators they create. The operators
+// are never added to the operator controller, and the schedule limits are
+// ignored. If both schedulers and checkers are empty, the enabled schedulers
+// in the config and all the checkers are run. At most limit operators are
+// created by the checkers. Like previewOperators, the plan runs in dry run
+// mode, so the peer IDs of the operators are placeholders.
+func (c *coordinator) planSchedule(schedulers []config.SchedulerConfig, checkers []string, limit int) ([]*PlannedOperator, error) {
+	if len(schedulers) == 0 && len(checkers) == 0 {
+		for _, cfg := range c.cluster.opt.Load().Clone().Schedulers {
+			if !cfg.Disable {
+				schedulers = append(schedulers, cfg)
+			}
+		}
+		checkers = planCheckers
+	}
+	for _, name := range checkers {
+		if !isPlanChecker(name) {
+			return nil, errors.Errorf("unknown checker %s", name)
+		}
+	}
+
+	cluster := schedule.NewDryRunCluster(c.cluster)
+	var planned []*PlannedOperator
+	for _, cfg := range schedulers {
+		// Creates a new scheduler instead of using the running one, so the
+		// state of the running scheduler is not changed. Prepare is skipped
+		// as some schedulers block the stores in it.
+		s, err := schedule.CreateScheduler(cfg.Type, c.opController, cfg.Args...)
+		if err != nil {
+			return nil, err
+		}
+		planned = append(planned, c.planOperators(s.GetName(), s.Schedule(cluster))...)
+	}
+
+	dryRunCheckers := c.newDryRunCheckers()
+	count := 0
+	for _, region := range c.cluster.GetRegions() {
+		if count >= limit {
+			break
+		}
+		if c.opController.GetOperator(region.GetID()) != nil {
+			continue
+		}
+		for _, name := range checkers {
+			if ops := c.planCheck(dryRunCheckers, name, region); len(ops) > 0 {
+				planned = append(planned, c.planOperators(name+"-checker", ops)...)
+				count++
+				break
+			}
+		}
+	}
+	return planned, nil
+}
+
+func isPlanChecker(name string) bool {
+	for _, checker := range planCheckers {
+		if checker == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *coordinator) planCheck(checkers *dryRunCheckers, name string, region *core.RegionInfo) []*operator.Operator {
+	var op *operator.Operator
+	switch name {
+	case PlanCheckerLearner:
+		op = checkers.learnerChecker.Check(region)
+	case PlanCheckerNamespace:
+		op = checkers.namespaceChecker.Check(region)
+	case PlanCheckerReplica:
+		op = checkers.replicaChecker.Check(region)
+	case PlanCheckerPlacement:
+		op = checkers.placementChecker.Check(region)
+	case PlanCheckerMerge:
+		if c.cluster.IsFeatureSupported(RegionMerge) {
+			return checkers.mergeChecker.Check(region)
+		}
+	}
+	if op == nil {
+		return nil
+	}
+	return []*operator.Operator{op}
+}
+
+func (c *coordinator) planOperators(source string, ops []*operator.Operator) []*PlannedOperator {
+	planned := make([]*PlannedOperator, 0, len(ops))
+	for _, op := range ops {
+		planned = append(planned, &PlannedOperator{
+			Source:    source,
+			Operator:  op,
+			Influence: c.storeInfluences(op),
+		})
+	}
+	return planned
+}
+
+// storeInfluences calculates the influence of the operator on each store it
+// touches. The influence is calculated against the current cluster state,
+// the other planned operators are not taken into account.
+func (c *coordinator) storeInfluences(op *operator.Operator) []StoreInfluence {
+	influence := schedule.NewTotalOpInfluence([]*operator.Operator{op}, c.cluster)
+	highSpaceRatio, lowSpaceRatio := c.cluster.GetHighSpaceRatio(), c.cluster.GetLowSpaceRatio()
+	influences := make([]StoreInfluence, 0, len(influence.StoresInfluence))
+	for storeID, inf := range influence.StoresInfluence {
+		si := StoreInfluence{
+			StoreID:     storeID,
+			LeaderCount: inf.LeaderCount,
+			LeaderSize:  inf.LeaderSize,
+			RegionCount: inf.RegionCount,
+			RegionSize:  inf.RegionSize,
+		}
+		if store := c.cluster.GetStore(storeID); store != nil {
+			si.LeaderScoreBefore = store.LeaderScore(0)
+			si.LeaderScoreAfter = store.LeaderScore(inf.LeaderSize)
+			si.RegionScoreBefore = store.RegionScore(highSpaceRatio, lowSpaceRatio, 0)
+			si.RegionScoreAfter = store.RegionScore(highSpaceRatio, lowSpaceRatio, inf.RegionSize)
+		}
+		influences = append(influences, si)
+	}
+	sort.Slice(influences, func(i, j int) bool { return influences[i].StoreID < influences[j].StoreID })
+	return influences
+}
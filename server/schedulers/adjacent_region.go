@@ -160,7 +160,9 @@ func (l *balanceAdjacentRegionScheduler) Schedule(cluster schedule.Cluster) []*o
 	regions := cluster.ScanRegions(l.lastKey, scanLimit)
 	// scan to the end
 	if len(regions) <= 1 {
-		schedulerStatus.WithLabelValues(l.GetName(), "adjacent_count").Set(float64(l.adjacentRegionsCount))
+		if !schedule.IsDryRun(cluster) {
+			schedulerStatus.WithLabelValues(l.GetName(), "adjacent_count").Set(float64(l.adjacentRegionsCount))
+		}
 		l.adjacentRegionsCount = 0
 		l.lastKey = []byte("")
 		return nil
@@ -218,16 +220,16 @@ func (l *balanceAdjacentRegionScheduler) process(cluster schedule.Cluster) []*op
 	// after the cluster is prepared, there is a gap that some regions heartbeats are not received.
 	// Leader of those region is nil, and we should skip them.
 	if r1.GetLeader() == nil || r2.GetLeader() == nil || l.unsafeToBalance(cluster, r1) {
-		schedulerCounter.WithLabelValues(l.GetName(), "skip").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(l.GetName(), "skip").Inc()
 		return nil
 	}
 	op := l.disperseLeader(cluster, r1, r2)
 	if op == nil {
-		schedulerCounter.WithLabelValues(l.GetName(), "no_leader").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(l.GetName(), "no_leader").Inc()
 		op = l.dispersePeer(cluster, r1)
 	}
 	if op == nil {
-		schedulerCounter.WithLabelValues(l.GetName(), "no_peer").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(l.GetName(), "no_peer").Inc()
 		l.cacheRegions.assignedStoreIds = l.cacheRegions.assignedStoreIds[:0]
 		return nil
 	}
@@ -235,7 +237,7 @@ func (l *balanceAdjacentRegionScheduler) process(cluster schedule.Cluster) []*op
 }
 
 func (l *balanceAdjacentRegionScheduler) unsafeToBalance(cluster schedule.Cluster, region *core.RegionInfo) bool {
-	if len(region.GetPeers()) != cluster.GetMaxReplicas() {
+	if len(region.GetPeers()) != cluster.GetRegionRule(region).GetReplicas() {
 		return true
 	}
 	store := cluster.GetStore(region.GetLeader().GetStoreId())
@@ -248,7 +250,7 @@ func (l *balanceAdjacentRegionScheduler) unsafeToBalance(cluster schedule.Cluste
 	}
 	// Skip hot regions.
 	if cluster.IsRegionHot(region) {
-		schedulerCounter.WithLabelValues(l.GetName(), "region_hot").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(l.GetName(), "region_hot").Inc()
 		return true
 	}
 	return false
@@ -274,7 +276,7 @@ func (l *balanceAdjacentRegionScheduler) disperseLeader(cluster schedule.Cluster
 	}
 	op := operator.CreateTransferLeaderOperator("balance-adjacent-leader", before, before.GetLeader().GetStoreId(), target.GetID(), operator.OpAdjacent)
 	op.SetPriorityLevel(core.LowPriority)
-	schedulerCounter.WithLabelValues(l.GetName(), "adjacent_leader").Inc()
+	schedule.Counter(cluster, schedulerCounter).WithLabelValues(l.GetName(), "adjacent_leader").Inc()
 	return op
 }
 
@@ -311,7 +313,7 @@ func (l *balanceAdjacentRegionScheduler) dispersePeer(cluster schedule.Cluster,
 		return nil
 	}
 	if newPeer == nil {
-		schedulerCounter.WithLabelValues(l.GetName(), "no_peer").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(l.GetName(), "no_peer").Inc()
 		return nil
 	}
 
@@ -320,10 +322,10 @@ func (l *balanceAdjacentRegionScheduler) dispersePeer(cluster schedule.Cluster,
 
 	op, err := operator.CreateMovePeerOperator("balance-adjacent-peer", cluster, region, operator.OpAdjacent, leaderStoreID, newPeer.GetStoreId(), newPeer.GetId())
 	if err != nil {
-		schedulerCounter.WithLabelValues(l.GetName(), "create_operator_fail").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(l.GetName(), "create_operator_fail").Inc()
 		return nil
 	}
 	op.SetPriorityLevel(core.LowPriority)
-	schedulerCounter.WithLabelValues(l.GetName(), "adjacent_peer").Inc()
+	schedule.Counter(cluster, schedulerCounter).WithLabelValues(l.GetName(), "adjacent_peer").Inc()
 	return op
 }
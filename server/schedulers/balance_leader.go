@@ -43,6 +43,7 @@ type balanceLeaderScheduler struct {
 	taintStores  *cache.TTLUint64
 	opController *schedule.OperatorController
 	counter      *prometheus.CounterVec
+	tracer       *schedule.DecisionTracer
 }
 
 // newBalanceLeaderScheduler creates a scheduler that tends to keep leaders on
@@ -54,13 +55,15 @@ func newBalanceLeaderScheduler(opController *schedule.OperatorController, opts .
 		filter.NewCacheFilter(taintStores),
 	}
 	base := newBaseScheduler(opController)
+	tracer := schedule.NewDecisionTracer(schedule.DefaultDecisionTraceLimit)
 
 	s := &balanceLeaderScheduler{
 		baseScheduler: base,
-		selector:      selector.NewBalanceSelector(core.LeaderKind, filters),
+		selector:      selector.NewBalanceSelector(core.LeaderKind, tracer.TraceFilters(filters...)),
 		taintStores:   taintStores,
 		opController:  opController,
 		counter:       balanceLeaderCounter,
+		tracer:        tracer,
 	}
 	for _, opt := range opts {
 		opt(s)
@@ -96,12 +99,17 @@ func (l *balanceLeaderScheduler) GetType() string {
 	return "balance-leader"
 }
 
+func (l *balanceLeaderScheduler) GetDecisionTracer() *schedule.DecisionTracer {
+	return l.tracer
+}
+
 func (l *balanceLeaderScheduler) IsScheduleAllowed(cluster schedule.Cluster) bool {
 	return l.opController.OperatorCount(operator.OpLeader) < cluster.GetLeaderScheduleLimit()
 }
 
 func (l *balanceLeaderScheduler) Schedule(cluster schedule.Cluster) []*operator.Operator {
-	schedulerCounter.WithLabelValues(l.GetName(), "schedule").Inc()
+	schedule.Counter(cluster, schedulerCounter).WithLabelValues(l.GetName(), "schedule").Inc()
+	l.tracer.StartRound()
 
 	stores := cluster.GetStores()
 
@@ -112,7 +120,7 @@ func (l *balanceLeaderScheduler) Schedule(cluster schedule.Cluster) []*operator.
 
 	// No store can be selected as source or target.
 	if source == nil || target == nil {
-		schedulerCounter.WithLabelValues(l.GetName(), "no_store").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(l.GetName(), "no_store").Inc()
 		// When the cluster is balanced, all stores will be added to the cache once
 		// all of them have been selected. This will cause the scheduler to not adapt
 		// to sudden change of a store's leader. Here we clear the taint cache and
@@ -160,13 +168,13 @@ func (l *balanceLeaderScheduler) transferLeaderOut(source *core.StoreInfo, clust
 	region := cluster.RandLeaderRegion(sourceID, core.HealthRegion())
 	if region == nil {
 		log.Debug("store has no leader", zap.String("scheduler", l.GetName()), zap.Uint64("store-id", sourceID))
-		schedulerCounter.WithLabelValues(l.GetName(), "no_leader_region").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(l.GetName(), "no_leader_region").Inc()
 		return nil
 	}
 	target := l.selector.SelectTarget(cluster, cluster.GetFollowerStores(region))
 	if target == nil {
 		log.Debug("region has no target store", zap.String("scheduler", l.GetName()), zap.Uint64("region-id", region.GetID()))
-		schedulerCounter.WithLabelValues(l.GetName(), "no_target_store").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(l.GetName(), "no_target_store").Inc()
 		return nil
 	}
 	return l.createOperator(region, source, target, cluster, opInfluence)
@@ -180,13 +188,13 @@ func (l *balanceLeaderScheduler) transferLeaderIn(target *core.StoreInfo, cluste
 	region := cluster.RandFollowerRegion(targetID, core.HealthRegion())
 	if region == nil {
 		log.Debug("store has no follower", zap.String("scheduler", l.GetName()), zap.Uint64("store-id", targetID))
-		schedulerCounter.WithLabelValues(l.GetName(), "no_follower_region").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(l.GetName(), "no_follower_region").Inc()
 		return nil
 	}
 	source := cluster.GetStore(region.GetLeader().GetStoreId())
 	if source == nil {
 		log.Debug("region has no leader", zap.String("scheduler", l.GetName()), zap.Uint64("region-id", region.GetID()))
-		schedulerCounter.WithLabelValues(l.GetName(), "no_leader").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(l.GetName(), "no_leader").Inc()
 		return nil
 	}
 	return l.createOperator(region, source, target, cluster, opInfluence)
@@ -199,13 +207,22 @@ func (l *balanceLeaderScheduler) transferLeaderIn(target *core.StoreInfo, cluste
 func (l *balanceLeaderScheduler) createOperator(region *core.RegionInfo, source, target *core.StoreInfo, cluster schedule.Cluster, opInfluence operator.OpInfluence) []*operator.Operator {
 	if cluster.IsRegionHot(region) {
 		log.Debug("region is hot region, ignore it", zap.String("scheduler", l.GetName()), zap.Uint64("region-id", region.GetID()))
-		schedulerCounter.WithLabelValues(l.GetName(), "region_hot").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(l.GetName(), "region_hot").Inc()
 		return nil
 	}
 
 	sourceID := source.GetID()
 	targetID := target.GetID()
-	if !shouldBalance(cluster, source, target, region, core.LeaderKind, opInfluence) {
+	sourceScore, targetScore := balanceScores(cluster, source, target, region, core.LeaderKind, opInfluence)
+	evaluation := schedule.CandidateEvaluation{
+		RegionID:    region.GetID(),
+		SourceStore: sourceID,
+		TargetStore: targetID,
+		SourceScore: sourceScore,
+		TargetScore: targetScore,
+		Result:      schedule.EvaluationSkip,
+	}
+	if sourceScore <= targetScore {
 		log.Debug("skip balance leader",
 			zap.String("scheduler", l.GetName()), zap.Uint64("region-id", region.GetID()), zap.Uint64("source-store", sourceID), zap.Uint64("target-store", targetID),
 			zap.Int64("source-size", source.GetLeaderSize()), zap.Float64("source-score", source.LeaderScore(0)),
@@ -213,11 +230,14 @@ func (l *balanceLeaderScheduler) createOperator(region *core.RegionInfo, source,
 			zap.Int64("target-size", target.GetLeaderSize()), zap.Float64("target-score", target.LeaderScore(0)),
 			zap.Int64("target-influence", opInfluence.GetStoreInfluence(targetID).ResourceSize(core.LeaderKind)),
 			zap.Int64("average-region-size", cluster.GetAverageRegionSize()))
-		schedulerCounter.WithLabelValues(l.GetName(), "skip").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(l.GetName(), "skip").Inc()
+		l.tracer.RecordEvaluation(evaluation)
 		return nil
 	}
+	evaluation.Result = schedule.EvaluationOperator
+	l.tracer.RecordEvaluation(evaluation)
 
-	schedulerCounter.WithLabelValues(l.GetName(), "new_operator").Inc()
+	schedule.Counter(cluster, schedulerCounter).WithLabelValues(l.GetName(), "new_operator").Inc()
 	sourceLabel := strconv.FormatUint(sourceID, 10)
 	targetLabel := strconv.FormatUint(targetID, 10)
 	l.counter.WithLabelValues("move_leader", source.GetAddress()+"-out", sourceLabel).Inc()
@@ -57,6 +57,7 @@ type balanceRegionScheduler struct {
 	opController *schedule.OperatorController
 	hitsCounter  *hitsStoreBuilder
 	counter      *prometheus.CounterVec
+	tracer       *schedule.DecisionTracer
 }
 
 // newBalanceRegionScheduler creates a scheduler that tends to keep regions on
@@ -66,12 +67,14 @@ func newBalanceRegionScheduler(opController *schedule.OperatorController, opts .
 		filter.StoreStateFilter{MoveRegion: true},
 	}
 	base := newBaseScheduler(opController)
+	tracer := schedule.NewDecisionTracer(schedule.DefaultDecisionTraceLimit)
 	s := &balanceRegionScheduler{
 		baseScheduler: base,
-		selector:      selector.NewBalanceSelector(core.RegionKind, filters),
+		selector:      selector.NewBalanceSelector(core.RegionKind, tracer.TraceFilters(filters...)),
 		opController:  opController,
 		hitsCounter:   newHitsStoreBuilder(hitsStoreTTL, hitsStoreCountThreshold),
 		counter:       balanceRegionCounter,
+		tracer:        tracer,
 	}
 	for _, opt := range opts {
 		opt(s)
@@ -107,19 +110,24 @@ func (s *balanceRegionScheduler) GetType() string {
 	return "balance-region"
 }
 
+func (s *balanceRegionScheduler) GetDecisionTracer() *schedule.DecisionTracer {
+	return s.tracer
+}
+
 func (s *balanceRegionScheduler) IsScheduleAllowed(cluster schedule.Cluster) bool {
 	return s.opController.OperatorCount(operator.OpRegion) < cluster.GetRegionScheduleLimit()
 }
 
 func (s *balanceRegionScheduler) Schedule(cluster schedule.Cluster) []*operator.Operator {
-	schedulerCounter.WithLabelValues(s.GetName(), "schedule").Inc()
+	schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "schedule").Inc()
+	s.tracer.StartRound()
 	stores := cluster.GetStores()
 
 	// source is the store with highest region score in the list that can be selected as balance source.
 	f := s.hitsCounter.buildSourceFilter(cluster)
-	source := s.selector.SelectSource(cluster, stores, f)
+	source := s.selector.SelectSource(cluster, stores, s.tracer.TraceFilters(f)...)
 	if source == nil {
-		schedulerCounter.WithLabelValues(s.GetName(), "no_store").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "no_store").Inc()
 		// Unlike the balanceLeaderScheduler, we don't need to clear the taintCache
 		// here. Because normally region score won't change rapidly, and the region
 		// balance requires lower sensitivity compare to leader balance.
@@ -146,16 +154,16 @@ func (s *balanceRegionScheduler) Schedule(cluster schedule.Cluster) []*operator.
 			region = cluster.RandLeaderRegion(sourceID, core.HealthRegion())
 		}
 		if region == nil {
-			schedulerCounter.WithLabelValues(s.GetName(), "no_region").Inc()
+			schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "no_region").Inc()
 			s.hitsCounter.put(source, nil)
 			continue
 		}
 		log.Debug("select region", zap.String("scheduler", s.GetName()), zap.Uint64("region-id", region.GetID()))
 
 		// We don't schedule region with abnormal number of replicas.
-		if len(region.GetPeers()) != cluster.GetMaxReplicas() {
+		if len(region.GetPeers()) != cluster.GetRegionRule(region).GetReplicas() {
 			log.Debug("region has abnormal replica count", zap.String("scheduler", s.GetName()), zap.Uint64("region-id", region.GetID()))
-			schedulerCounter.WithLabelValues(s.GetName(), "abnormal_replica").Inc()
+			schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "abnormal_replica").Inc()
 			s.hitsCounter.put(source, nil)
 			continue
 		}
@@ -163,14 +171,14 @@ func (s *balanceRegionScheduler) Schedule(cluster schedule.Cluster) []*operator.
 		// Skip hot regions.
 		if cluster.IsRegionHot(region) {
 			log.Debug("region is hot", zap.String("scheduler", s.GetName()), zap.Uint64("region-id", region.GetID()))
-			schedulerCounter.WithLabelValues(s.GetName(), "region_hot").Inc()
+			schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "region_hot").Inc()
 			s.hitsCounter.put(source, nil)
 			continue
 		}
 
 		oldPeer := region.GetStorePeer(sourceID)
 		if op := s.transferPeer(cluster, region, oldPeer, opInfluence); op != nil {
-			schedulerCounter.WithLabelValues(s.GetName(), "new_operator").Inc()
+			schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "new_operator").Inc()
 			return []*operator.Operator{op}
 		}
 	}
@@ -182,12 +190,13 @@ func (s *balanceRegionScheduler) transferPeer(cluster schedule.Cluster, region *
 	// scoreGuard guarantees that the distinct score will not decrease.
 	stores := cluster.GetRegionStores(region)
 	source := cluster.GetStore(oldPeer.GetStoreId())
-	scoreGuard := filter.NewDistinctScoreFilter(cluster.GetLocationLabels(), stores, source)
+	scoreGuard := filter.NewDistinctScoreFilter(cluster.GetRegionRule(region).LocationLabels, stores, source)
 	hitsFilter := s.hitsCounter.buildTargetFilter(cluster, source)
 	checker := checker.NewReplicaChecker(cluster, nil)
-	storeID, _ := checker.SelectBestReplacementStore(region, oldPeer, scoreGuard, hitsFilter)
+	checker.SetOperatorController(s.opController)
+	storeID, _ := checker.SelectBestReplacementStore(region, oldPeer, s.tracer.TraceFilters(scoreGuard, hitsFilter)...)
 	if storeID == 0 {
-		schedulerCounter.WithLabelValues(s.GetName(), "no_replacement").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "no_replacement").Inc()
 		s.hitsCounter.put(source, nil)
 		return nil
 	}
@@ -198,7 +207,16 @@ func (s *balanceRegionScheduler) transferPeer(cluster schedule.Cluster, region *
 	targetID := target.GetID()
 	log.Debug("", zap.Uint64("region-id", regionID), zap.Uint64("source-store", sourceID), zap.Uint64("target-store", targetID))
 
-	if !shouldBalance(cluster, source, target, region, core.RegionKind, opInfluence) {
+	sourceScore, targetScore := balanceScores(cluster, source, target, region, core.RegionKind, opInfluence)
+	evaluation := schedule.CandidateEvaluation{
+		RegionID:    regionID,
+		SourceStore: sourceID,
+		TargetStore: targetID,
+		SourceScore: sourceScore,
+		TargetScore: targetScore,
+		Result:      schedule.EvaluationSkip,
+	}
+	if sourceScore <= targetScore {
 		log.Debug("skip balance region",
 			zap.String("scheduler", s.GetName()), zap.Uint64("region-id", regionID), zap.Uint64("source-store", sourceID), zap.Uint64("target-store", targetID),
 			zap.Int64("source-size", source.GetRegionSize()), zap.Float64("source-score", source.RegionScore(cluster.GetHighSpaceRatio(), cluster.GetLowSpaceRatio(), 0)),
@@ -206,21 +224,24 @@ func (s *balanceRegionScheduler) transferPeer(cluster schedule.Cluster, region *
 			zap.Int64("target-size", target.GetRegionSize()), zap.Float64("target-score", target.RegionScore(cluster.GetHighSpaceRatio(), cluster.GetLowSpaceRatio(), 0)),
 			zap.Int64("target-influence", opInfluence.GetStoreInfluence(targetID).ResourceSize(core.RegionKind)),
 			zap.Int64("average-region-size", cluster.GetAverageRegionSize()))
-		schedulerCounter.WithLabelValues(s.GetName(), "skip").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "skip").Inc()
+		s.tracer.RecordEvaluation(evaluation)
 		s.hitsCounter.put(source, target)
 		return nil
 	}
 
 	newPeer, err := cluster.AllocPeer(storeID)
 	if err != nil {
-		schedulerCounter.WithLabelValues(s.GetName(), "no_peer").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "no_peer").Inc()
 		return nil
 	}
 	op, err := operator.CreateMovePeerOperator("balance-region", cluster, region, operator.OpBalance, oldPeer.GetStoreId(), newPeer.GetStoreId(), newPeer.GetId())
 	if err != nil {
-		schedulerCounter.WithLabelValues(s.GetName(), "create_operator_fail").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "create_operator_fail").Inc()
 		return nil
 	}
+	evaluation.Result = schedule.EvaluationOperator
+	s.tracer.RecordEvaluation(evaluation)
 	s.hitsCounter.remove(source, target)
 	s.hitsCounter.remove(source, nil)
 	sourceLabel := strconv.FormatUint(sourceID, 10)
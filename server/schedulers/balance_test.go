@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"time"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/kvproto/pkg/metapb"
@@ -28,7 +29,10 @@ import (
 	"github.com/pingcap/pd/server/checker"
 	"github.com/pingcap/pd/server/core"
 	"github.com/pingcap/pd/server/namespace"
+	"github.com/pingcap/pd/server/placement"
+	"github.com/pingcap/pd/server/placement/rule"
 	"github.com/pingcap/pd/server/schedule"
+	"github.com/pingcap/pd/server/schedule/filter"
 	"github.com/pingcap/pd/server/schedule/operator"
 	"github.com/pingcap/pd/server/statistics"
 )
@@ -235,6 +239,47 @@ func (s *testBalanceLeaderSchedulerSuite) TestBalanceFilter(c *C) {
 	c.Assert(s.schedule(), HasLen, 0)
 }
 
+func (s *testBalanceLeaderSchedulerSuite) TestDecisionTrace(c *C) {
+	// Stores:     1    2    3
+	// Leaders:    8    9   10
+	// Region1:    F    F    L
+	s.tc.AddLeaderStore(1, 8)
+	s.tc.AddLeaderStore(2, 9)
+	s.tc.AddLeaderStore(3, 10)
+	s.tc.AddLeaderRegion(1, 3, 1, 2)
+	// Store 1 is disconnected, so store 2 becomes the target, but the leaders
+	// are balanced between store 2 and 3.
+	s.tc.SetStoreDisconnect(1)
+	c.Assert(s.schedule(), HasLen, 0)
+
+	trace := s.lb.(schedule.DecisionTraced).GetDecisionTracer().GetTrace()
+	// Store 1 is rejected as the source once, and as the target in each
+	// retry, the most rejections first.
+	c.Assert(trace.Rejections, HasLen, 2)
+	c.Assert(trace.Rejections[0].Direction, Equals, schedule.RejectTarget)
+	c.Assert(trace.Rejections[1].Direction, Equals, schedule.RejectSource)
+	for _, rejection := range trace.Rejections {
+		c.Assert(rejection.Filter, Equals, filter.StoreStateFilter{}.Type())
+		c.Assert(rejection.StoreID, Equals, uint64(1))
+	}
+	c.Assert(trace.Evaluations, HasLen, 2*balanceLeaderRetryLimit)
+	for _, e := range trace.Evaluations {
+		c.Assert(e.Result, Equals, schedule.EvaluationSkip)
+		c.Assert(e.SourceStore, Equals, uint64(3))
+		c.Assert(e.TargetStore, Equals, uint64(2))
+		c.Assert(e.SourceScore <= e.TargetScore, IsTrue)
+	}
+
+	// Store 3 has much more leaders now. Store 2 and 3 are tainted in the
+	// last round, so no store can be selected until the taint is cleared.
+	s.tc.UpdateLeaderCount(3, 16)
+	c.Assert(s.schedule(), HasLen, 0)
+	testutil.CheckTransferLeader(c, s.schedule()[0], operator.OpBalance, 3, 2)
+	trace = s.lb.(schedule.DecisionTraced).GetDecisionTracer().GetTrace()
+	c.Assert(trace.Evaluations[0].Result, Equals, schedule.EvaluationOperator)
+	c.Assert(trace.Evaluations[0].SourceScore > trace.Evaluations[0].TargetScore, IsTrue)
+}
+
 func (s *testBalanceLeaderSchedulerSuite) TestLeaderWeight(c *C) {
 	// Stores:	1	2	3	4
 	// Leaders:    10      10      10      10
@@ -463,6 +508,57 @@ func (s *testBalanceRegionSchedulerSuite) TestReplicas5(c *C) {
 	testutil.CheckTransferPeer(c, sb.Schedule(tc)[0], operator.OpBalance, 11, 6)
 }
 
+func (s *testBalanceRegionSchedulerSuite) TestRule(c *C) {
+	opt := mockoption.NewScheduleOptions()
+	tc := mockcluster.NewCluster(opt)
+	oc := schedule.NewOperatorController(nil, nil)
+
+	// The max replicas is 3 but the rule requires 5 voters.
+	newTestReplication(opt, 3, "host")
+	c.Assert(tc.RuleManager.SetRule(&rule.Rule{ID: "r5", Voters: 5, LocationLabels: []string{"zone", "rack", "host"}}), IsNil)
+
+	sb, err := schedule.CreateScheduler("balance-region", oc)
+	c.Assert(err, IsNil)
+
+	tc.AddLabelsStore(1, 4, map[string]string{"zone": "z1", "rack": "r1", "host": "h1"})
+	tc.AddLabelsStore(2, 5, map[string]string{"zone": "z2", "rack": "r1", "host": "h1"})
+	tc.AddLabelsStore(3, 6, map[string]string{"zone": "z3", "rack": "r1", "host": "h1"})
+	tc.AddLabelsStore(4, 7, map[string]string{"zone": "z4", "rack": "r1", "host": "h1"})
+	tc.AddLabelsStore(5, 28, map[string]string{"zone": "z5", "rack": "r1", "host": "h1"})
+	tc.AddLabelsStore(6, 1, map[string]string{"zone": "z5", "rack": "r2", "host": "h1"})
+	tc.AddLabelsStore(7, 5, map[string]string{"zone": "z6", "rack": "r1", "host": "h2"})
+	tc.AddLeaderRegion(1, 1, 2, 3, 4, 5)
+
+	// The region is balanced with the replicas of the rule. By the location
+	// labels of the rule, store 6 is as distinct as store 7 and has a smaller
+	// region score, while store 7 is the only distinct one by the host label.
+	testutil.CheckTransferPeer(c, sb.Schedule(tc)[0], operator.OpBalance, 5, 6)
+}
+
+func (s *testBalanceRegionSchedulerSuite) TestPlacementConstraints(c *C) {
+	opt := mockoption.NewScheduleOptions()
+	tc := mockcluster.NewCluster(opt)
+	oc := schedule.NewOperatorController(nil, nil)
+
+	sb, err := schedule.CreateScheduler("balance-region", oc)
+	c.Assert(err, IsNil)
+
+	tc.AddLabelsStore(1, 6, map[string]string{"zone": "z1"})
+	tc.AddLabelsStore(2, 6, map[string]string{"zone": "z1"})
+	tc.AddLabelsStore(3, 6, map[string]string{"zone": "z1"})
+	tc.AddLabelsStore(4, 16, map[string]string{"zone": "z2"})
+	tc.AddLabelsStore(5, 0, map[string]string{"zone": "z1"})
+	tc.AddLabelsStore(6, 2, map[string]string{"zone": "z2"})
+	tc.AddLeaderRegion(1, 1, 2, 4)
+	testutil.CheckTransferPeer(c, sb.Schedule(tc)[0], operator.OpBalance, 4, 5)
+
+	// Store 5 breaks the constraint which requires a replica in zone z2.
+	cfg, err := placement.ParseConfig("count(zone:z2)>=1")
+	c.Assert(err, IsNil)
+	oc.SetPlacementConfig(cfg)
+	testutil.CheckTransferPeer(c, sb.Schedule(tc)[0], operator.OpBalance, 4, 6)
+}
+
 // TestBalance2 for cornor case 1:
 // 11 regions distributed across 5 stores.
 //| region_id | leader_store | follower_store | follower_store |
@@ -598,7 +694,7 @@ func (s *testReplicaCheckerSuite) TestBasic(c *C) {
 
 	// Region has 2 peers, we need to add a new peer.
 	region := tc.GetRegion(1)
-	testutil.CheckAddPeer(c, rc.Check(region), operator.OpReplica, 4)
+	testutil.CheckAddPeer(c, rc.Check(region), operator.OpReplica|operator.OpRepair, 4)
 
 	// Disable make up replica feature.
 	opt.DisableMakeUpReplica = true
@@ -628,7 +724,10 @@ func (s *testReplicaCheckerSuite) TestBasic(c *C) {
 	// Add peer in store 3, and we have redundant replicas.
 	peer3, _ := tc.AllocPeer(3)
 	region = region.Clone(core.WithAddPeer(peer3))
-	testutil.CheckRemovePeer(c, rc.Check(region), 1)
+	op := rc.Check(region)
+	testutil.CheckRemovePeer(c, op, 1)
+	// Removing the extra replica is not a repair.
+	c.Assert(op.GetPriorityClass(), Equals, operator.BalanceClass)
 
 	// Disable remove extra replica feature.
 	opt.DisableRemoveExtraReplica = true
@@ -645,13 +744,13 @@ func (s *testReplicaCheckerSuite) TestBasic(c *C) {
 	}
 
 	region = region.Clone(core.WithDownPeers(append(region.GetDownPeers(), downPeer)))
-	testutil.CheckTransferPeer(c, rc.Check(region), operator.OpReplica, 2, 1)
+	testutil.CheckTransferPeer(c, rc.Check(region), operator.OpReplica|operator.OpRepair, 2, 1)
 	region = region.Clone(core.WithDownPeers(nil))
 	c.Assert(rc.Check(region), IsNil)
 
 	// Peer in store 3 is offline, transfer peer to store 1.
 	tc.SetStoreOffline(3)
-	testutil.CheckTransferPeer(c, rc.Check(region), operator.OpReplica, 3, 1)
+	testutil.CheckTransferPeer(c, rc.Check(region), operator.OpReplica|operator.OpRepair, 3, 1)
 }
 
 func (s *testReplicaCheckerSuite) TestLostStore(c *C) {
@@ -672,6 +771,39 @@ func (s *testReplicaCheckerSuite) TestLostStore(c *C) {
 	c.Assert(op, IsNil)
 }
 
+func (s *testReplicaCheckerSuite) TestMaintenance(c *C) {
+	opt := mockoption.NewScheduleOptions()
+	tc := mockcluster.NewCluster(opt)
+	rc := checker.NewReplicaChecker(tc, namespace.DefaultClassifier)
+
+	tc.AddRegionStore(1, 100)
+	tc.AddRegionStore(2, 100)
+	tc.AddRegionStore(3, 100)
+	tc.AddRegionStore(4, 100)
+	tc.AddLeaderRegion(1, 1, 2, 3)
+
+	region := tc.GetRegion(1)
+	tc.SetStoreDown(3)
+	region = region.Clone(core.WithDownPeers([]*pdpb.PeerStats{
+		{
+			Peer:        region.GetStorePeer(3),
+			DownSeconds: 24 * 60 * 60,
+		},
+	}))
+	testutil.CheckTransferPeer(c, rc.Check(region), operator.OpReplica, 3, 4)
+
+	// The down peer on the store under maintenance is not repaired.
+	tc.SetStoreMaintenance(3, time.Hour, false)
+	c.Assert(rc.Check(region), IsNil)
+
+	// No peer is moved to the store under maintenance.
+	tc.SetStoreMaintenance(4, time.Hour, false)
+	tc.SetStoreMaintenance(3, -time.Second, false)
+	c.Assert(rc.Check(region), IsNil)
+	tc.SetStoreMaintenance(4, -time.Second, false)
+	testutil.CheckTransferPeer(c, rc.Check(region), operator.OpReplica, 3, 4)
+}
+
 func (s *testReplicaCheckerSuite) TestOffline(c *C) {
 	opt := mockoption.NewScheduleOptions()
 	tc := mockcluster.NewCluster(opt)
@@ -900,6 +1032,132 @@ func (s *testReplicaCheckerSuite) TestOpts(c *C) {
 	c.Assert(rc.Check(region), IsNil)
 }
 
+func (s *testReplicaCheckerSuite) TestRule(c *C) {
+	opt := mockoption.NewScheduleOptions()
+	tc := mockcluster.NewCluster(opt)
+	newTestReplication(opt, 3, "zone", "host")
+	rc := checker.NewReplicaChecker(tc, namespace.DefaultClassifier)
+
+	tc.AddLabelsStore(1, 1, map[string]string{"zone": "z1", "host": "h1", "disk": "ssd"})
+	tc.AddLabelsStore(2, 1, map[string]string{"zone": "z1", "host": "h2", "disk": "ssd"})
+	tc.AddLabelsStore(3, 1, map[string]string{"zone": "z2", "host": "h3", "disk": "ssd"})
+	tc.AddLabelsStore(4, 1, map[string]string{"zone": "z2", "host": "h4", "disk": "ssd"})
+	tc.AddLabelsStore(5, 1, map[string]string{"zone": "z3", "host": "h5", "disk": "ssd"})
+	tc.AddLabelsStore(6, 1, map[string]string{"zone": "z3", "host": "h6", "disk": "hdd"})
+
+	// The rule only covers the range [a, b).
+	r := &rule.Rule{ID: "t1", StartKeyHex: "61", EndKeyHex: "62", Voters: 5, LocationLabels: []string{"zone", "host"}}
+	c.Assert(tc.RuleManager.SetRule(r), IsNil)
+	tc.AddLeaderRegionWithRange(1, "a", "b", 1, 3, 5)
+	op := rc.Check(tc.GetRegion(1))
+	c.Assert(op, NotNil)
+	c.Assert(op.Desc(), Equals, "make-up-replica")
+	// The other regions still follow the max replicas.
+	tc.AddLeaderRegionWithRange(2, "c", "d", 1, 3, 5)
+	c.Assert(rc.Check(tc.GetRegion(2)), IsNil)
+	tc.AddLeaderRegionWithRange(2, "c", "d", 1, 2, 3, 5)
+	c.Assert(rc.Check(tc.GetRegion(2)).Desc(), Equals, "remove-extra-replica")
+
+	// The replica on the hdd store should be moved to a ssd store.
+	r.LabelConstraints = []rule.LabelConstraint{{Key: "disk", Op: rule.In, Values: []string{"ssd"}}}
+	c.Assert(tc.RuleManager.SetRule(r), IsNil)
+	tc.AddLeaderRegionWithRange(1, "a", "b", 1, 2, 3, 5, 6)
+	op = rc.Check(tc.GetRegion(1))
+	testutil.CheckTransferPeer(c, op, operator.OpReplica, 6, 4)
+	c.Assert(op.Desc(), Equals, "replace-misplaced-replica")
+
+	// The replicas should be isolated at the zone level.
+	r.Voters, r.IsolationLevel = 3, "zone"
+	c.Assert(tc.RuleManager.SetRule(r), IsNil)
+	tc.AddLeaderRegionWithRange(1, "a", "b", 1, 2, 5)
+	op = rc.Check(tc.GetRegion(1))
+	c.Assert(op, NotNil)
+	c.Assert(op.Desc(), Equals, "replace-misplaced-replica")
+	c.Assert(op.Step(0).(operator.AddLearner).ToStore, Not(Equals), uint64(6))
+	tc.AddLeaderRegionWithRange(1, "a", "b", 1, 3, 5)
+	c.Assert(rc.Check(tc.GetRegion(1)), IsNil)
+
+	// Make up the learner required by the rule.
+	r.Learners, r.IsolationLevel, r.LabelConstraints = 1, "", nil
+	c.Assert(tc.RuleManager.SetRule(r), IsNil)
+	op = rc.Check(tc.GetRegion(1))
+	c.Assert(op, NotNil)
+	c.Assert(op.Desc(), Equals, "make-up-learner")
+	c.Assert(op.Len(), Equals, 1)
+	c.Assert(op.Step(0), FitsTypeOf, operator.AddLearner{})
+	learner, _ := tc.AllocPeer(op.Step(0).(operator.AddLearner).ToStore)
+	learner.IsLearner = true
+	region := tc.GetRegion(1).Clone(core.WithAddPeer(learner))
+	tc.PutRegion(region)
+	c.Assert(rc.Check(region), IsNil)
+
+	// Remove the learner which is not required.
+	r.Learners = 0
+	c.Assert(tc.RuleManager.SetRule(r), IsNil)
+	testutil.CheckRemovePeer(c, rc.Check(region), learner.GetStoreId())
+	c.Assert(tc.RuleManager.DeleteRule("t1"), IsNil)
+	testutil.CheckRemovePeer(c, rc.Check(region), learner.GetStoreId())
+}
+
+func (s *testReplicaCheckerSuite) TestRuleIsolationLimit(c *C) {
+	opt := mockoption.NewScheduleOptions()
+	tc := mockcluster.NewCluster(opt)
+	newTestReplication(opt, 3, "zone", "host")
+	rc := checker.NewReplicaChecker(tc, namespace.DefaultClassifier)
+
+	for id := uint64(1); id <= 6; id++ {
+		tc.AddLabelsStore(id, 1, map[string]string{"zone": fmt.Sprintf("z%d", (id+1)/2), "host": fmt.Sprintf("h%d", id)})
+	}
+
+	// 5 voters over 3 zones, a zone is allowed to have 2 voters.
+	r := &rule.Rule{ID: "t1", StartKeyHex: "61", EndKeyHex: "62", Voters: 5, LocationLabels: []string{"zone", "host"}, IsolationLevel: "zone"}
+	c.Assert(tc.RuleManager.SetRule(r), IsNil)
+	tc.AddLeaderRegionWithRange(1, "a", "b", 1, 3, 5)
+	op := rc.Check(tc.GetRegion(1))
+	c.Assert(op, NotNil)
+	c.Assert(op.Desc(), Equals, "make-up-replica")
+	tc.AddLeaderRegionWithRange(1, "a", "b", 1, 2, 3, 5)
+	op = rc.Check(tc.GetRegion(1))
+	c.Assert(op, NotNil)
+	c.Assert(op.Desc(), Equals, "make-up-replica")
+	c.Assert(op.Step(0).(operator.AddLearner).ToStore, Not(Equals), uint64(1))
+	c.Assert(op.Step(0).(operator.AddLearner).ToStore, Not(Equals), uint64(2))
+	tc.AddLeaderRegionWithRange(1, "a", "b", 1, 2, 3, 4, 5)
+	c.Assert(rc.Check(tc.GetRegion(1)), IsNil)
+
+	// The third voter in a zone is misplaced.
+	tc.AddLabelsStore(7, 1, map[string]string{"zone": "z1", "host": "h7"})
+	tc.AddLeaderRegionWithRange(1, "a", "b", 1, 2, 7, 3, 5)
+	op = rc.Check(tc.GetRegion(1))
+	c.Assert(op, NotNil)
+	c.Assert(op.Desc(), Equals, "replace-misplaced-replica")
+	c.Assert(tc.GetStore(op.Step(0).(operator.AddLearner).ToStore).GetLabelValue("zone"), Not(Equals), "z1")
+}
+
+func (s *testReplicaCheckerSuite) TestPlacementConstraints(c *C) {
+	opt := mockoption.NewScheduleOptions()
+	tc := mockcluster.NewCluster(opt)
+	newTestReplication(opt, 3, "zone", "host")
+	oc := schedule.NewOperatorController(tc, nil)
+	rc := checker.NewReplicaChecker(tc, namespace.DefaultClassifier)
+	rc.SetOperatorController(oc)
+
+	tc.AddLabelsStore(1, 1, map[string]string{"zone": "z1", "host": "h1"})
+	tc.AddLabelsStore(2, 1, map[string]string{"zone": "z1", "host": "h2"})
+	tc.AddLabelsStore(3, 1, map[string]string{"zone": "z2", "host": "h3"})
+	tc.AddLabelsStore(4, 1, map[string]string{"zone": "z3", "host": "h4"})
+	tc.AddLeaderRegion(1, 1, 2, 3)
+	op := rc.Check(tc.GetRegion(1))
+	c.Assert(op, NotNil)
+	c.Assert(op.Step(0).(operator.AddLearner).ToStore, Equals, uint64(4))
+
+	// Moving a replica out of zone z1 breaks the constraint.
+	cfg, err := placement.ParseConfig("count(zone:z1)>=2")
+	c.Assert(err, IsNil)
+	oc.SetPlacementConfig(cfg)
+	c.Assert(rc.Check(tc.GetRegion(1)), IsNil)
+}
+
 var _ = Suite(&testRandomMergeSchedulerSuite{})
 
 type testRandomMergeSchedulerSuite struct{}
@@ -1039,6 +1297,50 @@ func (s *testBalanceHotWriteRegionSchedulerSuite) TestBalance(c *C) {
 	hb.Schedule(tc)
 }
 
+func (s *testBalanceHotWriteRegionSchedulerSuite) TestRule(c *C) {
+	statistics.Denoising = false
+	opt := mockoption.NewScheduleOptions()
+	newTestReplication(opt, 3, "zone", "host")
+	opt.HotRegionCacheHitsThreshold = 0
+	tc := mockcluster.NewCluster(opt)
+	hb, err := schedule.CreateScheduler("hot-write-region", schedule.NewOperatorController(nil, nil))
+	c.Assert(err, IsNil)
+
+	tc.AddLabelsStore(1, 3, map[string]string{"zone": "z1", "host": "h1", "disk": "ssd"})
+	tc.AddLabelsStore(2, 2, map[string]string{"zone": "z2", "host": "h2", "disk": "ssd"})
+	tc.AddLabelsStore(3, 2, map[string]string{"zone": "z3", "host": "h3", "disk": "ssd"})
+	tc.AddLabelsStore(4, 2, map[string]string{"zone": "z4", "host": "h4", "disk": "ssd"})
+	tc.AddLabelsStore(5, 0, map[string]string{"zone": "z2", "host": "h5", "disk": "ssd"})
+	tc.AddLabelsStore(6, 0, map[string]string{"zone": "z5", "host": "h6", "disk": "hdd"})
+	tc.UpdateStorageWrittenBytes(1, 75*1024*1024)
+	tc.UpdateStorageWrittenBytes(2, 45*1024*1024)
+	tc.UpdateStorageWrittenBytes(3, 45*1024*1024)
+	tc.UpdateStorageWrittenBytes(4, 60*1024*1024)
+	tc.UpdateStorageWrittenBytes(5, 0)
+	tc.UpdateStorageWrittenBytes(6, 0)
+	tc.AddLeaderRegionWithWriteInfo(1, 1, 512*1024*statistics.RegionHeartBeatReportInterval, 2, 3)
+	tc.AddLeaderRegionWithWriteInfo(2, 1, 512*1024*statistics.RegionHeartBeatReportInterval, 3, 4)
+	tc.AddLeaderRegionWithWriteInfo(3, 1, 512*1024*statistics.RegionHeartBeatReportInterval, 2, 4)
+
+	// The rule keeps the peers on the ssd stores, so the peers can not be
+	// moved to store 6 even if it has the best distinct score.
+	c.Assert(tc.RuleManager.SetRule(&rule.Rule{
+		ID:               "ssd",
+		Voters:           3,
+		LocationLabels:   []string{"zone", "host"},
+		LabelConstraints: []rule.LabelConstraint{{Key: "disk", Op: rule.In, Values: []string{"ssd"}}},
+	}), IsNil)
+	for i := 0; i < 20; i++ {
+		for _, op := range hb.Schedule(tc) {
+			if op.Len() == 1 {
+				testutil.CheckTransferLeaderFrom(c, op, operator.OpHotRegion, 1)
+				continue
+			}
+			testutil.CheckTransferPeerWithLeaderTransfer(c, op, operator.OpHotRegion, 1, 5)
+		}
+	}
+}
+
 var _ = Suite(&testBalanceHotReadRegionSchedulerSuite{})
 
 type testBalanceHotReadRegionSchedulerSuite struct{}
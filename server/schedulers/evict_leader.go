@@ -34,27 +34,55 @@ func init() {
 		if err != nil {
 			return nil, errors.WithStack(err)
 		}
-		return newEvictLeaderScheduler(opController, id), nil
+		return newEvictLeaderScheduler(opController, "evict-leader", id), nil
+	})
+
+	// evict-slow-store is added and removed by PD automatically when a store
+	// becomes slow and recovers.
+	schedule.RegisterPDManagedScheduler("evict-slow-store", func(opController *schedule.OperatorController, args []string) (schedule.Scheduler, error) {
+		if len(args) != 1 {
+			return nil, errors.New("evict-slow-store needs 1 argument")
+		}
+		id, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return newEvictLeaderScheduler(opController, "evict-slow-store", id), nil
+	})
+
+	// evict-maintenance-store is added and removed by PD automatically when a
+	// store enters and leaves the maintenance which evicts the leaders.
+	schedule.RegisterPDManagedScheduler("evict-maintenance-store", func(opController *schedule.OperatorController, args []string) (schedule.Scheduler, error) {
+		if len(args) != 1 {
+			return nil, errors.New("evict-maintenance-store needs 1 argument")
+		}
+		id, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return newEvictLeaderScheduler(opController, "evict-maintenance-store", id), nil
 	})
 }
 
 type evictLeaderScheduler struct {
 	*baseScheduler
 	name     string
+	tp       string
 	storeID  uint64
 	selector *selector.RandomSelector
 }
 
 // newEvictLeaderScheduler creates an admin scheduler that transfers all leaders
 // out of a store.
-func newEvictLeaderScheduler(opController *schedule.OperatorController, storeID uint64) schedule.Scheduler {
+func newEvictLeaderScheduler(opController *schedule.OperatorController, tp string, storeID uint64) schedule.Scheduler {
 	filters := []filter.Filter{
 		filter.StoreStateFilter{TransferLeader: true},
 	}
 	base := newBaseScheduler(opController)
 	return &evictLeaderScheduler{
 		baseScheduler: base,
-		name:          fmt.Sprintf("evict-leader-scheduler-%d", storeID),
+		name:          fmt.Sprintf("%s-scheduler-%d", tp, storeID),
+		tp:            tp,
 		storeID:       storeID,
 		selector:      selector.NewRandomSelector(filters),
 	}
@@ -65,7 +93,7 @@ func (s *evictLeaderScheduler) GetName() string {
 }
 
 func (s *evictLeaderScheduler) GetType() string {
-	return "evict-leader"
+	return s.tp
 }
 
 func (s *evictLeaderScheduler) Prepare(cluster schedule.Cluster) error {
@@ -81,19 +109,19 @@ func (s *evictLeaderScheduler) IsScheduleAllowed(cluster schedule.Cluster) bool
 }
 
 func (s *evictLeaderScheduler) Schedule(cluster schedule.Cluster) []*operator.Operator {
-	schedulerCounter.WithLabelValues(s.GetName(), "schedule").Inc()
+	schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "schedule").Inc()
 	region := cluster.RandLeaderRegion(s.storeID, core.HealthRegion())
 	if region == nil {
-		schedulerCounter.WithLabelValues(s.GetName(), "no_leader").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "no_leader").Inc()
 		return nil
 	}
 	target := s.selector.SelectTarget(cluster, cluster.GetFollowerStores(region))
 	if target == nil {
-		schedulerCounter.WithLabelValues(s.GetName(), "no_target_store").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "no_target_store").Inc()
 		return nil
 	}
-	schedulerCounter.WithLabelValues(s.GetName(), "new_operator").Inc()
-	op := operator.CreateTransferLeaderOperator("evict-leader", region, region.GetLeader().GetStoreId(), target.GetID(), operator.OpLeader)
+	schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "new_operator").Inc()
+	op := operator.CreateTransferLeaderOperator(s.tp, region, region.GetLeader().GetStoreId(), target.GetID(), operator.OpLeader)
 	op.SetPriorityLevel(core.HighPriority)
 	return []*operator.Operator{op}
 }
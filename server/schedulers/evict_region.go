@@ -0,0 +1,215 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedulers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/pkg/typeutil"
+	"github.com/pingcap/pd/server/checker"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/schedule"
+	"github.com/pingcap/pd/server/schedule/filter"
+	"github.com/pingcap/pd/server/schedule/operator"
+	"github.com/pingcap/pd/server/schedule/storelimit"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	schedule.RegisterScheduler("evict-region", func(opController *schedule.OperatorController, args []string) (schedule.Scheduler, error) {
+		if len(args) == 0 {
+			return nil, errors.New("evict-region needs at least 1 argument")
+		}
+		storeIDs := make([]uint64, 0, len(args))
+		for _, arg := range args {
+			id, err := strconv.ParseUint(arg, 10, 64)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			storeIDs = append(storeIDs, id)
+		}
+		return newEvictRegionScheduler(opController, storeIDs), nil
+	})
+}
+
+// evictRegionRetryLimit is the limit to retry schedule for each evicted store.
+const evictRegionRetryLimit = 10
+
+type evictRegionScheduler struct {
+	*baseScheduler
+	name     string
+	storeIDs []uint64
+	excluded map[uint64]struct{}
+	// The start time and the region count of the stores when the scheduler is
+	// prepared, they are used to estimate the remaining time.
+	startTime      time.Time
+	initialRegions int
+}
+
+// newEvictRegionScheduler creates an admin scheduler that moves all peers out
+// of the stores while keeping the stores up. It marks the stores as evicting
+// regions to prevent other schedulers from moving regions back.
+func newEvictRegionScheduler(opController *schedule.OperatorController, storeIDs []uint64) schedule.Scheduler {
+	ids := make([]string, 0, len(storeIDs))
+	excluded := make(map[uint64]struct{}, len(storeIDs))
+	for _, id := range storeIDs {
+		ids = append(ids, strconv.FormatUint(id, 10))
+		excluded[id] = struct{}{}
+	}
+	base := newBaseScheduler(opController)
+	return &evictRegionScheduler{
+		baseScheduler: base,
+		name:          fmt.Sprintf("evict-region-scheduler-%s", strings.Join(ids, "-")),
+		storeIDs:      storeIDs,
+		excluded:      excluded,
+	}
+}
+
+func (s *evictRegionScheduler) GetName() string {
+	return s.name
+}
+
+func (s *evictRegionScheduler) GetType() string {
+	return "evict-region"
+}
+
+func (s *evictRegionScheduler) Prepare(cluster schedule.Cluster) error {
+	for i, id := range s.storeIDs {
+		if err := cluster.EvictRegions(id); err != nil {
+			for _, evicting := range s.storeIDs[:i] {
+				cluster.UnEvictRegions(evicting)
+			}
+			return err
+		}
+	}
+	s.startTime = time.Now()
+	s.initialRegions, _ = s.remaining(cluster)
+	return nil
+}
+
+func (s *evictRegionScheduler) Cleanup(cluster schedule.Cluster) {
+	for _, id := range s.storeIDs {
+		cluster.UnEvictRegions(id)
+	}
+}
+
+func (s *evictRegionScheduler) IsScheduleAllowed(cluster schedule.Cluster) bool {
+	return s.opController.OperatorCount(operator.OpRegion) < cluster.GetRegionScheduleLimit()
+}
+
+func (s *evictRegionScheduler) Schedule(cluster schedule.Cluster) []*operator.Operator {
+	schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "schedule").Inc()
+	for _, storeID := range s.storeIDs {
+		for i := 0; i < evictRegionRetryLimit; i++ {
+			region := cluster.RandFollowerRegion(storeID, core.HealthRegionAllowPending())
+			if region == nil {
+				region = cluster.RandLeaderRegion(storeID, core.HealthRegionAllowPending())
+			}
+			if region == nil {
+				schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "no_region").Inc()
+				break
+			}
+			if op := s.transferPeer(cluster, region, region.GetStorePeer(storeID)); op != nil {
+				schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "new_operator").Inc()
+				op.SetPriorityLevel(core.HighPriority)
+				return []*operator.Operator{op}
+			}
+		}
+	}
+	return nil
+}
+
+// transferPeer moves the peer to the best store out of the evicted stores. The
+// stores reaching the store limit are skipped by the overload filter of the
+// replica checker.
+func (s *evictRegionScheduler) transferPeer(cluster schedule.Cluster, region *core.RegionInfo, oldPeer *metapb.Peer) *operator.Operator {
+	excluded := filter.NewExcludedFilter(nil, s.excluded)
+	checker := checker.NewReplicaChecker(cluster, nil)
+	checker.SetOperatorController(s.opController)
+	storeID, _ := checker.SelectBestReplacementStore(region, oldPeer, excluded, filter.StoreStateFilter{MoveRegion: true})
+	if storeID == 0 {
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "no_target_store").Inc()
+		return nil
+	}
+	newPeer, err := cluster.AllocPeer(storeID)
+	if err != nil {
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "no_peer").Inc()
+		return nil
+	}
+	if oldPeer.GetIsLearner() {
+		return operator.CreateMoveLearnerOperator("evict-region", region, operator.OpRegion, oldPeer.GetStoreId(), newPeer.GetStoreId(), newPeer.GetId())
+	}
+	op, err := operator.CreateMovePeerOperator("evict-region", cluster, region, operator.OpRegion, oldPeer.GetStoreId(), newPeer.GetStoreId(), newPeer.GetId())
+	if err != nil {
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "create_operator_fail").Inc()
+		return nil
+	}
+	return op
+}
+
+// GetProgress reports the regions left on the evicted stores. The ETA is
+// estimated by the eviction speed so far, or by the store limit of the target
+// stores if no region has been evicted yet.
+func (s *evictRegionScheduler) GetProgress(cluster schedule.Cluster) *schedule.Progress {
+	regions, size := s.remaining(cluster)
+	progress := &schedule.Progress{
+		RemainingRegions: regions,
+		RemainingBytes:   uint64(size) << 20,
+	}
+	if regions == 0 {
+		return progress
+	}
+	if evicted := s.initialRegions - regions; evicted > 0 {
+		elapsed := time.Since(s.startTime)
+		progress.ETA = typeutil.NewDuration(elapsed * time.Duration(regions) / time.Duration(evicted))
+		return progress
+	}
+	// The rate of the store limit is the number of regions per second.
+	var rate float64
+	limits := s.opController.GetAllStoresLimit(storelimit.AddPeer)
+	for _, store := range cluster.GetStores() {
+		if _, ok := s.excluded[store.GetID()]; ok || !store.IsUp() {
+			continue
+		}
+		if limit, ok := limits[store.GetID()]; ok {
+			rate += limit
+		} else {
+			rate += cluster.GetStoreBalanceRate() / schedule.StoreBalanceBaseTime
+		}
+	}
+	if rate > 0 {
+		progress.ETA = typeutil.NewDuration(time.Duration(float64(regions) / rate * float64(time.Second)))
+	}
+	return progress
+}
+
+// remaining returns the region count and the region size in MB of the evicted
+// stores.
+func (s *evictRegionScheduler) remaining(cluster schedule.Cluster) (int, int64) {
+	var (
+		count int
+		size  int64
+	)
+	for _, id := range s.storeIDs {
+		if store := cluster.GetStore(id); store != nil {
+			count += store.GetRegionCount()
+			size += store.GetRegionSize()
+		}
+	}
+	return count, size
+}
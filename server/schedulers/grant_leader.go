@@ -74,13 +74,13 @@ func (s *grantLeaderScheduler) IsScheduleAllowed(cluster schedule.Cluster) bool
 }
 
 func (s *grantLeaderScheduler) Schedule(cluster schedule.Cluster) []*operator.Operator {
-	schedulerCounter.WithLabelValues(s.GetName(), "schedule").Inc()
+	schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "schedule").Inc()
 	region := cluster.RandFollowerRegion(s.storeID, core.HealthRegion())
 	if region == nil {
-		schedulerCounter.WithLabelValues(s.GetName(), "no_follower").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "no_follower").Inc()
 		return nil
 	}
-	schedulerCounter.WithLabelValues(s.GetName(), "new_operator").Inc()
+	schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "new_operator").Inc()
 	op := operator.CreateTransferLeaderOperator("grant-leader", region, region.GetLeader().GetStoreId(), s.storeID, operator.OpLeader)
 	op.SetPriorityLevel(core.HighPriority)
 	return []*operator.Operator{op}
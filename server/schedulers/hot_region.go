@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/pd/server/schedule"
 	"github.com/pingcap/pd/server/schedule/filter"
 	"github.com/pingcap/pd/server/schedule/operator"
+	"github.com/pingcap/pd/server/schedule/opt"
 	"github.com/pingcap/pd/server/statistics"
 	"go.uber.org/zap"
 )
@@ -140,7 +141,7 @@ func (h *balanceHotRegionsScheduler) allowBalanceRegion(cluster schedule.Cluster
 }
 
 func (h *balanceHotRegionsScheduler) Schedule(cluster schedule.Cluster) []*operator.Operator {
-	schedulerCounter.WithLabelValues(h.GetName(), "schedule").Inc()
+	schedule.Counter(cluster, schedulerCounter).WithLabelValues(h.GetName(), "schedule").Inc()
 	return h.dispatch(h.types[h.r.Int()%len(h.types)], cluster)
 }
 
@@ -163,7 +164,7 @@ func (h *balanceHotRegionsScheduler) balanceHotReadRegions(cluster schedule.Clus
 	// balance by leader
 	srcRegion, newLeader := h.balanceByLeader(cluster, h.stats.readStatAsLeader)
 	if srcRegion != nil {
-		schedulerCounter.WithLabelValues(h.GetName(), "move_leader").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(h.GetName(), "move_leader").Inc()
 		op := operator.CreateTransferLeaderOperator("transfer-hot-read-leader", srcRegion, srcRegion.GetLeader().GetStoreId(), newLeader.GetStoreId(), operator.OpHotRegion)
 		op.SetPriorityLevel(core.HighPriority)
 		return []*operator.Operator{op}
@@ -174,14 +175,14 @@ func (h *balanceHotRegionsScheduler) balanceHotReadRegions(cluster schedule.Clus
 	if srcRegion != nil {
 		op, err := operator.CreateMovePeerOperator("move-hot-read-region", cluster, srcRegion, operator.OpHotRegion, srcPeer.GetStoreId(), destPeer.GetStoreId(), destPeer.GetId())
 		if err != nil {
-			schedulerCounter.WithLabelValues(h.GetName(), "create_operator_fail").Inc()
+			schedule.Counter(cluster, schedulerCounter).WithLabelValues(h.GetName(), "create_operator_fail").Inc()
 			return nil
 		}
 		op.SetPriorityLevel(core.HighPriority)
-		schedulerCounter.WithLabelValues(h.GetName(), "move_peer").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(h.GetName(), "move_peer").Inc()
 		return []*operator.Operator{op}
 	}
-	schedulerCounter.WithLabelValues(h.GetName(), "skip").Inc()
+	schedule.Counter(cluster, schedulerCounter).WithLabelValues(h.GetName(), "skip").Inc()
 	return nil
 }
 
@@ -197,18 +198,18 @@ func (h *balanceHotRegionsScheduler) balanceHotWriteRegions(cluster schedule.Clu
 			if srcRegion != nil {
 				op, err := operator.CreateMovePeerOperator("move-hot-write-region", cluster, srcRegion, operator.OpHotRegion, srcPeer.GetStoreId(), destPeer.GetStoreId(), destPeer.GetId())
 				if err != nil {
-					schedulerCounter.WithLabelValues(h.GetName(), "create_operator_fail").Inc()
+					schedule.Counter(cluster, schedulerCounter).WithLabelValues(h.GetName(), "create_operator_fail").Inc()
 					return nil
 				}
 				op.SetPriorityLevel(core.HighPriority)
-				schedulerCounter.WithLabelValues(h.GetName(), "move_peer").Inc()
+				schedule.Counter(cluster, schedulerCounter).WithLabelValues(h.GetName(), "move_peer").Inc()
 				return []*operator.Operator{op}
 			}
 		case 1:
 			// balance by leader
 			srcRegion, newLeader := h.balanceByLeader(cluster, h.stats.writeStatAsLeader)
 			if srcRegion != nil {
-				schedulerCounter.WithLabelValues(h.GetName(), "move_leader").Inc()
+				schedule.Counter(cluster, schedulerCounter).WithLabelValues(h.GetName(), "move_leader").Inc()
 				op := operator.CreateTransferLeaderOperator("transfer-hot-write-leader", srcRegion, srcRegion.GetLeader().GetStoreId(), newLeader.GetStoreId(), operator.OpHotRegion)
 				op.SetPriorityLevel(core.HighPriority)
 				return []*operator.Operator{op}
@@ -216,7 +217,7 @@ func (h *balanceHotRegionsScheduler) balanceHotWriteRegions(cluster schedule.Clu
 		}
 	}
 
-	schedulerCounter.WithLabelValues(h.GetName(), "skip").Inc()
+	schedule.Counter(cluster, schedulerCounter).WithLabelValues(h.GetName(), "skip").Inc()
 	return nil
 }
 
@@ -251,6 +252,7 @@ func calcScore(storeItems map[uint64][]*statistics.HotSpotPeerStat, cluster sche
 			s := statistics.HotSpotPeerStat{
 				RegionID:       r.RegionID,
 				FlowBytes:      uint64(r.Stats.Median()),
+				FlowKeys:       uint64(r.KeysStats.Median()),
 				HotDegree:      r.HotDegree,
 				LastUpdateTime: r.LastUpdateTime,
 				StoreID:        storeID,
@@ -258,6 +260,7 @@ func calcScore(storeItems map[uint64][]*statistics.HotSpotPeerStat, cluster sche
 				Version:        r.Version,
 			}
 			storeStat.TotalFlowBytes += r.FlowBytes
+			storeStat.TotalFlowKeys += r.FlowKeys
 			storeStat.RegionsCount++
 			storeStat.RegionsStat = append(storeStat.RegionsStat, s)
 		}
@@ -271,7 +274,8 @@ func (h *balanceHotRegionsScheduler) balanceByPeer(cluster schedule.Cluster, sto
 		return nil, nil, nil
 	}
 
-	srcStoreID := h.selectSrcStore(storesStat)
+	dim := cluster.GetHotRegionBalanceDimension()
+	srcStoreID := h.selectSrcStore(storesStat, dim)
 	if srcStoreID == 0 {
 		return nil, nil, nil
 	}
@@ -285,26 +289,30 @@ func (h *balanceHotRegionsScheduler) balanceByPeer(cluster schedule.Cluster, sto
 		rs := storesStat[srcStoreID].RegionsStat[i]
 		srcRegion := cluster.GetRegion(rs.RegionID)
 		if srcRegion == nil {
-			schedulerCounter.WithLabelValues(h.GetName(), "no_region").Inc()
+			schedule.Counter(cluster, schedulerCounter).WithLabelValues(h.GetName(), "no_region").Inc()
 			continue
 		}
 
 		if isRegionUnhealthy(srcRegion) {
-			schedulerCounter.WithLabelValues(h.GetName(), "unhealthy_replica").Inc()
+			schedule.Counter(cluster, schedulerCounter).WithLabelValues(h.GetName(), "unhealthy_replica").Inc()
 			continue
 		}
 
-		if len(srcRegion.GetPeers()) != cluster.GetMaxReplicas() {
+		rule := cluster.GetRegionRule(srcRegion)
+		if len(srcRegion.GetPeers()) != rule.GetReplicas() {
 			log.Debug("region has abnormal replica count", zap.String("scheduler", h.GetName()), zap.Uint64("region-id", srcRegion.GetID()))
-			schedulerCounter.WithLabelValues(h.GetName(), "abnormal_replica").Inc()
+			schedule.Counter(cluster, schedulerCounter).WithLabelValues(h.GetName(), "abnormal_replica").Inc()
 			continue
 		}
 
 		srcStore := cluster.GetStore(srcStoreID)
+		regionStores := cluster.GetRegionStores(srcRegion)
 		filters := []filter.Filter{
 			filter.StoreStateFilter{MoveRegion: true},
 			filter.NewExcludedFilter(srcRegion.GetStoreIds(), srcRegion.GetStoreIds()),
-			filter.NewDistinctScoreFilter(cluster.GetLocationLabels(), cluster.GetRegionStores(srcRegion), srcStore),
+			filter.NewDistinctScoreFilter(rule.LocationLabels, regionStores, srcStore),
+			filter.NewRuleFilter(rule, otherStores(regionStores, srcStoreID), stores),
+			filter.NewPlacementFilter(h.opController.GetPlacementConfig(), cluster, srcRegion, srcStore),
 		}
 		candidateStoreIDs := make([]uint64, 0, len(stores))
 		for _, store := range stores {
@@ -314,7 +322,7 @@ func (h *balanceHotRegionsScheduler) balanceByPeer(cluster schedule.Cluster, sto
 			candidateStoreIDs = append(candidateStoreIDs, store.GetID())
 		}
 
-		destStoreID = h.selectDestStore(candidateStoreIDs, rs.FlowBytes, srcStoreID, storesStat)
+		destStoreID = h.selectDestStore(candidateStoreIDs, rs, srcStoreID, storesStat, dim)
 		if destStoreID != 0 {
 			h.peerLimit = h.adjustBalanceLimit(srcStoreID, storesStat)
 
@@ -344,7 +352,8 @@ func (h *balanceHotRegionsScheduler) balanceByLeader(cluster schedule.Cluster, s
 		return nil, nil
 	}
 
-	srcStoreID := h.selectSrcStore(storesStat)
+	dim := cluster.GetHotRegionBalanceDimension()
+	srcStoreID := h.selectSrcStore(storesStat, dim)
 	if srcStoreID == 0 {
 		return nil, nil
 	}
@@ -354,12 +363,12 @@ func (h *balanceHotRegionsScheduler) balanceByLeader(cluster schedule.Cluster, s
 		rs := storesStat[srcStoreID].RegionsStat[i]
 		srcRegion := cluster.GetRegion(rs.RegionID)
 		if srcRegion == nil {
-			schedulerCounter.WithLabelValues(h.GetName(), "no_region").Inc()
+			schedule.Counter(cluster, schedulerCounter).WithLabelValues(h.GetName(), "no_region").Inc()
 			continue
 		}
 
 		if isRegionUnhealthy(srcRegion) {
-			schedulerCounter.WithLabelValues(h.GetName(), "unhealthy_replica").Inc()
+			schedule.Counter(cluster, schedulerCounter).WithLabelValues(h.GetName(), "unhealthy_replica").Inc()
 			continue
 		}
 
@@ -373,7 +382,7 @@ func (h *balanceHotRegionsScheduler) balanceByLeader(cluster schedule.Cluster, s
 		if len(candidateStoreIDs) == 0 {
 			continue
 		}
-		destStoreID := h.selectDestStore(candidateStoreIDs, rs.FlowBytes, srcStoreID, storesStat)
+		destStoreID := h.selectDestStore(candidateStoreIDs, rs, srcStoreID, storesStat, dim)
 		if destStoreID == 0 {
 			continue
 		}
@@ -390,18 +399,26 @@ func (h *balanceHotRegionsScheduler) balanceByLeader(cluster schedule.Cluster, s
 
 // Select the store to move hot regions from.
 // We choose the store with the maximum number of hot region first.
-// Inside these stores, we choose the one with maximum flow bytes.
-func (h *balanceHotRegionsScheduler) selectSrcStore(stats statistics.StoreHotRegionsStat) (srcStoreID uint64) {
+// Inside these stores, we choose the one with maximum flow on the primary
+// dimension, then the one with maximum flow on the secondary dimension.
+func (h *balanceHotRegionsScheduler) selectSrcStore(stats statistics.StoreHotRegionsStat, dim string) (srcStoreID uint64) {
 	var (
-		maxFlowBytes           uint64
+		maxPrimaryFlow         uint64
+		maxSecondaryFlow       uint64
 		maxHotStoreRegionCount int
 	)
 
 	for storeID, statistics := range stats {
-		count, flowBytes := statistics.RegionsStat.Len(), statistics.TotalFlowBytes
-		if count >= 2 && (count > maxHotStoreRegionCount || (count == maxHotStoreRegionCount && flowBytes > maxFlowBytes)) {
+		count := statistics.RegionsStat.Len()
+		if count < 2 {
+			continue
+		}
+		primary, secondary := storeFlows(statistics, dim)
+		if count > maxHotStoreRegionCount ||
+			(count == maxHotStoreRegionCount && primary > maxPrimaryFlow) ||
+			(count == maxHotStoreRegionCount && primary == maxPrimaryFlow && secondary > maxSecondaryFlow) {
 			maxHotStoreRegionCount = count
-			maxFlowBytes = flowBytes
+			maxPrimaryFlow, maxSecondaryFlow = primary, secondary
 			srcStoreID = storeID
 		}
 	}
@@ -409,27 +426,34 @@ func (h *balanceHotRegionsScheduler) selectSrcStore(stats statistics.StoreHotReg
 }
 
 // selectDestStore selects a target store to hold the region of the source region.
-// We choose a target store based on the hot region number and flow bytes of this store.
-func (h *balanceHotRegionsScheduler) selectDestStore(candidateStoreIDs []uint64, regionFlowBytes uint64, srcStoreID uint64, storesStat statistics.StoreHotRegionsStat) (destStoreID uint64) {
+// We choose a target store based on the hot region number and the flow on the
+// primary dimension of this store. A store is never chosen if moving the region
+// makes it hotter than the source store on the secondary dimension.
+func (h *balanceHotRegionsScheduler) selectDestStore(candidateStoreIDs []uint64, rs statistics.HotSpotPeerStat, srcStoreID uint64, storesStat statistics.StoreHotRegionsStat, dim string) (destStoreID uint64) {
 	sr := storesStat[srcStoreID]
-	srcFlowBytes := sr.TotalFlowBytes
+	srcPrimaryFlow, srcSecondaryFlow := storeFlows(sr, dim)
+	regionPrimaryFlow, regionSecondaryFlow := regionFlows(rs, dim)
 	srcHotRegionsCount := sr.RegionsStat.Len()
 
 	var (
-		minFlowBytes    uint64 = math.MaxUint64
+		minPrimaryFlow  uint64 = math.MaxUint64
 		minRegionsCount        = int(math.MaxInt32)
 	)
 	for _, storeID := range candidateStoreIDs {
 		if s, ok := storesStat[storeID]; ok {
+			primary, secondary := storeFlows(s, dim)
+			if secondary+regionSecondaryFlow > srcSecondaryFlow {
+				continue
+			}
 			if srcHotRegionsCount-s.RegionsStat.Len() > 1 && minRegionsCount > s.RegionsStat.Len() {
 				destStoreID = storeID
-				minFlowBytes = s.TotalFlowBytes
+				minPrimaryFlow = primary
 				minRegionsCount = s.RegionsStat.Len()
 				continue
 			}
-			if minRegionsCount == s.RegionsStat.Len() && minFlowBytes > s.TotalFlowBytes &&
-				uint64(float64(srcFlowBytes)*hotRegionScheduleFactor) > s.TotalFlowBytes+2*regionFlowBytes {
-				minFlowBytes = s.TotalFlowBytes
+			if minRegionsCount == s.RegionsStat.Len() && minPrimaryFlow > primary &&
+				uint64(float64(srcPrimaryFlow)*hotRegionScheduleFactor) > primary+2*regionPrimaryFlow {
+				minPrimaryFlow = primary
 				destStoreID = storeID
 			}
 		} else {
@@ -440,6 +464,24 @@ func (h *balanceHotRegionsScheduler) selectDestStore(candidateStoreIDs []uint64,
 	return
 }
 
+// storeFlows returns the total flow of the hot regions of a store on the
+// primary dimension and the secondary dimension.
+func storeFlows(stat *statistics.HotRegionsStat, dim string) (primary uint64, secondary uint64) {
+	if dim == opt.HotRegionBalanceByKeys {
+		return stat.TotalFlowKeys, stat.TotalFlowBytes
+	}
+	return stat.TotalFlowBytes, stat.TotalFlowKeys
+}
+
+// regionFlows returns the flow of a hot region on the primary dimension and
+// the secondary dimension.
+func regionFlows(stat statistics.HotSpotPeerStat, dim string) (primary uint64, secondary uint64) {
+	if dim == opt.HotRegionBalanceByKeys {
+		return stat.FlowKeys, stat.FlowBytes
+	}
+	return stat.FlowBytes, stat.FlowKeys
+}
+
 func (h *balanceHotRegionsScheduler) adjustBalanceLimit(storeID uint64, storesStat statistics.StoreHotRegionsStat) uint64 {
 	srcStoreStatistics := storesStat[storeID]
 
@@ -61,7 +61,7 @@ func (s *labelScheduler) IsScheduleAllowed(cluster schedule.Cluster) bool {
 }
 
 func (s *labelScheduler) Schedule(cluster schedule.Cluster) []*operator.Operator {
-	schedulerCounter.WithLabelValues(s.GetName(), "schedule").Inc()
+	schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "schedule").Inc()
 	stores := cluster.GetStores()
 	rejectLeaderStores := make(map[uint64]struct{})
 	for _, s := range stores {
@@ -70,7 +70,7 @@ func (s *labelScheduler) Schedule(cluster schedule.Cluster) []*operator.Operator
 		}
 	}
 	if len(rejectLeaderStores) == 0 {
-		schedulerCounter.WithLabelValues(s.GetName(), "skip").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "skip").Inc()
 		return nil
 	}
 	log.Debug("label scheduler reject leader store list", zap.Reflect("stores", rejectLeaderStores))
@@ -88,15 +88,15 @@ func (s *labelScheduler) Schedule(cluster schedule.Cluster) []*operator.Operator
 			target := s.selector.SelectTarget(cluster, cluster.GetFollowerStores(region), f)
 			if target == nil {
 				log.Debug("label scheduler no target found for region", zap.Uint64("region-id", region.GetID()))
-				schedulerCounter.WithLabelValues(s.GetName(), "no_target").Inc()
+				schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "no_target").Inc()
 				continue
 			}
 
-			schedulerCounter.WithLabelValues(s.GetName(), "new_operator").Inc()
+			schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "new_operator").Inc()
 			op := operator.CreateTransferLeaderOperator("label-reject-leader", region, id, target.GetID(), operator.OpLeader)
 			return []*operator.Operator{op}
 		}
 	}
-	schedulerCounter.WithLabelValues(s.GetName(), "no_region").Inc()
+	schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "no_region").Inc()
 	return nil
 }
@@ -63,6 +63,14 @@ var scatterRangeRegionCounter = prometheus.NewCounterVec(
 		Help:      "Counter of scatter range region scheduler.",
 	}, []string{"type", "address", "store"})
 
+var hotRegionSplitCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "pd",
+		Subsystem: "scheduler",
+		Name:      "hot_region_split",
+		Help:      "Counter of the splits triggered by load.",
+	}, []string{"type"})
+
 func init() {
 	prometheus.MustRegister(schedulerCounter)
 	prometheus.MustRegister(schedulerStatus)
@@ -70,4 +78,5 @@ func init() {
 	prometheus.MustRegister(balanceRegionCounter)
 	prometheus.MustRegister(scatterRangeLeaderCounter)
 	prometheus.MustRegister(scatterRangeRegionCounter)
+	prometheus.MustRegister(hotRegionSplitCounter)
 }
@@ -60,17 +60,17 @@ func (s *randomMergeScheduler) IsScheduleAllowed(cluster schedule.Cluster) bool
 }
 
 func (s *randomMergeScheduler) Schedule(cluster schedule.Cluster) []*operator.Operator {
-	schedulerCounter.WithLabelValues(s.GetName(), "schedule").Inc()
+	schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "schedule").Inc()
 
 	stores := cluster.GetStores()
 	store := s.selector.SelectSource(cluster, stores)
 	if store == nil {
-		schedulerCounter.WithLabelValues(s.GetName(), "no_store").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "no_store").Inc()
 		return nil
 	}
 	region := cluster.RandLeaderRegion(store.GetID(), core.HealthRegion())
 	if region == nil {
-		schedulerCounter.WithLabelValues(s.GetName(), "no_region").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "no_region").Inc()
 		return nil
 	}
 
@@ -79,7 +79,7 @@ func (s *randomMergeScheduler) Schedule(cluster schedule.Cluster) []*operator.Op
 		target = other
 	}
 	if target == nil {
-		schedulerCounter.WithLabelValues(s.GetName(), "no_adjacent").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "no_adjacent").Inc()
 		return nil
 	}
 
@@ -87,6 +87,6 @@ func (s *randomMergeScheduler) Schedule(cluster schedule.Cluster) []*operator.Op
 	if err != nil {
 		return nil
 	}
-	schedulerCounter.WithLabelValues(s.GetName(), "new_operator").Inc()
+	schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "new_operator").Inc()
 	return ops
 }
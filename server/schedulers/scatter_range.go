@@ -83,7 +83,7 @@ func (l *scatterRangeScheduler) IsScheduleAllowed(cluster schedule.Cluster) bool
 }
 
 func (l *scatterRangeScheduler) Schedule(cluster schedule.Cluster) []*operator.Operator {
-	schedulerCounter.WithLabelValues(l.GetName(), "schedule").Inc()
+	schedule.Counter(cluster, schedulerCounter).WithLabelValues(l.GetName(), "schedule").Inc()
 	// isolate a new cluster according to the key range
 	c := schedule.GenRangeCluster(cluster, l.startKey, l.endKey)
 	c.SetTolerantSizeRatio(2)
@@ -91,16 +91,16 @@ func (l *scatterRangeScheduler) Schedule(cluster schedule.Cluster) []*operator.O
 	if len(ops) > 0 {
 		ops[0].SetDesc(fmt.Sprintf("scatter-range-leader-%s", l.rangeName))
 		ops[0].AttachKind(operator.OpRange)
-		schedulerCounter.WithLabelValues(l.GetName(), "new-leader-operator").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(l.GetName(), "new-leader-operator").Inc()
 		return ops
 	}
 	ops = l.balanceRegion.Schedule(c)
 	if len(ops) > 0 {
 		ops[0].SetDesc(fmt.Sprintf("scatter-range-region-%s", l.rangeName))
 		ops[0].AttachKind(operator.OpRange)
-		schedulerCounter.WithLabelValues(l.GetName(), "new-region-operator").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(l.GetName(), "new-region-operator").Inc()
 		return ops
 	}
-	schedulerCounter.WithLabelValues(l.GetName(), "no-need").Inc()
+	schedule.Counter(cluster, schedulerCounter).WithLabelValues(l.GetName(), "no-need").Inc()
 	return nil
 }
@@ -14,6 +14,8 @@
 package schedulers
 
 import (
+	"time"
+
 	. "github.com/pingcap/check"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/pd/pkg/mock/mockcluster"
@@ -22,7 +24,9 @@ import (
 	"github.com/pingcap/pd/pkg/testutil"
 	"github.com/pingcap/pd/server/core"
 	"github.com/pingcap/pd/server/namespace"
+	"github.com/pingcap/pd/server/placement/rule"
 	"github.com/pingcap/pd/server/schedule"
+	"github.com/pingcap/pd/server/schedule/filter"
 	"github.com/pingcap/pd/server/schedule/operator"
 	"github.com/pingcap/pd/server/schedule/opt"
 	"github.com/pingcap/pd/server/statistics"
@@ -225,6 +229,38 @@ func (s *testScatterRegionSuite) scatter(c *C, numStores, numRegions uint64) {
 	}
 }
 
+func (s *testScatterRegionSuite) TestRule(c *C) {
+	opt := mockoption.NewScheduleOptions()
+	tc := mockcluster.NewCluster(opt)
+	for i := uint64(1); i <= 4; i++ {
+		tc.AddLabelsStore(i, 0, map[string]string{"disk": "ssd"})
+	}
+	tc.AddLabelsStore(5, 0, map[string]string{"disk": "hdd"})
+	tc.AddLabelsStore(6, 0, map[string]string{"disk": "hdd"})
+	c.Assert(tc.RuleManager.SetRule(&rule.Rule{
+		ID:               "ssd",
+		Voters:           3,
+		LabelConstraints: []rule.LabelConstraint{{Key: "disk", Op: rule.In, Values: []string{"ssd"}}},
+	}), IsNil)
+
+	scatterer := schedule.NewRegionScatterer(tc, namespace.DefaultClassifier)
+	for i := uint64(1); i <= 10; i++ {
+		tc.AddLeaderRegion(i, 1, 2, 3)
+		if op, _ := scatterer.Scatter(tc.GetRegion(i)); op != nil {
+			s.checkOperator(op, c)
+			schedule.ApplyOperator(tc, op)
+		}
+		for _, peer := range tc.GetRegion(i).GetPeers() {
+			c.Assert(peer.GetStoreId(), LessEqual, uint64(4))
+		}
+	}
+
+	// The region should have the number of replicas required by the rule.
+	tc.AddLeaderRegion(11, 1, 2, 3, 4)
+	_, err := scatterer.Scatter(tc.GetRegion(11))
+	c.Assert(err, NotNil)
+}
+
 func (s *testScatterRegionSuite) TestStorelimit(c *C) {
 	opt := mockoption.NewScheduleOptions()
 	tc := mockcluster.NewCluster(opt)
@@ -391,6 +427,115 @@ func (s *testHotRegionSchedulerSuite) TestAbnormalReplica(c *C) {
 	c.Assert(hb.Schedule(tc), IsNil)
 }
 
+func (s *testHotRegionSchedulerSuite) TestHotByKeys(c *C) {
+	opt := mockoption.NewScheduleOptions()
+	tc := mockcluster.NewCluster(opt)
+	tc.AddRegionStore(1, 2)
+	tc.AddRegionStore(2, 2)
+	tc.AddRegionStore(3, 2)
+
+	// Region 1 writes a few bytes with a lot of keys, region 2 writes a few
+	// bytes and a few keys.
+	tc.AddLeaderRegionWithWriteFlow(1, 1, 1024*statistics.RegionHeartBeatReportInterval, 1024*statistics.RegionHeartBeatReportInterval, 2, 3)
+	tc.AddLeaderRegionWithWriteFlow(2, 1, 1024*statistics.RegionHeartBeatReportInterval, 16*statistics.RegionHeartBeatReportInterval, 2, 3)
+	opt.HotRegionCacheHitsThreshold = 0
+	c.Assert(tc.IsRegionHot(tc.GetRegion(1)), IsTrue)
+	c.Assert(tc.IsRegionHot(tc.GetRegion(2)), IsFalse)
+	stats := tc.RegionStats(statistics.WriteFlow)
+	c.Assert(stats[1], HasLen, 1)
+	c.Assert(stats[1][0].FlowBytes, Equals, uint64(1024))
+	c.Assert(stats[1][0].FlowKeys, Equals, uint64(1024))
+
+	tc.AddLeaderRegionWithReadFlow(3, 1, 1024*statistics.RegionHeartBeatReportInterval, 1024*statistics.RegionHeartBeatReportInterval, 2, 3)
+	c.Assert(tc.IsRegionHot(tc.GetRegion(3)), IsTrue)
+}
+
+func (s *testHotRegionSchedulerSuite) TestSelectStoreByDimension(c *C) {
+	hb := newBalanceHotRegionsScheduler(schedule.NewOperatorController(nil, nil))
+	newStat := func(count int, bytes, keys uint64) *statistics.HotRegionsStat {
+		return &statistics.HotRegionsStat{
+			TotalFlowBytes: bytes,
+			TotalFlowKeys:  keys,
+			RegionsCount:   count,
+			RegionsStat:    make(statistics.RegionsStat, count),
+		}
+	}
+
+	// Store 1 has the most bytes and store 2 has the most keys.
+	stats := statistics.StoreHotRegionsStat{
+		1: newStat(5, 300, 30),
+		2: newStat(5, 30, 300),
+		3: newStat(3, 100, 100),
+		4: newStat(3, 120, 10),
+		5: newStat(3, 10, 120),
+	}
+	c.Assert(hb.selectSrcStore(stats, opt.HotRegionBalanceByBytes), Equals, uint64(1))
+	c.Assert(hb.selectSrcStore(stats, opt.HotRegionBalanceByKeys), Equals, uint64(2))
+
+	// Store 3 has as many hot regions as store 4 and 5, but it would be hotter
+	// than the source store on the secondary dimension.
+	rs := statistics.HotSpotPeerStat{FlowBytes: 50, FlowKeys: 5}
+	c.Assert(hb.selectDestStore([]uint64{3, 4}, rs, 1, stats, opt.HotRegionBalanceByBytes), Equals, uint64(4))
+	rs = statistics.HotSpotPeerStat{FlowBytes: 5, FlowKeys: 50}
+	c.Assert(hb.selectDestStore([]uint64{3, 5}, rs, 2, stats, opt.HotRegionBalanceByKeys), Equals, uint64(5))
+}
+
+var _ = Suite(&testSplitHotRegionSuite{})
+
+type testSplitHotRegionSuite struct{}
+
+func (s *testSplitHotRegionSuite) TestSplitAndScatter(c *C) {
+	opt := mockoption.NewScheduleOptions()
+	opt.HotRegionCacheHitsThreshold = 0
+	opt.HotRegionSplitBytesThreshold = 256 * 1024
+	opt.HotRegionSplitKeysThreshold = 1024
+	tc := mockcluster.NewCluster(opt)
+	oc := schedule.NewOperatorController(nil, nil)
+	sc, err := schedule.CreateScheduler("split-hot-region", oc)
+	c.Assert(err, IsNil)
+	c.Assert(sc.Prepare(tc), IsNil)
+
+	for i := uint64(1); i <= 4; i++ {
+		tc.AddRegionStore(i, 1)
+	}
+	// Region 1 is hot above the threshold, region 2 is hot but below it.
+	tc.AddLeaderRegionWithWriteInfo(1, 1, 512*1024*statistics.RegionHeartBeatReportInterval, 2, 3)
+	tc.AddLeaderRegionWithWriteInfo(2, 1, 128*1024*statistics.RegionHeartBeatReportInterval, 2, 3)
+
+	opt.SplitScheduleLimit = 0
+	c.Assert(sc.IsScheduleAllowed(tc), IsFalse)
+	opt.SplitScheduleLimit = 4
+	c.Assert(sc.IsScheduleAllowed(tc), IsTrue)
+
+	// The region is not split until it stays hot for the duration.
+	opt.HotRegionSplitDuration = time.Hour
+	c.Assert(sc.Schedule(tc), IsNil)
+	opt.HotRegionSplitDuration = 0
+	ops := sc.Schedule(tc)
+	c.Assert(ops, HasLen, 1)
+	c.Assert(ops[0].RegionID(), Equals, uint64(1))
+	c.Assert(ops[0].Kind()&operator.OpSplit, Not(Equals), operator.OpKind(0))
+	c.Assert(ops[0].Step(0), FitsTypeOf, operator.SplitRegion{})
+	// The region is not split again during the cooldown, which is the same
+	// as the duration.
+	opt.HotRegionSplitDuration = time.Hour
+	sc.(*splitHotRegionScheduler).hotSince[1] = time.Now().Add(-2 * time.Hour)
+	c.Assert(sc.Schedule(tc), IsNil)
+	sc.(*splitHotRegionScheduler).lastSplit[1] = time.Now().Add(-2 * time.Hour)
+	ops = sc.Schedule(tc)
+	c.Assert(ops, HasLen, 1)
+	c.Assert(ops[0].Step(0), FitsTypeOf, operator.SplitRegion{})
+
+	// The halves are scattered after the split is finished.
+	region := tc.GetRegion(1)
+	splitKey := string(region.GetStartKey()) + "5"
+	tc.AddLeaderRegionWithRange(1, string(region.GetStartKey()), splitKey, 1, 2, 3)
+	tc.AddLeaderRegionWithRange(10, splitKey, string(region.GetEndKey()), 1, 2, 3)
+	ops = sc.Schedule(tc)
+	c.Assert(ops, HasLen, 1)
+	c.Assert(ops[0].Desc(), Equals, "scatter-region")
+}
+
 var _ = Suite(&testEvictLeaderSuite{})
 
 type testEvictLeaderSuite struct{}
@@ -415,6 +560,62 @@ func (s *testEvictLeaderSuite) TestEvictLeader(c *C) {
 	testutil.CheckTransferLeader(c, op[0], operator.OpLeader, 1, 2)
 }
 
+var _ = Suite(&testEvictRegionSuite{})
+
+type testEvictRegionSuite struct{}
+
+func (s *testEvictRegionSuite) TestEvictRegion(c *C) {
+	opt := mockoption.NewScheduleOptions()
+	tc := mockcluster.NewCluster(opt)
+
+	// Add stores 1, 2, 3, 4
+	tc.AddRegionStore(1, 6)
+	tc.AddRegionStore(2, 6)
+	tc.AddRegionStore(3, 6)
+	tc.AddRegionStore(4, 0)
+	// Add regions 1, 2 with followers in store 1
+	tc.AddLeaderRegion(1, 2, 1, 3)
+	tc.AddLeaderRegion(2, 3, 1, 2)
+
+	sl, err := schedule.CreateScheduler("evict-region", schedule.NewOperatorController(nil, nil), "1")
+	c.Assert(err, IsNil)
+	c.Assert(sl.GetName(), Equals, "evict-region-scheduler-1")
+	// The leaders of the store can be evicted at the same time.
+	el, err := schedule.CreateScheduler("evict-leader", schedule.NewOperatorController(nil, nil), "1")
+	c.Assert(err, IsNil)
+	c.Assert(el.Prepare(tc), IsNil)
+	c.Assert(sl.Prepare(tc), IsNil)
+	c.Assert(sl.IsScheduleAllowed(tc), IsTrue)
+	// The evicted store can not receive regions, but the blocked store can.
+	c.Assert(filter.Target(tc, tc.GetStore(1), []filter.Filter{filter.StoreStateFilter{MoveRegion: true}}), IsTrue)
+	c.Assert(tc.BlockStore(4), IsNil)
+	c.Assert(filter.Target(tc, tc.GetStore(4), []filter.Filter{filter.StoreStateFilter{MoveRegion: true}}), IsFalse)
+	op := sl.Schedule(tc)
+	testutil.CheckTransferPeer(c, op[0], operator.OpRegion, 1, 4)
+	tc.UnblockStore(4)
+	el.Cleanup(tc)
+
+	reporter := sl.(schedule.ProgressReporter)
+	progress := reporter.GetProgress(tc)
+	c.Assert(progress.RemainingRegions, Equals, 6)
+	c.Assert(progress.RemainingBytes, Equals, uint64(60<<20))
+	// Stores 2, 3, 4 can receive 1 region per second.
+	c.Assert(progress.ETA.Duration, Equals, 2*time.Second)
+	tc.AddRegionStore(1, 3)
+	progress = reporter.GetProgress(tc)
+	c.Assert(progress.RemainingRegions, Equals, 3)
+	c.Assert(progress.ETA.Duration, Greater, time.Duration(0))
+
+	sl.Cleanup(tc)
+	// Store 5 does not exist.
+	sl, err = schedule.CreateScheduler("evict-region", schedule.NewOperatorController(nil, nil), "2", "5")
+	c.Assert(err, IsNil)
+	c.Assert(sl.Prepare(tc), NotNil)
+	c.Assert(tc.GetStore(2).IsEvictingRegions(), IsFalse)
+	_, err = schedule.CreateScheduler("evict-region", schedule.NewOperatorController(nil, nil))
+	c.Assert(err, NotNil)
+}
+
 var _ = Suite(&testShuffleRegionSuite{})
 
 type testShuffleRegionSuite struct{}
@@ -81,7 +81,7 @@ func (s *shuffleHotRegionScheduler) IsScheduleAllowed(cluster schedule.Cluster)
 }
 
 func (s *shuffleHotRegionScheduler) Schedule(cluster schedule.Cluster) []*operator.Operator {
-	schedulerCounter.WithLabelValues(s.GetName(), "schedule").Inc()
+	schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "schedule").Inc()
 	i := s.r.Int() % len(s.types)
 	return s.dispatch(s.types[i], cluster)
 }
@@ -109,12 +109,16 @@ func (s *shuffleHotRegionScheduler) randomSchedule(cluster schedule.Cluster, sto
 		}
 		srcStoreID := srcRegion.GetLeader().GetStoreId()
 		srcStore := cluster.GetStore(srcStoreID)
+		rule := cluster.GetRegionRule(srcRegion)
+		regionStores := cluster.GetRegionStores(srcRegion)
+		stores := cluster.GetStores()
 		filters := []filter.Filter{
 			filter.StoreStateFilter{MoveRegion: true},
 			filter.NewExcludedFilter(srcRegion.GetStoreIds(), srcRegion.GetStoreIds()),
-			filter.NewDistinctScoreFilter(cluster.GetLocationLabels(), cluster.GetRegionStores(srcRegion), srcStore),
+			filter.NewDistinctScoreFilter(rule.LocationLabels, regionStores, srcStore),
+			filter.NewRuleFilter(rule, otherStores(regionStores, srcStoreID), stores),
+			filter.NewPlacementFilter(s.opController.GetPlacementConfig(), cluster, srcRegion, srcStore),
 		}
-		stores := cluster.GetStores()
 		destStoreIDs := make([]uint64, 0, len(stores))
 		for _, store := range stores {
 			if filter.Target(cluster, store, filters) {
@@ -143,9 +147,9 @@ func (s *shuffleHotRegionScheduler) randomSchedule(cluster schedule.Cluster, sto
 		if err != nil {
 			return nil
 		}
-		schedulerCounter.WithLabelValues(s.GetName(), "create_operator").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "create_operator").Inc()
 		return []*operator.Operator{op}
 	}
-	schedulerCounter.WithLabelValues(s.GetName(), "skip").Inc()
+	schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "skip").Inc()
 	return nil
 }
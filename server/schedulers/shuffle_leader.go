@@ -61,19 +61,19 @@ func (s *shuffleLeaderScheduler) Schedule(cluster schedule.Cluster) []*operator.
 	// We shuffle leaders between stores by:
 	// 1. random select a valid store.
 	// 2. transfer a leader to the store.
-	schedulerCounter.WithLabelValues(s.GetName(), "schedule").Inc()
+	schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "schedule").Inc()
 	stores := cluster.GetStores()
 	targetStore := s.selector.SelectTarget(cluster, stores)
 	if targetStore == nil {
-		schedulerCounter.WithLabelValues(s.GetName(), "no_target_store").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "no_target_store").Inc()
 		return nil
 	}
 	region := cluster.RandFollowerRegion(targetStore.GetID(), core.HealthRegion())
 	if region == nil {
-		schedulerCounter.WithLabelValues(s.GetName(), "no_follower").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "no_follower").Inc()
 		return nil
 	}
-	schedulerCounter.WithLabelValues(s.GetName(), "new_operator").Inc()
+	schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "new_operator").Inc()
 	op := operator.CreateTransferLeaderOperator("shuffle-leader", region, region.GetLeader().GetId(), targetStore.GetID(), operator.OpAdmin)
 	op.SetPriorityLevel(core.HighPriority)
 	return []*operator.Operator{op}
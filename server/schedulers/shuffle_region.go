@@ -61,26 +61,26 @@ func (s *shuffleRegionScheduler) IsScheduleAllowed(cluster schedule.Cluster) boo
 }
 
 func (s *shuffleRegionScheduler) Schedule(cluster schedule.Cluster) []*operator.Operator {
-	schedulerCounter.WithLabelValues(s.GetName(), "schedule").Inc()
+	schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "schedule").Inc()
 	region, oldPeer := s.scheduleRemovePeer(cluster)
 	if region == nil {
-		schedulerCounter.WithLabelValues(s.GetName(), "no_region").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "no_region").Inc()
 		return nil
 	}
 
 	excludedFilter := filter.NewExcludedFilter(nil, region.GetStoreIds())
 	newPeer := s.scheduleAddPeer(cluster, excludedFilter)
 	if newPeer == nil {
-		schedulerCounter.WithLabelValues(s.GetName(), "no_new_peer").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "no_new_peer").Inc()
 		return nil
 	}
 
 	op, err := operator.CreateMovePeerOperator("shuffle-region", cluster, region, operator.OpAdmin, oldPeer.GetStoreId(), newPeer.GetStoreId(), newPeer.GetId())
 	if err != nil {
-		schedulerCounter.WithLabelValues(s.GetName(), "create_operator_fail").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "create_operator_fail").Inc()
 		return nil
 	}
-	schedulerCounter.WithLabelValues(s.GetName(), "new_operator").Inc()
+	schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "new_operator").Inc()
 	op.SetPriorityLevel(core.HighPriority)
 	return []*operator.Operator{op}
 }
@@ -90,7 +90,7 @@ func (s *shuffleRegionScheduler) scheduleRemovePeer(cluster schedule.Cluster) (*
 
 	source := s.selector.SelectSource(cluster, stores)
 	if source == nil {
-		schedulerCounter.WithLabelValues(s.GetName(), "no_store").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "no_store").Inc()
 		return nil, nil
 	}
 
@@ -99,7 +99,7 @@ func (s *shuffleRegionScheduler) scheduleRemovePeer(cluster schedule.Cluster) (*
 		region = cluster.RandLeaderRegion(source.GetID(), core.HealthRegion())
 	}
 	if region == nil {
-		schedulerCounter.WithLabelValues(s.GetName(), "no_region").Inc()
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "no_region").Inc()
 		return nil, nil
 	}
 
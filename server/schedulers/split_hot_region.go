@@ -0,0 +1,243 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedulers
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/namespace"
+	"github.com/pingcap/pd/server/schedule"
+	"github.com/pingcap/pd/server/schedule/operator"
+	"github.com/pingcap/pd/server/statistics"
+)
+
+func init() {
+	schedule.RegisterScheduler("split-hot-region", func(opController *schedule.OperatorController, args []string) (schedule.Scheduler, error) {
+		return newSplitHotRegionScheduler(opController), nil
+	})
+}
+
+const (
+	// splitHotRegionScatterTimeout is the time to wait for a split to finish
+	// before giving up scattering its halves.
+	splitHotRegionScatterTimeout = 10 * time.Minute
+	// splitHotRegionScanLimit is the max number of regions to scatter after
+	// splitting a region.
+	splitHotRegionScanLimit = 8
+)
+
+// splitRecord records a region split by load, its halves are scattered once
+// the split is finished.
+type splitRecord struct {
+	regionID   uint64
+	startKey   []byte
+	endKey     []byte
+	createTime time.Time
+}
+
+type splitHotRegionScheduler struct {
+	*baseScheduler
+	scatterer *schedule.RegionScatterer
+	// hotSince is the time since when a region stays hot above the threshold.
+	hotSince map[uint64]time.Time
+	// lastSplit is the time when a region was split by load last time.
+	lastSplit map[uint64]time.Time
+	splitting []splitRecord
+	// toScatter are the IDs of the halves waiting to be scattered.
+	toScatter []uint64
+}
+
+// newSplitHotRegionScheduler creates a scheduler that splits the regions which
+// stay hot for a long time, then scatters the halves. Moving such a region
+// between stores does not help as the load moves with it.
+func newSplitHotRegionScheduler(opController *schedule.OperatorController) schedule.Scheduler {
+	base := newBaseScheduler(opController)
+	return &splitHotRegionScheduler{
+		baseScheduler: base,
+		hotSince:      make(map[uint64]time.Time),
+		lastSplit:     make(map[uint64]time.Time),
+	}
+}
+
+func (s *splitHotRegionScheduler) GetName() string {
+	return "split-hot-region-scheduler"
+}
+
+func (s *splitHotRegionScheduler) GetType() string {
+	return "split-hot-region"
+}
+
+func (s *splitHotRegionScheduler) Prepare(cluster schedule.Cluster) error {
+	s.scatterer = schedule.NewRegionScatterer(cluster, namespace.DefaultClassifier)
+	s.scatterer.SetOperatorController(s.opController)
+	return nil
+}
+
+func (s *splitHotRegionScheduler) IsScheduleAllowed(cluster schedule.Cluster) bool {
+	return s.allowSplit(cluster) || s.allowScatter(cluster)
+}
+
+func (s *splitHotRegionScheduler) allowSplit(cluster schedule.Cluster) bool {
+	return s.opController.OperatorCount(operator.OpSplit) < cluster.GetSplitScheduleLimit()
+}
+
+func (s *splitHotRegionScheduler) allowScatter(cluster schedule.Cluster) bool {
+	return (len(s.splitting) > 0 || len(s.toScatter) > 0) &&
+		s.opController.OperatorCount(operator.OpRegion) < cluster.GetRegionScheduleLimit()
+}
+
+func (s *splitHotRegionScheduler) Schedule(cluster schedule.Cluster) []*operator.Operator {
+	schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "schedule").Inc()
+	now := time.Now()
+	s.updateHotRegions(cluster, now)
+	s.collectSplitRegions(cluster, now)
+
+	if s.allowScatter(cluster) {
+		if op := s.scatter(cluster); op != nil {
+			return []*operator.Operator{op}
+		}
+	}
+	if s.allowSplit(cluster) {
+		if op := s.split(cluster, now); op != nil {
+			return []*operator.Operator{op}
+		}
+	}
+	return nil
+}
+
+// updateHotRegions records when the regions become hot above the threshold,
+// and forgets the regions which are no longer hot.
+func (s *splitHotRegionScheduler) updateHotRegions(cluster schedule.Cluster, now time.Time) {
+	hot := make(map[uint64]struct{})
+	s.collectHotRegions(cluster, cluster.RegionWriteStats(), hot)
+	s.collectHotRegions(cluster, cluster.RegionReadStats(), hot)
+	for id := range s.hotSince {
+		if _, ok := hot[id]; !ok {
+			delete(s.hotSince, id)
+		}
+	}
+	for id := range hot {
+		if _, ok := s.hotSince[id]; !ok {
+			s.hotSince[id] = now
+		}
+	}
+	// The same region is not split again until it stays hot for another
+	// duration.
+	for id, t := range s.lastSplit {
+		if now.Sub(t) >= cluster.GetHotRegionSplitDuration() {
+			delete(s.lastSplit, id)
+		}
+	}
+}
+
+func (s *splitHotRegionScheduler) collectHotRegions(cluster schedule.Cluster, storeItems map[uint64][]*statistics.HotSpotPeerStat, hot map[uint64]struct{}) {
+	bytesThreshold, keysThreshold := cluster.GetHotRegionSplitBytesThreshold(), cluster.GetHotRegionSplitKeysThreshold()
+	for _, items := range storeItems {
+		for _, item := range items {
+			if item.HotDegree < cluster.GetHotRegionCacheHitsThreshold() {
+				continue
+			}
+			if item.FlowBytes >= bytesThreshold || item.FlowKeys >= keysThreshold {
+				hot[item.RegionID] = struct{}{}
+			}
+		}
+	}
+}
+
+// split picks a region which stays hot longer than the configured duration
+// and is not split recently, then splits it.
+func (s *splitHotRegionScheduler) split(cluster schedule.Cluster, now time.Time) *operator.Operator {
+	for id, since := range s.hotSince {
+		if now.Sub(since) < cluster.GetHotRegionSplitDuration() {
+			continue
+		}
+		if _, ok := s.lastSplit[id]; ok {
+			schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "cooldown").Inc()
+			continue
+		}
+		region := cluster.GetRegion(id)
+		if region == nil {
+			schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "no_region").Inc()
+			continue
+		}
+		if isRegionUnhealthy(region) {
+			schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "unhealthy_replica").Inc()
+			continue
+		}
+		op := operator.CreateSplitRegionOperator("split-hot-region", region, operator.OpSplit, "approximate")
+		op.SetPriorityLevel(core.HighPriority)
+		delete(s.hotSince, id)
+		s.lastSplit[id] = now
+		s.splitting = append(s.splitting, splitRecord{
+			regionID:   id,
+			startKey:   region.GetStartKey(),
+			endKey:     region.GetEndKey(),
+			createTime: now,
+		})
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "new_operator").Inc()
+		schedule.Counter(cluster, hotRegionSplitCounter).WithLabelValues("split").Inc()
+		return op
+	}
+	return nil
+}
+
+// collectSplitRegions finds the halves of the finished splits and queues them
+// to be scattered.
+func (s *splitHotRegionScheduler) collectSplitRegions(cluster schedule.Cluster, now time.Time) {
+	splitting := s.splitting[:0]
+	for _, record := range s.splitting {
+		region := cluster.GetRegion(record.regionID)
+		if region != nil && bytes.Equal(region.GetStartKey(), record.startKey) && bytes.Equal(region.GetEndKey(), record.endKey) {
+			if now.Sub(record.createTime) < splitHotRegionScatterTimeout {
+				splitting = append(splitting, record)
+			} else {
+				schedule.Counter(cluster, hotRegionSplitCounter).WithLabelValues("timeout").Inc()
+			}
+			continue
+		}
+		for _, r := range cluster.ScanRegions(record.startKey, splitHotRegionScanLimit) {
+			if len(record.endKey) > 0 && bytes.Compare(r.GetStartKey(), record.endKey) >= 0 {
+				break
+			}
+			s.toScatter = append(s.toScatter, r.GetID())
+		}
+	}
+	s.splitting = splitting
+}
+
+// scatter scatters a half of the regions split by load.
+func (s *splitHotRegionScheduler) scatter(cluster schedule.Cluster) *operator.Operator {
+	for len(s.toScatter) > 0 {
+		id := s.toScatter[0]
+		s.toScatter = s.toScatter[1:]
+		region := cluster.GetRegion(id)
+		if region == nil {
+			continue
+		}
+		op, err := s.scatterer.ScatterSplitRegion(region)
+		if err != nil {
+			schedule.Counter(cluster, hotRegionSplitCounter).WithLabelValues("scatter_fail").Inc()
+			continue
+		}
+		if op == nil {
+			continue
+		}
+		schedule.Counter(cluster, schedulerCounter).WithLabelValues(s.GetName(), "new_operator").Inc()
+		schedule.Counter(cluster, hotRegionSplitCounter).WithLabelValues("scatter").Inc()
+		return op
+	}
+	return nil
+}
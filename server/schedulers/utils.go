@@ -54,7 +54,26 @@ func isRegionUnhealthy(region *core.RegionInfo) bool {
 	return len(region.GetDownPeers()) != 0 || len(region.GetLearners()) != 0
 }
 
+// otherStores returns the stores except the one with the storeID.
+func otherStores(stores []*core.StoreInfo, storeID uint64) []*core.StoreInfo {
+	others := make([]*core.StoreInfo, 0, len(stores))
+	for _, store := range stores {
+		if store.GetID() != storeID {
+			others = append(others, store)
+		}
+	}
+	return others
+}
+
 func shouldBalance(cluster schedule.Cluster, source, target *core.StoreInfo, region *core.RegionInfo, kind core.ResourceKind, opInfluence operator.OpInfluence) bool {
+	sourceScore, targetScore := balanceScores(cluster, source, target, region, kind, opInfluence)
+	// Make sure after move, source score is still greater than target score.
+	return sourceScore > targetScore
+}
+
+// balanceScores returns the scores of the source and target stores after the
+// region is moved from the source store to the target store.
+func balanceScores(cluster schedule.Cluster, source, target *core.StoreInfo, region *core.RegionInfo, kind core.ResourceKind, opInfluence operator.OpInfluence) (float64, float64) {
 	// The reason we use max(regionSize, averageRegionSize) to check is:
 	// 1. prevent moving small regions between stores with close scores, leading to unnecessary balance.
 	// 2. prevent moving huge regions, leading to over balance.
@@ -67,8 +86,7 @@ func shouldBalance(cluster schedule.Cluster, source, target *core.StoreInfo, reg
 	sourceDelta := opInfluence.GetStoreInfluence(source.GetID()).ResourceSize(kind) - regionSize
 	targetDelta := opInfluence.GetStoreInfluence(target.GetID()).ResourceSize(kind) + regionSize
 
-	// Make sure after move, source score is still greater than target score.
-	return source.ResourceScore(kind, cluster.GetHighSpaceRatio(), cluster.GetLowSpaceRatio(), sourceDelta) >
+	return source.ResourceScore(kind, cluster.GetHighSpaceRatio(), cluster.GetLowSpaceRatio(), sourceDelta),
 		target.ResourceScore(kind, cluster.GetHighSpaceRatio(), cluster.GetLowSpaceRatio(), targetDelta)
 }
 
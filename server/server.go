@@ -39,6 +39,7 @@ import (
 	"github.com/pingcap/pd/server/id"
 	"github.com/pingcap/pd/server/kv"
 	"github.com/pingcap/pd/server/namespace"
+	"github.com/pingcap/pd/server/placement"
 	"github.com/pingcap/pd/server/tso"
 	"github.com/pkg/errors"
 	"go.etcd.io/etcd/clientv3"
@@ -225,13 +226,25 @@ func (s *Server) startServer() error {
 
 	s.idAllocator = id.NewAllocatorImpl(s.client, s.rootPath, s.memberValue)
 	s.tso = tso.NewTimestampOracle(s.client, s.rootPath, s.memberValue, s.cfg.TsoSaveInterval.Duration)
-	kvBase := kv.NewEtcdKVBase(s.client, s.rootPath)
+	// The regions are written in batches in the background, which may happen
+	// after the leadership is lost.
+	kvBase := kv.NewLeaderEtcdKVBase(s.client, s.rootPath, s.memberValue)
 	path := filepath.Join(s.cfg.DataDir, "region-meta")
 	regionStorage, err := core.NewRegionStorage(path)
 	if err != nil {
 		return err
 	}
-	s.storage = core.NewStorage(kvBase).SetRegionStorage(regionStorage)
+	operatorHistoryKV, err := kv.NewLeveldbKV(filepath.Join(s.cfg.DataDir, "operator-history"))
+	if err != nil {
+		return err
+	}
+	hotRegionHistoryKV, err := kv.NewLeveldbKV(filepath.Join(s.cfg.DataDir, "hot-region-history"))
+	if err != nil {
+		return err
+	}
+	s.storage = core.NewStorage(kvBase).SetRegionStorage(regionStorage).
+		SetOperatorHistoryStorage(operatorHistoryKV).
+		SetHotRegionHistoryStorage(hotRegionHistoryKV)
 	s.cluster = newRaftCluster(s, s.clusterID)
 	s.hbStreams = newHeartbeatStreams(s.clusterID, s.cluster)
 	if s.classifier, err = namespace.CreateClassifier(s.cfg.NamespaceClassifier, s.storage, s.idAllocator); err != nil {
@@ -445,6 +458,12 @@ func (s *Server) createRaftCluster() error {
 
 func (s *Server) stopRaftCluster() {
 	s.cluster.stop()
+	// Flush the regions in the queue before the lease is released, so that
+	// the next leader loads the latest regions. The regions are dropped if
+	// the leadership is already lost.
+	if err := s.storage.Flush(); err != nil {
+		log.Error("flush regions meet error", zap.Error(err))
+	}
 }
 
 // GetAddr returns the server urls for clients.
@@ -704,6 +723,30 @@ func (s *Server) GetLabelProperty() config.LabelPropertyConfig {
 	return s.scheduleOpt.LoadLabelPropertyConfig().Clone()
 }
 
+// GetPlacementConfig gets the placement constraints config.
+func (s *Server) GetPlacementConfig() (*placement.Config, error) {
+	cfg := &placement.Config{}
+	if _, err := s.storage.LoadPlacementConfig(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// SetPlacementConfig sets the placement constraints config.
+func (s *Server) SetPlacementConfig(cfg *placement.Config) error {
+	if err := s.storage.SavePlacementConfig(cfg); err != nil {
+		log.Error("failed to update placement config",
+			zap.Stringer("new", cfg),
+			zap.Error(err))
+		return err
+	}
+	if cluster := s.GetRaftCluster(); cluster != nil {
+		cluster.setPlacementConfig(cfg)
+	}
+	log.Info("placement config is updated", zap.Stringer("new", cfg))
+	return nil
+}
+
 // SetClusterVersion sets the version of cluster.
 func (s *Server) SetClusterVersion(v string) error {
 	version, err := ParseVersion(v)
@@ -0,0 +1,295 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/table"
+)
+
+const (
+	// DefaultHeatmapTimeSlices is the default max number of the time slices
+	// kept in the heatmap.
+	DefaultHeatmapTimeSlices = 120
+	// DefaultHeatmapKeyRanges is the default max number of the key ranges in a
+	// time slice, the adjacent regions are merged to fit in it.
+	DefaultHeatmapKeyRanges = 256
+)
+
+// The flows recorded in the heatmap.
+const (
+	heatmapWrittenBytes = iota
+	heatmapReadBytes
+	heatmapWrittenKeys
+	heatmapReadKeys
+	heatmapFlowCount
+)
+
+var heatmapFlowNames = [heatmapFlowCount]string{"written_bytes", "read_bytes", "written_keys", "read_keys"}
+
+type heatmapFlows [heatmapFlowCount]uint64
+
+func (f *heatmapFlows) add(other *heatmapFlows) {
+	for i := range f {
+		f[i] += other[i]
+	}
+}
+
+// heatmapRange is a key range in a time slice, an empty end key means the
+// range is unbounded.
+type heatmapRange struct {
+	startKey, endKey []byte
+	flows            heatmapFlows
+}
+
+type heatmapSlice struct {
+	startTime, endTime time.Time
+	ranges             []*heatmapRange
+}
+
+// Heatmap aggregates the flows of the regions into the time slices by the key
+// ranges.
+type Heatmap struct {
+	sync.Mutex
+	maxTimeSlices int
+	maxKeyRanges  int
+	// current is the flows of the regions observed since startTime.
+	current   map[uint64]*heatmapFlows
+	startTime time.Time
+	slices    []*heatmapSlice
+}
+
+// NewHeatmap creates a Heatmap which starts the first time slice at now.
+func NewHeatmap(maxTimeSlices, maxKeyRanges int, now time.Time) *Heatmap {
+	return &Heatmap{
+		maxTimeSlices: maxTimeSlices,
+		maxKeyRanges:  maxKeyRanges,
+		current:       make(map[uint64]*heatmapFlows),
+		startTime:     now,
+	}
+}
+
+// Observe records the flows reported by the region heartbeat.
+func (h *Heatmap) Observe(region *core.RegionInfo) {
+	flows := heatmapFlows{
+		heatmapWrittenBytes: region.GetBytesWritten(),
+		heatmapReadBytes:    region.GetBytesRead(),
+		heatmapWrittenKeys:  region.GetKeysWritten(),
+		heatmapReadKeys:     region.GetKeysRead(),
+	}
+	if flows == (heatmapFlows{}) {
+		return
+	}
+	h.Lock()
+	defer h.Unlock()
+	if f, ok := h.current[region.GetID()]; ok {
+		f.add(&flows)
+		return
+	}
+	h.current[region.GetID()] = &flows
+}
+
+// GetStartTime returns the start time of the current time slice.
+func (h *Heatmap) GetStartTime() time.Time {
+	h.Lock()
+	defer h.Unlock()
+	return h.startTime
+}
+
+// Rotate ends the current time slice at now and starts a new one. The regions
+// should be ordered by the key, and the adjacent regions are merged if there
+// are more regions than the max key ranges.
+func (h *Heatmap) Rotate(regions []*core.RegionInfo, now time.Time) {
+	h.Lock()
+	defer h.Unlock()
+	slice := &heatmapSlice{startTime: h.startTime, endTime: now}
+	groupSize := (len(regions) + h.maxKeyRanges - 1) / h.maxKeyRanges
+	for i := 0; i < len(regions); i += groupSize {
+		end := i + groupSize
+		if end > len(regions) {
+			end = len(regions)
+		}
+		r := &heatmapRange{
+			startKey: regions[i].GetStartKey(),
+			endKey:   regions[end-1].GetEndKey(),
+		}
+		for _, region := range regions[i:end] {
+			if f, ok := h.current[region.GetID()]; ok {
+				r.flows.add(f)
+			}
+		}
+		slice.ranges = append(slice.ranges, r)
+	}
+	h.slices = append(h.slices, slice)
+	if len(h.slices) > h.maxTimeSlices {
+		h.slices = h.slices[len(h.slices)-h.maxTimeSlices:]
+	}
+	h.current = make(map[uint64]*heatmapFlows)
+	h.startTime = now
+}
+
+// HeatmapKeyRange is a range of the key axis of the heatmap.
+type HeatmapKeyRange struct {
+	// StartKey and EndKey are hex encoded, an empty end key means the range
+	// is unbounded.
+	StartKey string `json:"start_key"`
+	EndKey   string `json:"end_key"`
+	// Label is the table of the start key, which is "table <id>" or empty.
+	Label   string `json:"label"`
+	TableID int64  `json:"table_id,omitempty"`
+}
+
+// HeatmapMatrix is the matrix of the flows by the time slices and the key
+// ranges.
+type HeatmapMatrix struct {
+	// TimeAxis is the start time of the time slices, and EndTime is the end
+	// time of the last time slice.
+	TimeAxis []time.Time        `json:"time_axis"`
+	EndTime  time.Time          `json:"end_time"`
+	KeyAxis  []*HeatmapKeyRange `json:"key_axis"`
+	// Data maps the name of the flow to the values indexed by the time slice
+	// and then the key range.
+	Data map[string][][]uint64 `json:"data"`
+}
+
+// GetMatrix returns the matrix of the time slices which end in the time
+// window [start, end], the zero value of start or end means unbounded. The
+// key ranges of the time slices are aligned to a common key axis, and the
+// adjacent ranges are merged if there are more ranges than the max key ranges.
+func (h *Heatmap) GetMatrix(start, end time.Time) *HeatmapMatrix {
+	h.Lock()
+	defer h.Unlock()
+	var slices []*heatmapSlice
+	for _, s := range h.slices {
+		if (start.IsZero() || !s.endTime.Before(start)) && (end.IsZero() || !s.endTime.After(end)) {
+			slices = append(slices, s)
+		}
+	}
+	matrix := &HeatmapMatrix{
+		TimeAxis: make([]time.Time, 0, len(slices)),
+		KeyAxis:  make([]*HeatmapKeyRange, 0),
+		Data:     make(map[string][][]uint64, heatmapFlowCount),
+	}
+	if len(slices) == 0 {
+		return matrix
+	}
+	matrix.EndTime = slices[len(slices)-1].endTime
+
+	axis := newHeatmapKeyAxis(slices)
+	groupSize := (len(axis) + h.maxKeyRanges - 1) / h.maxKeyRanges
+	for i := 0; i < len(axis); i += groupSize {
+		matrix.KeyAxis = append(matrix.KeyAxis, newHeatmapKeyRange(axis[i].startKey))
+	}
+	for i := 1; i < len(matrix.KeyAxis); i++ {
+		matrix.KeyAxis[i-1].EndKey = matrix.KeyAxis[i].StartKey
+	}
+	matrix.KeyAxis[len(matrix.KeyAxis)-1].EndKey = string(core.HexRegionKey(axis[len(axis)-1].endKey))
+
+	values := make([][][]uint64, heatmapFlowCount)
+	for _, s := range slices {
+		matrix.TimeAxis = append(matrix.TimeAxis, s.startTime)
+		row := axis.align(s)
+		for flow := range values {
+			merged := make([]uint64, len(matrix.KeyAxis))
+			for i, f := range row {
+				merged[i/groupSize] += f[flow]
+			}
+			values[flow] = append(values[flow], merged)
+		}
+	}
+	for flow, name := range heatmapFlowNames {
+		matrix.Data[name] = values[flow]
+	}
+	return matrix
+}
+
+func newHeatmapKeyRange(startKey []byte) *HeatmapKeyRange {
+	r := &HeatmapKeyRange{StartKey: string(core.HexRegionKey(startKey))}
+	if tableID := table.Key(startKey).TableID(); tableID != 0 {
+		r.Label = fmt.Sprintf("table %d", tableID)
+		r.TableID = tableID
+	}
+	return r
+}
+
+// heatmapKeyAxis is the key ranges split by the start keys of all the ranges
+// in the time slices.
+type heatmapKeyAxis []*heatmapRange
+
+func newHeatmapKeyAxis(slices []*heatmapSlice) heatmapKeyAxis {
+	keys := make(map[string]struct{})
+	var endKey []byte
+	unbounded := false
+	for _, s := range slices {
+		for _, r := range s.ranges {
+			keys[string(r.startKey)] = struct{}{}
+			if len(r.endKey) == 0 {
+				unbounded = true
+			} else if bytes.Compare(r.endKey, endKey) > 0 {
+				endKey = r.endKey
+			}
+		}
+	}
+	if unbounded {
+		endKey = nil
+	}
+	startKeys := make([]string, 0, len(keys))
+	for key := range keys {
+		startKeys = append(startKeys, key)
+	}
+	sort.Strings(startKeys)
+	axis := make(heatmapKeyAxis, 0, len(startKeys))
+	for i, key := range startKeys {
+		r := &heatmapRange{startKey: []byte(key), endKey: endKey}
+		if i+1 < len(startKeys) {
+			r.endKey = []byte(startKeys[i+1])
+		}
+		axis = append(axis, r)
+	}
+	return axis
+}
+
+// align splits the flows of the ranges in the time slice evenly to the ranges
+// of the axis they cover.
+func (axis heatmapKeyAxis) align(s *heatmapSlice) []heatmapFlows {
+	row := make([]heatmapFlows, len(axis))
+	for _, r := range s.ranges {
+		first := sort.Search(len(axis), func(i int) bool {
+			return bytes.Compare(axis[i].startKey, r.startKey) >= 0
+		})
+		last := len(axis)
+		if len(r.endKey) > 0 {
+			last = sort.Search(len(axis), func(i int) bool {
+				return bytes.Compare(axis[i].startKey, r.endKey) >= 0
+			})
+		}
+		if first >= last {
+			continue
+		}
+		n := uint64(last - first)
+		for flow, v := range r.flows {
+			for i := first; i < last; i++ {
+				row[i][flow] += v / n
+			}
+			row[first][flow] += v % n
+		}
+	}
+	return row
+}
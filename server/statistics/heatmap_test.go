@@ -0,0 +1,95 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/table"
+)
+
+var _ = Suite(&testHeatmapSuite{})
+
+type testHeatmapSuite struct{}
+
+func (t *testHeatmapSuite) newRegion(id uint64, startTable, endTable int64, opts ...core.RegionCreateOption) *core.RegionInfo {
+	meta := &metapb.Region{Id: id}
+	if startTable != 0 {
+		meta.StartKey = table.EncodeBytes(table.GenerateTableKey(startTable))
+	}
+	if endTable != 0 {
+		meta.EndKey = table.EncodeBytes(table.GenerateTableKey(endTable))
+	}
+	return core.NewRegionInfo(meta, nil, opts...)
+}
+
+func (t *testHeatmapSuite) TestHeatmap(c *C) {
+	now := time.Now()
+	h := NewHeatmap(2, 2, now)
+
+	// The first time slice merges 4 regions into 2 key ranges.
+	regions := []*core.RegionInfo{
+		t.newRegion(1, 0, 1),
+		t.newRegion(2, 1, 2),
+		t.newRegion(3, 2, 3),
+		t.newRegion(4, 3, 0),
+	}
+	h.Observe(t.newRegion(1, 0, 1, core.SetWrittenBytes(60), core.SetWrittenKeys(6)))
+	h.Observe(t.newRegion(1, 0, 1, core.SetWrittenBytes(40), core.SetWrittenKeys(4)))
+	h.Observe(t.newRegion(3, 2, 3, core.SetReadBytes(50), core.SetReadKeys(5)))
+	h.Rotate(regions, now.Add(time.Minute))
+	c.Assert(h.GetStartTime(), Equals, now.Add(time.Minute))
+
+	// The second time slice has 2 regions with different key ranges.
+	regions = []*core.RegionInfo{
+		t.newRegion(1, 0, 1),
+		t.newRegion(5, 1, 0),
+	}
+	h.Observe(t.newRegion(5, 1, 0, core.SetWrittenBytes(30)))
+	h.Rotate(regions, now.Add(2*time.Minute))
+
+	// The key axis is split by [ "", t1, t2 ) and then merged into 2 ranges.
+	matrix := h.GetMatrix(time.Time{}, time.Time{})
+	c.Assert(matrix.TimeAxis, DeepEquals, []time.Time{now, now.Add(time.Minute)})
+	c.Assert(matrix.EndTime, Equals, now.Add(2*time.Minute))
+	c.Assert(matrix.KeyAxis, HasLen, 2)
+	c.Assert(matrix.KeyAxis[0].StartKey, Equals, "")
+	c.Assert(matrix.KeyAxis[0].Label, Equals, "")
+	c.Assert(matrix.KeyAxis[0].EndKey, Equals, string(core.HexRegionKey(table.EncodeBytes(table.GenerateTableKey(2)))))
+	c.Assert(matrix.KeyAxis[1].Label, Equals, "table 2")
+	c.Assert(matrix.KeyAxis[1].TableID, Equals, int64(2))
+	c.Assert(matrix.KeyAxis[1].EndKey, Equals, "")
+	c.Assert(matrix.Data["written_bytes"], DeepEquals, [][]uint64{{100, 0}, {15, 15}})
+	c.Assert(matrix.Data["written_keys"], DeepEquals, [][]uint64{{10, 0}, {0, 0}})
+	c.Assert(matrix.Data["read_bytes"], DeepEquals, [][]uint64{{0, 50}, {0, 0}})
+	c.Assert(matrix.Data["read_keys"], DeepEquals, [][]uint64{{0, 5}, {0, 0}})
+
+	// The time slices are filtered by the end time.
+	matrix = h.GetMatrix(now.Add(90*time.Second), time.Time{})
+	c.Assert(matrix.TimeAxis, HasLen, 1)
+	c.Assert(matrix.KeyAxis, HasLen, 2)
+	c.Assert(matrix.KeyAxis[1].Label, Equals, "table 1")
+	c.Assert(matrix.Data["written_bytes"], DeepEquals, [][]uint64{{0, 30}})
+	matrix = h.GetMatrix(time.Time{}, now)
+	c.Assert(matrix.TimeAxis, HasLen, 0)
+	c.Assert(matrix.KeyAxis, HasLen, 0)
+
+	// The oldest time slice is dropped.
+	h.Rotate(regions, now.Add(3*time.Minute))
+	matrix = h.GetMatrix(time.Time{}, time.Time{})
+	c.Assert(matrix.TimeAxis, DeepEquals, []time.Time{now.Add(time.Minute), now.Add(2 * time.Minute)})
+}
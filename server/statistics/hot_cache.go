@@ -16,6 +16,7 @@ package statistics
 import (
 	"fmt"
 	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/pingcap/kvproto/pkg/metapb"
@@ -37,6 +38,8 @@ const (
 	storeStatCacheMaxLen       = 200
 	hotWriteRegionMinFlowRate  = 16 * 1024
 	hotReadRegionMinFlowRate   = 128 * 1024
+	hotWriteRegionMinKeyRate   = 256
+	hotReadRegionMinKeyRate    = 512
 	minHotRegionReportInterval = 3
 	hotRegionAntiCount         = 1
 )
@@ -232,14 +235,14 @@ const rollingWindowsSize = 5
 
 // GenHotSpotPeerStats implements HotSpotPeerStatsGenerator.
 func (flowStats *hotSpotPeerStatGenerator) GenHotSpotPeerStats(stats *StoresStats) *HotSpotPeerStat {
-	var hotRegionThreshold uint64
+	var bytesThreshold, keysThreshold uint64
 	switch flowStats.Kind {
 	case WriteFlow:
-		hotRegionThreshold = calculateWriteHotThresholdWithStore(stats, flowStats.StoreID)
+		bytesThreshold, keysThreshold = calculateWriteHotThresholdWithStore(stats, flowStats.StoreID)
 	case ReadFlow:
-		hotRegionThreshold = calculateReadHotThresholdWithStore(stats, flowStats.StoreID)
+		bytesThreshold, keysThreshold = calculateReadHotThresholdWithStore(stats, flowStats.StoreID)
 	}
-	flowBytes := flowStats.FlowBytes
+	flowBytes, flowKeys := flowStats.FlowBytes, flowStats.FlowKeys
 	oldItem := flowStats.lastHotSpotPeerStats
 	region := flowStats.Region
 	newItem := &HotSpotPeerStat{
@@ -265,14 +268,18 @@ func (flowStats *hotSpotPeerStatGenerator) GenHotSpotPeerStats(stats *StoresStat
 	if oldItem != nil {
 		newItem.HotDegree = oldItem.HotDegree + 1
 		newItem.Stats = oldItem.Stats
+		newItem.KeysStats = oldItem.KeysStats
 	}
 
-	if flowBytes >= hotRegionThreshold {
+	// The region is hot if the flow exceeds the threshold on any dimension.
+	if flowBytes >= bytesThreshold || flowKeys >= keysThreshold {
 		if oldItem == nil {
 			newItem.Stats = NewRollingStats(rollingWindowsSize)
+			newItem.KeysStats = NewRollingStats(rollingWindowsSize)
 		}
 		newItem.isNew = true
 		newItem.Stats.Add(float64(flowBytes))
+		newItem.KeysStats.Add(float64(flowKeys))
 		return newItem
 	}
 
@@ -288,11 +295,14 @@ func (flowStats *hotSpotPeerStatGenerator) GenHotSpotPeerStats(stats *StoresStat
 	newItem.HotDegree = oldItem.HotDegree - 1
 	newItem.AntiCount = oldItem.AntiCount - 1
 	newItem.Stats.Add(float64(flowBytes))
+	newItem.KeysStats.Add(float64(flowKeys))
 	return newItem
 }
 
-// HotSpotCache is a cache hold hot regions.
+// HotSpotCache is a cache hold hot regions. It is safe for concurrent use, so
+// that the region heartbeats can update it without the cluster lock.
 type HotSpotCache struct {
+	sync.RWMutex
 	writeFlow *HotStoresStats
 	readFlow  *HotStoresStats
 }
@@ -307,6 +317,8 @@ func NewHotSpotCache() *HotSpotCache {
 
 // CheckWrite checks the write status, returns update items.
 func (w *HotSpotCache) CheckWrite(region *core.RegionInfo, stats *StoresStats) []*HotSpotPeerStat {
+	w.RLock()
+	defer w.RUnlock()
 	var updateItems []*HotSpotPeerStat
 	hotStatGenerators := w.writeFlow.CheckRegionFlow(region, WriteFlow)
 	for _, hotGen := range hotStatGenerators {
@@ -320,6 +332,8 @@ func (w *HotSpotCache) CheckWrite(region *core.RegionInfo, stats *StoresStats) [
 
 // CheckRead checks the read status, returns update items.
 func (w *HotSpotCache) CheckRead(region *core.RegionInfo, stats *StoresStats) []*HotSpotPeerStat {
+	w.RLock()
+	defer w.RUnlock()
 	var updateItems []*HotSpotPeerStat
 	hotStatGenerators := w.readFlow.CheckRegionFlow(region, ReadFlow)
 	for _, hotGen := range hotStatGenerators {
@@ -343,6 +357,8 @@ func (w *HotSpotCache) incMetrics(name string, storeID uint64, kind FlowKind) {
 
 // Update updates the cache.
 func (w *HotSpotCache) Update(item *HotSpotPeerStat) {
+	w.Lock()
+	defer w.Unlock()
 	var stats *HotStoresStats
 	switch item.Kind {
 	case WriteFlow:
@@ -362,6 +378,8 @@ func (w *HotSpotCache) Update(item *HotSpotPeerStat) {
 
 // RegionStats returns hot items according to kind
 func (w *HotSpotCache) RegionStats(kind FlowKind) map[uint64][]*HotSpotPeerStat {
+	w.RLock()
+	defer w.RUnlock()
 	var flowMap map[uint64]cache.Cache
 	switch kind {
 	case WriteFlow:
@@ -400,23 +418,29 @@ func (w *HotSpotCache) RandHotRegionFromStore(storeID uint64, kind FlowKind, hot
 
 // CollectMetrics collect the hot cache metrics
 func (w *HotSpotCache) CollectMetrics(stats *StoresStats) {
+	w.RLock()
+	defer w.RUnlock()
 	for storeID, flowStats := range w.writeFlow.hotStoreStats {
 		storeTag := fmt.Sprintf("store-%d", storeID)
-		threshold := calculateWriteHotThresholdWithStore(stats, storeID)
+		bytesThreshold, keysThreshold := calculateWriteHotThresholdWithStore(stats, storeID)
 		hotCacheStatusGauge.WithLabelValues("total_length", storeTag, "write").Set(float64(flowStats.Len()))
-		hotCacheStatusGauge.WithLabelValues("hotThreshold", storeTag, "write").Set(float64(threshold))
+		hotCacheStatusGauge.WithLabelValues("hotThreshold", storeTag, "write").Set(float64(bytesThreshold))
+		hotCacheStatusGauge.WithLabelValues("hotKeysThreshold", storeTag, "write").Set(float64(keysThreshold))
 	}
 
 	for storeID, flowStats := range w.readFlow.hotStoreStats {
 		storeTag := fmt.Sprintf("store-%d", storeID)
-		threshold := calculateReadHotThresholdWithStore(stats, storeID)
+		bytesThreshold, keysThreshold := calculateReadHotThresholdWithStore(stats, storeID)
 		hotCacheStatusGauge.WithLabelValues("total_length", storeTag, "read").Set(float64(flowStats.Len()))
-		hotCacheStatusGauge.WithLabelValues("hotThreshold", storeTag, "read").Set(float64(threshold))
+		hotCacheStatusGauge.WithLabelValues("hotThreshold", storeTag, "read").Set(float64(bytesThreshold))
+		hotCacheStatusGauge.WithLabelValues("hotKeysThreshold", storeTag, "read").Set(float64(keysThreshold))
 	}
 }
 
 // IsRegionHot checks if the region is hot.
 func (w *HotSpotCache) IsRegionHot(region *core.RegionInfo, hotThreshold int) bool {
+	w.RLock()
+	defer w.RUnlock()
 	stats := w.writeFlow
 	if stats.isRegionHotWithAnyPeers(region, hotThreshold) {
 		return true
@@ -440,26 +464,40 @@ func calculateWriteHotThreshold(stats *StoresStats) uint64 {
 	return hotRegionThreshold
 }
 
-func calculateWriteHotThresholdWithStore(stats *StoresStats, storeID uint64) uint64 {
+// calculateWriteHotThresholdWithStore returns the bytes threshold and the keys
+// threshold of the hot write regions of the store.
+func calculateWriteHotThresholdWithStore(stats *StoresStats, storeID uint64) (uint64, uint64) {
 	writeBytes, _ := stats.GetStoreBytesRate(storeID)
+	writeKeys, _ := stats.GetStoreKeysRate(storeID)
 	divisor := float64(storeStatCacheMaxLen) * 2
-	hotRegionThreshold := uint64(float64(writeBytes) / divisor)
+	bytesThreshold := uint64(writeBytes / divisor)
+	keysThreshold := uint64(writeKeys / divisor)
 
-	if hotRegionThreshold < hotWriteRegionMinFlowRate {
-		hotRegionThreshold = hotWriteRegionMinFlowRate
+	if bytesThreshold < hotWriteRegionMinFlowRate {
+		bytesThreshold = hotWriteRegionMinFlowRate
 	}
-	return hotRegionThreshold
+	if keysThreshold < hotWriteRegionMinKeyRate {
+		keysThreshold = hotWriteRegionMinKeyRate
+	}
+	return bytesThreshold, keysThreshold
 }
 
-func calculateReadHotThresholdWithStore(stats *StoresStats, storeID uint64) uint64 {
+// calculateReadHotThresholdWithStore returns the bytes threshold and the keys
+// threshold of the hot read regions of the store.
+func calculateReadHotThresholdWithStore(stats *StoresStats, storeID uint64) (uint64, uint64) {
 	_, readBytes := stats.GetStoreBytesRate(storeID)
+	_, readKeys := stats.GetStoreKeysRate(storeID)
 	divisor := float64(storeStatCacheMaxLen) * 2
-	hotRegionThreshold := uint64(float64(readBytes) / divisor)
+	bytesThreshold := uint64(readBytes / divisor)
+	keysThreshold := uint64(readKeys / divisor)
 
-	if hotRegionThreshold < hotReadRegionMinFlowRate {
-		hotRegionThreshold = hotReadRegionMinFlowRate
+	if bytesThreshold < hotReadRegionMinFlowRate {
+		bytesThreshold = hotReadRegionMinFlowRate
 	}
-	return hotRegionThreshold
+	if keysThreshold < hotReadRegionMinKeyRate {
+		keysThreshold = hotReadRegionMinKeyRate
+	}
+	return bytesThreshold, keysThreshold
 }
 
 func calculateReadHotThreshold(stats *StoresStats) uint64 {
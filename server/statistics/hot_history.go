@@ -0,0 +1,238 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/kv"
+	"github.com/pingcap/pd/table"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	hotRegionHistoryPath       = "hot_region_history"
+	hotRegionHistoryRangeLimit = 1000
+	// hotRegionHistoryQueryBatch is the number of the snapshots loaded at a
+	// time when querying, so a query with a limit only decodes the latest
+	// snapshots.
+	hotRegionHistoryQueryBatch = 16
+	// HotRegionHistoryInterval is the interval to save the hot regions to the
+	// hot region history.
+	HotRegionHistoryInterval = time.Minute
+)
+
+// HotRegionHistoryRecord is a hot peer in the hot region history.
+type HotRegionHistoryRecord struct {
+	UpdateTime time.Time `json:"update_time"`
+	RegionID   uint64    `json:"region_id"`
+	StoreID    uint64    `json:"store_id"`
+	IsLeader   bool      `json:"is_leader"`
+	// Kind is the kind of the flow, which is read or write.
+	Kind      string `json:"kind"`
+	FlowBytes uint64 `json:"flow_bytes"`
+	FlowKeys  uint64 `json:"flow_keys"`
+	HotDegree int    `json:"hot_degree"`
+	// StartKey and EndKey are the hex encoded key range of the region.
+	StartKey string `json:"start_key"`
+	EndKey   string `json:"end_key"`
+}
+
+// NewHotRegionHistoryRecords creates the records of the hot peers, the peers
+// whose hot degree is less than the threshold or whose region is not found by
+// getRegion are skipped.
+func NewHotRegionHistoryRecords(stats map[uint64][]*HotSpotPeerStat, kind FlowKind, threshold int, getRegion func(regionID uint64) *core.RegionInfo, now time.Time) []*HotRegionHistoryRecord {
+	var records []*HotRegionHistoryRecord
+	for storeID, peers := range stats {
+		for _, peer := range peers {
+			if peer.HotDegree < threshold {
+				continue
+			}
+			region := getRegion(peer.RegionID)
+			if region == nil {
+				continue
+			}
+			records = append(records, &HotRegionHistoryRecord{
+				UpdateTime: now,
+				RegionID:   peer.RegionID,
+				StoreID:    storeID,
+				IsLeader:   region.GetLeader().GetStoreId() == storeID,
+				Kind:       kind.String(),
+				FlowBytes:  peer.FlowBytes,
+				FlowKeys:   peer.FlowKeys,
+				HotDegree:  peer.HotDegree,
+				StartKey:   string(core.HexRegionKey(region.GetStartKey())),
+				EndKey:     string(core.HexRegionKey(region.GetEndKey())),
+			})
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].RegionID != records[j].RegionID {
+			return records[i].RegionID < records[j].RegionID
+		}
+		return records[i].StoreID < records[j].StoreID
+	})
+	return records
+}
+
+// inTable checks if the key range of the record overlaps the table.
+func (r *HotRegionHistoryRecord) inTable(tableID int64) bool {
+	startKey, err := hex.DecodeString(r.StartKey)
+	if err != nil {
+		return false
+	}
+	endKey, err := hex.DecodeString(r.EndKey)
+	if err != nil {
+		return false
+	}
+	tableStart := table.EncodeBytes(table.GenerateTableKey(tableID))
+	tableEnd := table.EncodeBytes(table.GenerateTableKey(tableID + 1))
+	return bytes.Compare(startKey, tableEnd) < 0 && (len(endKey) == 0 || bytes.Compare(endKey, tableStart) > 0)
+}
+
+// HotRegionHistoryFilter filters the records in the hot region history, the
+// zero value of a field matches all the records.
+type HotRegionHistoryFilter struct {
+	// Start and End are the time window of the update time.
+	Start   time.Time
+	End     time.Time
+	StoreID uint64
+	// TableID matches the records whose key range overlaps the table.
+	TableID int64
+	Kind    string
+	// Limit is the max number of the latest records to return.
+	Limit int
+}
+
+func (f *HotRegionHistoryFilter) match(r *HotRegionHistoryRecord) bool {
+	if f.StoreID != 0 && r.StoreID != f.StoreID {
+		return false
+	}
+	if f.Kind != "" && r.Kind != f.Kind {
+		return false
+	}
+	if f.TableID != 0 && !r.inTable(f.TableID) {
+		return false
+	}
+	return true
+}
+
+// HotRegionHistory persists the snapshots of the hot regions.
+type HotRegionHistory struct {
+	sync.Mutex
+	kv kv.ReverseBase
+}
+
+// NewHotRegionHistory creates a HotRegionHistory which saves the hot regions
+// to kv.
+func NewHotRegionHistory(kv kv.ReverseBase) *HotRegionHistory {
+	return &HotRegionHistory{
+		kv: kv,
+	}
+}
+
+func hotRegionHistoryKey(t int64) string {
+	return path.Join(hotRegionHistoryPath, fmt.Sprintf("%020d", t))
+}
+
+// Put saves a snapshot of the hot regions taken at now, and removes the
+// snapshots older than the retention.
+func (h *HotRegionHistory) Put(records []*HotRegionHistoryRecord, now time.Time, retention time.Duration) {
+	h.Lock()
+	defer h.Unlock()
+	if len(records) > 0 {
+		value, err := json.Marshal(records)
+		if err != nil {
+			log.Warn("marshal hot region history failed", zap.Error(err))
+			return
+		}
+		if err = h.kv.Save(hotRegionHistoryKey(now.UnixNano()), string(value)); err != nil {
+			log.Warn("save hot region history failed", zap.Error(err))
+			return
+		}
+	}
+	if err := h.removeBefore(now.Add(-retention)); err != nil {
+		log.Warn("remove expired hot region history failed", zap.Error(err))
+	}
+}
+
+func (h *HotRegionHistory) removeBefore(t time.Time) error {
+	startKey, endKey := hotRegionHistoryKey(0), hotRegionHistoryKey(t.UnixNano())
+	for {
+		keys, _, err := h.kv.LoadRange(startKey, endKey, hotRegionHistoryRangeLimit)
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if err := h.kv.Remove(key); err != nil {
+				return err
+			}
+		}
+		if len(keys) < hotRegionHistoryRangeLimit {
+			return nil
+		}
+	}
+}
+
+// Query returns the records matching the filter, the latest first. The
+// snapshots are loaded from the latest one and the loading stops once the
+// limit is reached.
+func (h *HotRegionHistory) Query(filter *HotRegionHistoryFilter) ([]*HotRegionHistoryRecord, error) {
+	h.Lock()
+	defer h.Unlock()
+	startKey, endKey := hotRegionHistoryKey(0), hotRegionHistoryKey(math.MaxInt64)
+	if !filter.Start.IsZero() {
+		startKey = hotRegionHistoryKey(filter.Start.UnixNano())
+	}
+	if !filter.End.IsZero() {
+		endKey = hotRegionHistoryKey(filter.End.UnixNano() + 1)
+	}
+	var records []*HotRegionHistoryRecord
+	for {
+		keys, values, err := h.kv.LoadRangeReverse(startKey, endKey, hotRegionHistoryQueryBatch)
+		if err != nil {
+			return nil, err
+		}
+		for _, value := range values {
+			var snapshot []*HotRegionHistoryRecord
+			if err := json.Unmarshal([]byte(value), &snapshot); err != nil {
+				return nil, errors.WithStack(err)
+			}
+			for _, record := range snapshot {
+				if !filter.match(record) {
+					continue
+				}
+				records = append(records, record)
+				if filter.Limit > 0 && len(records) >= filter.Limit {
+					return records, nil
+				}
+			}
+		}
+		if len(keys) < hotRegionHistoryQueryBatch {
+			return records, nil
+		}
+		endKey = keys[len(keys)-1]
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/kv"
+	"github.com/pingcap/pd/table"
+)
+
+var _ = Suite(&testHotRegionHistorySuite{})
+
+type testHotRegionHistorySuite struct{}
+
+func (t *testHotRegionHistorySuite) newRegion(id uint64, startKey, endKey []byte, leaderStore uint64, stores ...uint64) *core.RegionInfo {
+	meta := &metapb.Region{Id: id, StartKey: startKey, EndKey: endKey}
+	var leader *metapb.Peer
+	for _, storeID := range stores {
+		peer := &metapb.Peer{Id: id*10 + storeID, StoreId: storeID}
+		meta.Peers = append(meta.Peers, peer)
+		if storeID == leaderStore {
+			leader = peer
+		}
+	}
+	return core.NewRegionInfo(meta, leader)
+}
+
+func (t *testHotRegionHistorySuite) TestPutAndQuery(c *C) {
+	regions := core.NewBasicCluster()
+	regions.PutRegion(t.newRegion(1, table.EncodeBytes(table.GenerateTableKey(1)), table.EncodeBytes(table.GenerateTableKey(2)), 1, 1, 2))
+	regions.PutRegion(t.newRegion(2, table.EncodeBytes(table.GenerateTableKey(3)), nil, 2, 2, 3))
+	stats := map[uint64][]*HotSpotPeerStat{
+		1: {{RegionID: 1, FlowBytes: 100, FlowKeys: 10, HotDegree: 3}},
+		2: {
+			{RegionID: 1, FlowBytes: 100, FlowKeys: 10, HotDegree: 3},
+			{RegionID: 2, FlowBytes: 200, FlowKeys: 20, HotDegree: 4},
+			// The region is not found.
+			{RegionID: 3, FlowBytes: 300, FlowKeys: 30, HotDegree: 3},
+		},
+		// The peer is not hot enough.
+		3: {{RegionID: 2, FlowBytes: 200, FlowKeys: 20, HotDegree: 1}},
+	}
+
+	now := time.Now()
+	writeRecords := NewHotRegionHistoryRecords(stats, WriteFlow, 3, regions.GetRegion, now)
+	c.Assert(writeRecords, HasLen, 3)
+	c.Assert(writeRecords[0].RegionID, Equals, uint64(1))
+	c.Assert(writeRecords[0].StoreID, Equals, uint64(1))
+	c.Assert(writeRecords[0].IsLeader, IsTrue)
+	c.Assert(writeRecords[0].Kind, Equals, "write")
+	c.Assert(writeRecords[0].StartKey, Equals, string(core.HexRegionKey(table.EncodeBytes(table.GenerateTableKey(1)))))
+	c.Assert(writeRecords[1].StoreID, Equals, uint64(2))
+	c.Assert(writeRecords[1].IsLeader, IsFalse)
+	c.Assert(writeRecords[2].RegionID, Equals, uint64(2))
+	c.Assert(writeRecords[2].FlowBytes, Equals, uint64(200))
+	c.Assert(writeRecords[2].EndKey, Equals, "")
+
+	h := NewHotRegionHistory(kv.NewMemoryKV())
+	h.Put(writeRecords, now, time.Hour)
+	h.Put(NewHotRegionHistoryRecords(stats, ReadFlow, 3, regions.GetRegion, now.Add(time.Minute)), now.Add(time.Minute), time.Hour)
+
+	// The latest snapshot comes first.
+	records, err := h.Query(&HotRegionHistoryFilter{})
+	c.Assert(err, IsNil)
+	c.Assert(records, HasLen, 6)
+	c.Assert(records[0].Kind, Equals, "read")
+	c.Assert(records[5].Kind, Equals, "write")
+	records, err = h.Query(&HotRegionHistoryFilter{Kind: "write", StoreID: 2})
+	c.Assert(err, IsNil)
+	c.Assert(records, HasLen, 2)
+	records, err = h.Query(&HotRegionHistoryFilter{Start: now.Add(30 * time.Second)})
+	c.Assert(err, IsNil)
+	c.Assert(records, HasLen, 3)
+	c.Assert(records[0].Kind, Equals, "read")
+	records, err = h.Query(&HotRegionHistoryFilter{End: now})
+	c.Assert(err, IsNil)
+	c.Assert(records, HasLen, 3)
+	c.Assert(records[0].Kind, Equals, "write")
+	records, err = h.Query(&HotRegionHistoryFilter{Limit: 1})
+	c.Assert(err, IsNil)
+	c.Assert(records, HasLen, 1)
+	c.Assert(records[0].Kind, Equals, "read")
+	records, err = h.Query(&HotRegionHistoryFilter{Kind: "write", Limit: 2})
+	c.Assert(err, IsNil)
+	c.Assert(records, HasLen, 2)
+
+	// The regions overlapping the table are matched.
+	records, err = h.Query(&HotRegionHistoryFilter{TableID: 1, Kind: "write"})
+	c.Assert(err, IsNil)
+	c.Assert(records, HasLen, 2)
+	c.Assert(records[0].RegionID, Equals, uint64(1))
+	records, err = h.Query(&HotRegionHistoryFilter{TableID: 2})
+	c.Assert(err, IsNil)
+	c.Assert(records, HasLen, 0)
+	records, err = h.Query(&HotRegionHistoryFilter{TableID: 5, Kind: "read"})
+	c.Assert(err, IsNil)
+	c.Assert(records, HasLen, 1)
+	c.Assert(records[0].RegionID, Equals, uint64(2))
+
+	// The expired snapshots are removed.
+	h.Put(nil, now.Add(time.Hour+30*time.Second), time.Hour)
+	records, err = h.Query(&HotRegionHistoryFilter{})
+	c.Assert(err, IsNil)
+	c.Assert(records, HasLen, 3)
+	c.Assert(records[0].Kind, Equals, "read")
+}
+
+func (t *testHotRegionHistorySuite) TestQueryBatches(c *C) {
+	regions := core.NewBasicCluster()
+	regions.PutRegion(t.newRegion(1, nil, nil, 1, 1))
+	stats := map[uint64][]*HotSpotPeerStat{1: {{RegionID: 1, HotDegree: 3}}}
+	h := NewHotRegionHistory(kv.NewMemoryKV())
+	now := time.Now()
+	n := hotRegionHistoryQueryBatch*2 + 1
+	for i := 0; i < n; i++ {
+		ts := now.Add(time.Duration(i) * time.Minute)
+		h.Put(NewHotRegionHistoryRecords(stats, WriteFlow, 3, regions.GetRegion, ts), ts, 24*time.Hour)
+	}
+
+	records, err := h.Query(&HotRegionHistoryFilter{})
+	c.Assert(err, IsNil)
+	c.Assert(records, HasLen, n)
+	for i, record := range records {
+		c.Assert(record.UpdateTime.Equal(now.Add(time.Duration(n-1-i)*time.Minute)), IsTrue)
+	}
+	records, err = h.Query(&HotRegionHistoryFilter{End: now.Add(time.Duration(n-2) * time.Minute), Limit: hotRegionHistoryQueryBatch + 1})
+	c.Assert(err, IsNil)
+	c.Assert(records, HasLen, hotRegionHistoryQueryBatch+1)
+	c.Assert(records[0].UpdateTime.Equal(now.Add(time.Duration(n-2)*time.Minute)), IsTrue)
+}
@@ -0,0 +1,146 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/table"
+)
+
+// RegionStaleLeaderThreshold is the duration after which a region whose
+// leader has not reported the heartbeat is considered stale.
+const RegionStaleLeaderThreshold = 10 * time.Minute
+
+// The kinds of the key space issues.
+const (
+	KeySpaceHole        = "hole"
+	KeySpaceStaleLeader = "stale-leader"
+)
+
+// KeySpaceIssue is a key range with a hole or a stale leader.
+type KeySpaceIssue struct {
+	Kind string `json:"kind"`
+	// StartKey and EndKey are the hex encoded key range, an empty end key
+	// means the range is unbounded.
+	StartKey string `json:"start_key"`
+	EndKey   string `json:"end_key"`
+	// StartTableID and EndTableID are the tables of the keys decoded by the
+	// table codec, which are 0 for the keys not in a table.
+	StartTableID int64 `json:"start_table_id,omitempty"`
+	EndTableID   int64 `json:"end_table_id,omitempty"`
+	// RegionIDs are the regions around the hole or the region with the stale
+	// leader.
+	RegionIDs []uint64 `json:"region_ids,omitempty"`
+	// LastHeartbeat is the last time the stale leader reported.
+	LastHeartbeat *time.Time `json:"last_heartbeat,omitempty"`
+}
+
+func newKeySpaceIssue(kind string, startKey, endKey []byte, regionIDs ...uint64) *KeySpaceIssue {
+	return &KeySpaceIssue{
+		Kind:         kind,
+		StartKey:     string(core.HexRegionKey(startKey)),
+		EndKey:       string(core.HexRegionKey(endKey)),
+		StartTableID: table.Key(startKey).TableID(),
+		EndTableID:   table.Key(endKey).TableID(),
+		RegionIDs:    regionIDs,
+	}
+}
+
+// KeySpaceAuditReport is the issues found by an audit of the key space.
+type KeySpaceAuditReport struct {
+	Time         time.Time        `json:"time"`
+	RegionCount  int              `json:"region_count"`
+	Holes        []*KeySpaceIssue `json:"holes"`
+	StaleLeaders []*KeySpaceIssue `json:"stale_leaders"`
+}
+
+// KeySpaceAuditor checks the key space of the regions for the holes between
+// the adjacent regions and the regions whose leader has not reported for a
+// while. The regions never overlap as the region tree replaces the overlapped
+// regions on insert.
+type KeySpaceAuditor struct {
+	sync.Mutex
+	// heartbeats is the last heartbeat time of the regions, the regions
+	// without heartbeat are recorded when they are first audited.
+	heartbeats map[uint64]time.Time
+}
+
+// NewKeySpaceAuditor creates a KeySpaceAuditor.
+func NewKeySpaceAuditor() *KeySpaceAuditor {
+	return &KeySpaceAuditor{
+		heartbeats: make(map[uint64]time.Time),
+	}
+}
+
+// Observe records the heartbeat of the region.
+func (a *KeySpaceAuditor) Observe(regionID uint64, now time.Time) {
+	a.Lock()
+	defer a.Unlock()
+	a.heartbeats[regionID] = now
+}
+
+// Audit walks the regions in the key order to find the issues, and updates
+// the metrics.
+func (a *KeySpaceAuditor) Audit(regions *core.RegionsInfo, staleThreshold time.Duration, now time.Time) *KeySpaceAuditReport {
+	a.Lock()
+	defer a.Unlock()
+	report := &KeySpaceAuditReport{
+		Time:         now,
+		Holes:        make([]*KeySpaceIssue, 0),
+		StaleLeaders: make([]*KeySpaceIssue, 0),
+	}
+	var prev *metapb.Region
+	regions.ScanRangeWithIterator(nil, func(region *metapb.Region) bool {
+		report.RegionCount++
+		startKey := region.GetStartKey()
+		if prev == nil {
+			if len(startKey) > 0 {
+				report.Holes = append(report.Holes, newKeySpaceIssue(KeySpaceHole, nil, startKey, region.GetId()))
+			}
+		} else if bytes.Compare(startKey, prev.GetEndKey()) > 0 {
+			report.Holes = append(report.Holes, newKeySpaceIssue(KeySpaceHole, prev.GetEndKey(), startKey, prev.GetId(), region.GetId()))
+		}
+
+		last, ok := a.heartbeats[region.GetId()]
+		if !ok {
+			a.heartbeats[region.GetId()] = now
+		} else if now.Sub(last) > staleThreshold {
+			issue := newKeySpaceIssue(KeySpaceStaleLeader, startKey, region.GetEndKey(), region.GetId())
+			issue.LastHeartbeat = &last
+			report.StaleLeaders = append(report.StaleLeaders, issue)
+		}
+		prev = region
+		return true
+	})
+	if prev == nil {
+		report.Holes = append(report.Holes, newKeySpaceIssue(KeySpaceHole, nil, nil))
+	} else if len(prev.GetEndKey()) > 0 {
+		report.Holes = append(report.Holes, newKeySpaceIssue(KeySpaceHole, prev.GetEndKey(), nil, prev.GetId()))
+	}
+
+	for regionID := range a.heartbeats {
+		if regions.GetRegion(regionID) == nil {
+			delete(a.heartbeats, regionID)
+		}
+	}
+
+	keySpaceIssueGauge.WithLabelValues(KeySpaceHole).Set(float64(len(report.Holes)))
+	keySpaceIssueGauge.WithLabelValues(KeySpaceStaleLeader).Set(float64(len(report.StaleLeaders)))
+	return report
+}
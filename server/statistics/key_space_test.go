@@ -0,0 +1,82 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/table"
+)
+
+var _ = Suite(&testKeySpaceSuite{})
+
+type testKeySpaceSuite struct{}
+
+func (t *testKeySpaceSuite) TestAudit(c *C) {
+	t1 := table.EncodeBytes(table.GenerateTableKey(1))
+	t2 := table.EncodeBytes(table.GenerateTableKey(2))
+	t3 := table.EncodeBytes(table.GenerateTableKey(3))
+	newRegion := func(id uint64, startKey, endKey []byte) *core.RegionInfo {
+		return core.NewRegionInfo(&metapb.Region{Id: id, StartKey: startKey, EndKey: endKey}, nil)
+	}
+	regions := core.NewRegionsInfo()
+	a := NewKeySpaceAuditor()
+	now := time.Now()
+
+	// The whole key space is a hole if there is no region.
+	report := a.Audit(regions, time.Minute, now)
+	c.Assert(report.RegionCount, Equals, 0)
+	c.Assert(report.Holes, HasLen, 1)
+	c.Assert(report.Holes[0].StartKey, Equals, "")
+	c.Assert(report.Holes[0].EndKey, Equals, "")
+
+	regions.AddRegion(newRegion(1, nil, t1))
+	regions.AddRegion(newRegion(2, t2, t3))
+	regions.AddRegion(newRegion(3, t3, nil))
+	a.Observe(1, now)
+	a.Observe(4, now)
+	report = a.Audit(regions, time.Minute, now)
+	c.Assert(report.RegionCount, Equals, 3)
+	c.Assert(report.Holes, HasLen, 1)
+	hole := report.Holes[0]
+	c.Assert(hole.Kind, Equals, KeySpaceHole)
+	c.Assert(hole.StartKey, Equals, string(core.HexRegionKey(t1)))
+	c.Assert(hole.EndKey, Equals, string(core.HexRegionKey(t2)))
+	c.Assert(hole.StartTableID, Equals, int64(1))
+	c.Assert(hole.EndTableID, Equals, int64(2))
+	c.Assert(hole.RegionIDs, DeepEquals, []uint64{1, 2})
+	c.Assert(report.StaleLeaders, HasLen, 0)
+
+	// The regions without heartbeat are recorded when they are first
+	// audited, and the removed regions are forgotten.
+	a.Observe(2, now.Add(time.Minute))
+	report = a.Audit(regions, time.Minute, now.Add(90*time.Second))
+	c.Assert(report.StaleLeaders, HasLen, 2)
+	c.Assert(report.StaleLeaders[0].Kind, Equals, KeySpaceStaleLeader)
+	c.Assert(report.StaleLeaders[0].RegionIDs, DeepEquals, []uint64{1})
+	c.Assert(*report.StaleLeaders[0].LastHeartbeat, Equals, now)
+	c.Assert(report.StaleLeaders[1].RegionIDs, DeepEquals, []uint64{3})
+	c.Assert(a.heartbeats, HasLen, 3)
+
+	// The tail of the key space is a hole.
+	regions.RemoveRegion(regions.GetRegion(3))
+	report = a.Audit(regions, time.Minute, now)
+	c.Assert(report.Holes, HasLen, 2)
+	c.Assert(report.Holes[1].StartKey, Equals, string(core.HexRegionKey(t3)))
+	c.Assert(report.Holes[1].EndKey, Equals, "")
+	c.Assert(report.Holes[1].RegionIDs, DeepEquals, []uint64{2})
+}
@@ -71,6 +71,14 @@ var (
 			Name:      "label_level",
 			Help:      "Number of regions in the different label level.",
 		}, []string{"type"})
+
+	keySpaceIssueGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "regions",
+			Name:      "key_space_issues",
+			Help:      "Number of the holes and stale leaders in the key space of the regions.",
+		}, []string{"type"})
 )
 
 func init() {
@@ -81,4 +89,5 @@ func init() {
 	prometheus.MustRegister(placementStatusGauge)
 	prometheus.MustRegister(configStatusGauge)
 	prometheus.MustRegister(regionLabelLevelGauge)
+	prometheus.MustRegister(keySpaceIssueGauge)
 }
@@ -35,8 +35,10 @@ type HotSpotPeerStat struct {
 	AntiCount int
 	// Version used to check the region split times
 	Version uint64
-	// Stats is a rolling statistics, recording some recently added records.
-	Stats *RollingStats
+	// Stats and KeysStats are rolling statistics of the flow bytes and the
+	// flow keys, recording some recently added records.
+	Stats     *RollingStats
+	KeysStats *RollingStats
 
 	needDelete bool
 	isLeader   bool
@@ -68,6 +70,7 @@ func (m RegionsStat) Less(i, j int) bool { return m[i].FlowBytes < m[j].FlowByte
 // HotRegionsStat records all hot regions statistics
 type HotRegionsStat struct {
 	TotalFlowBytes uint64      `json:"total_flow_bytes"`
+	TotalFlowKeys  uint64      `json:"total_flow_keys"`
 	RegionsCount   int         `json:"regions_count"`
 	RegionsStat    RegionsStat `json:"statistics"`
 }
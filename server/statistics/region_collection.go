@@ -168,7 +168,7 @@ func NewLabelLevelStatistics() *LabelLevelStatistics {
 // Observe records the current label status.
 func (l *LabelLevelStatistics) Observe(region *core.RegionInfo, stores []*core.StoreInfo, labels []string) {
 	regionID := region.GetID()
-	regionLabelLevel := getRegionLabelIsolationLevel(stores, labels)
+	regionLabelLevel := GetRegionLabelIsolationLevel(stores, labels)
 	if level, ok := l.regionLabelLevelStats[regionID]; ok {
 		if level == regionLabelLevel {
 			return
@@ -195,7 +195,10 @@ func (l *LabelLevelStatistics) ClearDefunctRegion(regionID uint64) {
 	}
 }
 
-func getRegionLabelIsolationLevel(stores []*core.StoreInfo, labels []string) int {
+// GetRegionLabelIsolationLevel returns the isolation level of the stores. Level
+// i (i > 0) means the stores are isolated by the i-th label, and 0 means they
+// are not isolated by any label.
+func GetRegionLabelIsolationLevel(stores []*core.StoreInfo, labels []string) int {
 	if len(stores) == 0 || len(labels) == 0 {
 		return 0
 	}
@@ -150,7 +150,7 @@ func (t *testRegionStatisticsSuite) TestRegionLabelIsolationLevel(c *C) {
 			stores = append(stores, s)
 		}
 		region := core.NewRegionInfo(&metapb.Region{Id: uint64(regionID)}, nil)
-		level := getRegionLabelIsolationLevel(stores, []string{"zone", "rack", "host"})
+		level := GetRegionLabelIsolationLevel(stores, []string{"zone", "rack", "host"})
 		labelLevelStats.Observe(region, stores, []string{"zone", "rack", "host"})
 		c.Assert(level, Equals, res)
 		regionID++
@@ -163,8 +163,8 @@ func (t *testRegionStatisticsSuite) TestRegionLabelIsolationLevel(c *C) {
 		c.Assert(labelLevelStats.labelLevelCounter[i], Equals, res)
 	}
 
-	level := getRegionLabelIsolationLevel(nil, []string{"zone", "rack", "host"})
+	level := GetRegionLabelIsolationLevel(nil, []string{"zone", "rack", "host"})
 	c.Assert(level, Equals, 0)
-	level = getRegionLabelIsolationLevel(nil, nil)
+	level = GetRegionLabelIsolationLevel(nil, nil)
 	c.Assert(level, Equals, 0)
 }
@@ -15,6 +15,7 @@ package statistics
 
 import (
 	"sync"
+	"time"
 
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/pingcap/pd/server/core"
@@ -63,6 +64,26 @@ func (s *StoresStats) Observe(storeID uint64, stats *pdpb.StoreStats) {
 	s.rollingStoresStats[storeID].Observe(stats)
 }
 
+// ObserveSlowness records the interval since the last heartbeat and the
+// pending peer count of the store to update its slowness score.
+func (s *StoresStats) ObserveSlowness(storeID uint64, stats *pdpb.StoreStats, interval time.Duration, pendingPeerCount int) {
+	s.RLock()
+	defer s.RUnlock()
+	if storeStat, ok := s.rollingStoresStats[storeID]; ok {
+		storeStat.ObserveSlowness(stats, interval, pendingPeerCount)
+	}
+}
+
+// GetStoreSlowScore returns the slowness score of the specified store.
+func (s *StoresStats) GetStoreSlowScore(storeID uint64) float64 {
+	s.RLock()
+	defer s.RUnlock()
+	if storeStat, ok := s.rollingStoresStats[storeID]; ok {
+		return storeStat.GetSlowScore()
+	}
+	return 0
+}
+
 // UpdateTotalBytesRate updates the total bytes write rate and read rate.
 func (s *StoresStats) UpdateTotalBytesRate(stores *core.StoresInfo) {
 	s.RLock()
@@ -102,6 +123,17 @@ func (s *StoresStats) GetStoreBytesRate(storeID uint64) (writeRate float64, read
 	return 0, 0
 }
 
+// GetStoreKeysRate returns the keys write rate and the keys read rate of the
+// specified store.
+func (s *StoresStats) GetStoreKeysRate(storeID uint64) (writeRate float64, readRate float64) {
+	s.RLock()
+	defer s.RUnlock()
+	if storeStat, ok := s.rollingStoresStats[storeID]; ok {
+		return storeStat.GetKeysWriteRate(), storeStat.GetKeysReadRate()
+	}
+	return 0, 0
+}
+
 // GetStoresBytesWriteStat returns the bytes write stat of all StoreInfo.
 func (s *StoresStats) GetStoresBytesWriteStat() map[uint64]uint64 {
 	s.RLock()
@@ -164,9 +196,29 @@ type RollingStoreStats struct {
 	bytesReadRate  *RollingStats
 	keysWriteRate  *RollingStats
 	keysReadRate   *RollingStats
+	// heartbeatIntervals and pendingPeers are the baseline of the store when
+	// it is not slow, and slowScores are the recent slowness scores.
+	heartbeatIntervals *RollingStats
+	pendingPeers       *RollingStats
+	slowScores         *RollingStats
 }
 
-const storeStatsRollingWindows = 3
+const (
+	storeStatsRollingWindows = 3
+	slowBaselineWindows      = 30
+	// slowBaselineMinSamples is the min number of the samples in the baseline
+	// to score the slowness.
+	slowBaselineMinSamples = 5
+	// slowPendingPeerStep is the number of the pending peers beyond the
+	// baseline which adds 1 to the slowness score.
+	slowPendingPeerStep = 10
+	// SlowStoreScoreThreshold is the slowness score from which a store is
+	// considered slow.
+	SlowStoreScoreThreshold = 3
+	// SlowStoreRecoverScore is the slowness score below which a slow store is
+	// considered recovered.
+	SlowStoreRecoverScore = 1.5
+)
 
 // NewRollingStoreStats creates a RollingStoreStats.
 func newRollingStoreStats() *RollingStoreStats {
@@ -175,6 +227,10 @@ func newRollingStoreStats() *RollingStoreStats {
 		bytesReadRate:  NewRollingStats(storeStatsRollingWindows),
 		keysWriteRate:  NewRollingStats(storeStatsRollingWindows),
 		keysReadRate:   NewRollingStats(storeStatsRollingWindows),
+
+		heartbeatIntervals: NewRollingStats(slowBaselineWindows),
+		pendingPeers:       NewRollingStats(slowBaselineWindows),
+		slowScores:         NewRollingStats(storeStatsRollingWindows),
 	}
 }
 
@@ -193,6 +249,43 @@ func (r *RollingStoreStats) Observe(stats *pdpb.StoreStats) {
 	r.keysReadRate.Add(float64(stats.KeysRead / interval))
 }
 
+// ObserveSlowness scores the slowness of the store by the interval since the
+// last heartbeat and the pending peer count compared to the baseline, and the
+// score is doubled if the store is busy. A normal store scores about 1. The
+// sample is added to the baseline only if the store is not slow, so that the
+// baseline does not follow a slow store.
+func (r *RollingStoreStats) ObserveSlowness(stats *pdpb.StoreStats, interval time.Duration, pendingPeerCount int) {
+	if interval <= 0 {
+		return
+	}
+	r.Lock()
+	defer r.Unlock()
+	seconds, pendingPeers := interval.Seconds(), float64(pendingPeerCount)
+	if r.heartbeatIntervals.count >= slowBaselineMinSamples {
+		score := seconds / r.heartbeatIntervals.Median()
+		if growth := pendingPeers - r.pendingPeers.Median(); growth > 0 {
+			score += growth / slowPendingPeerStep
+		}
+		if stats.GetIsBusy() {
+			score *= 2
+		}
+		r.slowScores.Add(score)
+		if score >= SlowStoreScoreThreshold {
+			return
+		}
+	}
+	r.heartbeatIntervals.Add(seconds)
+	r.pendingPeers.Add(pendingPeers)
+}
+
+// GetSlowScore returns the slowness score of the store, it is 0 if the
+// baseline is not ready.
+func (r *RollingStoreStats) GetSlowScore() float64 {
+	r.RLock()
+	defer r.RUnlock()
+	return r.slowScores.Median()
+}
+
 // GetBytesRate returns the bytes write rate and the bytes read rate.
 func (r *RollingStoreStats) GetBytesRate() (writeRate float64, readRate float64) {
 	r.RLock()
@@ -0,0 +1,192 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/pd/server/core"
+)
+
+const (
+	// CapacitySampleInterval is the min interval between two capacity samples
+	// of a store.
+	CapacitySampleInterval = 10 * time.Minute
+	// DefaultCapacitySampleLimit is the default max number of the capacity
+	// samples kept for a store, which covers 7 days.
+	DefaultCapacitySampleLimit = 7 * 24 * 6
+
+	secondsPerDay = float64(24 * time.Hour / time.Second)
+)
+
+type capacitySample struct {
+	time      time.Time
+	available uint64
+	usedSize  uint64
+}
+
+// CapacityForecast is the projection of the space of a store or the cluster.
+type CapacityForecast struct {
+	// StoreID is 0 for the cluster.
+	StoreID   uint64 `json:"store_id,omitempty"`
+	Capacity  uint64 `json:"capacity"`
+	Available uint64 `json:"available"`
+	UsedSize  uint64 `json:"used_size"`
+	// LowSpaceAvailable is the available size when the store reaches the
+	// low space ratio.
+	LowSpaceAvailable uint64 `json:"low_space_available"`
+	// UsedGrowthPerDay and AvailableDeclinePerDay are the fitted trend of the
+	// used size and the available size in bytes per day.
+	UsedGrowthPerDay       float64 `json:"used_growth_per_day"`
+	AvailableDeclinePerDay float64 `json:"available_decline_per_day"`
+	// DaysToLowSpace is the projected days until the low space ratio is
+	// reached, it is nil if the available size is not declining or there are
+	// not enough samples.
+	DaysToLowSpace *float64 `json:"days_to_low_space,omitempty"`
+	Samples        int      `json:"samples"`
+}
+
+func (f *CapacityForecast) project() {
+	if f.Available <= f.LowSpaceAvailable {
+		days := 0.0
+		f.DaysToLowSpace = &days
+		return
+	}
+	if f.AvailableDeclinePerDay <= 0 {
+		return
+	}
+	days := float64(f.Available-f.LowSpaceAvailable) / f.AvailableDeclinePerDay
+	f.DaysToLowSpace = &days
+}
+
+// CapacityForecasts is the capacity forecast of the stores and the cluster.
+type CapacityForecasts struct {
+	Stores  []*CapacityForecast `json:"stores"`
+	Cluster *CapacityForecast   `json:"cluster"`
+}
+
+// StoresCapacityHistory keeps the samples of the available size and the used
+// size of the stores to forecast when the stores run out of space.
+type StoresCapacityHistory struct {
+	sync.RWMutex
+	limit   int
+	samples map[uint64][]capacitySample
+}
+
+// NewStoresCapacityHistory creates a StoresCapacityHistory which keeps at most
+// limit samples for a store.
+func NewStoresCapacityHistory(limit int) *StoresCapacityHistory {
+	return &StoresCapacityHistory{
+		limit:   limit,
+		samples: make(map[uint64][]capacitySample),
+	}
+}
+
+// Observe records a sample of the store stats taken at now, the sample is
+// skipped if the last sample is taken within CapacitySampleInterval.
+func (h *StoresCapacityHistory) Observe(stats *pdpb.StoreStats, now time.Time) {
+	h.Lock()
+	defer h.Unlock()
+	storeID := stats.GetStoreId()
+	samples := h.samples[storeID]
+	if len(samples) > 0 && now.Sub(samples[len(samples)-1].time) < CapacitySampleInterval {
+		return
+	}
+	samples = append(samples, capacitySample{
+		time:      now,
+		available: stats.GetAvailable(),
+		usedSize:  stats.GetUsedSize(),
+	})
+	if len(samples) > h.limit {
+		samples = samples[len(samples)-h.limit:]
+	}
+	h.samples[storeID] = samples
+}
+
+// Remove removes the samples of the store.
+func (h *StoresCapacityHistory) Remove(storeID uint64) {
+	h.Lock()
+	defer h.Unlock()
+	delete(h.samples, storeID)
+}
+
+// Forecast projects the days until the stores and the cluster reach the low
+// space ratio by the linear trend of the samples. The tombstone stores and
+// the stores which have not reported the capacity are skipped.
+func (h *StoresCapacityHistory) Forecast(stores []*core.StoreInfo, lowSpaceRatio float64) *CapacityForecasts {
+	h.RLock()
+	defer h.RUnlock()
+	forecasts := &CapacityForecasts{
+		Stores:  make([]*CapacityForecast, 0, len(stores)),
+		Cluster: &CapacityForecast{},
+	}
+	for _, store := range stores {
+		if store.GetCapacity() == 0 || store.IsTombstone() {
+			continue
+		}
+		samples := h.samples[store.GetID()]
+		f := &CapacityForecast{
+			StoreID:                store.GetID(),
+			Capacity:               store.GetCapacity(),
+			Available:              store.GetAvailable(),
+			UsedSize:               store.GetUsedSize(),
+			LowSpaceAvailable:      uint64(float64(store.GetCapacity()) * (1 - lowSpaceRatio)),
+			UsedGrowthPerDay:       fitSlope(samples, func(s capacitySample) uint64 { return s.usedSize }) * secondsPerDay,
+			AvailableDeclinePerDay: -fitSlope(samples, func(s capacitySample) uint64 { return s.available }) * secondsPerDay,
+			Samples:                len(samples),
+		}
+		if len(samples) >= 2 {
+			f.project()
+		}
+		forecasts.Stores = append(forecasts.Stores, f)
+
+		c := forecasts.Cluster
+		c.Capacity += f.Capacity
+		c.Available += f.Available
+		c.UsedSize += f.UsedSize
+		c.LowSpaceAvailable += f.LowSpaceAvailable
+		c.UsedGrowthPerDay += f.UsedGrowthPerDay
+		c.AvailableDeclinePerDay += f.AvailableDeclinePerDay
+		c.Samples += f.Samples
+	}
+	if forecasts.Cluster.Samples >= 2 {
+		forecasts.Cluster.project()
+	}
+	return forecasts
+}
+
+// fitSlope returns the slope per second of the least squares line fitted to
+// the values of the samples.
+func fitSlope(samples []capacitySample, value func(s capacitySample) uint64) float64 {
+	if len(samples) < 2 {
+		return 0
+	}
+	var sumX, sumY, sumXY, sumXX float64
+	for _, s := range samples {
+		x := s.time.Sub(samples[0].time).Seconds()
+		y := float64(value(s))
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	n := float64(len(samples))
+	d := n*sumXX - sumX*sumX
+	if d == 0 {
+		return 0
+	}
+	return (n*sumXY - sumX*sumY) / d
+}
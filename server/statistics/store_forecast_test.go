@@ -0,0 +1,81 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/pd/server/core"
+)
+
+var _ = Suite(&testCapacityForecastSuite{})
+
+type testCapacityForecastSuite struct{}
+
+func (t *testCapacityForecastSuite) TestForecast(c *C) {
+	const gb = 1 << 30
+	h := NewStoresCapacityHistory(3)
+	now := time.Now()
+	newStats := func(storeID, available uint64) *pdpb.StoreStats {
+		return &pdpb.StoreStats{StoreId: storeID, Capacity: 100 * gb, Available: available, UsedSize: 100*gb - available}
+	}
+	// Store 1 consumes 1GB per day, store 2 is stable and store 3 is low
+	// space already.
+	for i := 0; i < 5; i++ {
+		ts := now.Add(time.Duration(i) * 24 * time.Hour)
+		h.Observe(newStats(1, uint64(50-i)*gb), ts)
+		h.Observe(newStats(2, 50*gb), ts)
+		h.Observe(newStats(3, 10*gb), ts)
+		// The sample within the sample interval is skipped.
+		h.Observe(newStats(1, 0), ts.Add(time.Minute))
+	}
+	h.Observe(newStats(4, 50*gb), now)
+	h.Remove(4)
+
+	var stores []*core.StoreInfo
+	for id := uint64(1); id <= 4; id++ {
+		stores = append(stores, core.NewStoreInfo(&metapb.Store{Id: id}, core.SetStoreStats(newStats(id, 0))))
+	}
+	stores[0] = stores[0].Clone(core.SetStoreStats(newStats(1, 46*gb)))
+	stores[1] = stores[1].Clone(core.SetStoreStats(newStats(2, 50*gb)))
+	stores[2] = stores[2].Clone(core.SetStoreStats(newStats(3, 10*gb)))
+	stores[3] = stores[3].Clone(core.SetStoreStats(newStats(4, 50*gb)))
+	stores = append(stores, core.NewStoreInfo(&metapb.Store{Id: 5}))
+
+	forecasts := h.Forecast(stores, 0.75)
+	c.Assert(forecasts.Stores, HasLen, 4)
+	f := forecasts.Stores[0]
+	c.Assert(f.StoreID, Equals, uint64(1))
+	c.Assert(f.Samples, Equals, 3)
+	c.Assert(f.LowSpaceAvailable, Equals, uint64(25*gb))
+	c.Assert(f.AvailableDeclinePerDay, Equals, float64(gb))
+	c.Assert(f.UsedGrowthPerDay, Equals, float64(gb))
+	c.Assert(*f.DaysToLowSpace, Equals, float64(21))
+	c.Assert(forecasts.Stores[1].AvailableDeclinePerDay, Equals, float64(0))
+	c.Assert(forecasts.Stores[1].DaysToLowSpace, IsNil)
+	c.Assert(*forecasts.Stores[2].DaysToLowSpace, Equals, float64(0))
+	// There are not enough samples of store 4.
+	c.Assert(forecasts.Stores[3].Samples, Equals, 0)
+	c.Assert(forecasts.Stores[3].DaysToLowSpace, IsNil)
+
+	cluster := forecasts.Cluster
+	c.Assert(cluster.StoreID, Equals, uint64(0))
+	c.Assert(cluster.Capacity, Equals, uint64(400*gb))
+	c.Assert(cluster.Available, Equals, uint64(156*gb))
+	c.Assert(cluster.LowSpaceAvailable, Equals, uint64(100*gb))
+	c.Assert(*cluster.DaysToLowSpace, Equals, float64(56))
+}
@@ -0,0 +1,59 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+)
+
+var _ = Suite(&testStoreStatsSuite{})
+
+type testStoreStatsSuite struct{}
+
+func (t *testStoreStatsSuite) TestSlowScore(c *C) {
+	s := NewStoresStats()
+	s.CreateRollingStoreStats(1)
+	normal := &pdpb.StoreStats{StoreId: 1}
+	busy := &pdpb.StoreStats{StoreId: 1, IsBusy: true}
+
+	// The score is 0 before the baseline is ready.
+	for i := 0; i < slowBaselineMinSamples; i++ {
+		s.ObserveSlowness(1, normal, 10*time.Second, 10)
+	}
+	c.Assert(s.GetStoreSlowScore(1), Equals, float64(0))
+	c.Assert(s.GetStoreSlowScore(2), Equals, float64(0))
+
+	s.ObserveSlowness(1, normal, 10*time.Second, 10)
+	c.Assert(s.GetStoreSlowScore(1), Equals, float64(1))
+
+	// The busy flag doubles the score and the pending peers beyond the
+	// baseline add to it.
+	for i := 0; i < storeStatsRollingWindows; i++ {
+		s.ObserveSlowness(1, busy, 10*time.Second, 20)
+	}
+	c.Assert(s.GetStoreSlowScore(1), Equals, float64(4))
+
+	// The slow samples are not added to the baseline.
+	for i := 0; i < slowBaselineWindows; i++ {
+		s.ObserveSlowness(1, normal, 30*time.Second, 10)
+	}
+	c.Assert(s.GetStoreSlowScore(1), Equals, float64(3))
+	for i := 0; i < storeStatsRollingWindows; i++ {
+		s.ObserveSlowness(1, normal, 10*time.Second, 10)
+	}
+	c.Assert(s.GetStoreSlowScore(1), Equals, float64(1))
+}
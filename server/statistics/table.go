@@ -0,0 +1,116 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/table"
+	"github.com/pkg/errors"
+)
+
+// TableStats records the statistics of the regions of a table and the flows
+// reported by their latest heartbeats.
+type TableStats struct {
+	TableID int64 `json:"table_id"`
+	*RegionStats
+	WrittenBytes uint64 `json:"written_bytes"`
+	ReadBytes    uint64 `json:"read_bytes"`
+	WrittenKeys  uint64 `json:"written_keys"`
+	ReadKeys     uint64 `json:"read_keys"`
+}
+
+func newTableStats(tableID int64) *TableStats {
+	return &TableStats{
+		TableID:     tableID,
+		RegionStats: newRegionStats(),
+	}
+}
+
+// Observe adds a region's statistics into TableStats.
+func (s *TableStats) Observe(r *core.RegionInfo) {
+	s.RegionStats.Observe(r)
+	s.WrittenBytes += r.GetBytesWritten()
+	s.ReadBytes += r.GetBytesRead()
+	s.WrittenKeys += r.GetKeysWritten()
+	s.ReadKeys += r.GetKeysRead()
+}
+
+func tableStartKey(tableID int64) []byte {
+	return table.EncodeBytes(table.GenerateTableKey(tableID))
+}
+
+// GetTableStats sums up the statistics of the regions that intersect the
+// table.
+func GetTableStats(r *core.RegionsInfo, tableID int64) *TableStats {
+	stats := newTableStats(tableID)
+	for _, region := range r.ScanRangeWithEndKey(tableStartKey(tableID), tableStartKey(tableID+1)) {
+		stats.Observe(region)
+	}
+	return stats
+}
+
+// GetTablesStats sums up the statistics of the tables, ordered by the table
+// ID. A region is counted in the tables of its start key and end key, so the
+// tables whose keys are all inside a region spanning more than two tables are
+// not listed.
+func GetTablesStats(r *core.RegionsInfo) []*TableStats {
+	tables := make([]*TableStats, 0)
+	observe := func(tableID int64, region *core.RegionInfo) {
+		if len(tables) == 0 || tables[len(tables)-1].TableID != tableID {
+			tables = append(tables, newTableStats(tableID))
+		}
+		tables[len(tables)-1].Observe(region)
+	}
+	for _, region := range r.ScanRangeWithEndKey(nil, nil) {
+		startID := table.Key(region.GetStartKey()).TableID()
+		if startID != 0 {
+			observe(startID, region)
+		}
+		endKey := region.GetEndKey()
+		endID := table.Key(endKey).TableID()
+		// The end key is exclusive, so the region has no key of the table if
+		// the end key is the start key of the table.
+		if endID != 0 && endID != startID && bytes.Compare(endKey, tableStartKey(endID)) > 0 {
+			observe(endID, region)
+		}
+	}
+	return tables
+}
+
+// tableStatsSortKeys are the keys to sort the table statistics by.
+var tableStatsSortKeys = map[string]func(s *TableStats) uint64{
+	"size":          func(s *TableStats) uint64 { return uint64(s.StorageSize) },
+	"keys":          func(s *TableStats) uint64 { return uint64(s.StorageKeys) },
+	"regions":       func(s *TableStats) uint64 { return uint64(s.Count) },
+	"written_bytes": func(s *TableStats) uint64 { return s.WrittenBytes },
+	"read_bytes":    func(s *TableStats) uint64 { return s.ReadBytes },
+	"written_keys":  func(s *TableStats) uint64 { return s.WrittenKeys },
+	"read_keys":     func(s *TableStats) uint64 { return s.ReadKeys },
+}
+
+// SortTableStats sorts the table statistics by the key in descending order,
+// the tables with the same value are ordered by the table ID.
+func SortTableStats(stats []*TableStats, key string) error {
+	value, ok := tableStatsSortKeys[key]
+	if !ok {
+		return errors.Errorf("unknown sort key %s", key)
+	}
+	sort.SliceStable(stats, func(i, j int) bool {
+		return value(stats[i]) > value(stats[j])
+	})
+	return nil
+}
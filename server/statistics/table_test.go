@@ -0,0 +1,72 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/table"
+)
+
+var _ = Suite(&testTableStatsSuite{})
+
+type testTableStatsSuite struct{}
+
+func (t *testTableStatsSuite) TestTableStats(c *C) {
+	t1 := table.EncodeBytes(table.GenerateTableKey(1))
+	t1r := table.EncodeBytes(table.GenerateRowKey(1, 10))
+	t3r := table.EncodeBytes(table.GenerateRowKey(3, 10))
+	newRegion := func(id uint64, startKey, endKey []byte, opts ...core.RegionCreateOption) *core.RegionInfo {
+		peer := &metapb.Peer{Id: id, StoreId: id}
+		meta := &metapb.Region{Id: id, StartKey: startKey, EndKey: endKey, Peers: []*metapb.Peer{peer}}
+		return core.NewRegionInfo(meta, peer, opts...)
+	}
+	regions := core.NewRegionsInfo()
+	regions.AddRegion(newRegion(1, nil, t1, core.SetApproximateSize(10)))
+	regions.AddRegion(newRegion(2, t1, t1r, core.SetApproximateSize(20), core.SetWrittenBytes(100)))
+	regions.AddRegion(newRegion(3, t1r, t3r, core.SetApproximateSize(30), core.SetReadBytes(50)))
+	regions.AddRegion(newRegion(4, t3r, nil, core.SetApproximateSize(40)))
+
+	// Region 1 has no key of table 1, and region 3 is counted in table 1 and
+	// table 3.
+	stats := GetTablesStats(regions)
+	c.Assert(stats, HasLen, 2)
+	c.Assert(stats[0].TableID, Equals, int64(1))
+	c.Assert(stats[0].Count, Equals, 2)
+	c.Assert(stats[0].StorageSize, Equals, int64(50))
+	c.Assert(stats[0].StoreLeaderCount, DeepEquals, map[uint64]int{2: 1, 3: 1})
+	c.Assert(stats[0].WrittenBytes, Equals, uint64(100))
+	c.Assert(stats[0].ReadBytes, Equals, uint64(50))
+	c.Assert(stats[1].TableID, Equals, int64(3))
+	c.Assert(stats[1].Count, Equals, 2)
+	c.Assert(stats[1].StorageSize, Equals, int64(70))
+	c.Assert(stats[1].WrittenBytes, Equals, uint64(0))
+
+	c.Assert(SortTableStats(stats, "size"), IsNil)
+	c.Assert(stats[0].TableID, Equals, int64(3))
+	c.Assert(SortTableStats(stats, "written_bytes"), IsNil)
+	c.Assert(stats[0].TableID, Equals, int64(1))
+	c.Assert(SortTableStats(stats, "unknown"), NotNil)
+
+	// The table inside a region is not listed but can be queried.
+	s := GetTableStats(regions, 2)
+	c.Assert(s.TableID, Equals, int64(2))
+	c.Assert(s.Count, Equals, 1)
+	c.Assert(s.StorageSize, Equals, int64(30))
+	c.Assert(s.ReadBytes, Equals, uint64(50))
+	s = GetTableStats(regions, 5)
+	c.Assert(s.Count, Equals, 1)
+	c.Assert(s.StorageSize, Equals, int64(40))
+}
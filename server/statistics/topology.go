@@ -0,0 +1,174 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"sort"
+
+	"github.com/pingcap/pd/server/core"
+)
+
+// TopologyNode is a location or a store in the topology of the cluster, the
+// stats of a location are aggregated from the stores inside it.
+type TopologyNode struct {
+	// Label and Value are the location label of the node, both are empty for
+	// the root and the stores.
+	Label string `json:"label,omitempty"`
+	Value string `json:"value,omitempty"`
+	// StoreID and Address are only set for the stores.
+	StoreID uint64 `json:"store_id,omitempty"`
+	Address string `json:"address,omitempty"`
+
+	StoreCount     int     `json:"store_count"`
+	Capacity       uint64  `json:"capacity"`
+	UsedSize       uint64  `json:"used_size"`
+	Available      uint64  `json:"available"`
+	RegionCount    int     `json:"region_count"`
+	LeaderCount    int     `json:"leader_count"`
+	BytesWriteRate float64 `json:"bytes_write_rate"`
+	BytesReadRate  float64 `json:"bytes_read_rate"`
+
+	// MaxRegionReplicas is the max replicas a region with the max replicas of
+	// the cluster is allowed to have inside the node, and
+	// IsolationRiskRegions is the number of the regions which have more
+	// replicas inside the node than their rules allow.
+	MaxRegionReplicas    int  `json:"max_region_replicas,omitempty"`
+	IsolationRiskRegions int  `json:"isolation_risk_regions,omitempty"`
+	IsolationAtRisk      bool `json:"isolation_at_risk,omitempty"`
+
+	Children []*TopologyNode `json:"children,omitempty"`
+
+	// level is the index of the location label of the node.
+	level int
+}
+
+// child returns the child location with the value, created is true if the
+// child is newly created.
+func (n *TopologyNode) child(label, value string) (child *TopologyNode, created bool) {
+	for _, c := range n.Children {
+		if c.Value == value {
+			return c, false
+		}
+	}
+	child = &TopologyNode{Label: label, Value: value, level: n.level + 1}
+	n.Children = append(n.Children, child)
+	return child, true
+}
+
+func (n *TopologyNode) observe(store *core.StoreInfo, storesStats *StoresStats) {
+	n.StoreCount++
+	n.Capacity += store.GetCapacity()
+	n.UsedSize += store.GetUsedSize()
+	n.Available += store.GetAvailable()
+	n.RegionCount += store.GetRegionCount()
+	n.LeaderCount += store.GetLeaderCount()
+	writeRate, readRate := storesStats.GetStoreBytesRate(store.GetID())
+	n.BytesWriteRate += writeRate
+	n.BytesReadRate += readRate
+}
+
+func (n *TopologyNode) sort() {
+	sort.Slice(n.Children, func(i, j int) bool {
+		if n.Children[i].StoreID != n.Children[j].StoreID {
+			return n.Children[i].StoreID < n.Children[j].StoreID
+		}
+		return n.Children[i].Value < n.Children[j].Value
+	})
+	for _, c := range n.Children {
+		c.sort()
+	}
+}
+
+// Topology is the tree of the stores grouped by the location labels.
+type Topology struct {
+	LocationLabels []string      `json:"location_labels"`
+	MaxReplicas    int           `json:"max_replicas"`
+	Root           *TopologyNode `json:"root"`
+}
+
+// BuildTopology groups the stores by the location labels level by level, and
+// checks the isolation of the regions. A location is at risk if a region has
+// more voters inside it than allowed, which is the voters required by the
+// region's rule divided by the number of the locations on the same level,
+// rounded up. The tombstone stores are skipped, and a store without a label
+// is put in the location with the empty value, which is not counted as a
+// location when dividing the voters.
+func BuildTopology(stores []*core.StoreInfo, regions []*core.RegionInfo, storesStats *StoresStats, locationLabels []string, maxReplicas int, regionVoters func(region *core.RegionInfo) int) *Topology {
+	topo := &Topology{
+		LocationLabels: locationLabels,
+		MaxReplicas:    maxReplicas,
+		Root:           &TopologyNode{level: -1},
+	}
+	// paths is the locations of the stores from the top level to the bottom.
+	paths := make(map[uint64][]*TopologyNode)
+	levelCounts := make([]int, len(locationLabels))
+	for _, store := range stores {
+		if store.IsTombstone() {
+			continue
+		}
+		topo.Root.observe(store, storesStats)
+		node := topo.Root
+		path := make([]*TopologyNode, 0, len(locationLabels))
+		for level, label := range locationLabels {
+			var created bool
+			value := store.GetLabelValue(label)
+			node, created = node.child(label, value)
+			if created && value != "" {
+				levelCounts[level]++
+			}
+			node.observe(store, storesStats)
+			path = append(path, node)
+		}
+		leaf := &TopologyNode{StoreID: store.GetID(), Address: store.GetAddress()}
+		leaf.observe(store, storesStats)
+		node.Children = append(node.Children, leaf)
+		paths[store.GetID()] = path
+	}
+
+	// allowed returns the max replicas a region is allowed to have inside a
+	// location on the level.
+	allowed := func(replicas, level int) int {
+		count := levelCounts[level]
+		if count == 0 {
+			count = 1
+		}
+		return (replicas + count - 1) / count
+	}
+	for _, path := range paths {
+		for level, node := range path {
+			node.MaxRegionReplicas = allowed(maxReplicas, level)
+		}
+	}
+
+	replicas := make(map[*TopologyNode]int)
+	for _, region := range regions {
+		for k := range replicas {
+			delete(replicas, k)
+		}
+		for _, peer := range region.GetVoters() {
+			for _, node := range paths[peer.GetStoreId()] {
+				replicas[node]++
+			}
+		}
+		voters := regionVoters(region)
+		for node, count := range replicas {
+			if count > allowed(voters, node.level) {
+				node.IsolationRiskRegions++
+				node.IsolationAtRisk = true
+			}
+		}
+	}
+	topo.Root.sort()
+	return topo
+}
@@ -0,0 +1,147 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"fmt"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/pd/server/core"
+)
+
+var _ = Suite(&testTopologySuite{})
+
+type testTopologySuite struct{}
+
+func (t *testTopologySuite) TestBuildTopology(c *C) {
+	newStore := func(id uint64, zone, host string, state metapb.StoreState) *core.StoreInfo {
+		meta := &metapb.Store{
+			Id:      id,
+			Address: fmt.Sprintf("tikv%d", id),
+			State:   state,
+			Labels: []*metapb.StoreLabel{
+				{Key: "zone", Value: zone},
+				{Key: "host", Value: host},
+			},
+		}
+		return core.NewStoreInfo(meta,
+			core.SetStoreStats(&pdpb.StoreStats{StoreId: id, Capacity: 100, Available: 60, UsedSize: 30}),
+			core.SetRegionCount(int(id)),
+			core.SetLeaderCount(1),
+		)
+	}
+	stores := []*core.StoreInfo{
+		newStore(2, "z1", "h2", metapb.StoreState_Up),
+		newStore(1, "z1", "h1", metapb.StoreState_Up),
+		newStore(3, "z2", "h3", metapb.StoreState_Up),
+		newStore(4, "z3", "h4", metapb.StoreState_Offline),
+		newStore(5, "z4", "h5", metapb.StoreState_Tombstone),
+	}
+	newRegion := func(id uint64, storeIDs ...uint64) *core.RegionInfo {
+		meta := &metapb.Region{Id: id, StartKey: []byte(fmt.Sprintf("%20d", id)), EndKey: []byte(fmt.Sprintf("%20d", id+1))}
+		for i, storeID := range storeIDs {
+			meta.Peers = append(meta.Peers, &metapb.Peer{Id: id*10 + uint64(i), StoreId: storeID})
+		}
+		return core.NewRegionInfo(meta, meta.Peers[0])
+	}
+	region := newRegion(3, 1, 2, 4)
+	region = region.Clone(core.WithAddPeer(&metapb.Peer{Id: 100, StoreId: 3, IsLearner: true}))
+	regions := []*core.RegionInfo{newRegion(1, 1, 3, 4), newRegion(2, 1, 2, 3), region}
+	voters := make(map[uint64]int)
+	regionVoters := func(region *core.RegionInfo) int {
+		if n, ok := voters[region.GetID()]; ok {
+			return n
+		}
+		return 3
+	}
+	storesStats := NewStoresStats()
+	for _, store := range stores {
+		storesStats.CreateRollingStoreStats(store.GetID())
+	}
+
+	topo := BuildTopology(stores, regions, storesStats, []string{"zone", "host"}, 3, regionVoters)
+	c.Assert(topo.LocationLabels, DeepEquals, []string{"zone", "host"})
+	c.Assert(topo.MaxReplicas, Equals, 3)
+	root := topo.Root
+	c.Assert(root.StoreCount, Equals, 4)
+	c.Assert(root.Capacity, Equals, uint64(400))
+	c.Assert(root.Available, Equals, uint64(240))
+	c.Assert(root.UsedSize, Equals, uint64(120))
+	c.Assert(root.RegionCount, Equals, 10)
+	c.Assert(root.LeaderCount, Equals, 4)
+	c.Assert(root.IsolationAtRisk, IsFalse)
+
+	// The tombstone store is skipped and the locations are sorted.
+	c.Assert(root.Children, HasLen, 3)
+	z1, z2, z3 := root.Children[0], root.Children[1], root.Children[2]
+	c.Assert(z1.Label, Equals, "zone")
+	c.Assert(z1.Value, Equals, "z1")
+	c.Assert(z2.Value, Equals, "z2")
+	c.Assert(z3.Value, Equals, "z3")
+	c.Assert(z1.StoreCount, Equals, 2)
+	c.Assert(z1.RegionCount, Equals, 3)
+
+	// 3 zones allow 1 replica of a region in a zone. Region 2 and region 3
+	// have 2 voters in zone z1, and the learner of region 3 is not counted.
+	c.Assert(z1.MaxRegionReplicas, Equals, 1)
+	c.Assert(z1.IsolationRiskRegions, Equals, 2)
+	c.Assert(z1.IsolationAtRisk, IsTrue)
+	c.Assert(z2.IsolationRiskRegions, Equals, 0)
+	c.Assert(z3.IsolationAtRisk, IsFalse)
+
+	c.Assert(z1.Children, HasLen, 2)
+	h1 := z1.Children[0]
+	c.Assert(h1.Label, Equals, "host")
+	c.Assert(h1.Value, Equals, "h1")
+	c.Assert(h1.MaxRegionReplicas, Equals, 1)
+	c.Assert(h1.IsolationAtRisk, IsFalse)
+	c.Assert(h1.Children, HasLen, 1)
+	c.Assert(h1.Children[0].StoreID, Equals, uint64(1))
+	c.Assert(h1.Children[0].Address, Equals, "tikv1")
+	c.Assert(h1.Children[0].Children, HasLen, 0)
+
+	// A rule with 5 voters allows 2 voters of region 2 in a zone.
+	voters[2] = 5
+	topo = BuildTopology(stores, regions, storesStats, []string{"zone", "host"}, 3, regionVoters)
+	c.Assert(topo.Root.Children[0].MaxRegionReplicas, Equals, 1)
+	c.Assert(topo.Root.Children[0].IsolationRiskRegions, Equals, 1)
+	delete(voters, 2)
+
+	// With 2 zones, a zone is allowed to have 2 replicas of a region.
+	topo = BuildTopology(stores[:3], regions, storesStats, []string{"zone"}, 3, regionVoters)
+	c.Assert(topo.Root.Children, HasLen, 2)
+	c.Assert(topo.Root.Children[0].MaxRegionReplicas, Equals, 2)
+	c.Assert(topo.Root.Children[0].IsolationAtRisk, IsFalse)
+
+	// The stores without the label are not counted as a zone.
+	storesStats.CreateRollingStoreStats(6)
+	noLabel := core.NewStoreInfo(&metapb.Store{Id: 6, Address: "tikv6", State: metapb.StoreState_Up})
+	topo = BuildTopology(append(stores[:3:3], noLabel), regions, storesStats, []string{"zone"}, 3, regionVoters)
+	c.Assert(topo.Root.Children, HasLen, 3)
+	c.Assert(topo.Root.Children[0].Value, Equals, "")
+	c.Assert(topo.Root.Children[1].MaxRegionReplicas, Equals, 2)
+	c.Assert(topo.Root.Children[1].IsolationAtRisk, IsFalse)
+
+	// Without the location labels, the stores are the children of the root.
+	topo = BuildTopology(stores, regions, storesStats, nil, 3, regionVoters)
+	c.Assert(topo.Root.Children, HasLen, 4)
+	c.Assert(topo.Root.Children[0].StoreID, Equals, uint64(1))
+	c.Assert(topo.Root.IsolationAtRisk, IsFalse)
+
+	topo = BuildTopology(nil, nil, storesStats, []string{"zone"}, 3, regionVoters)
+	c.Assert(topo.Root.StoreCount, Equals, 0)
+	c.Assert(topo.Root.Children, HasLen, 0)
+}
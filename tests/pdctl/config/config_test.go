@@ -15,6 +15,8 @@ package config_test
 
 import (
 	"encoding/json"
+	"io/ioutil"
+	"os"
 	"testing"
 
 	"github.com/coreos/go-semver/semver"
@@ -22,6 +24,8 @@ import (
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/pd/server"
 	"github.com/pingcap/pd/server/config"
+	"github.com/pingcap/pd/server/placement"
+	"github.com/pingcap/pd/server/placement/rule"
 	"github.com/pingcap/pd/tests"
 	"github.com/pingcap/pd/tests/pdctl"
 )
@@ -168,6 +172,9 @@ func (s *configTestSuite) TestConfig(c *C) {
 	args1 = []string{"-u", pdAddr, "config", "set", "hot-region-cache-hits-threshold", "5"}
 	_, _, err = pdctl.ExecuteCommandC(cmd, args1...)
 	c.Assert(err, IsNil)
+	args1 = []string{"-u", pdAddr, "config", "set", "hot-region-balance-dimension", "keys"}
+	_, _, err = pdctl.ExecuteCommandC(cmd, args1...)
+	c.Assert(err, IsNil)
 	args2 = []string{"-u", pdAddr, "config", "show"}
 	_, output, err = pdctl.ExecuteCommandC(cmd, args2...)
 	c.Assert(err, IsNil)
@@ -178,6 +185,7 @@ func (s *configTestSuite) TestConfig(c *C) {
 	c.Assert(scheduleCfg.HotRegionScheduleLimit, Equals, svr.GetScheduleConfig().HotRegionScheduleLimit)
 	c.Assert(scheduleCfg.HotRegionCacheHitsThreshold, Equals, svr.GetScheduleConfig().HotRegionCacheHitsThreshold)
 	c.Assert(scheduleCfg.HotRegionCacheHitsThreshold, Equals, uint64(5))
+	c.Assert(scheduleCfg.HotRegionBalanceDimension, Equals, "keys")
 	c.Assert(scheduleCfg.HotRegionScheduleLimit, Equals, uint64(64))
 	c.Assert(scheduleCfg.LeaderScheduleLimit, Equals, uint64(64))
 	args1 = []string{"-u", pdAddr, "config", "set", "disable-raft-learner", "true"}
@@ -190,4 +198,61 @@ func (s *configTestSuite) TestConfig(c *C) {
 	c.Assert(json.Unmarshal(output, &cfg), IsNil)
 	scheduleCfg = cfg.Schedule
 	c.Assert(scheduleCfg.DisableLearner, Equals, svr.GetScheduleConfig().DisableLearner)
+
+	// config placement set <constraints>
+	args1 = []string{"-u", pdAddr, "config", "placement", "set", "count(zone:z1)>=2;count_leader(zone:z1)=1"}
+	_, _, err = pdctl.ExecuteCommandC(cmd, args1...)
+	c.Assert(err, IsNil)
+	placementCfg, err := svr.GetPlacementConfig()
+	c.Assert(err, IsNil)
+	c.Assert(placementCfg.String(), Equals, "count(zone:z1)>=2;count_leader(zone:z1)=1")
+
+	// config placement show
+	args2 = []string{"-u", pdAddr, "config", "placement", "show"}
+	_, output, err = pdctl.ExecuteCommandC(cmd, args2...)
+	c.Assert(err, IsNil)
+	showCfg := &placement.Config{}
+	c.Assert(json.Unmarshal(output, showCfg), IsNil)
+	c.Assert(showCfg, DeepEquals, placementCfg)
+
+	// config placement delete
+	args3 = []string{"-u", pdAddr, "config", "placement", "delete"}
+	_, _, err = pdctl.ExecuteCommandC(cmd, args3...)
+	c.Assert(err, IsNil)
+	placementCfg, err = svr.GetPlacementConfig()
+	c.Assert(err, IsNil)
+	c.Assert(placementCfg.Constraints, HasLen, 0)
+
+	// config rule set <rule_file>
+	ruleFile, err := ioutil.TempFile("", "rule")
+	c.Assert(err, IsNil)
+	defer os.Remove(ruleFile.Name())
+	_, err = ruleFile.WriteString(`{"id": "t1", "start_key": "61", "end_key": "62", "voters": 5}`)
+	c.Assert(err, IsNil)
+	c.Assert(ruleFile.Close(), IsNil)
+	args1 = []string{"-u", pdAddr, "config", "rule", "set", ruleFile.Name()}
+	_, _, err = pdctl.ExecuteCommandC(cmd, args1...)
+	c.Assert(err, IsNil)
+	ruleManager := svr.GetRaftCluster().GetRuleManager()
+	c.Assert(ruleManager.GetRule("t1").Voters, Equals, 5)
+
+	// config rule show [<rule_id>]
+	args2 = []string{"-u", pdAddr, "config", "rule", "show"}
+	_, output, err = pdctl.ExecuteCommandC(cmd, args2...)
+	c.Assert(err, IsNil)
+	var rules []*rule.Rule
+	c.Assert(json.Unmarshal(output, &rules), IsNil)
+	c.Assert(rules, HasLen, 1)
+	args2 = []string{"-u", pdAddr, "config", "rule", "show", "t1"}
+	_, output, err = pdctl.ExecuteCommandC(cmd, args2...)
+	c.Assert(err, IsNil)
+	showRule := &rule.Rule{}
+	c.Assert(json.Unmarshal(output, showRule), IsNil)
+	c.Assert(showRule.StartKeyHex, Equals, "61")
+
+	// config rule delete <rule_id>
+	args3 = []string{"-u", pdAddr, "config", "rule", "delete", "t1"}
+	_, _, err = pdctl.ExecuteCommandC(cmd, args3...)
+	c.Assert(err, IsNil)
+	c.Assert(ruleManager.GetRule("t1"), IsNil)
 }
@@ -50,11 +50,13 @@ func InitCommand() *cobra.Command {
 		command.NewPingCommand(),
 		command.NewOperatorCommand(),
 		command.NewSchedulerCommand(),
+		command.NewScheduleCommand(),
 		command.NewTSOCommand(),
 		command.NewHotSpotCommand(),
 		command.NewClusterCommand(),
 		command.NewTableNamespaceCommand(),
 		command.NewHealthCommand(),
+		command.NewTopologyCommand(),
 		command.NewLogCommand(),
 	)
 	return rootCmd
@@ -24,6 +24,7 @@ import (
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/pingcap/pd/server"
 	"github.com/pingcap/pd/server/api"
+	"github.com/pingcap/pd/server/statistics"
 	"github.com/pingcap/pd/tests"
 	"github.com/pingcap/pd/tests/pdctl"
 )
@@ -101,4 +102,12 @@ func (s *hotTestSuite) TestHot(c *C) {
 	c.Assert(hotStores.BytesReadStats[1], Equals, bytesRead/10)
 	c.Assert(hotStores.KeysWriteStats[1], Equals, keysWritten/10)
 	c.Assert(hotStores.KeysReadStats[1], Equals, keysRead/10)
+
+	// hot history
+	args = []string{"-u", pdAddr, "hot", "history", "--store", "1", "--kind", "write", "--start", "0"}
+	_, output, err = pdctl.ExecuteCommandC(cmd, args...)
+	c.Assert(err, IsNil)
+	var records []*statistics.HotRegionHistoryRecord
+	c.Assert(json.Unmarshal(output, &records), IsNil)
+	c.Assert(records, HasLen, 0)
 }
@@ -14,6 +14,10 @@
 package operator_test
 
 import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -23,6 +27,7 @@ import (
 	"github.com/pingcap/pd/server"
 	"github.com/pingcap/pd/server/config"
 	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/schedule"
 	"github.com/pingcap/pd/tests"
 	"github.com/pingcap/pd/tests/pdctl"
 )
@@ -195,4 +200,50 @@ func (s *operatorTestSuite) TestOperator(c *C) {
 	_, output, err = pdctl.ExecuteCommandC(cmd, args...)
 	c.Assert(err, IsNil)
 	c.Assert(strings.Contains(string(output), "scatter-region"), IsTrue)
+
+	// operator history [--region <region_id>] [--status <status>]
+	args = []string{"-u", pdAddr, "operator", "history", "--region", "1", "--status", "cancel"}
+	_, output, err = pdctl.ExecuteCommandC(cmd, args...)
+	c.Assert(err, IsNil)
+	var records []*schedule.OperatorHistoryRecord
+	c.Assert(json.Unmarshal(output, &records), IsNil)
+	c.Assert(len(records), Greater, 0)
+	c.Assert(records[0].Desc, Equals, "admin-merge-region")
+	for _, record := range records {
+		c.Assert(record.RegionID, Equals, uint64(1))
+		c.Assert(record.Status, Equals, "CANCEL")
+	}
+
+	// operator batch -f <plan.json>
+	for _, regionID := range []string{"1", "3"} {
+		args = []string{"-u", pdAddr, "operator", "remove", regionID}
+		_, _, err = pdctl.ExecuteCommandC(cmd, args...)
+		c.Assert(err, IsNil)
+	}
+	plan := filepath.Join(c.MkDir(), "plan.json")
+	c.Assert(ioutil.WriteFile(plan, []byte(`[{"name": "transfer-leader", "region_id": 1, "to_store_id": 2}, {"name": "transfer-leader", "region_id": 3, "to_store_id": 1}]`), 0644), IsNil)
+	args = []string{"-u", pdAddr, "operator", "batch", "-f", plan}
+	_, output, err = pdctl.ExecuteCommandC(cmd, args...)
+	c.Assert(err, IsNil)
+	var status schedule.OperatorBatchStatus
+	c.Assert(json.Unmarshal(output, &status), IsNil)
+	c.Assert(status.Total, Equals, 2)
+	c.Assert(status.Operators[1].Desc, Equals, "admin-transfer-leader")
+	// operator batch show <batch_id>
+	batchID := strconv.FormatUint(status.ID, 10)
+	args = []string{"-u", pdAddr, "operator", "batch", "show", batchID}
+	_, output, err = pdctl.ExecuteCommandC(cmd, args...)
+	c.Assert(err, IsNil)
+	c.Assert(json.Unmarshal(output, &status), IsNil)
+	c.Assert(status.Running, Equals, 2)
+	// operator batch cancel <batch_id>
+	args = []string{"-u", pdAddr, "operator", "batch", "cancel", batchID}
+	_, _, err = pdctl.ExecuteCommandC(cmd, args...)
+	c.Assert(err, IsNil)
+	args = []string{"-u", pdAddr, "operator", "batch", "show", batchID}
+	_, output, err = pdctl.ExecuteCommandC(cmd, args...)
+	c.Assert(err, IsNil)
+	c.Assert(json.Unmarshal(output, &status), IsNil)
+	c.Assert(status.Failed, Equals, 2)
+	c.Assert(status.Finished, IsTrue)
 }
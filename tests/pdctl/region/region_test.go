@@ -27,6 +27,7 @@ import (
 	"github.com/pingcap/pd/server"
 	"github.com/pingcap/pd/server/api"
 	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/statistics"
 	"github.com/pingcap/pd/tests"
 	"github.com/pingcap/pd/tests/pdctl"
 	ctl "github.com/pingcap/pd/tools/pd-ctl/pdctl"
@@ -229,6 +230,17 @@ func (s *regionTestSuite) TestRegion(c *C) {
 	c.Assert(json.Unmarshal(output, &regionsInfo), IsNil)
 	pdctl.CheckRegionsInfo(c, regionsInfo, []*core.RegionInfo{r3})
 
+	// region check hole command
+	args = []string{"-u", pdAddr, "region", "check", "hole"}
+	_, output, err = pdctl.ExecuteCommandC(cmd, args...)
+	c.Assert(err, IsNil)
+	report := statistics.KeySpaceAuditReport{}
+	c.Assert(json.Unmarshal(output, &report), IsNil)
+	c.Assert(report.RegionCount, Equals, 4)
+	c.Assert(report.Holes, HasLen, 2)
+	c.Assert(report.Holes[0].EndKey, Equals, string(core.HexRegionKey([]byte("a"))))
+	c.Assert(report.Holes[1].StartKey, Equals, string(core.HexRegionKey([]byte("e"))))
+
 	// region key --format=raw <key> command
 	args = []string{"-u", pdAddr, "region", "key", "--format=raw", "b"}
 	_, output, err = pdctl.ExecuteCommandC(cmd, args...)
@@ -15,6 +15,7 @@ package scheduler_test
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
@@ -131,4 +132,71 @@ func (s *schedulerTestSuite) TestScheduler(c *C) {
 	for _, scheduler := range schedulers {
 		c.Assert(expected[scheduler], Equals, true)
 	}
+
+	// scheduler add evict-region-scheduler with multiple stores
+	args = []string{"-u", pdAddr, "scheduler", "add", "evict-region-scheduler", "2", "3"}
+	_, _, err = pdctl.ExecuteCommandC(cmd, args...)
+	c.Assert(err, IsNil)
+	args = []string{"-u", pdAddr, "scheduler", "show"}
+	_, output, err = pdctl.ExecuteCommandC(cmd, args...)
+	c.Assert(err, IsNil)
+	schedulers = schedulers[:0]
+	c.Assert(json.Unmarshal(output, &schedulers), IsNil)
+	c.Assert(schedulers, HasLen, 5)
+	expected["evict-region-scheduler-2-3"] = true
+	for _, scheduler := range schedulers {
+		c.Assert(expected[scheduler], Equals, true)
+	}
+
+	// scheduler progress command
+	args = []string{"-u", pdAddr, "scheduler", "progress", "evict-region-scheduler-2-3"}
+	_, output, err = pdctl.ExecuteCommandC(cmd, args...)
+	c.Assert(err, IsNil)
+	progress := make(map[string]interface{})
+	c.Assert(json.Unmarshal(output, &progress), IsNil)
+	c.Assert(progress, HasKey, "remaining_regions")
+	c.Assert(progress, HasKey, "eta")
+
+	// scheduler diagnosis command
+	args = []string{"-u", pdAddr, "scheduler", "diagnosis", "balance-leader-scheduler"}
+	_, output, err = pdctl.ExecuteCommandC(cmd, args...)
+	c.Assert(err, IsNil)
+	diagnosis := make(map[string]interface{})
+	c.Assert(json.Unmarshal(output, &diagnosis), IsNil)
+	c.Assert(diagnosis["name"], Equals, "balance-leader-scheduler")
+	c.Assert(diagnosis, HasKey, "not_allowed_count")
+	c.Assert(diagnosis, HasKey, "trace")
+
+	// scheduler pause and resume command
+	args = []string{"-u", pdAddr, "scheduler", "pause", "balance-leader-scheduler", "60"}
+	_, output, err = pdctl.ExecuteCommandC(cmd, args...)
+	c.Assert(err, IsNil)
+	c.Assert(strings.Contains(string(output), "success"), IsTrue)
+	args = []string{"-u", pdAddr, "scheduler", "show", "--status", "paused"}
+	_, output, err = pdctl.ExecuteCommandC(cmd, args...)
+	c.Assert(err, IsNil)
+	schedulers = schedulers[:0]
+	c.Assert(json.Unmarshal(output, &schedulers), IsNil)
+	c.Assert(schedulers, DeepEquals, []string{"balance-leader-scheduler"})
+	args = []string{"-u", pdAddr, "scheduler", "resume", "balance-leader-scheduler"}
+	_, output, err = pdctl.ExecuteCommandC(cmd, args...)
+	c.Assert(err, IsNil)
+	c.Assert(strings.Contains(string(output), "success"), IsTrue)
+	args = []string{"-u", pdAddr, "scheduler", "show", "--status", "paused"}
+	_, output, err = pdctl.ExecuteCommandC(cmd, args...)
+	c.Assert(err, IsNil)
+	schedulers = schedulers[:0]
+	c.Assert(json.Unmarshal(output, &schedulers), IsNil)
+	c.Assert(schedulers, HasLen, 0)
+
+	// schedule plan command
+	args = []string{"-u", pdAddr, "schedule", "plan", "--scheduler", "evict-leader,1", "--checker", "replica,merge"}
+	_, output, err = pdctl.ExecuteCommandC(cmd, args...)
+	c.Assert(err, IsNil)
+	var planned []map[string]interface{}
+	c.Assert(json.Unmarshal(output, &planned), IsNil)
+	args = []string{"-u", pdAddr, "schedule", "plan", "--checker", "unknown"}
+	_, output, err = pdctl.ExecuteCommandC(cmd, args...)
+	c.Assert(err, IsNil)
+	c.Assert(strings.Contains(string(output), "unknown checker"), IsTrue)
 }
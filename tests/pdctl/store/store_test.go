@@ -119,6 +119,26 @@ func (s *storeTestSuite) TestStore(c *C) {
 	c.Assert(storeInfo.Status.LeaderWeight, Equals, float64(5))
 	c.Assert(storeInfo.Status.RegionWeight, Equals, float64(10))
 
+	// store limit <store_id> <rate> [<type>] command
+	args = []string{"-u", pdAddr, "store", "limit", "1", "12"}
+	_, _, err = pdctl.ExecuteCommandC(cmd, args...)
+	c.Assert(err, IsNil)
+	args = []string{"-u", pdAddr, "store", "limit", "1", "6", "remove-peer"}
+	_, _, err = pdctl.ExecuteCommandC(cmd, args...)
+	c.Assert(err, IsNil)
+	args = []string{"-u", pdAddr, "stores", "show", "limit"}
+	_, output, err = pdctl.ExecuteCommandC(cmd, args...)
+	c.Assert(err, IsNil)
+	limits := make(map[uint64]map[string]float64)
+	c.Assert(json.Unmarshal(output, &limits), IsNil)
+	c.Assert(limits[1], DeepEquals, map[string]float64{"add-peer": 12, "remove-peer": 6})
+	args = []string{"-u", pdAddr, "stores", "show", "limit", "remove-peer"}
+	_, output, err = pdctl.ExecuteCommandC(cmd, args...)
+	c.Assert(err, IsNil)
+	limits = make(map[uint64]map[string]float64)
+	c.Assert(json.Unmarshal(output, &limits), IsNil)
+	c.Assert(limits[1], DeepEquals, map[string]float64{"remove-peer": 6})
+
 	// store delete <store_id> command
 	c.Assert(storeInfo.Store.State, Equals, metapb.StoreState_Up)
 	args = []string{"-u", pdAddr, "store", "delete", "1"}
@@ -0,0 +1,94 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topology_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/server"
+	"github.com/pingcap/pd/server/config"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/statistics"
+	"github.com/pingcap/pd/tests"
+	"github.com/pingcap/pd/tests/pdctl"
+)
+
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&topologyTestSuite{})
+
+type topologyTestSuite struct{}
+
+func (s *topologyTestSuite) SetUpSuite(c *C) {
+	server.EnableZap = true
+}
+
+func (s *topologyTestSuite) TestTopology(c *C) {
+	c.Parallel()
+
+	cluster, err := tests.NewTestCluster(1, func(cfg *config.Config) { cfg.Replication.LocationLabels = []string{"zone"} })
+	c.Assert(err, IsNil)
+	err = cluster.RunInitialServers()
+	c.Assert(err, IsNil)
+	cluster.WaitLeader()
+	pdAddr := cluster.GetConfig().GetClientURLs()
+	cmd := pdctl.InitCommand()
+
+	leaderServer := cluster.GetServer(cluster.GetLeader())
+	c.Assert(leaderServer.BootstrapCluster(), IsNil)
+	for id, zone := range map[uint64]string{1: "z1", 2: "z1", 3: "z2", 4: "z3"} {
+		pdctl.MustPutStore(c, leaderServer.GetServer(), id, metapb.StoreState_Up, []*metapb.StoreLabel{{Key: "zone", Value: zone}})
+	}
+	defer cluster.Destroy()
+
+	// The region has 2 replicas in zone z1.
+	peers := []*metapb.Peer{{Id: 11, StoreId: 1}, {Id: 12, StoreId: 2}, {Id: 13, StoreId: 3}}
+	region := core.NewRegionInfo(&metapb.Region{
+		Id:          10,
+		Peers:       peers,
+		RegionEpoch: &metapb.RegionEpoch{ConfVer: 1, Version: 1},
+	}, peers[0])
+	c.Assert(cluster.HandleRegionHeartbeat(region), IsNil)
+
+	// topology command
+	args := []string{"-u", pdAddr, "topology"}
+	_, output, err := pdctl.ExecuteCommandC(cmd, args...)
+	c.Assert(err, IsNil)
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	c.Assert(lines, HasLen, 9)
+	c.Assert(lines[0], Equals, "location labels: [zone], max replicas: 3")
+	c.Assert(strings.HasPrefix(lines[1], "cluster  stores: 4, regions: 3, leaders: 1"), IsTrue)
+	c.Assert(strings.HasPrefix(lines[2], "├── zone=z1  stores: 2, regions: 2, leaders: 1"), IsTrue)
+	c.Assert(strings.HasSuffix(lines[2], "[isolation at risk: 1 regions have more than 1 replicas]"), IsTrue)
+	c.Assert(strings.HasPrefix(lines[3], "│   ├── store 1 (tikv1)"), IsTrue)
+	c.Assert(strings.HasPrefix(lines[4], "│   └── store 2 (tikv2)"), IsTrue)
+	c.Assert(strings.HasPrefix(lines[5], "├── zone=z2  stores: 1"), IsTrue)
+	c.Assert(strings.Contains(lines[5], "isolation at risk"), IsFalse)
+	c.Assert(strings.HasPrefix(lines[8], "    └── store 4 (tikv4)"), IsTrue)
+
+	// topology --json command
+	args = []string{"-u", pdAddr, "topology", "--json"}
+	_, output, err = pdctl.ExecuteCommandC(cmd, args...)
+	c.Assert(err, IsNil)
+	topo := &statistics.Topology{}
+	c.Assert(json.Unmarshal(output, topo), IsNil)
+	c.Assert(topo.Root.Children, HasLen, 3)
+	c.Assert(topo.Root.Children[0].IsolationAtRisk, IsTrue)
+}
@@ -16,6 +16,7 @@ package command
 import (
 	"bytes"
 	"encoding/json"
+	"io/ioutil"
 	"net/http"
 	"path"
 	"strconv"
@@ -30,6 +31,9 @@ var (
 	namespacePrefix      = "pd/api/v1/config/namespace"
 	labelPropertyPrefix  = "pd/api/v1/config/label-property"
 	clusterVersionPrefix = "pd/api/v1/config/cluster-version"
+	placementPrefix      = "pd/api/v1/config/placement"
+	rulesPrefix          = "pd/api/v1/config/rules"
+	rulePrefix           = "pd/api/v1/config/rule"
 )
 
 // NewConfigCommand return a config subcommand of rootCmd
@@ -41,6 +45,8 @@ func NewConfigCommand() *cobra.Command {
 	conf.AddCommand(NewShowConfigCommand())
 	conf.AddCommand(NewSetConfigCommand())
 	conf.AddCommand(NewDeleteConfigCommand())
+	conf.AddCommand(NewPlacementConfigCommand())
+	conf.AddCommand(NewRuleConfigCommand())
 	return conf
 }
 
@@ -183,6 +189,90 @@ func NewDeleteLabelPropertyConfigCommand() *cobra.Command {
 	return sc
 }
 
+// NewPlacementConfigCommand returns a placement subcommand of configCmd.
+func NewPlacementConfigCommand() *cobra.Command {
+	sc := &cobra.Command{
+		Use:   "placement show|set|delete",
+		Short: "tune the placement constraints",
+	}
+	sc.AddCommand(NewShowPlacementConfigCommand())
+	sc.AddCommand(NewSetPlacementConfigCommand())
+	sc.AddCommand(NewDeletePlacementConfigCommand())
+	return sc
+}
+
+// NewShowPlacementConfigCommand returns a show subcommand of placement subcommand.
+func NewShowPlacementConfigCommand() *cobra.Command {
+	sc := &cobra.Command{
+		Use:   "show",
+		Short: "show the placement constraints",
+		Run:   showPlacementConfigCommandFunc,
+	}
+	return sc
+}
+
+// NewSetPlacementConfigCommand returns a set subcommand of placement subcommand.
+func NewSetPlacementConfigCommand() *cobra.Command {
+	sc := &cobra.Command{
+		Use:   "set <constraints>",
+		Short: "replace the placement constraints, for example 'count(zone:z1,rack:r1,host)>=3;count_leader(zone:z1)=1'",
+		Run:   setPlacementConfigCommandFunc,
+	}
+	return sc
+}
+
+// NewDeletePlacementConfigCommand returns a delete subcommand of placement subcommand.
+func NewDeletePlacementConfigCommand() *cobra.Command {
+	sc := &cobra.Command{
+		Use:   "delete",
+		Short: "remove all the placement constraints",
+		Run:   deletePlacementConfigCommandFunc,
+	}
+	return sc
+}
+
+// NewRuleConfigCommand returns a rule subcommand of configCmd.
+func NewRuleConfigCommand() *cobra.Command {
+	sc := &cobra.Command{
+		Use:   "rule show|set|delete",
+		Short: "tune the placement rules bound to key ranges",
+	}
+	sc.AddCommand(NewShowRuleConfigCommand())
+	sc.AddCommand(NewSetRuleConfigCommand())
+	sc.AddCommand(NewDeleteRuleConfigCommand())
+	return sc
+}
+
+// NewShowRuleConfigCommand returns a show subcommand of rule subcommand.
+func NewShowRuleConfigCommand() *cobra.Command {
+	sc := &cobra.Command{
+		Use:   "show [<rule_id>]",
+		Short: "show all placement rules or the specified one",
+		Run:   showRuleConfigCommandFunc,
+	}
+	return sc
+}
+
+// NewSetRuleConfigCommand returns a set subcommand of rule subcommand.
+func NewSetRuleConfigCommand() *cobra.Command {
+	sc := &cobra.Command{
+		Use:   "set <rule_file>",
+		Short: "create or update a placement rule with the JSON file",
+		Run:   setRuleConfigCommandFunc,
+	}
+	return sc
+}
+
+// NewDeleteRuleConfigCommand returns a delete subcommand of rule subcommand.
+func NewDeleteRuleConfigCommand() *cobra.Command {
+	sc := &cobra.Command{
+		Use:   "delete <rule_id>",
+		Short: "delete a placement rule",
+		Run:   deleteRuleConfigCommandFunc,
+	}
+	return sc
+}
+
 func showConfigCommandFunc(cmd *cobra.Command, args []string) {
 	allR, err := doRequest(cmd, configPrefix, http.MethodGet)
 	if err != nil {
@@ -367,3 +457,81 @@ func setClusterVersionCommandFunc(cmd *cobra.Command, args []string) {
 	}
 	postJSON(cmd, clusterVersionPrefix, input)
 }
+
+func showPlacementConfigCommandFunc(cmd *cobra.Command, args []string) {
+	r, err := doRequest(cmd, placementPrefix, http.MethodGet)
+	if err != nil {
+		cmd.Printf("Failed to get placement config: %s\n", err)
+		return
+	}
+	cmd.Println(r)
+}
+
+func setPlacementConfigCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		cmd.Println(cmd.UsageString())
+		return
+	}
+	input := map[string]interface{}{
+		"constraints": args[0],
+	}
+	postJSON(cmd, placementPrefix, input)
+}
+
+func deletePlacementConfigCommandFunc(cmd *cobra.Command, args []string) {
+	_, err := doRequest(cmd, placementPrefix, http.MethodDelete)
+	if err != nil {
+		cmd.Printf("Failed to delete placement config: %s\n", err)
+		return
+	}
+	cmd.Println("Success!")
+}
+
+func showRuleConfigCommandFunc(cmd *cobra.Command, args []string) {
+	prefix := rulesPrefix
+	switch len(args) {
+	case 0:
+	case 1:
+		prefix = path.Join(rulePrefix, args[0])
+	default:
+		cmd.Println(cmd.UsageString())
+		return
+	}
+	r, err := doRequest(cmd, prefix, http.MethodGet)
+	if err != nil {
+		cmd.Printf("Failed to get placement rule: %s\n", err)
+		return
+	}
+	cmd.Println(r)
+}
+
+func setRuleConfigCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		cmd.Println(cmd.UsageString())
+		return
+	}
+	data, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		cmd.Printf("Failed to read the rule file: %s\n", err)
+		return
+	}
+	_, err = doRequest(cmd, rulePrefix, http.MethodPost, WithBody("application/json", bytes.NewBuffer(data)))
+	if err != nil {
+		cmd.Printf("Failed to set placement rule: %s\n", err)
+		return
+	}
+	cmd.Println("Success!")
+}
+
+func deleteRuleConfigCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		cmd.Println(cmd.UsageString())
+		return
+	}
+	_, err := doRequest(cmd, path.Join(rulePrefix, args[0]), http.MethodDelete)
+	if err != nil {
+		cmd.Printf("Failed to delete placement rule: %s\n", err)
+		return
+	}
+	cmd.Println("Success!")
+}
@@ -15,6 +15,7 @@ package command
 
 import (
 	"net/http"
+	"net/url"
 
 	"github.com/spf13/cobra"
 )
@@ -23,6 +24,7 @@ const (
 	hotReadRegionsPrefix  = "pd/api/v1/hotspot/regions/read"
 	hotWriteRegionsPrefix = "pd/api/v1/hotspot/regions/write"
 	hotStoresPrefix       = "pd/api/v1/hotspot/stores"
+	hotHistoryPrefix      = "pd/api/v1/hotspot/regions/history"
 )
 
 // NewHotSpotCommand return a hot subcommand of rootCmd
@@ -34,6 +36,7 @@ func NewHotSpotCommand() *cobra.Command {
 	cmd.AddCommand(NewHotWriteRegionCommand())
 	cmd.AddCommand(NewHotReadRegionCommand())
 	cmd.AddCommand(NewHotStoreCommand())
+	cmd.AddCommand(NewHotHistoryCommand())
 	return cmd
 }
 
@@ -93,3 +96,49 @@ func showHotStoresCommandFunc(cmd *cobra.Command, args []string) {
 	}
 	cmd.Println(r)
 }
+
+// NewHotHistoryCommand return a hot history subcommand of hotSpotCmd
+func NewHotHistoryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history [--store <store_id>] [--table <table_id>] [--kind <read|write>] [--start <unix_time>] [--end <unix_time>] [--limit <limit>]",
+		Short: "show the hot peers saved in the history, the latest first",
+		Run:   showHotHistoryCommandFunc,
+	}
+	cmd.Flags().String("store", "", "the store which the hot peers are on")
+	cmd.Flags().String("table", "", "the table which the Regions of the hot peers overlap")
+	cmd.Flags().String("kind", "", "the kind of the flow, read or write")
+	cmd.Flags().String("start", "", "the earliest time the hot peers are saved in unix seconds")
+	cmd.Flags().String("end", "", "the latest time the hot peers are saved in unix seconds")
+	cmd.Flags().String("limit", "", "the max number of the hot peers to show")
+	return cmd
+}
+
+func showHotHistoryCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 0 {
+		cmd.Println(cmd.UsageString())
+		return
+	}
+	query := url.Values{}
+	for flag, param := range map[string]string{
+		"store": "store_id",
+		"table": "table_id",
+		"kind":  "kind",
+		"start": "start",
+		"end":   "end",
+		"limit": "limit",
+	} {
+		if v := cmd.Flags().Lookup(flag).Value.String(); v != "" {
+			query.Set(param, v)
+		}
+	}
+	path := hotHistoryPrefix
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	r, err := doRequest(cmd, path, http.MethodGet)
+	if err != nil {
+		cmd.Printf("Failed to get hot history: %s\n", err)
+		return
+	}
+	cmd.Println(r)
+}
@@ -14,8 +14,11 @@
 package command
 
 import (
+	"bytes"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strconv"
 
 	"github.com/pkg/errors"
@@ -36,6 +39,8 @@ func NewOperatorCommand() *cobra.Command {
 	c.AddCommand(NewCheckOperatorCommand())
 	c.AddCommand(NewAddOperatorCommand())
 	c.AddCommand(NewRemoveOperatorCommand())
+	c.AddCommand(NewOperatorHistoryCommand())
+	c.AddCommand(NewOperatorBatchCommand())
 	return c
 }
 
@@ -78,6 +83,54 @@ func showOperatorCommandFunc(cmd *cobra.Command, args []string) {
 	cmd.Println(r)
 }
 
+// NewOperatorHistoryCommand returns a command to show the ended operators.
+func NewOperatorHistoryCommand() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "history [--region <region_id>] [--store <store_id>] [--kind <kind>] [--status <status>] [--start <unix_time>] [--end <unix_time>] [--limit <limit>]",
+		Short: "show the ended operators, the latest first",
+		Run:   showOperatorHistoryCommandFunc,
+	}
+	c.Flags().String("region", "", "the region which the operators are on")
+	c.Flags().String("store", "", "the store which the operators touch")
+	c.Flags().String("kind", "", "the kind of the operators, for example 'leader' or 'region,replica'")
+	c.Flags().String("status", "", "the status the operators end with, one of success, timeout, cancel and replace")
+	c.Flags().String("start", "", "the earliest finish time of the operators in unix seconds")
+	c.Flags().String("end", "", "the latest finish time of the operators in unix seconds")
+	c.Flags().String("limit", "", "the max number of the operators to show")
+	return c
+}
+
+func showOperatorHistoryCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 0 {
+		cmd.Println(cmd.UsageString())
+		return
+	}
+	query := url.Values{}
+	for flag, param := range map[string]string{
+		"region": "region_id",
+		"store":  "store_id",
+		"kind":   "kind",
+		"status": "status",
+		"start":  "start",
+		"end":    "end",
+		"limit":  "limit",
+	} {
+		if v := cmd.Flags().Lookup(flag).Value.String(); v != "" {
+			query.Set(param, v)
+		}
+	}
+	path := operatorsPrefix + "/history"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	r, err := doRequest(cmd, path, http.MethodGet)
+	if err != nil {
+		cmd.Printf("Failed to get operator history: %s\n", err)
+		return
+	}
+	cmd.Println(r)
+}
+
 func checkOperatorCommandFunc(cmd *cobra.Command, args []string) {
 	var path string
 	if len(args) == 0 {
@@ -410,6 +463,72 @@ func removeOperatorCommandFunc(cmd *cobra.Command, args []string) {
 	}
 }
 
+// NewOperatorBatchCommand returns a command to add a batch of operators.
+func NewOperatorBatchCommand() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "batch -f <plan.json>",
+		Short: "add a batch of operators in the plan file, either all of them are added or none of them",
+		Run:   addOperatorBatchCommandFunc,
+	}
+	c.Flags().StringP("file", "f", "", "the JSON file of the operators, for example [{\"name\": \"transfer-leader\", \"region_id\": 1, \"to_store_id\": 2}]")
+	c.AddCommand(&cobra.Command{
+		Use:   "show <batch_id>",
+		Short: "show the progress of the operator batch",
+		Run:   showOperatorBatchCommandFunc,
+	})
+	c.AddCommand(&cobra.Command{
+		Use:   "cancel <batch_id>",
+		Short: "cancel the running operators of the batch",
+		Run:   cancelOperatorBatchCommandFunc,
+	})
+	return c
+}
+
+func addOperatorBatchCommandFunc(cmd *cobra.Command, args []string) {
+	file := cmd.Flags().Lookup("file").Value.String()
+	if len(args) != 0 || file == "" {
+		cmd.Println(cmd.UsageString())
+		return
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		cmd.Printf("Failed to read the plan file: %s\n", err)
+		return
+	}
+	r, err := doRequest(cmd, operatorsPrefix+"/batch", http.MethodPost, WithBody("application/json", bytes.NewBuffer(data)))
+	if err != nil {
+		cmd.Printf("Failed to add operator batch: %s\n", err)
+		return
+	}
+	cmd.Println(r)
+}
+
+func showOperatorBatchCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		cmd.Println(cmd.UsageString())
+		return
+	}
+	r, err := doRequest(cmd, operatorsPrefix+"/batch/"+args[0], http.MethodGet)
+	if err != nil {
+		cmd.Printf("Failed to get operator batch: %s\n", err)
+		return
+	}
+	cmd.Println(r)
+}
+
+func cancelOperatorBatchCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		cmd.Println(cmd.UsageString())
+		return
+	}
+	_, err := doRequest(cmd, operatorsPrefix+"/batch/"+args[0], http.MethodDelete)
+	if err != nil {
+		cmd.Printf("Failed to cancel operator batch: %s\n", err)
+		return
+	}
+	cmd.Println("Success!")
+}
+
 func parseUint64s(args []string) ([]uint64, error) {
 	results := make([]uint64, 0, len(args))
 	for _, arg := range args {
@@ -414,7 +414,7 @@ func showRegionsFromStartKeyCommandFunc(cmd *cobra.Command, args []string) {
 // NewRegionWithCheckCommand returns a region with check subcommand of regionCmd
 func NewRegionWithCheckCommand() *cobra.Command {
 	r := &cobra.Command{
-		Use:   "check [miss-peer|extra-peer|down-peer|pending-peer|incorrect-ns]",
+		Use:   "check [miss-peer|extra-peer|down-peer|pending-peer|incorrect-ns|hole]",
 		Short: "show the region with check specific status",
 		Run:   showRegionWithCheckCommandFunc,
 	}
@@ -427,6 +427,10 @@ func showRegionWithCheckCommandFunc(cmd *cobra.Command, args []string) {
 		return
 	}
 	state := args[0]
+	// The holes and the stale leaders are found by auditing the key space.
+	if state == "hole" {
+		state = "key-holes"
+	}
 	prefix := regionsCheckPrefix + "/" + state
 	r, err := doRequest(cmd, prefix, http.MethodGet)
 	if err != nil {
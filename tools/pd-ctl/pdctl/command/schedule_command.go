@@ -0,0 +1,95 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const schedulePlanPrefix = "pd/api/v1/schedule/plan"
+
+// NewScheduleCommand returns a schedule subcommand of rootCmd
+func NewScheduleCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schedule <subcommand>",
+		Short: "schedule commands",
+	}
+	cmd.AddCommand(NewSchedulePlanCommand())
+	return cmd
+}
+
+// NewSchedulePlanCommand returns a plan subcommand of scheduleCmd
+func NewSchedulePlanCommand() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "plan [--scheduler <type>[,<arg>...]]... [--checker <checker>]... [--limit <limit>]",
+		Short: "show the operators the schedulers and checkers would create without dispatching them",
+		Run:   showSchedulePlanCommandFunc,
+	}
+	c.Flags().StringArray("scheduler", nil, "the scheduler to run with its arguments, for example 'evict-leader,1'")
+	c.Flags().StringSlice("checker", nil, "the checker to run, one of learner, namespace, replica, placement and merge")
+	c.Flags().Int("limit", 16, "the max number of operators created by the checkers")
+	return c
+}
+
+func showSchedulePlanCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 0 {
+		cmd.Println(cmd.UsageString())
+		return
+	}
+	schedulerFlags, err := cmd.Flags().GetStringArray("scheduler")
+	if err != nil {
+		cmd.Println(err)
+		return
+	}
+	checkers, err := cmd.Flags().GetStringSlice("checker")
+	if err != nil {
+		cmd.Println(err)
+		return
+	}
+	limit, err := cmd.Flags().GetInt("limit")
+	if err != nil {
+		cmd.Println(err)
+		return
+	}
+
+	schedulers := make([]map[string]interface{}, 0, len(schedulerFlags))
+	for _, s := range schedulerFlags {
+		fields := strings.Split(s, ",")
+		schedulers = append(schedulers, map[string]interface{}{
+			"type": fields[0],
+			"args": fields[1:],
+		})
+	}
+	input := map[string]interface{}{
+		"schedulers": schedulers,
+		"checkers":   checkers,
+		"limit":      limit,
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		cmd.Println(err)
+		return
+	}
+	r, err := doRequest(cmd, schedulePlanPrefix, http.MethodPost, WithBody("application/json", bytes.NewBuffer(data)))
+	if err != nil {
+		cmd.Printf("Failed to get schedule plan: %s\n", err)
+		return
+	}
+	cmd.Println(r)
+}
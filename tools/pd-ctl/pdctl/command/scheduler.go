@@ -34,6 +34,10 @@ func NewSchedulerCommand() *cobra.Command {
 	c.AddCommand(NewShowSchedulerCommand())
 	c.AddCommand(NewAddSchedulerCommand())
 	c.AddCommand(NewRemoveSchedulerCommand())
+	c.AddCommand(NewSchedulerProgressCommand())
+	c.AddCommand(NewSchedulerDiagnosisCommand())
+	c.AddCommand(NewPauseSchedulerCommand())
+	c.AddCommand(NewResumeSchedulerCommand())
 	return c
 }
 
@@ -44,6 +48,7 @@ func NewShowSchedulerCommand() *cobra.Command {
 		Short: "show schedulers",
 		Run:   showSchedulerCommandFunc,
 	}
+	c.Flags().String("status", "", "only show the schedulers in the status, such as paused")
 	return c
 }
 
@@ -53,7 +58,11 @@ func showSchedulerCommandFunc(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	r, err := doRequest(cmd, schedulersPrefix, http.MethodGet)
+	path := schedulersPrefix
+	if status, _ := cmd.Flags().GetString("status"); status != "" {
+		path += "?status=" + url.QueryEscape(status)
+	}
+	r, err := doRequest(cmd, path, http.MethodGet)
 	if err != nil {
 		cmd.Println(err)
 		return
@@ -69,6 +78,7 @@ func NewAddSchedulerCommand() *cobra.Command {
 	}
 	c.AddCommand(NewGrantLeaderSchedulerCommand())
 	c.AddCommand(NewEvictLeaderSchedulerCommand())
+	c.AddCommand(NewEvictRegionSchedulerCommand())
 	c.AddCommand(NewShuffleLeaderSchedulerCommand())
 	c.AddCommand(NewShuffleRegionSchedulerCommand())
 	c.AddCommand(NewShuffleHotRegionSchedulerCommand())
@@ -77,6 +87,7 @@ func NewAddSchedulerCommand() *cobra.Command {
 	c.AddCommand(NewBalanceRegionSchedulerCommand())
 	c.AddCommand(NewBalanceHotRegionSchedulerCommand())
 	c.AddCommand(NewRandomMergeSchedulerCommand())
+	c.AddCommand(NewSplitHotRegionSchedulerCommand())
 	c.AddCommand(NewBalanceAdjacentRegionSchedulerCommand())
 	c.AddCommand(NewLabelSchedulerCommand())
 	return c
@@ -120,6 +131,38 @@ func addSchedulerForStoreCommandFunc(cmd *cobra.Command, args []string) {
 	postJSON(cmd, schedulersPrefix, input)
 }
 
+// NewEvictRegionSchedulerCommand returns a command to add a evict-region-scheduler.
+func NewEvictRegionSchedulerCommand() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "evict-region-scheduler <store_id> [<store_id>...]",
+		Short: "add a scheduler to evict all regions from stores",
+		Run:   addSchedulerForStoresCommandFunc,
+	}
+	return c
+}
+
+func addSchedulerForStoresCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) == 0 {
+		cmd.Println(cmd.UsageString())
+		return
+	}
+
+	storeIDs := make([]uint64, 0, len(args))
+	for _, arg := range args {
+		storeID, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			cmd.Println(err)
+			return
+		}
+		storeIDs = append(storeIDs, storeID)
+	}
+
+	input := make(map[string]interface{})
+	input["name"] = cmd.Name()
+	input["store_ids"] = storeIDs
+	postJSON(cmd, schedulersPrefix, input)
+}
+
 // NewShuffleLeaderSchedulerCommand returns a command to add a shuffle-leader-scheduler.
 func NewShuffleLeaderSchedulerCommand() *cobra.Command {
 	c := &cobra.Command{
@@ -210,6 +253,16 @@ func NewRandomMergeSchedulerCommand() *cobra.Command {
 	return c
 }
 
+// NewSplitHotRegionSchedulerCommand returns a command to add a split-hot-region-scheduler.
+func NewSplitHotRegionSchedulerCommand() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "split-hot-region-scheduler",
+		Short: "add a scheduler to split the regions which stay hot for a long time",
+		Run:   addSchedulerCommandFunc,
+	}
+	return c
+}
+
 // NewLabelSchedulerCommand returns a command to add a label-scheduler.
 func NewLabelSchedulerCommand() *cobra.Command {
 	c := &cobra.Command{
@@ -316,3 +369,104 @@ func removeSchedulerCommandFunc(cmd *cobra.Command, args []string) {
 		return
 	}
 }
+
+// NewSchedulerProgressCommand returns a command to show the progress of a scheduler.
+func NewSchedulerProgressCommand() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "progress <scheduler>",
+		Short: "show the progress of a scheduler, such as evict-region-scheduler",
+		Run:   schedulerProgressCommandFunc,
+	}
+	return c
+}
+
+func schedulerProgressCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		cmd.Println(cmd.UsageString())
+		return
+	}
+
+	path := schedulersPrefix + "/" + args[0] + "/progress"
+	r, err := doRequest(cmd, path, http.MethodGet)
+	if err != nil {
+		cmd.Println(err)
+		return
+	}
+	cmd.Println(r)
+}
+
+// NewSchedulerDiagnosisCommand returns a command to show why a scheduler
+// creates no operator.
+func NewSchedulerDiagnosisCommand() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "diagnosis <scheduler>",
+		Short: "show why a scheduler creates no operator, such as balance-region-scheduler",
+		Run:   schedulerDiagnosisCommandFunc,
+	}
+	return c
+}
+
+func schedulerDiagnosisCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		cmd.Println(cmd.UsageString())
+		return
+	}
+
+	path := schedulersPrefix + "/" + args[0] + "/diagnosis"
+	r, err := doRequest(cmd, path, http.MethodGet)
+	if err != nil {
+		cmd.Println(err)
+		return
+	}
+	cmd.Println(r)
+}
+
+// NewPauseSchedulerCommand returns a command to pause a scheduler.
+func NewPauseSchedulerCommand() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "pause <scheduler> [seconds]",
+		Short: "pause a scheduler, it resumes automatically after the seconds if specified",
+		Run:   pauseSchedulerCommandFunc,
+	}
+	return c
+}
+
+func pauseSchedulerCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 1 && len(args) != 2 {
+		cmd.Println(cmd.UsageString())
+		return
+	}
+
+	input := make(map[string]interface{})
+	input["action"] = "pause"
+	if len(args) == 2 {
+		seconds, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			cmd.Println(err)
+			return
+		}
+		input["seconds"] = seconds
+	}
+	postJSON(cmd, schedulersPrefix+"/"+args[0], input)
+}
+
+// NewResumeSchedulerCommand returns a command to resume a paused scheduler.
+func NewResumeSchedulerCommand() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "resume <scheduler>",
+		Short: "resume a paused scheduler",
+		Run:   resumeSchedulerCommandFunc,
+	}
+	return c
+}
+
+func resumeSchedulerCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		cmd.Println(cmd.UsageString())
+		return
+	}
+
+	input := make(map[string]interface{})
+	input["action"] = "resume"
+	postJSON(cmd, schedulersPrefix+"/"+args[0], input)
+}
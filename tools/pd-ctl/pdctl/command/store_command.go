@@ -86,8 +86,8 @@ func NewSetStoreWeightCommand() *cobra.Command {
 // NewSetStoreLimitCommand returns a limit subcommand of storeCmd.
 func NewSetStoreLimitCommand() *cobra.Command {
 	return &cobra.Command{
-		Use:   "limit <store_id> <rate>",
-		Short: "set a store's rate limit",
+		Use:   "limit <store_id> <rate> [add-peer|remove-peer]",
+		Short: "set a store's rate limit, both the add-peer and remove-peer limits are set if the type is unset",
 		Run:   setStoreLimitCommandFunc,
 	}
 }
@@ -128,7 +128,7 @@ func NewShowStoresCommand() *cobra.Command {
 // NewShowAllLimitCommand return a show limit subcommand of show command
 func NewShowAllLimitCommand() *cobra.Command {
 	sc := &cobra.Command{
-		Use:   "limit",
+		Use:   "limit [add-peer|remove-peer]",
 		Short: "show all stores' limit",
 		Run:   showAllLimitCommandFunc,
 	}
@@ -148,8 +148,8 @@ func NewSetStoresCommand() *cobra.Command {
 // NewSetAllLimitCommand returns a set limit subcommand of set command.
 func NewSetAllLimitCommand() *cobra.Command {
 	return &cobra.Command{
-		Use:   "limit <rate>",
-		Short: "set all store's rate limit",
+		Use:   "limit <rate> [add-peer|remove-peer]",
+		Short: "set all store's rate limit, both the add-peer and remove-peer limits are set if the type is unset",
 		Run:   setAllLimitCommandFunc,
 	}
 }
@@ -280,8 +280,8 @@ func setStoreWeightCommandFunc(cmd *cobra.Command, args []string) {
 }
 
 func setStoreLimitCommandFunc(cmd *cobra.Command, args []string) {
-	if len(args) != 2 {
-		cmd.Println("Usage: store limit <store_id> <rate>")
+	if len(args) != 2 && len(args) != 3 {
+		cmd.Println("Usage: store limit <store_id> <rate> [add-peer|remove-peer]")
 		return
 	}
 	rate, err := strconv.ParseFloat(args[1], 64)
@@ -289,10 +289,14 @@ func setStoreLimitCommandFunc(cmd *cobra.Command, args []string) {
 		cmd.Println("rate should be a number that >= 0.")
 		return
 	}
-	prefix := fmt.Sprintf(path.Join(storePrefix, "limit"), args[0])
-	postJSON(cmd, prefix, map[string]interface{}{
+	input := map[string]interface{}{
 		"rate": rate,
-	})
+	}
+	if len(args) == 3 {
+		input["type"] = args[2]
+	}
+	prefix := fmt.Sprintf(path.Join(storePrefix, "limit"), args[0])
+	postJSON(cmd, prefix, input)
 }
 
 func showStoresCommandFunc(cmd *cobra.Command, args []string) {
@@ -310,7 +314,14 @@ func showStoresCommandFunc(cmd *cobra.Command, args []string) {
 }
 
 func showAllLimitCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) > 1 {
+		cmd.Println("Usage: stores show limit [add-peer|remove-peer]")
+		return
+	}
 	prefix := path.Join(storesPrefix, "limit")
+	if len(args) == 1 {
+		prefix += "?type=" + args[0]
+	}
 	r, err := doRequest(cmd, prefix, http.MethodGet)
 	if err != nil {
 		cmd.Printf("Failed to get all limit: %s\n", err)
@@ -330,8 +341,8 @@ func removeTombStoneCommandFunc(cmd *cobra.Command, args []string) {
 }
 
 func setAllLimitCommandFunc(cmd *cobra.Command, args []string) {
-	if len(args) != 1 {
-		cmd.Println("Usage: stores set limit <rate>")
+	if len(args) != 1 && len(args) != 2 {
+		cmd.Println("Usage: stores set limit <rate> [add-peer|remove-peer]")
 		return
 	}
 	rate, err := strconv.ParseFloat(args[0], 64)
@@ -339,8 +350,12 @@ func setAllLimitCommandFunc(cmd *cobra.Command, args []string) {
 		cmd.Println("rate should be a number that >= 0.")
 		return
 	}
-	prefix := path.Join(storesPrefix, "limit")
-	postJSON(cmd, prefix, map[string]interface{}{
+	input := map[string]interface{}{
 		"rate": rate,
-	})
+	}
+	if len(args) == 2 {
+		input["type"] = args[1]
+	}
+	prefix := path.Join(storesPrefix, "limit")
+	postJSON(cmd, prefix, input)
 }
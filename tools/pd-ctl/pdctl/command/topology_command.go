@@ -0,0 +1,121 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	gh "github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+)
+
+var topologyPrefix = "pd/api/v1/topology"
+
+type topologyNode struct {
+	Label                string          `json:"label"`
+	Value                string          `json:"value"`
+	StoreID              uint64          `json:"store_id"`
+	Address              string          `json:"address"`
+	StoreCount           int             `json:"store_count"`
+	Capacity             uint64          `json:"capacity"`
+	UsedSize             uint64          `json:"used_size"`
+	RegionCount          int             `json:"region_count"`
+	LeaderCount          int             `json:"leader_count"`
+	BytesWriteRate       float64         `json:"bytes_write_rate"`
+	BytesReadRate        float64         `json:"bytes_read_rate"`
+	MaxRegionReplicas    int             `json:"max_region_replicas"`
+	IsolationRiskRegions int             `json:"isolation_risk_regions"`
+	IsolationAtRisk      bool            `json:"isolation_at_risk"`
+	Children             []*topologyNode `json:"children"`
+}
+
+type topology struct {
+	LocationLabels []string      `json:"location_labels"`
+	MaxReplicas    int           `json:"max_replicas"`
+	Root           *topologyNode `json:"root"`
+}
+
+// NewTopologyCommand return a topology subcommand of rootCmd
+func NewTopologyCommand() *cobra.Command {
+	t := &cobra.Command{
+		Use:   "topology [--json]",
+		Short: "show the stores grouped by the location labels as a tree",
+		Run:   showTopologyCommandFunc,
+	}
+	t.Flags().Bool("json", false, "show the topology in JSON")
+	return t
+}
+
+func showTopologyCommandFunc(cmd *cobra.Command, args []string) {
+	r, err := doRequest(cmd, topologyPrefix, http.MethodGet)
+	if err != nil {
+		cmd.Printf("Failed to get topology: %s\n", err)
+		return
+	}
+	if showJSON, _ := cmd.Flags().GetBool("json"); showJSON {
+		cmd.Println(r)
+		return
+	}
+	topo := &topology{}
+	if err := json.Unmarshal([]byte(r), topo); err != nil {
+		cmd.Printf("Failed to unmarshal topology: %s\n", err)
+		return
+	}
+	cmd.Printf("location labels: [%s], max replicas: %d\n", strings.Join(topo.LocationLabels, ", "), topo.MaxReplicas)
+	if topo.Root == nil {
+		return
+	}
+	cmd.Println(topo.Root.describe())
+	printTopologyChildren(cmd, topo.Root, "")
+}
+
+// printTopologyChildren prints the children of the node as the branches of
+// a tree, the prefix is the indentation of the node.
+func printTopologyChildren(cmd *cobra.Command, node *topologyNode, prefix string) {
+	for i, child := range node.Children {
+		branch, indent := "├── ", "│   "
+		if i == len(node.Children)-1 {
+			branch, indent = "└── ", "    "
+		}
+		cmd.Println(prefix + branch + child.describe())
+		printTopologyChildren(cmd, child, prefix+indent)
+	}
+}
+
+func (n *topologyNode) describe() string {
+	var name string
+	switch {
+	case n.StoreID != 0:
+		name = fmt.Sprintf("store %d (%s)", n.StoreID, n.Address)
+	case n.Label != "":
+		value := n.Value
+		if value == "" {
+			value = "<none>"
+		}
+		name = fmt.Sprintf("%s=%s", n.Label, value)
+	default:
+		name = "cluster"
+	}
+	s := fmt.Sprintf("%s  stores: %d, regions: %d, leaders: %d, used: %s/%s, write: %s/s, read: %s/s",
+		name, n.StoreCount, n.RegionCount, n.LeaderCount,
+		gh.IBytes(n.UsedSize), gh.IBytes(n.Capacity),
+		gh.IBytes(uint64(n.BytesWriteRate)), gh.IBytes(uint64(n.BytesReadRate)))
+	if n.IsolationAtRisk {
+		s += fmt.Sprintf("  [isolation at risk: %d regions have more than %d replicas]", n.IsolationRiskRegions, n.MaxRegionReplicas)
+	}
+	return s
+}
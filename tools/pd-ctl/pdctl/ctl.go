@@ -57,11 +57,13 @@ func Start(args []string) {
 		command.NewPingCommand(),
 		command.NewOperatorCommand(),
 		command.NewSchedulerCommand(),
+		command.NewScheduleCommand(),
 		command.NewTSOCommand(),
 		command.NewHotSpotCommand(),
 		command.NewClusterCommand(),
 		command.NewTableNamespaceCommand(),
 		command.NewHealthCommand(),
+		command.NewTopologyCommand(),
 		command.NewLogCommand(),
 	)
 